@@ -0,0 +1,21 @@
+package openapi
+
+// XAnchor is a vendor extension of operations and schema components with a stable deep link identifier.
+const XAnchor = "x-anchor"
+
+// OperationAnchor returns deep link identifier of operation, e.g. "operation/getUsersId".
+//
+// Identifier is based on operation ID, or on method and path pattern if operation has no ID,
+// so that links to documentation survive regeneration of spec.
+func OperationAnchor(method, pathPattern, operationID string) string {
+	if operationID == "" {
+		operationID = methodPathOperationID(method, pathPattern)
+	}
+
+	return "operation/" + operationID
+}
+
+// SchemaAnchor returns deep link identifier of schema component, e.g. "schema/User".
+func SchemaAnchor(name string) string {
+	return "schema/" + name
+}
@@ -0,0 +1,75 @@
+package openapi
+
+import "time"
+
+// APIKeyRotation declares API keys that are both accepted during rotation window.
+//
+// Primary and secondary keys are defined as separate security schemes, secondary scheme
+// is marked with "x-deprecated" and "x-sunset" vendor extensions.
+//
+//	rotation := openapi.APIKeyRotation{
+//		SecurityName: "apiKey",
+//		FieldIn:      openapi.InHeader,
+//		Primary:      "X-API-Key",
+//		Secondary:    "X-API-Key-Previous",
+//		Sunset:       time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+//	}
+//
+//	rotation.Setup(r.SpecSchema())
+//	rotation.Trait().Apply(oc)
+type APIKeyRotation struct {
+	// SecurityName is a name of primary security scheme, secondary scheme name has "Secondary" suffix.
+	SecurityName string
+	FieldIn      In
+	Description  string
+
+	// Primary is a field name of current key, e.g. "X-API-Key".
+	Primary string
+
+	// Secondary is a field name of key that is being phased out, e.g. "X-API-Key-Previous".
+	Secondary string
+
+	// Sunset is a time after which secondary key is no longer accepted, zero value omits "x-sunset".
+	Sunset time.Time
+}
+
+// SecondarySecurityName returns name of secondary security scheme.
+func (k APIKeyRotation) SecondarySecurityName() string {
+	return k.SecurityName + "Secondary"
+}
+
+// Setup defines security schemes of primary and secondary keys, secondary scheme is marked
+// with "x-deprecated" and "x-sunset" extensions if spec implements SpecExtender.
+func (k APIKeyRotation) Setup(s SpecSchema) {
+	s.SetAPIKeySecurity(k.SecurityName, k.Primary, k.FieldIn, k.Description)
+
+	if k.Secondary == "" {
+		return
+	}
+
+	name := k.SecondarySecurityName()
+
+	s.SetAPIKeySecurity(name, k.Secondary, k.FieldIn, k.Description)
+
+	e, ok := s.(SpecExtender)
+	if !ok {
+		return
+	}
+
+	e.SetSecuritySchemeExtension(name, "x-deprecated", true)
+
+	if !k.Sunset.IsZero() {
+		e.SetSecuritySchemeExtension(name, "x-sunset", k.Sunset.UTC().Format(time.RFC3339))
+	}
+}
+
+// Trait returns a Trait that adds primary and secondary keys as alternative security requirements.
+func (k APIKeyRotation) Trait() Trait {
+	t := Trait{Security: map[string][]string{k.SecurityName: nil}}
+
+	if k.Secondary != "" {
+		t.Security[k.SecondarySecurityName()] = nil
+	}
+
+	return t
+}
@@ -0,0 +1,53 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestAPIKeyRotation(t *testing.T) {
+	rotation := openapi.APIKeyRotation{
+		SecurityName: "apiKey",
+		FieldIn:      openapi.InHeader,
+		Description:  "Account API key.",
+		Primary:      "X-API-Key",
+		Secondary:    "X-API-Key-Previous",
+		Sunset:       time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	r31 := openapi31.NewReflector()
+	r3 := openapi3.NewReflector()
+
+	for _, r := range []openapi.Reflector{r3, r31} {
+		rotation.Setup(r.SpecSchema())
+
+		oc, err := r.NewOperationContext(http.MethodGet, "/orders")
+		require.NoError(t, err)
+
+		rotation.Trait().Apply(oc)
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	expected := `{
+	  "securitySchemes":{
+		"apiKey":{"type":"apiKey","name":"X-API-Key","in":"header","description":"Account API key."},
+		"apiKeySecondary":{
+		  "type":"apiKey","name":"X-API-Key-Previous","in":"header","description":"Account API key.",
+		  "x-deprecated":true,"x-sunset":"2024-06-01T00:00:00Z"
+		}
+	  }
+	}`
+
+	assertjson.EqMarshal(t, expected, r3.Spec.Components)
+	assertjson.EqMarshal(t, expected, r31.Spec.Components)
+
+	assertjson.EqMarshal(t, `[{"apiKey":[]},{"apiKeySecondary":[]}]`, r3.Spec.Paths.MapOfPathItemValues["/orders"].MapOfOperationValues["get"].Security)
+	assertjson.EqMarshal(t, `[{"apiKey":[]},{"apiKeySecondary":[]}]`, r31.Spec.Paths.MapOfPathItemValues["/orders"].Get.Security)
+}
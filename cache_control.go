@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// CacheControl documents caching headers of response.
+type CacheControl struct {
+	// Directives is an example value of Cache-Control header, e.g. "public, max-age=3600".
+	Directives string
+
+	// Age enables Age header with number of seconds the response has been in a shared cache.
+	Age bool
+
+	// Expires enables Expires header with HTTP date after which response is stale.
+	Expires bool
+}
+
+// WithCacheControl is a ContentUnit option, it documents Cache-Control response header,
+// and Age and Expires headers if enabled.
+func WithCacheControl(cc CacheControl) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		cu.CacheControl = &cc
+	}
+}
+
+// HeadersStructure returns a structure with header fields of cache control.
+func (c CacheControl) HeadersStructure() interface{} {
+	cacheControlTag := `header:"Cache-Control" description:"Directives for caches along request-response chain."`
+	if c.Directives != "" {
+		cacheControlTag += ` example:` + strconv.Quote(c.Directives)
+	}
+
+	fields := []reflect.StructField{
+		{Name: "CacheControl", Type: reflect.TypeOf(""), Tag: reflect.StructTag(cacheControlTag)},
+	}
+
+	if c.Age {
+		fields = append(fields, reflect.StructField{
+			Name: "Age", Type: reflect.TypeOf(0),
+			Tag: `header:"Age" minimum:"0" description:"Number of seconds the response has been in a shared cache."`,
+		})
+	}
+
+	if c.Expires {
+		fields = append(fields, reflect.StructField{
+			Name: "Expires", Type: reflect.TypeOf(""),
+			Tag: `header:"Expires" example:"Wed, 21 Oct 2015 07:28:00 GMT" description:"HTTP date after which the response is considered stale."`,
+		})
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Elem().Interface()
+}
@@ -0,0 +1,160 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// clientMethod writes client method of operation.
+func (g *generator) clientMethod(w *bytes.Buffer, o operation) {
+	var (
+		code      bytes.Buffer
+		hasQuery  bool
+		hasHeader bool
+	)
+
+	errReturn := "err"
+	if o.result != "" {
+		errReturn = "nil, err"
+	}
+
+	for _, p := range o.params {
+		switch p.in {
+		case "query":
+			hasQuery = true
+
+			fmt.Fprintf(&code, "for _, v := range paramValues(req.%s) {\nquery.Add(%q, v)\n}\n\n", p.field.name, p.name)
+		case "header":
+			hasHeader = true
+
+			fmt.Fprintf(&code, "for _, v := range paramValues(req.%s) {\nheader.Add(%q, v)\n}\n\n", p.field.name, p.name)
+		case "cookie":
+			hasHeader = true
+
+			fmt.Fprintf(&code, "for _, v := range paramValues(req.%s) {\n"+
+				"header.Add(\"Cookie\", (&http.Cookie{Name: %q, Value: v}).String())\n}\n\n", p.field.name, p.name)
+		}
+	}
+
+	if b := o.body; b != nil {
+		hasHeader = true
+
+		if !isJSON(b.mediaType) {
+			fmt.Fprintf(&code, "if req.Body != nil {\nbody = req.Body\nheader.Set(\"Content-Type\", %q)\n}\n\n", b.mediaType)
+		} else {
+			encode := fmt.Sprintf("b, err := json.Marshal(req.Body)\nif err != nil {\nreturn %s\n}\n\n"+
+				"body = bytes.NewReader(b)\nheader.Set(\"Content-Type\", %q)\n", errReturn, b.mediaType)
+
+			g.importName("bytes")
+
+			if nilable(b.typ) {
+				encode = "if req.Body != nil {\n" + encode + "}\n"
+			}
+
+			code.WriteString(encode + "\n")
+		}
+	}
+
+	w.WriteString("\n")
+	methodComment(w, o, "performs")
+	fmt.Fprintf(w, "func (c *%s) %s%s {\n", g.cfg.ClientName, o.name, o.signature())
+
+	args := []string{"ctx", strconv.Quote(o.method), clientPath(o), "nil", "nil", "nil", "nil"}
+
+	if hasQuery {
+		w.WriteString("query := url.Values{}\n")
+
+		args[3] = "query"
+	}
+
+	if hasHeader {
+		w.WriteString("header := http.Header{}\n")
+
+		args[4] = "header"
+	}
+
+	if o.body != nil {
+		w.WriteString("var body io.Reader\n")
+
+		args[5] = "body"
+	}
+
+	if hasQuery || hasHeader {
+		w.WriteString("\n")
+	}
+
+	w.Write(code.Bytes())
+
+	if o.result == "" {
+		fmt.Fprintf(w, "return c.do(%s)\n}\n", strings.Join(args, ", "))
+
+		return
+	}
+
+	args[6] = "&result"
+
+	res := "result"
+	if optional(o.result) != o.result {
+		res = "&result"
+	}
+
+	fmt.Fprintf(w, "var result %s\n\nif err := c.do(%s); err != nil {\nreturn nil, err\n}\n\nreturn %s, nil\n}\n",
+		o.result, strings.Join(args, ", "), res)
+}
+
+// clientPath returns Go expression of operation path with escaped parameters.
+func clientPath(o operation) string {
+	fields := map[string]string{}
+
+	for _, p := range o.params {
+		if p.in == "path" {
+			fields[p.name] = p.field.name
+		}
+	}
+
+	var parts []string
+
+	for _, segment := range splitPath(o.path) {
+		if f, ok := fields[segment.param]; ok {
+			parts = append(parts, "url.PathEscape(strings.Join(paramValues(req."+f+"), \",\"))")
+		} else {
+			parts = append(parts, strconv.Quote(segment.text))
+		}
+	}
+
+	if len(parts) == 0 {
+		return `""`
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+type pathPart struct {
+	text  string
+	param string
+}
+
+// splitPath splits path template into literal text and parameters.
+func splitPath(path string) []pathPart {
+	var res []pathPart
+
+	for path != "" {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+
+		if start == -1 || end < start {
+			return append(res, pathPart{text: path})
+		}
+
+		if start > 0 {
+			res = append(res, pathPart{text: path[:start]})
+		}
+
+		res = append(res, pathPart{text: path[start : end+1], param: path[start+1 : end]})
+		path = path[end+1:]
+	}
+
+	return res
+}
@@ -0,0 +1,109 @@
+package codegen
+
+// clientRuntime is a source of client structure and helpers, %[1]s is a name of client structure.
+const clientRuntime = `
+// %[1]s is a client of API.
+type %[1]s struct {
+	// BaseURL is a URL of API server, e.g. "https://api.example.com".
+	BaseURL string
+
+	// HTTPClient performs requests, http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+}
+
+// New%[1]s creates client of API server.
+func New%[1]s(baseURL string) *%[1]s {
+	return &%[1]s{BaseURL: baseURL}
+}
+
+// Error is returned for responses with unsuccessful HTTP status.
+type Error struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("unexpected response status %%d: %%s", e.StatusCode, e.Body)
+}
+
+func (c *%[1]s) do(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, result interface{}) error {
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		return &Error{StatusCode: resp.StatusCode, Body: b}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// paramValues formats parameter value, nil pointers have no values and slices have a value per item.
+func paramValues(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if rv.Kind() == reflect.Slice {
+		res := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			res = append(res, paramValues(rv.Index(i).Interface())...)
+		}
+
+		return res
+	}
+
+	if t, ok := rv.Interface().(time.Time); ok {
+		return []string{t.Format(time.RFC3339Nano)}
+	}
+
+	return []string{fmt.Sprint(rv.Interface())}
+}
+`
+
+// clientImports are used by clientRuntime.
+var clientImports = []string{
+	"context", "encoding/json", "fmt", "io", "net/http", "net/url", "reflect", "strings", "time",
+}
@@ -0,0 +1,319 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// Config controls generated client.
+type Config struct {
+	// PackageName is a name of generated package, default "client" or "server".
+	PackageName string
+
+	// PackagePath is an import path of generated package, its types are referenced without qualifier.
+	PackagePath string
+
+	// ClientName is a name of client structure, default "Client".
+	ClientName string
+
+	// SkipTypes disables declarations of request and schema types,
+	// e.g. when they are generated with another file of the same package.
+	SkipTypes bool
+}
+
+// Client generates source of Go client of operations added to reflector.
+//
+// Each operation becomes a method named after its operationId. Schemas annotated with "x-go-type"
+// (see openapi.GoTypes) are reused from originating packages, other schemas become generated types.
+func Client(r openapi.Reflector, cfg Config) ([]byte, error) {
+	return GenerateClient(r.SpecSchema(), cfg)
+}
+
+// GenerateClient creates source of Go client of a reflected or loaded spec, see Client.
+func GenerateClient(spec interface{}, cfg Config) ([]byte, error) {
+	if cfg.PackageName == "" {
+		cfg.PackageName = "client"
+	}
+
+	g, err := newGenerator(spec, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := g.operations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range clientImports {
+		g.importName(path)
+	}
+
+	var code bytes.Buffer
+
+	fmt.Fprintf(&code, clientRuntime, g.cfg.ClientName)
+
+	for _, o := range ops {
+		g.clientMethod(&code, o)
+	}
+
+	return g.source("client", code.Bytes(), ops)
+}
+
+func newGenerator(spec interface{}, cfg Config) (*generator, error) {
+	if cfg.ClientName == "" {
+		cfg.ClientName = "Client"
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	g := generator{
+		cfg:        cfg,
+		imports:    map[string]string{},
+		importUsed: map[string]bool{},
+		methods:    map[string]bool{},
+		types:      map[string]string{},
+	}
+
+	if err := json.Unmarshal(data, &g.doc); err != nil {
+		return nil, err
+	}
+
+	g.schemas = object(object(g.doc["components"])["schemas"])
+
+	// Local variables of generated methods and standard packages must not be shadowed by imports.
+	for _, name := range localNames {
+		g.importUsed[name] = true
+	}
+
+	for _, name := range stdImports {
+		g.importUsed[name] = true
+	}
+
+	return &g, nil
+}
+
+type generator struct {
+	cfg     Config
+	doc     map[string]interface{}
+	schemas map[string]interface{}
+
+	// imports maps import path to package name.
+	imports    map[string]string
+	importUsed map[string]bool
+
+	methods map[string]bool
+
+	// types maps schema component name to name of generated type, pending are not generated yet.
+	types   map[string]string
+	pending []string
+}
+
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// localNames are used for variables of generated methods.
+var localNames = []string{
+	"b", "body", "c", "ctx", "err", "h", "header", "path", "query", "r", "req", "res", "result", "si", "v", "w",
+}
+
+// stdImports maps standard packages used by generated code to their names.
+var stdImports = map[string]string{
+	"bytes": "bytes", "context": "context", "encoding": "encoding", "encoding/json": "json", "errors": "errors",
+	"fmt": "fmt", "io": "io", "net/http": "http", "net/url": "url", "reflect": "reflect", "strconv": "strconv",
+	"strings": "strings", "time": "time",
+}
+
+// source assembles formatted source of generated code with request and schema types, role describes package.
+func (g *generator) source(role string, code []byte, ops []operation) ([]byte, error) {
+	var types bytes.Buffer
+
+	if !g.cfg.SkipTypes {
+		for _, o := range ops {
+			if o.hasRequest() {
+				requestType(&types, o)
+			}
+		}
+
+		var decls []string
+
+		for len(g.pending) > 0 {
+			name := g.pending[0]
+			g.pending = g.pending[1:]
+
+			decls = append(decls, g.declaration(name))
+		}
+
+		sort.Strings(decls)
+
+		for _, d := range decls {
+			types.WriteString("\n" + d)
+		}
+	}
+
+	var src bytes.Buffer
+
+	src.WriteString("// Code generated by openapi-go codegen. DO NOT EDIT.\n\n")
+
+	if title := str(object(g.doc["info"])["title"]); title != "" && !g.cfg.SkipTypes {
+		fmt.Fprintf(&src, "// Package %s is a %s of %s.\n", g.cfg.PackageName, role, title)
+	}
+
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", g.cfg.PackageName)
+
+	// Standard library imports are grouped before others.
+	var std, other []string
+
+	for _, path := range internal.SortedKeys(g.imports) {
+		spec := strconv.Quote(path)
+		if name := g.imports[path]; name != path[strings.LastIndex(path, "/")+1:] {
+			spec = name + " " + spec
+		}
+
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+
+	src.WriteString(strings.Join(std, "\n") + "\n")
+
+	if len(other) > 0 {
+		src.WriteString("\n" + strings.Join(other, "\n") + "\n")
+	}
+
+	src.WriteString(")\n")
+	src.Write(code)
+	src.Write(types.Bytes())
+
+	res, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+
+	return res, nil
+}
+
+// importName returns name to qualify identifiers of imported package.
+func (g *generator) importName(path string) string {
+	if name, ok := g.imports[path]; ok {
+		return name
+	}
+
+	if name, ok := stdImports[path]; ok {
+		g.imports[path] = name
+
+		return name
+	}
+
+	elems := strings.Split(path, "/")
+	base := elems[len(elems)-1]
+
+	if len(elems) > 1 && len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		base = elems[len(elems)-2]
+	}
+
+	base = strings.TrimSuffix(strings.TrimPrefix(base, "go-"), "-go")
+
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+
+		return -1
+	}, base)
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "pkg" + name
+	}
+
+	unique := name
+	for i := 2; g.importUsed[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+
+	g.imports[path] = unique
+	g.importUsed[unique] = true
+
+	return unique
+}
+
+// qualify returns reference to Go type from "x-go-type", e.g. "pets.Pet" for "github.com/acme/pets.Pet".
+func (g *generator) qualify(goType string) string {
+	i := strings.LastIndex(goType, ".")
+	if i <= 0 || i < strings.LastIndex(goType, "/") {
+		return "interface{}"
+	}
+
+	path, name := goType[:i], goType[i+1:]
+	if path == g.cfg.PackagePath {
+		return name
+	}
+
+	return g.importName(path) + "." + name
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{}) //nolint:errcheck // Other values are empty.
+
+	return m
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string) //nolint:errcheck // Other values are empty.
+
+	return s
+}
+
+// comment formats text as Go comment lines.
+func comment(w *bytes.Buffer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		w.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
+
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "https": true, "id": true, "ip": true, "json": true,
+	"sql": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// identifier converts name to exported Go identifier, e.g. "get_pet-by-id" to "GetPetByID".
+func identifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9')
+	})
+
+	var b strings.Builder
+
+	for _, p := range parts {
+		if initialisms[strings.ToLower(p)] {
+			b.WriteString(strings.ToUpper(p))
+
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+
+	res := b.String()
+	if res == "" || (res[0] >= '0' && res[0] <= '9') {
+		res = "X" + res
+	}
+
+	return res
+}
@@ -0,0 +1,181 @@
+package codegen_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/codegen"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/report"
+)
+
+type petReq struct {
+	ID    int      `path:"id" description:"Pet ID."`
+	Trace string   `header:"X-Trace"`
+	Tags  []string `query:"tags"`
+}
+
+type pet struct {
+	Name  string    `json:"name" required:"true" description:"Name of pet."`
+	Born  time.Time `json:"born"`
+	Owner *owner    `json:"owner"`
+}
+
+type owner struct {
+	Email string `json:"email" required:"true"`
+}
+
+type checkReq struct {
+	Dry bool `query:"dry"`
+	pet
+}
+
+func newReflector(t *testing.T) *openapi31.Reflector {
+	t.Helper()
+
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Pet store")
+	r.DefaultOptions = append(r.DefaultOptions, openapi.GoTypes)
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets/{id}")
+	require.NoError(t, err)
+
+	oc.SetID("getPet")
+	oc.SetSummary("Get pet.")
+	oc.AddReqStructure(petReq{})
+	oc.AddRespStructure(pet{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNotFound))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+
+	oc.SetID("create_pet")
+	oc.SetIsDeprecated(true)
+	oc.AddReqStructure(pet{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/pets/check")
+	require.NoError(t, err)
+
+	oc.SetID("checkPet")
+	oc.AddReqStructure(checkReq{})
+	oc.AddRespStructure([]report.Finding{})
+	require.NoError(t, r.AddOperation(oc))
+
+	return r
+}
+
+func TestClient(t *testing.T) {
+	src, err := codegen.Client(newReflector(t), codegen.Config{PackageName: "petstore"})
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("testdata/client.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(src))
+
+	// Generated source must compile, report.Finding is reused from its package.
+	typeCheck(t, src)
+}
+
+func TestServer(t *testing.T) {
+	r := newReflector(t)
+
+	src, err := codegen.Server(r, codegen.Config{PackageName: "petstore"})
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("testdata/server.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(src))
+
+	// Client of the same package implements ServerInterface.
+	client, err := codegen.Client(r, codegen.Config{PackageName: "petstore", SkipTypes: true})
+	require.NoError(t, err)
+
+	typeCheck(t, src, client, []byte("package petstore\n\nvar _ ServerInterface = &Client{}\n"))
+}
+
+func typeCheck(t *testing.T, sources ...[]byte) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(sources))
+
+	for i, src := range sources {
+		f, err := parser.ParseFile(fset, fmt.Sprintf("file%d.go", i), src, 0)
+		require.NoError(t, err)
+
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err := conf.Check("petstore", fset, files, nil)
+	require.NoError(t, err)
+}
+
+func TestClient_missingOperationID(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets")
+	require.NoError(t, err)
+	require.NoError(t, r.AddOperation(oc))
+
+	_, err = codegen.Client(r, codegen.Config{})
+	assert.EqualError(t, err, "missing operationId of GET /pets")
+}
+
+type cat struct {
+	Lives int `json:"lives" required:"true"`
+}
+
+type dog struct {
+	Breed string `json:"breed" enum:"husky,corgi"`
+}
+
+type animal struct{}
+
+func (animal) JSONSchemaOneOf() []interface{} {
+	return []interface{}{cat{}, dog{}}
+}
+
+type zoo struct {
+	Name     string            `json:"name" required:"true" description:"Name of zoo."`
+	Keeper   *owner            `json:"keeper"`
+	Animals  []animal          `json:"animals"`
+	Labels   map[string]string `json:"labels"`
+	ID       int               `json:"id" readOnly:"true"`
+	Legacy   string            `json:"legacy-code" deprecated:"true"`
+	Visitors *int              `json:"visitors"`
+}
+
+func TestTypeScript(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/zoo")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(zoo{})
+	require.NoError(t, r.AddOperation(oc))
+
+	src, err := codegen.TypeScript(r)
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("testdata/types.d.ts")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(src))
+}
@@ -0,0 +1,3 @@
+// Package codegen generates typed Go client, server interface and TypeScript definitions
+// of an API from a reflected or loaded spec.
+package codegen
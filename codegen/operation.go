@@ -0,0 +1,322 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+)
+
+type field struct {
+	name        string
+	typ         string
+	tag         string
+	description string
+}
+
+type param struct {
+	field
+
+	in       string
+	name     string
+	required bool
+}
+
+type requestBody struct {
+	field
+
+	mediaType string
+	required  bool
+}
+
+// operation describes generated method of operation.
+type operation struct {
+	name        string
+	method      string
+	path        string
+	summary     string
+	description string
+	deprecated  bool
+
+	params []param
+	body   *requestBody
+
+	// result is a Go type of successful response, empty if response has no JSON content.
+	result          string
+	resultMediaType string
+
+	// status is an HTTP status of successful response.
+	status int
+}
+
+func (o operation) hasRequest() bool {
+	return len(o.params) > 0 || o.body != nil
+}
+
+func (o operation) hasParamIn(in string) bool {
+	for _, p := range o.params {
+		if p.in == in {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o operation) hasParam(in, name string) bool {
+	for _, p := range o.params {
+		if p.in == in && p.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o operation) fields() []field {
+	res := make([]field, 0, len(o.params)+1)
+
+	for _, p := range o.params {
+		res = append(res, p.field)
+	}
+
+	if o.body != nil {
+		res = append(res, o.body.field)
+	}
+
+	return res
+}
+
+// signature returns method signature without name, e.g. "(ctx context.Context, req GetPetRequest) (*Pet, error)".
+func (o operation) signature() string {
+	res := "(ctx context.Context"
+
+	if o.hasRequest() {
+		res += ", req " + o.name + "Request"
+	}
+
+	if o.result == "" {
+		return res + ") error"
+	}
+
+	return res + ") (" + optional(o.result) + ", error)"
+}
+
+// operations collects operations of spec ordered by path and method.
+func (g *generator) operations() ([]operation, error) {
+	var res []operation
+
+	paths := object(g.doc["paths"])
+
+	for _, path := range internal.SortedKeys(paths) {
+		pathItem := object(paths[path])
+
+		for _, method := range operationMethods {
+			op, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			o, err := g.operation(method, path, pathItem, op)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, o)
+		}
+	}
+
+	return res, nil
+}
+
+func (g *generator) operation(method, path string, pathItem, op map[string]interface{}) (operation, error) {
+	o := operation{
+		method:      strings.ToUpper(method),
+		path:        path,
+		summary:     str(op["summary"]),
+		description: str(op["description"]),
+		deprecated:  op["deprecated"] == true,
+	}
+
+	id := str(op["operationId"])
+	if id == "" {
+		return o, fmt.Errorf("missing operationId of %s %s", o.method, path)
+	}
+
+	o.name = identifier(id)
+	if g.methods[o.name] {
+		return o, fmt.Errorf("duplicate method %s of operationId %q", o.name, id)
+	}
+
+	g.methods[o.name] = true
+
+	for _, p := range g.parameters(pathItem, op) {
+		pName, in := str(p["name"]), str(p["in"])
+		pp := param{
+			field: field{
+				name:        identifier(pName),
+				typ:         g.typeOf(p["schema"]),
+				tag:         fmt.Sprintf("%s:%q", in, pName),
+				description: str(p["description"]),
+			},
+			in:       in,
+			name:     pName,
+			required: p["required"] == true,
+		}
+
+		if !pp.required {
+			pp.typ = optional(pp.typ)
+		}
+
+		o.params = append(o.params, pp)
+	}
+
+	for _, part := range splitPath(path) {
+		if part.param != "" && !o.hasParam("path", part.param) {
+			return o, fmt.Errorf("undeclared path parameter %q of %s %s", part.param, o.method, path)
+		}
+	}
+
+	if rb := internal.ResolveLocalRef(g.doc, op["requestBody"]); rb != nil {
+		o.body = g.body(rb)
+	}
+
+	g.result(&o, op)
+
+	return o, nil
+}
+
+// parameters returns parameters of path item overridden by parameters of operation.
+func (g *generator) parameters(pathItem, op map[string]interface{}) []map[string]interface{} {
+	var (
+		res   []map[string]interface{}
+		index = map[string]int{}
+	)
+
+	for _, params := range []interface{}{pathItem["parameters"], op["parameters"]} {
+		params, _ := params.([]interface{}) //nolint:errcheck // Missing parameters are empty.
+
+		for _, p := range params {
+			p := internal.ResolveLocalRef(g.doc, p)
+			key := str(p["in"]) + " " + str(p["name"])
+
+			if i, ok := index[key]; ok {
+				res[i] = p
+
+				continue
+			}
+
+			index[key] = len(res)
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+// body returns request body field, JSON content is preferred, other content is streamed with io.Reader.
+func (g *generator) body(rb map[string]interface{}) *requestBody {
+	content := object(rb["content"])
+	mediaTypes := internal.SortedKeys(content)
+
+	if len(mediaTypes) == 0 {
+		return nil
+	}
+
+	b := requestBody{
+		field:     field{name: "Body", typ: "io.Reader", description: str(rb["description"])},
+		mediaType: mediaTypes[0],
+		required:  rb["required"] == true,
+	}
+
+	for _, mt := range mediaTypes {
+		if isJSON(mt) {
+			b.mediaType = mt
+
+			break
+		}
+	}
+
+	if !isJSON(b.mediaType) {
+		return &b
+	}
+
+	b.typ = g.typeOf(object(content[b.mediaType])["schema"])
+	if !b.required {
+		b.typ = optional(b.typ)
+	}
+
+	return &b
+}
+
+// result sets Go type of first successful JSON response and its HTTP status.
+func (g *generator) result(o *operation, op map[string]interface{}) {
+	responses := object(op["responses"])
+	o.status = 200
+
+	for _, status := range internal.SortedKeys(responses) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+
+		if code, err := strconv.Atoi(status); err == nil {
+			o.status = code
+		}
+
+		content := object(internal.ResolveLocalRef(g.doc, responses[status])["content"])
+
+		for _, mt := range internal.SortedKeys(content) {
+			if isJSON(mt) {
+				o.result = g.typeOf(object(content[mt])["schema"])
+				o.resultMediaType = mt
+
+				return
+			}
+		}
+
+		return
+	}
+}
+
+// requestType writes request structure of operation.
+func requestType(w *bytes.Buffer, o operation) {
+	fmt.Fprintf(w, "\n// %sRequest is a request of %s.\ntype %sRequest struct {\n", o.name, o.name, o.name)
+
+	for _, f := range o.fields() {
+		if f.description != "" {
+			comment(w, f.description)
+		}
+
+		fmt.Fprintf(w, "%s %s", f.name, f.typ)
+
+		if f.tag != "" {
+			fmt.Fprintf(w, " `%s`", f.tag)
+		}
+
+		w.WriteString("\n")
+	}
+
+	w.WriteString("}\n")
+}
+
+// methodComment writes documentation of operation method, verb describes method, e.g. "performs".
+func methodComment(w *bytes.Buffer, o operation, verb string) {
+	fmt.Fprintf(w, "// %s %s %s %s.\n", o.name, verb, o.method, o.path)
+
+	for _, text := range []string{o.summary, o.description} {
+		if text != "" {
+			w.WriteString("//\n")
+			comment(w, text)
+		}
+	}
+
+	if o.deprecated {
+		w.WriteString("//\n// Deprecated: operation is deprecated.\n")
+	}
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,147 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// Server generates source of Go server interface of operations added to reflector.
+//
+// ServerInterface has a method per operation with the same signature as the method of generated client,
+// NewHandler routes HTTP requests, binds parameters and bodies to request structures and calls ServerInterface.
+// Errors returned by ServerInterface can implement StatusCode() int to control HTTP status of response.
+func Server(r openapi.Reflector, cfg Config) ([]byte, error) {
+	return GenerateServer(r.SpecSchema(), cfg)
+}
+
+// GenerateServer creates source of Go server interface of a reflected or loaded spec, see Server.
+func GenerateServer(spec interface{}, cfg Config) ([]byte, error) {
+	if cfg.PackageName == "" {
+		cfg.PackageName = "server"
+	}
+
+	g, err := newGenerator(spec, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := g.operations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range serverImports {
+		g.importName(path)
+	}
+
+	var code bytes.Buffer
+
+	code.WriteString("\n// ServerInterface is implemented by server of API.\ntype ServerInterface interface {\n")
+
+	for i, o := range ops {
+		if i > 0 {
+			code.WriteString("\n")
+		}
+
+		g.importName("context")
+		methodComment(&code, o, "handles")
+		code.WriteString(o.name + o.signature() + "\n")
+	}
+
+	code.WriteString("}\n\n// NewHandler creates HTTP handler of ServerInterface.\n" +
+		"func NewHandler(si ServerInterface) http.Handler {\nh := &handler{si: si}\nh.routes = []route{\n")
+
+	for _, o := range ops {
+		segments := strings.Split(o.path, "/")
+		for i, s := range segments {
+			segments[i] = strconv.Quote(s)
+		}
+
+		fmt.Fprintf(&code, "{method: %q, segments: []string{%s}, handle: h.handle%s},\n",
+			o.method, strings.Join(segments, ", "), o.name)
+	}
+
+	code.WriteString("}\n\nreturn h\n}\n")
+	code.WriteString(serverRuntime)
+
+	for _, o := range ops {
+		g.serverHandler(&code, o)
+	}
+
+	return g.source("server", code.Bytes(), ops)
+}
+
+// serverHandler writes handler method that binds request of operation and calls ServerInterface.
+func (g *generator) serverHandler(w *bytes.Buffer, o operation) {
+	fmt.Fprintf(w, "\nfunc (h *handler) handle%s(w http.ResponseWriter, r *http.Request, "+
+		"pathParams map[string]string) {\n", o.name)
+
+	if o.hasRequest() {
+		fmt.Fprintf(w, "var req %sRequest\n\n", o.name)
+	}
+
+	if o.hasParamIn("query") {
+		w.WriteString("query := r.URL.Query()\n\n")
+	}
+
+	for _, p := range o.params {
+		var values string
+
+		switch p.in {
+		case "path":
+			values = fmt.Sprintf("[]string{pathParams[%q]}", p.name)
+
+			if strings.HasPrefix(strings.TrimPrefix(p.typ, "*"), "[]") {
+				values = fmt.Sprintf("strings.Split(pathParams[%q], \",\")", p.name)
+			}
+		case "query":
+			values = fmt.Sprintf("query[%q]", p.name)
+		case "header":
+			values = fmt.Sprintf("r.Header.Values(%q)", p.name)
+		case "cookie":
+			values = fmt.Sprintf("cookieValues(r, %q)", p.name)
+		default:
+			continue
+		}
+
+		fmt.Fprintf(w, "if err := bindParam(%s, %t, &req.%s); err != nil {\n"+
+			"writeError(w, http.StatusBadRequest, fmt.Errorf(\"%s parameter %s: %%w\", err))\n\nreturn\n}\n\n",
+			values, p.required, p.field.name, p.in, p.name)
+	}
+
+	if b := o.body; b != nil {
+		switch {
+		case !isJSON(b.mediaType):
+			g.importName("io")
+			w.WriteString("req.Body = r.Body\n\n")
+		case b.required:
+			w.WriteString("if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {\n" +
+				"writeError(w, http.StatusBadRequest, fmt.Errorf(\"request body: %w\", err))\n\nreturn\n}\n\n")
+		default:
+			g.importName("io")
+			w.WriteString("if err := json.NewDecoder(r.Body).Decode(&req.Body); " +
+				"err != nil && !errors.Is(err, io.EOF) {\n" +
+				"writeError(w, http.StatusBadRequest, fmt.Errorf(\"request body: %w\", err))\n\nreturn\n}\n\n")
+		}
+	}
+
+	args := "r.Context()"
+	if o.hasRequest() {
+		args += ", req"
+	}
+
+	const fail = "writeError(w, http.StatusInternalServerError, err)\n\nreturn\n}\n\n"
+
+	if o.result == "" {
+		fmt.Fprintf(w, "if err := h.si.%s(%s); err != nil {\n"+fail+"w.WriteHeader(%d)\n}\n", o.name, args, o.status)
+
+		return
+	}
+
+	fmt.Fprintf(w, "res, err := h.si.%s(%s)\nif err != nil {\n"+fail+"writeJSON(w, %d, %q, res)\n}\n",
+		o.name, args, o.status, o.resultMediaType)
+}
@@ -0,0 +1,232 @@
+// Code generated by openapi-go codegen. DO NOT EDIT.
+
+// Package petstore is a client of Pet store.
+package petstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/swaggest/openapi-go/report"
+)
+
+// Client is a client of API.
+type Client struct {
+	// BaseURL is a URL of API server, e.g. "https://api.example.com".
+	BaseURL string
+
+	// HTTPClient performs requests, http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+}
+
+// NewClient creates client of API server.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL}
+}
+
+// Error is returned for responses with unsuccessful HTTP status.
+type Error struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("unexpected response status %d: %s", e.StatusCode, e.Body)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, result interface{}) error {
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		return &Error{StatusCode: resp.StatusCode, Body: b}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// paramValues formats parameter value, nil pointers have no values and slices have a value per item.
+func paramValues(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if rv.Kind() == reflect.Slice {
+		res := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			res = append(res, paramValues(rv.Index(i).Interface())...)
+		}
+
+		return res
+	}
+
+	if t, ok := rv.Interface().(time.Time); ok {
+		return []string{t.Format(time.RFC3339Nano)}
+	}
+
+	return []string{fmt.Sprint(rv.Interface())}
+}
+
+// CreatePet performs POST /pets.
+//
+// Deprecated: operation is deprecated.
+func (c *Client) CreatePet(ctx context.Context, req CreatePetRequest) error {
+	header := http.Header{}
+	var body io.Reader
+
+	if req.Body != nil {
+		b, err := json.Marshal(req.Body)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(b)
+		header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(ctx, "POST", "/pets", nil, header, body, nil)
+}
+
+// CheckPet performs POST /pets/check.
+func (c *Client) CheckPet(ctx context.Context, req CheckPetRequest) ([]report.Finding, error) {
+	query := url.Values{}
+	header := http.Header{}
+	var body io.Reader
+
+	for _, v := range paramValues(req.Dry) {
+		query.Add("dry", v)
+	}
+
+	if req.Body != nil {
+		b, err := json.Marshal(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(b)
+		header.Set("Content-Type", "application/json")
+	}
+
+	var result []report.Finding
+
+	if err := c.do(ctx, "POST", "/pets/check", query, header, body, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetPet performs GET /pets/{id}.
+//
+// Get pet.
+func (c *Client) GetPet(ctx context.Context, req GetPetRequest) (*CodegenTestPet, error) {
+	query := url.Values{}
+	header := http.Header{}
+
+	for _, v := range paramValues(req.Tags) {
+		query.Add("tags", v)
+	}
+
+	for _, v := range paramValues(req.XTrace) {
+		header.Add("X-Trace", v)
+	}
+
+	var result CodegenTestPet
+
+	if err := c.do(ctx, "GET", "/pets/"+url.PathEscape(strings.Join(paramValues(req.ID), ",")), query, header, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreatePetRequest is a request of CreatePet.
+type CreatePetRequest struct {
+	Body *CodegenTestPet
+}
+
+// CheckPetRequest is a request of CheckPet.
+type CheckPetRequest struct {
+	Dry  *bool `query:"dry"`
+	Body *CodegenTestCheckReq
+}
+
+// GetPetRequest is a request of GetPet.
+type GetPetRequest struct {
+	Tags []string `query:"tags"`
+	// Pet ID.
+	ID     int     `path:"id"`
+	XTrace *string `header:"X-Trace"`
+}
+
+// CodegenTestCheckReq is generated from #/components/schemas/CodegenTestCheckReq schema.
+type CodegenTestCheckReq struct {
+	Born *time.Time `json:"born,omitempty"`
+	// Name of pet.
+	Name  string            `json:"name"`
+	Owner *CodegenTestOwner `json:"owner,omitempty"`
+}
+
+// CodegenTestOwner is generated from #/components/schemas/CodegenTestOwner schema.
+type CodegenTestOwner struct {
+	Email string `json:"email"`
+}
+
+// CodegenTestPet is generated from #/components/schemas/CodegenTestPet schema.
+type CodegenTestPet struct {
+	Born *time.Time `json:"born,omitempty"`
+	// Name of pet.
+	Name  string            `json:"name"`
+	Owner *CodegenTestOwner `json:"owner,omitempty"`
+}
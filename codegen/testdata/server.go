@@ -0,0 +1,350 @@
+// Code generated by openapi-go codegen. DO NOT EDIT.
+
+// Package petstore is a server of Pet store.
+package petstore
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/swaggest/openapi-go/report"
+)
+
+// ServerInterface is implemented by server of API.
+type ServerInterface interface {
+	// CreatePet handles POST /pets.
+	//
+	// Deprecated: operation is deprecated.
+	CreatePet(ctx context.Context, req CreatePetRequest) error
+
+	// CheckPet handles POST /pets/check.
+	CheckPet(ctx context.Context, req CheckPetRequest) ([]report.Finding, error)
+
+	// GetPet handles GET /pets/{id}.
+	//
+	// Get pet.
+	GetPet(ctx context.Context, req GetPetRequest) (*CodegenTestPet, error)
+}
+
+// NewHandler creates HTTP handler of ServerInterface.
+func NewHandler(si ServerInterface) http.Handler {
+	h := &handler{si: si}
+	h.routes = []route{
+		{method: "POST", segments: []string{"", "pets"}, handle: h.handleCreatePet},
+		{method: "POST", segments: []string{"", "pets", "check"}, handle: h.handleCheckPet},
+		{method: "GET", segments: []string{"", "pets", "{id}"}, handle: h.handleGetPet},
+	}
+
+	return h
+}
+
+type route struct {
+	method   string
+	segments []string
+	handle   func(w http.ResponseWriter, r *http.Request, pathParams map[string]string)
+}
+
+type handler struct {
+	si     ServerInterface
+	routes []route
+}
+
+// ServeHTTP routes request to operation of ServerInterface.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+
+	var allowed []string
+
+	for _, rt := range h.routes {
+		pathParams, ok := matchSegments(rt.segments, segments)
+		if !ok {
+			continue
+		}
+
+		if rt.method != r.Method {
+			allowed = append(allowed, rt.method)
+
+			continue
+		}
+
+		rt.handle(w, r, pathParams)
+
+		return
+	}
+
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+
+		return
+	}
+
+	writeError(w, http.StatusNotFound, errors.New("not found"))
+}
+
+func matchSegments(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+
+	pathParams := map[string]string{}
+
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			v, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+
+			pathParams[p[1:len(p)-1]] = v
+
+			continue
+		}
+
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+
+	return pathParams, true
+}
+
+// writeError writes error message with HTTP status, errors can implement StatusCode() int
+// to control status of failed operations.
+func writeError(w http.ResponseWriter, status int, err error) {
+	var sc interface{ StatusCode() int }
+	if errors.As(err, &sc) {
+		status = sc.StatusCode()
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func cookieValues(r *http.Request, name string) []string {
+	var res []string
+
+	for _, c := range r.Cookies() {
+		if c.Name == name {
+			res = append(res, c.Value)
+		}
+	}
+
+	return res
+}
+
+// bindParam decodes parameter values into value pointed by dst.
+func bindParam(values []string, required bool, dst interface{}) error {
+	if len(values) == 0 {
+		if required {
+			return errors.New("missing value")
+		}
+
+		return nil
+	}
+
+	return setValue(reflect.ValueOf(dst).Elem(), values)
+}
+
+func setValue(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+
+		return setValue(v.Elem(), values)
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(values[0]))
+	}
+
+	s := values[0]
+
+	switch v.Kind() {
+	case reflect.Slice:
+		items := reflect.MakeSlice(v.Type(), len(values), len(values))
+
+		for i, item := range values {
+			if err := setValue(items.Index(i), []string{item}); err != nil {
+				return err
+			}
+		}
+
+		v.Set(items)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(s))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+func (h *handler) handleCreatePet(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	var req CreatePetRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("request body: %w", err))
+
+		return
+	}
+
+	if err := h.si.CreatePet(r.Context(), req); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (h *handler) handleCheckPet(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	var req CheckPetRequest
+
+	query := r.URL.Query()
+
+	if err := bindParam(query["dry"], false, &req.Dry); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("query parameter dry: %w", err))
+
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("request body: %w", err))
+
+		return
+	}
+
+	res, err := h.si.CheckPet(r.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeJSON(w, 200, "application/json", res)
+}
+
+func (h *handler) handleGetPet(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	var req GetPetRequest
+
+	query := r.URL.Query()
+
+	if err := bindParam(query["tags"], false, &req.Tags); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("query parameter tags: %w", err))
+
+		return
+	}
+
+	if err := bindParam([]string{pathParams["id"]}, true, &req.ID); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("path parameter id: %w", err))
+
+		return
+	}
+
+	if err := bindParam(r.Header.Values("X-Trace"), false, &req.XTrace); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("header parameter X-Trace: %w", err))
+
+		return
+	}
+
+	res, err := h.si.GetPet(r.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeJSON(w, 200, "application/json", res)
+}
+
+// CreatePetRequest is a request of CreatePet.
+type CreatePetRequest struct {
+	Body *CodegenTestPet
+}
+
+// CheckPetRequest is a request of CheckPet.
+type CheckPetRequest struct {
+	Dry  *bool `query:"dry"`
+	Body *CodegenTestCheckReq
+}
+
+// GetPetRequest is a request of GetPet.
+type GetPetRequest struct {
+	Tags []string `query:"tags"`
+	// Pet ID.
+	ID     int     `path:"id"`
+	XTrace *string `header:"X-Trace"`
+}
+
+// CodegenTestCheckReq is generated from #/components/schemas/CodegenTestCheckReq schema.
+type CodegenTestCheckReq struct {
+	Born *time.Time `json:"born,omitempty"`
+	// Name of pet.
+	Name  string            `json:"name"`
+	Owner *CodegenTestOwner `json:"owner,omitempty"`
+}
+
+// CodegenTestOwner is generated from #/components/schemas/CodegenTestOwner schema.
+type CodegenTestOwner struct {
+	Email string `json:"email"`
+}
+
+// CodegenTestPet is generated from #/components/schemas/CodegenTestPet schema.
+type CodegenTestPet struct {
+	Born *time.Time `json:"born,omitempty"`
+	// Name of pet.
+	Name  string            `json:"name"`
+	Owner *CodegenTestOwner `json:"owner,omitempty"`
+}
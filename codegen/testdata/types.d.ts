@@ -0,0 +1,27 @@
+// Code generated by openapi-go codegen. DO NOT EDIT.
+
+export type CodegenTestAnimal = CodegenTestCat | CodegenTestDog;
+
+export interface CodegenTestCat {
+  lives: number;
+}
+
+export interface CodegenTestDog {
+  breed?: "husky" | "corgi";
+}
+
+export interface CodegenTestOwner {
+  email: string;
+}
+
+export interface CodegenTestZoo {
+  animals?: CodegenTestAnimal[] | null;
+  readonly id?: number;
+  keeper?: CodegenTestOwner;
+  labels?: Record<string, string> | null;
+  /** @deprecated */
+  "legacy-code"?: string;
+  /** Name of zoo. */
+  name: string;
+  visitors?: number | null;
+}
@@ -0,0 +1,206 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+const componentsSchemas = "#/components/schemas/"
+
+// typeOf returns Go type of schema, referenced components are scheduled for declaration.
+func (g *generator) typeOf(v interface{}) string {
+	s := object(v)
+
+	if goType := str(s[openapi.GoTypeExtension]); goType != "" {
+		return g.qualify(goType)
+	}
+
+	if ref := str(s["$ref"]); ref != "" {
+		if !strings.HasPrefix(ref, componentsSchemas) {
+			return "interface{}"
+		}
+
+		return g.component(openapi.UnescapePointerToken(ref[len(componentsSchemas):]))
+	}
+
+	return g.schemaType(s)
+}
+
+// schemaType returns Go type of schema without reusing its Go type.
+func (g *generator) schemaType(s map[string]interface{}) string {
+	if allOf, ok := s["allOf"].([]interface{}); ok && len(allOf) == 1 {
+		return g.typeOf(allOf[0])
+	}
+
+	var (
+		types    []string
+		nullable = s["nullable"] == true
+	)
+
+	switch t := s["type"].(type) {
+	case string:
+		types = append(types, t)
+	case []interface{}:
+		for _, item := range t {
+			if item == "null" {
+				nullable = true
+			} else {
+				types = append(types, str(item))
+			}
+		}
+	}
+
+	if len(types) == 0 && s["properties"] != nil {
+		types = append(types, "object")
+	}
+
+	if len(types) != 1 {
+		return "interface{}"
+	}
+
+	var res string
+
+	switch types[0] {
+	case "string":
+		res = "string"
+
+		if s["format"] == "date-time" {
+			res = g.qualify("time.Time")
+		}
+	case "integer":
+		res = "int"
+
+		if f := str(s["format"]); f == "int32" || f == "int64" {
+			res = f
+		}
+	case "number":
+		res = "float64"
+
+		if s["format"] == "float" {
+			res = "float32"
+		}
+	case "boolean":
+		res = "bool"
+	case "array":
+		res = "[]" + g.typeOf(s["items"])
+	case "object":
+		res = g.objectType(s)
+	default:
+		res = "interface{}"
+	}
+
+	if nullable {
+		res = optional(res)
+	}
+
+	return res
+}
+
+func (g *generator) objectType(s map[string]interface{}) string {
+	props := object(s["properties"])
+
+	if len(props) == 0 {
+		if ap, ok := s["additionalProperties"].(map[string]interface{}); ok && len(ap) > 0 {
+			return "map[string]" + g.typeOf(ap)
+		}
+
+		return "map[string]interface{}"
+	}
+
+	required := map[string]bool{}
+
+	if req, ok := s["required"].([]interface{}); ok {
+		for _, name := range req {
+			required[str(name)] = true
+		}
+	}
+
+	var b bytes.Buffer
+
+	b.WriteString("struct {\n")
+
+	for _, name := range internal.SortedKeys(props) {
+		p := object(props[name])
+		typ := g.typeOf(p)
+		tag := name
+
+		if !required[name] {
+			typ = optional(typ)
+			tag += ",omitempty"
+		}
+
+		if d := str(p["description"]); d != "" {
+			comment(&b, d)
+		}
+
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", identifier(name), typ, tag)
+	}
+
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// component returns Go type of schema component.
+func (g *generator) component(name string) string {
+	if goName, ok := g.types[name]; ok {
+		return goName
+	}
+
+	s, ok := g.schemas[name].(map[string]interface{})
+	if !ok {
+		return "interface{}"
+	}
+
+	if goType := str(s[openapi.GoTypeExtension]); goType != "" {
+		return g.qualify(goType)
+	}
+
+	goName := identifier(name)
+	g.types[name] = goName
+	g.pending = append(g.pending, name)
+
+	return goName
+}
+
+// declaration returns source of type declaration of schema component.
+func (g *generator) declaration(name string) string {
+	s := object(g.schemas[name])
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// %s is generated from %s%s schema.\n",
+		g.types[name], componentsSchemas, openapi.PointerToken(name))
+
+	if d := str(s["description"]); d != "" {
+		b.WriteString("//\n")
+		comment(&b, d)
+	}
+
+	fmt.Fprintf(&b, "type %s %s\n", g.types[name], g.schemaType(s))
+
+	return b.String()
+}
+
+// optional returns type that can hold missing value.
+func optional(t string) string {
+	if nilable(t) {
+		return t
+	}
+
+	return "*" + t
+}
+
+func nilable(t string) bool {
+	for _, prefix := range []string{"*", "[]", "map[", "interface{}", "io.Reader"} {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,361 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// TypeScript generates TypeScript definitions (.d.ts) of schema components of reflector.
+//
+// Object schemas become interfaces, other schemas become type aliases, oneOf and anyOf are
+// rendered as unions, allOf as intersections, enums as unions of literals and nullable types as unions with null.
+func TypeScript(r openapi.Reflector) ([]byte, error) {
+	return GenerateTypeScript(r.SpecSchema())
+}
+
+// GenerateTypeScript creates TypeScript definitions of schema components of a reflected or loaded spec,
+// see TypeScript.
+func GenerateTypeScript(spec interface{}) ([]byte, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	schemas := object(object(doc["components"])["schemas"])
+
+	var b bytes.Buffer
+
+	b.WriteString("// Code generated by openapi-go codegen. DO NOT EDIT.\n")
+
+	for _, name := range internal.SortedKeys(schemas) {
+		s := object(schemas[name])
+
+		b.WriteString("\n")
+		jsDoc(&b, "", s)
+
+		if isObjectSchema(s) && len(object(s["properties"])) > 0 {
+			fmt.Fprintf(&b, "export interface %s %s\n", identifier(name), tsObject(s, ""))
+
+			continue
+		}
+
+		fmt.Fprintf(&b, "export type %s = %s;\n", identifier(name), tsType(s, ""))
+	}
+
+	return b.Bytes(), nil
+}
+
+// tsType returns TypeScript type of schema, indent is used for nested object literals.
+func tsType(v interface{}, indent string) string {
+	if v == true {
+		return "unknown"
+	}
+
+	if v == false {
+		return "never"
+	}
+
+	s := object(v)
+
+	t := tsBaseType(s, indent)
+
+	if isNullable(s) && t != "null" && t != "unknown" {
+		t = tsUnion([]string{t, "null"})
+	}
+
+	return t
+}
+
+func tsBaseType(s map[string]interface{}, indent string) string {
+	if ref := str(s["$ref"]); ref != "" {
+		if !strings.HasPrefix(ref, componentsSchemas) {
+			return "unknown"
+		}
+
+		return identifier(openapi.UnescapePointerToken(ref[len(componentsSchemas):]))
+	}
+
+	if c, ok := s["const"]; ok {
+		return tsLiteral(c)
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		literals := make([]string, 0, len(enum))
+		for _, e := range enum {
+			literals = append(literals, tsLiteral(e))
+		}
+
+		return tsUnion(literals)
+	}
+
+	for _, kw := range []string{"oneOf", "anyOf"} {
+		if variants, ok := s[kw].([]interface{}); ok && len(variants) > 0 {
+			types := make([]string, 0, len(variants))
+			for _, variant := range variants {
+				types = append(types, tsType(variant, indent))
+			}
+
+			return tsUnion(types)
+		}
+	}
+
+	if allOf, ok := s["allOf"].([]interface{}); ok && len(allOf) > 0 {
+		types := make([]string, 0, len(allOf)+1)
+		for _, item := range allOf {
+			types = append(types, parenthesize(tsType(item, indent)))
+		}
+
+		if len(object(s["properties"])) > 0 {
+			types = append(types, tsObject(s, indent))
+		}
+
+		return strings.Join(types, " & ")
+	}
+
+	var types []string
+
+	switch t := s["type"].(type) {
+	case string:
+		types = append(types, t)
+	case []interface{}:
+		for _, item := range t {
+			if item != "null" {
+				types = append(types, str(item))
+			}
+		}
+	}
+
+	if len(types) == 0 && s["properties"] != nil {
+		types = append(types, "object")
+	}
+
+	if len(types) == 0 {
+		if s["type"] != nil {
+			return "null"
+		}
+
+		return "unknown"
+	}
+
+	res := make([]string, 0, len(types))
+
+	for _, t := range types {
+		switch t {
+		case "string":
+			res = append(res, "string")
+		case "integer", "number":
+			res = append(res, "number")
+		case "boolean":
+			res = append(res, "boolean")
+		case "null":
+			res = append(res, "null")
+		case "array":
+			res = append(res, tsArray(s, indent))
+		case "object":
+			res = append(res, tsObject(s, indent))
+		default:
+			res = append(res, "unknown")
+		}
+	}
+
+	return tsUnion(res)
+}
+
+func tsArray(s map[string]interface{}, indent string) string {
+	if prefixItems, ok := s["prefixItems"].([]interface{}); ok {
+		items := make([]string, 0, len(prefixItems))
+		for _, item := range prefixItems {
+			items = append(items, tsType(item, indent))
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	if _, ok := s["items"]; !ok {
+		return "unknown[]"
+	}
+
+	return parenthesize(tsType(s["items"], indent)) + "[]"
+}
+
+// tsObject returns object literal type with properties and index signature of additional properties.
+func tsObject(s map[string]interface{}, indent string) string {
+	props := object(s["properties"])
+	ap, hasAP := s["additionalProperties"]
+
+	if len(props) == 0 {
+		if hasAP && ap != false {
+			return "Record<string, " + tsType(ap, indent) + ">"
+		}
+
+		return "Record<string, unknown>"
+	}
+
+	required := map[string]bool{}
+
+	if req, ok := s["required"].([]interface{}); ok {
+		for _, name := range req {
+			required[str(name)] = true
+		}
+	}
+
+	var b bytes.Buffer
+
+	inner := indent + "  "
+
+	b.WriteString("{\n")
+
+	for _, name := range internal.SortedKeys(props) {
+		p := object(props[name])
+
+		jsDoc(&b, inner, p)
+		b.WriteString(inner)
+
+		if p["readOnly"] == true {
+			b.WriteString("readonly ")
+		}
+
+		b.WriteString(tsPropertyName(name))
+
+		if !required[name] {
+			b.WriteString("?")
+		}
+
+		b.WriteString(": " + tsType(props[name], inner) + ";\n")
+	}
+
+	if hasAP && ap != false {
+		b.WriteString(inner + "[key: string]: unknown;\n")
+	}
+
+	b.WriteString(indent + "}")
+
+	return b.String()
+}
+
+// jsDoc writes documentation comment of schema with description and deprecation.
+func jsDoc(b *bytes.Buffer, indent string, s map[string]interface{}) {
+	var lines []string
+
+	if d := strings.TrimSpace(str(s["description"])); d != "" {
+		lines = append(lines, strings.Split(d, "\n")...)
+	}
+
+	if s["deprecated"] == true {
+		lines = append(lines, "@deprecated")
+	}
+
+	if len(lines) == 0 {
+		return
+	}
+
+	if len(lines) == 1 {
+		b.WriteString(indent + "/** " + strings.ReplaceAll(lines[0], "*/", "*\\/") + " */\n")
+
+		return
+	}
+
+	b.WriteString(indent + "/**\n")
+
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(indent+" * "+strings.ReplaceAll(line, "*/", "*\\/"), " ") + "\n")
+	}
+
+	b.WriteString(indent + " */\n")
+}
+
+func isNullable(s map[string]interface{}) bool {
+	if s["nullable"] == true {
+		return true
+	}
+
+	if types, ok := s["type"].([]interface{}); ok {
+		for _, t := range types {
+			if t == "null" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isObjectSchema(s map[string]interface{}) bool {
+	if s["$ref"] != nil || s["allOf"] != nil || s["oneOf"] != nil || s["anyOf"] != nil || isNullable(s) {
+		return false
+	}
+
+	return s["type"] == "object" || (s["type"] == nil && s["properties"] != nil)
+}
+
+func tsLiteral(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "unknown"
+	}
+
+	return string(b)
+}
+
+// tsUnion joins distinct types with "|".
+func tsUnion(types []string) string {
+	seen := map[string]bool{}
+	res := make([]string, 0, len(types))
+
+	for _, t := range types {
+		if !seen[t] {
+			seen[t] = true
+
+			res = append(res, t)
+		}
+	}
+
+	return strings.Join(res, " | ")
+}
+
+// parenthesize wraps union and intersection types to use them as operands.
+func parenthesize(t string) string {
+	depth, quoted := 0, false
+
+	for i := 0; i < len(t); i++ {
+		switch c := t[i]; {
+		case quoted:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+		case c == '{' || c == '[' || c == '(' || c == '<':
+			depth++
+		case c == '}' || c == ']' || c == ')' || c == '>':
+			depth--
+		case depth == 0 && (c == '|' || c == '&'):
+			return "(" + t + ")"
+		}
+	}
+
+	return t
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+
+	return tsLiteral(name)
+}
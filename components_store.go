@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// ErrComponentConflict is returned when a component is added to ComponentsStore with a different schema.
+var ErrComponentConflict = errors.New("component conflict")
+
+// ComponentsStore is a pool of schema components shared by multiple reflectors, e.g. one per module.
+//
+// Schemas are deduplicated by name, so that documents of all reflectors refer to the same components.
+// It is safe for concurrent use.
+type ComponentsStore struct {
+	mu        sync.Mutex
+	schemas   map[string]jsonschema.SchemaOrBool
+	conflicts map[string]bool
+}
+
+// Add stores schema by name and returns stored schema.
+//
+// If a schema with the same name was added before, it is returned instead.
+// When schemas are different, ErrComponentConflict is returned and the name is reported by Conflicts.
+func (cs *ComponentsStore) Add(name string, schema jsonschema.SchemaOrBool) (jsonschema.SchemaOrBool, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if stored, ok := cs.schemas[name]; ok {
+		if !sameSchema(stored, schema) {
+			if cs.conflicts == nil {
+				cs.conflicts = make(map[string]bool)
+			}
+
+			cs.conflicts[name] = true
+
+			return stored, fmt.Errorf("%w: %s has a different schema", ErrComponentConflict, name)
+		}
+
+		return stored, nil
+	}
+
+	if cs.schemas == nil {
+		cs.schemas = make(map[string]jsonschema.SchemaOrBool)
+	}
+
+	cs.schemas[name] = schema
+
+	return schema, nil
+}
+
+// Schemas returns stored schemas by names.
+func (cs *ComponentsStore) Schemas() map[string]jsonschema.SchemaOrBool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	res := make(map[string]jsonschema.SchemaOrBool, len(cs.schemas))
+
+	for name, s := range cs.schemas {
+		res[name] = s
+	}
+
+	return res
+}
+
+// Conflicts returns sorted names of components that were added with different schemas.
+func (cs *ComponentsStore) Conflicts() []string {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	res := make([]string, 0, len(cs.conflicts))
+
+	for name := range cs.conflicts {
+		res = append(res, name)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func sameSchema(a, b jsonschema.SchemaOrBool) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return string(ja) == string(jb)
+}
@@ -0,0 +1,21 @@
+package openapi
+
+// RequiredIf declares properties that are required depending on values of other properties.
+type RequiredIf struct {
+	// If has values of properties by their names, condition matches when all values are equal.
+	If map[string]interface{}
+
+	// Then lists properties required when condition matches.
+	Then []string
+
+	// Else lists properties required when condition does not match.
+	Else []string
+}
+
+// ConditionalRequirer is implemented by structures with conditionally required properties.
+//
+// Conditions are reflected as if/then/else in OpenAPI 3.1. Properties that require other properties
+// to be present can also be tagged with `dependentRequired:"foo,bar"`.
+type ConditionalRequirer interface {
+	ConditionalRequired() []RequiredIf
+}
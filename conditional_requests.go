@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+type entityTagHeader struct {
+	ETag string `header:"ETag" description:"Entity tag of current representation of resource."`
+}
+
+type ifNoneMatchHeader struct {
+	IfNoneMatch string `header:"If-None-Match" description:"Entity tags of representations that client has, or * to match any."`
+}
+
+type ifMatchHeader struct {
+	IfMatch string `header:"If-Match" description:"Entity tags that current representation must match, or * to match any."`
+}
+
+// ConditionalRequests returns a Trait that documents conditional requests with entity tags.
+//
+// "ETag" header is added to successful responses added before, "If-None-Match" request header and
+// "304 Not Modified" response are added to GET and HEAD operations, "If-Match" and "If-None-Match"
+// request headers and "412 Precondition Failed" response are added to other operations.
+//
+//	oc.AddRespStructure(doc{})
+//	openapi.ConditionalRequests().Apply(oc)
+func ConditionalRequests() Trait {
+	return Trait{Setup: func(oc OperationContext) {
+		var statuses []int
+
+		seen := map[int]bool{}
+
+		for _, cu := range oc.Response() {
+			status := cu.HTTPStatus
+			if status == 0 && !cu.IsDefault {
+				status = http.StatusOK
+			}
+
+			if status >= 200 && status < 300 && !seen[status] {
+				seen[status] = true
+
+				statuses = append(statuses, status)
+			}
+		}
+
+		if len(statuses) == 0 {
+			statuses = append(statuses, http.StatusOK)
+		}
+
+		responseHeaders(entityTagHeader{}, statuses...).Apply(oc)
+
+		switch strings.ToUpper(oc.Method()) {
+		case http.MethodGet, http.MethodHead:
+			oc.AddReqStructure(ifNoneMatchHeader{})
+			oc.AddRespStructure(entityTagHeader{}, WithHTTPStatus(http.StatusNotModified))
+		default:
+			oc.AddReqStructure(struct {
+				ifMatchHeader
+				ifNoneMatchHeader
+			}{})
+			oc.AddRespStructure(nil, WithHTTPStatus(http.StatusPreconditionFailed))
+		}
+	}}
+}
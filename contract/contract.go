@@ -0,0 +1,243 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// TestingT is a subset of testing.TB.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Checker validates responses against operations of spec.
+//
+//	c, err := contract.NewChecker(r.Spec)
+//	require.NoError(t, err)
+//
+//	rec := httptest.NewRecorder()
+//	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+//
+//	c.AssertRecorder(t, http.MethodGet, "/users/{id}", rec)
+type Checker struct {
+	doc map[string]interface{}
+}
+
+// NewChecker creates checker of spec, e.g. *openapi31.Spec of a reflector.
+//
+// Spec is captured at creation, operations added later are not checked.
+func NewChecker(spec json.Marshaler) (*Checker, error) {
+	data, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Checker{}
+	if err := json.Unmarshal(data, &c.doc); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// AssertRecorder checks recorded response, see AssertResponse.
+func (c *Checker) AssertRecorder(t TestingT, method, pathPattern string, rec *httptest.ResponseRecorder) bool {
+	t.Helper()
+
+	return c.AssertResponse(t, method, pathPattern, rec.Result())
+}
+
+// AssertResponse checks that status, content type, headers and body of response are declared
+// by operation of spec with path pattern (e.g. "/users/{id}"), mismatches are reported with JSON pointers
+// of response body and body is closed.
+//
+// Mismatches of values are followed by a diff of expected schema ("-") and actual value ("+").
+func (c *Checker) AssertResponse(t TestingT, method, pathPattern string, resp *http.Response) bool {
+	t.Helper()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Errorf("read response body: %s", err)
+
+		return false
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		t.Errorf("close response body: %s", err)
+
+		return false
+	}
+
+	mismatches := c.check(strings.ToLower(method), pathPattern, resp, body)
+	if len(mismatches) == 0 {
+		return true
+	}
+
+	msg := fmt.Sprintf("response of %s %s does not match spec:\n  %s",
+		strings.ToUpper(method), pathPattern, strings.Join(mismatches, "\n  "))
+
+	if len(body) > 0 {
+		msg += "\nbody:\n" + indentJSON(body)
+	}
+
+	t.Errorf("%s", msg)
+
+	return false
+}
+
+func (c *Checker) check(method, pathPattern string, resp *http.Response, body []byte) []string {
+	paths, _ := c.doc["paths"].(map[string]interface{})        //nolint:errcheck // Missing paths are empty.
+	pathItem, _ := paths[pathPattern].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+	op, ok := pathItem[method].(map[string]interface{})
+	if !ok {
+		return []string{"operation is not declared"}
+	}
+
+	responses, _ := op["responses"].(map[string]interface{}) //nolint:errcheck // Missing responses are empty.
+
+	var declared map[string]interface{}
+
+	status := strconv.Itoa(resp.StatusCode)
+	for _, key := range []string{status, status[:1] + "XX", "default"} {
+		if r, ok := responses[key]; ok {
+			declared = c.resolve(r)
+
+			break
+		}
+	}
+
+	if declared == nil {
+		return []string{fmt.Sprintf("status %s is not declared, expected one of %s",
+			status, strings.Join(internal.SortedKeys(responses), ", "))}
+	}
+
+	mismatches := c.checkHeaders(declared, resp.Header)
+
+	content, _ := declared["content"].(map[string]interface{}) //nolint:errcheck // Missing content is empty.
+	if len(content) == 0 {
+		if len(body) > 0 {
+			mismatches = append(mismatches, "body is not declared")
+		}
+
+		return mismatches
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+
+	media, ok := internal.MatchMediaType(content, contentType)
+	if !ok {
+		return append(mismatches, fmt.Sprintf("content type %q is not declared, expected one of %s",
+			contentType, strings.Join(internal.SortedKeys(content), ", ")))
+	}
+
+	schema, _ := media["schema"].(map[string]interface{}) //nolint:errcheck // Missing schema accepts any body.
+	if schema == nil || !internal.IsJSONMediaType(contentType) {
+		return mismatches
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return append(mismatches, "invalid JSON body: "+err.Error())
+	}
+
+	for _, e := range internal.ValidateValue(schema, value, c.resolveRef) {
+		mismatches = append(mismatches, "body"+e.Pointer+": "+e.Message+valueDiff(e))
+	}
+
+	return mismatches
+}
+
+func (c *Checker) checkHeaders(declared map[string]interface{}, header http.Header) []string {
+	headers, _ := declared["headers"].(map[string]interface{}) //nolint:errcheck // Missing headers are empty.
+
+	var mismatches []string
+
+	for _, name := range internal.SortedKeys(headers) {
+		h := c.resolve(headers[name])
+		values, found := header[http.CanonicalHeaderKey(name)]
+
+		if !found {
+			if h["required"] == true {
+				mismatches = append(mismatches, "header "+name+": required header is missing")
+			}
+
+			continue
+		}
+
+		schema, _ := h["schema"].(map[string]interface{}) //nolint:errcheck // Missing schema accepts any value.
+		if schema == nil {
+			continue
+		}
+
+		for _, e := range internal.ValidateValue(schema, internal.ParameterValue(schema, values, c.resolve), c.resolveRef) {
+			mismatches = append(mismatches, "header "+name+e.Pointer+": "+e.Message+valueDiff(e))
+		}
+	}
+
+	return mismatches
+}
+
+// resolve follows local references of value.
+func (c *Checker) resolve(v interface{}) map[string]interface{} {
+	return internal.ResolveLocalRef(c.doc, v)
+}
+
+func (c *Checker) resolveRef(ref string) (map[string]interface{}, bool) {
+	schema := c.resolve(map[string]interface{}{"$ref": ref})
+
+	return schema, schema != nil
+}
+
+// maxDiffSchemaLength limits length of schema in diff of mismatch.
+const maxDiffSchemaLength = 200
+
+// valueDiff renders expected schema and actual value of mismatch.
+func valueDiff(e internal.ValueError) string {
+	if e.Schema == nil {
+		return ""
+	}
+
+	expected := compactJSON(e.Schema)
+	if r := []rune(expected); len(r) > maxDiffSchemaLength {
+		expected = string(r[:maxDiffSchemaLength]) + "..."
+	}
+
+	actual := "<missing>"
+	if !e.Missing {
+		actual = compactJSON(e.Value)
+	}
+
+	return "\n    - " + expected + "\n    + " + actual
+}
+
+func compactJSON(v interface{}) string {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(j)
+}
+
+func indentJSON(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	j, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return string(body)
+	}
+
+	return string(j)
+}
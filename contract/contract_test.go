@@ -0,0 +1,203 @@
+package contract_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/contract"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type recorder struct {
+	errors []string
+}
+
+func (*recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type userReq struct {
+	ID int `path:"id"`
+}
+
+type user struct {
+	RateLimit int    `header:"X-Rate-Limit" json:"-" required:"true"`
+	ID        int    `json:"id" required:"true"`
+	Name      string `json:"name" minLength:"2"`
+	Role      string `json:"role" enum:"admin,guest"`
+}
+
+func newChecker(t *testing.T) *contract.Checker {
+	t.Helper()
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(userReq{})
+	oc.AddRespStructure(user{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNotFound))
+	require.NoError(t, r.AddOperation(oc))
+
+	c, err := contract.NewChecker(r.Spec)
+	require.NoError(t, err)
+
+	return c
+}
+
+func respond(status int, contentType, body string, header map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+
+	for k, v := range header {
+		rec.Header().Set(k, v)
+	}
+
+	if contentType != "" {
+		rec.Header().Set("Content-Type", contentType)
+	}
+
+	rec.WriteHeader(status)
+	_, _ = rec.WriteString(body) //nolint:errcheck // Recorder does not fail.
+
+	return rec
+}
+
+func TestChecker_AssertRecorder(t *testing.T) {
+	c := newChecker(t)
+
+	assert.True(t, c.AssertRecorder(t, http.MethodGet, "/users/{id}", respond(http.StatusOK,
+		"application/json; charset=utf-8", `{"id":1,"name":"Jane","role":"admin"}`,
+		map[string]string{"X-Rate-Limit": "100"})))
+	assert.True(t, c.AssertRecorder(t, http.MethodGet, "/users/{id}", respond(http.StatusNotFound, "", "", nil)))
+
+	rec := &recorder{}
+
+	assert.False(t, c.AssertRecorder(rec, http.MethodGet, "/users/{id}", respond(http.StatusOK,
+		"application/json", `{"name":"J","role":"root","extra":true}`,
+		map[string]string{"X-Rate-Limit": "many"})))
+	assert.False(t, c.AssertRecorder(rec, http.MethodGet, "/users/{id}", respond(http.StatusOK,
+		"text/plain", `Jane`, map[string]string{"X-Rate-Limit": "100"})))
+	assert.False(t, c.AssertRecorder(rec, http.MethodGet, "/users/{id}", respond(http.StatusTeapot, "", "", nil)))
+	assert.False(t, c.AssertRecorder(rec, http.MethodPost, "/users/{id}", respond(http.StatusOK, "", "", nil)))
+
+	assert.Equal(t, []string{
+		`response of GET /users/{id} does not match spec:
+  header X-Rate-Limit: expected type integer, got string "many"
+    - {"type":"integer"}
+    + "many"
+  body/id: required property is missing
+    - {"type":"integer"}
+    + <missing>
+  body/name: expected at least 2 characters, got 1
+    - {"minLength":2,"type":"string"}
+    + "J"
+  body/role: value "root" is not one of enum ["admin","guest"]
+    - {"enum":["admin","guest"],"type":"string"}
+    + "root"
+body:
+{
+  "extra": true,
+  "name": "J",
+  "role": "root"
+}`,
+		`response of GET /users/{id} does not match spec:
+  content type "text/plain" is not declared, expected one of application/json
+body:
+Jane`,
+		`response of GET /users/{id} does not match spec:
+  status 418 is not declared, expected one of 200, 404`,
+		`response of POST /users/{id} does not match spec:
+  operation is not declared`,
+	}, rec.errors)
+}
+
+func TestChecker_AssertResponse_keywords(t *testing.T) {
+	c, err := contract.NewChecker(json.RawMessage(`{
+  "openapi":"3.1.0","info":{"title":"","version":""},
+  "paths":{"/things":{"get":{"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{
+    "$ref":"#/components/schemas/Thing","required":["kind"]
+  }}}}}}}},
+  "components":{"schemas":{
+    "Thing":{
+      "type":"object","minProperties":2,"maxProperties":6,
+      "properties":{"kind":{"type":"string"},"size":{"type":"integer"},"tuple":{"type":"array","prefixItems":[{"type":"string"},{"type":"integer"}],"items":false},
+        "tags":{"type":"array","uniqueItems":true,"contains":{"const":"main"}}},
+      "patternProperties":{"^x-":{"type":"string"}},
+      "propertyNames":{"maxLength":5},
+      "additionalProperties":false,
+      "dependentRequired":{"card":["billing"]},
+      "if":{"required":["kind"],"properties":{"kind":{"const":"box"}}},"then":{"required":["size"]},"else":{"not":{"required":["size"]}}
+    }
+  }}
+}`))
+	require.NoError(t, err)
+
+	check := func(body string) []string {
+		rec := &recorder{}
+		c.AssertRecorder(rec, http.MethodGet, "/things", respond(http.StatusOK, "application/json", body, nil))
+
+		if len(rec.errors) == 0 {
+			return nil
+		}
+
+		var mismatches []string
+
+		for _, line := range strings.Split(rec.errors[0], "\n")[1:] {
+			if strings.HasPrefix(line, "  body") {
+				mismatches = append(mismatches, strings.TrimSpace(line))
+			}
+		}
+
+		return mismatches
+	}
+
+	assert.Empty(t, check(`{"kind":"box","size":1,"tuple":["a",1],"tags":["main","x"],"x-id":"1"}`))
+	assert.Equal(t, []string{
+		"body: expected at least 2 properties, got 1",
+		"body/kind: required property is missing",
+	}, check(`{"x-a":"1"}`))
+	assert.Equal(t, []string{
+		"body/size: required property is missing",
+		"body/billing: property is required by card",
+		"body/card: additional property is not allowed",
+		"body/tags/1: item duplicates item 0",
+		"body/tags: expected at least 1 items matching contains schema, got 0",
+		"body/tuple/1: expected type integer, got string \"b\"",
+		"body/tuple/2: additional item is not allowed",
+		"body/x-long: property name \"x-long\" does not match propertyNames schema",
+		"body/x-long: expected type string, got integer 1",
+	}, check(`{"kind":"box","card":"1","tuple":["a","b","c"],"tags":["a","a"],"x-long":1}`))
+	assert.Equal(t, []string{
+		"body: value matches schema of not",
+	}, check(`{"kind":"bag","size":1}`))
+
+	c, err = contract.NewChecker(json.RawMessage(`{
+  "openapi":"3.1.0","info":{"title":"","version":""},
+  "paths":{
+    "/loop":{"get":{"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Loop"}}}}}}},
+    "/dynamic":{"get":{"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Dynamic"}}}}}}}
+  },
+  "components":{"schemas":{
+    "Loop":{"allOf":[{"$ref":"#/components/schemas/Loop"}]},
+    "Dynamic":{"unevaluatedProperties":false}
+  }}
+}`))
+	require.NoError(t, err)
+
+	rec := &recorder{}
+	assert.False(t, c.AssertRecorder(rec, http.MethodGet, "/loop", respond(http.StatusOK, "application/json", `{}`, nil)))
+	assert.False(t, c.AssertRecorder(rec, http.MethodGet, "/dynamic", respond(http.StatusOK, "application/json", `{}`, nil)))
+	require.Len(t, rec.errors, 2)
+	assert.Contains(t, rec.errors[0], "body: schema nesting exceeds 256 levels, possibly a cyclic reference")
+	assert.Contains(t, rec.errors[1], "body: unsupported schema keyword unevaluatedProperties")
+}
@@ -0,0 +1,2 @@
+// Package contract checks live HTTP responses against spec operations in tests.
+package contract
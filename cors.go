@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CORSPreflight returns a Trait of OPTIONS operation that documents "Allow" header and
+// CORS preflight headers for methods of a path.
+//
+//	oc, err := r.NewOperationContext(http.MethodOptions, "/users")
+//	openapi.CORSPreflight(http.MethodGet, http.MethodPost).Apply(oc)
+func CORSPreflight(methods ...string) Trait {
+	allowed := make([]string, 0, len(methods)+1)
+	hasOptions := false
+
+	for _, m := range methods {
+		m = strings.ToUpper(m)
+		hasOptions = hasOptions || m == http.MethodOptions
+
+		allowed = append(allowed, m)
+	}
+
+	if !hasOptions {
+		allowed = append(allowed, http.MethodOptions)
+	}
+
+	allow := strconv.Quote(strings.Join(allowed, ", "))
+
+	return Trait{
+		Request: []interface{}{
+			headersStructure(
+				headerField{Tag: `header:"Origin" description:"Origin of cross-origin request."`},
+				headerField{Tag: `header:"Access-Control-Request-Method" enum:"` + strings.Join(allowed, ",") +
+					`" description:"Method of cross-origin request."`},
+				headerField{Tag: `header:"Access-Control-Request-Headers" description:"Comma-separated headers of cross-origin request."`},
+			),
+		},
+		Responses: []TraitResponse{
+			{
+				Structure: headersStructure(
+					headerField{Tag: `header:"Allow" required:"true" example:` + allow + ` description:"Methods supported by resource."`},
+					headerField{Tag: `header:"Access-Control-Allow-Origin" description:"Origin that is allowed to access resource."`},
+					headerField{Tag: `header:"Access-Control-Allow-Methods" example:` + allow +
+						` description:"Methods allowed for cross-origin requests."`},
+					headerField{Tag: `header:"Access-Control-Allow-Headers" description:"Headers allowed for cross-origin requests."`},
+					headerField{
+						Tag:    `header:"Access-Control-Max-Age" description:"Seconds for which preflight response can be cached."`,
+						Sample: 0,
+					},
+				),
+				Options: []ContentOption{WithHTTPStatus(http.StatusNoContent)},
+			},
+		},
+	}
+}
+
+// headerField is a field of structure created by headersStructure, Sample defines type, default is string.
+type headerField struct {
+	Tag    string
+	Sample interface{}
+}
+
+// headersStructure creates a structure with tagged fields, so that headers can have dynamic values.
+func headersStructure(headers ...headerField) interface{} {
+	fields := make([]reflect.StructField, 0, len(headers))
+
+	for i, h := range headers {
+		t := reflect.TypeOf("")
+		if h.Sample != nil {
+			t = reflect.TypeOf(h.Sample)
+		}
+
+		fields = append(fields, reflect.StructField{
+			Name: "F" + strconv.Itoa(i),
+			Type: t,
+			Tag:  reflect.StructTag(h.Tag),
+		})
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Elem().Interface()
+}
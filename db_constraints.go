@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// DatabaseConstraints is a jsonschema.ReflectContext option to document persistence constraints of model fields.
+//
+// It reads `gorm` tags (e.g. "size:255;not null;uniqueIndex") and `bun` or `pg` tags (e.g. ",notnull,unique").
+// Size and "varchar(N)" column types set maxLength of strings, "not null" makes property required,
+// unique constraints and indexes add "x-unique": true.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.DatabaseConstraints())
+func DatabaseConstraints() func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if !params.Processed || params.Field.Tag == "" {
+			return nil
+		}
+
+		var c dbConstraints
+
+		if tag, ok := params.Field.Tag.Lookup("gorm"); ok {
+			c.parseGorm(tag)
+		}
+
+		for _, name := range []string{"bun", "pg"} {
+			if tag, ok := params.Field.Tag.Lookup(name); ok {
+				c.parseBun(tag)
+			}
+		}
+
+		c.apply(params)
+
+		return nil
+	})
+}
+
+var columnSize = regexp.MustCompile(`(?i)^\s*(?:var)?char(?:acter)?(?:\s+varying)?\s*\(\s*([0-9]+)\s*\)`)
+
+type dbConstraints struct {
+	maxLength *int64
+	notNull   bool
+	unique    bool
+}
+
+// parseGorm reads tag like "column:name;size:255;not null;uniqueIndex:idx_name".
+func (c *dbConstraints) parseGorm(tag string) {
+	for _, part := range strings.Split(tag, ";") {
+		key, value := part, ""
+		if i := strings.Index(part, ":"); i >= 0 {
+			key, value = part[:i], part[i+1:]
+		}
+
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "SIZE":
+			c.setSize(value)
+		case "TYPE":
+			c.setColumnType(value)
+		case "NOT NULL":
+			c.notNull = true
+		case "UNIQUE", "UNIQUEINDEX":
+			c.unique = true
+		}
+	}
+}
+
+// parseBun reads tag like "name,notnull,unique,type:varchar(255)", first element is a column name.
+func (c *dbConstraints) parseBun(tag string) {
+	parts := strings.Split(tag, ",")
+
+	for _, part := range parts[1:] {
+		key, value := part, ""
+		if i := strings.Index(part, ":"); i >= 0 {
+			key, value = part[:i], part[i+1:]
+		}
+
+		switch strings.TrimSpace(key) {
+		case "type":
+			c.setColumnType(value)
+		case "notnull":
+			c.notNull = true
+		case "unique":
+			c.unique = true
+		}
+	}
+}
+
+func (c *dbConstraints) setSize(value string) {
+	if n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+		c.maxLength = &n
+	}
+}
+
+func (c *dbConstraints) setColumnType(value string) {
+	if m := columnSize.FindStringSubmatch(value); m != nil {
+		c.setSize(m[1])
+	}
+}
+
+func (c dbConstraints) apply(params jsonschema.InterceptPropParams) {
+	s := params.PropertySchema
+
+	// Schemas of named types are shared definitions and can not be changed per field.
+	if s.Ref == nil {
+		if c.maxLength != nil && s.HasType(jsonschema.String) && s.MaxLength == nil {
+			s.WithMaxLength(*c.maxLength)
+		}
+
+		if c.unique {
+			s.WithExtraPropertiesItem("x-unique", true)
+		}
+	}
+
+	if !c.notNull {
+		return
+	}
+
+	for _, r := range params.ParentSchema.Required {
+		if r == params.Name {
+			return
+		}
+	}
+
+	params.ParentSchema.Required = append(params.ParentSchema.Required, params.Name)
+}
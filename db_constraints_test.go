@@ -0,0 +1,38 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestDatabaseConstraints(t *testing.T) {
+	type User struct {
+		ID       int    `json:"id" gorm:"primaryKey"`
+		Email    string `json:"email" gorm:"size:255;not null;uniqueIndex"`
+		Nickname string `json:"nickname" gorm:"type:varchar(32);unique" maxLength:"16"`
+		Bio      string `json:"bio" bun:"bio,notnull,type:varchar(1024)"`
+		Company  string `json:"company" pg:",unique"`
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.DatabaseConstraints())
+
+	s, err := r.Reflect(User{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "required":["email","bio"],
+	  "properties":{
+		"id":{"type":"integer"},
+		"email":{"maxLength":255,"type":"string","x-unique":true},
+		"nickname":{"maxLength":16,"type":"string","x-unique":true},
+		"bio":{"maxLength":1024,"type":"string"},
+		"company":{"type":"string","x-unique":true}
+	  },
+	  "type":"object"
+	}`, s)
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import "net/http"
+
+// DefaultResponses are responses that are added to every operation, unless operation
+// declares a response with the same HTTP status, e.g. standard 401/403/500 error bodies.
+type DefaultResponses struct {
+	units []defaultResponse
+}
+
+type defaultResponse struct {
+	cu        ContentUnit
+	structure interface{}
+	options   []ContentOption
+}
+
+// Add registers default response structure with HTTP status, status 0 registers default response.
+func (d *DefaultResponses) Add(httpStatus int, structure interface{}, options ...ContentOption) {
+	if httpStatus == 0 {
+		options = append([]ContentOption{func(cu *ContentUnit) { cu.IsDefault = true }}, options...)
+	} else {
+		options = append([]ContentOption{WithHTTPStatus(httpStatus)}, options...)
+	}
+
+	dr := defaultResponse{structure: structure, options: options}
+
+	for _, option := range options {
+		option(&dr.cu)
+	}
+
+	d.units = append(d.units, dr)
+}
+
+// Apply adds default responses that are not overridden by operation context.
+func (d DefaultResponses) Apply(oc OperationContext) {
+	if len(d.units) == 0 {
+		return
+	}
+
+	declared := oc.Response()
+
+	for _, dr := range d.units {
+		overridden := false
+
+		for _, cu := range declared {
+			if sameResponseStatus(cu, dr.cu) {
+				overridden = true
+
+				break
+			}
+		}
+
+		if !overridden {
+			oc.AddRespStructure(dr.structure, dr.options...)
+		}
+	}
+}
+
+func sameResponseStatus(a, b ContentUnit) bool {
+	if a.IsDefault || b.IsDefault {
+		return a.IsDefault == b.IsDefault
+	}
+
+	return responseStatus(a) == responseStatus(b)
+}
+
+func responseStatus(cu ContentUnit) int {
+	if cu.HTTPStatus == 0 {
+		return http.StatusOK
+	}
+
+	return cu.HTTPStatus
+}
@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// DescriptionMerge defines how `description` field tag is combined with description of field type,
+// e.g. from jsonschema.Described or type mapping.
+type DescriptionMerge int
+
+// DescriptionMerge values enumeration.
+const (
+	// DescriptionOverride replaces type description with field tag, this is default behavior.
+	DescriptionOverride DescriptionMerge = iota
+
+	// DescriptionKeep keeps type description and ignores field tag.
+	DescriptionKeep
+
+	// DescriptionAppend appends field tag to type description with a separator.
+	DescriptionAppend
+
+	// DescriptionConflictError fails reflection if field tag and type description are different.
+	DescriptionConflictError
+)
+
+// MergeDescriptions is a jsonschema.ReflectContext option to control precedence of property descriptions.
+//
+// Separator is used with DescriptionAppend. Properties that refer to shared definitions are not affected,
+// because type description stays in the definition.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.MergeDescriptions(openapi.DescriptionAppend, " "))
+func MergeDescriptions(merge DescriptionMerge, separator string) func(rc *jsonschema.ReflectContext) {
+	return func(rc *jsonschema.ReflectContext) {
+		// Nullability of a property is checked after its type is reflected and right before field tags are applied.
+		var typeDescription string
+
+		jsonschema.InterceptNullability(func(params jsonschema.InterceptNullabilityParams) {
+			typeDescription = ""
+			if params.Schema.Description != nil {
+				typeDescription = *params.Schema.Description
+			}
+		})(rc)
+
+		jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+			if !params.Processed || typeDescription == "" {
+				return nil
+			}
+
+			td := typeDescription
+			typeDescription = ""
+
+			tagDescription := params.Field.Tag.Get("description")
+			s := params.PropertySchema
+
+			if tagDescription == "" || tagDescription == td || s.Ref != nil {
+				return nil
+			}
+
+			switch merge {
+			case DescriptionOverride:
+			case DescriptionKeep:
+				s.WithDescription(td)
+			case DescriptionAppend:
+				s.WithDescription(td + separator + tagDescription)
+			case DescriptionConflictError:
+				return fmt.Errorf("conflicting descriptions of %s: %q in type, %q in field tag",
+					strings.Join(append(rc.Path[1:], params.Name), "."), td, tagDescription)
+			}
+
+			return nil
+		})(rc)
+	}
+}
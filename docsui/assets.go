@@ -0,0 +1,46 @@
+package docsui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Pinned versions of UI assets.
+const (
+	swaggerUIVersion = "5.17.14"
+	redocVersion     = "2.1.5"
+	scalarVersion    = "1.24.0"
+)
+
+// Assets of pinned versions are downloaded into assets directory to be embedded,
+// versions have to match constants above.
+//
+//go:generate go run ./internal/fetchassets -dir assets/swagger-ui -url https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14 swagger-ui.css swagger-ui-bundle.js
+//go:generate go run ./internal/fetchassets -dir assets/redoc -url https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles redoc.standalone.js
+//go:generate go run ./internal/fetchassets -dir assets/scalar -url https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.24.0 dist/browser/standalone.js
+
+//go:embed assets
+var assets embed.FS
+
+// assetFiles are checked to find out if assets of UI are embedded.
+var assetFiles = map[UI][]string{
+	SwaggerUI: {"swagger-ui.css", "swagger-ui-bundle.js"},
+	Redoc:     {"redoc.standalone.js"},
+	Scalar:    {"dist/browser/standalone.js"},
+}
+
+// embeddedAssets returns file system of embedded assets of UI, or nil if they are missing.
+func embeddedAssets(ui UI) fs.FS {
+	sub, err := fs.Sub(assets, "assets/"+string(ui))
+	if err != nil {
+		return nil
+	}
+
+	for _, name := range assetFiles[ui] {
+		if _, err := fs.Stat(sub, name); err != nil {
+			return nil
+		}
+	}
+
+	return sub
+}
@@ -0,0 +1,25 @@
+package docsui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestNew_notEmbedded(t *testing.T) {
+	if embeddedAssets(Redoc) != nil {
+		t.Skip("assets are embedded")
+	}
+
+	h := New(openapi31.NewReflector().Spec, Config{UI: Redoc, Path: "/docs"})
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Contains(t, rw.Body.String(), "assets of redoc are not embedded")
+	assert.NotContains(t, rw.Body.String(), "cdn.jsdelivr.net")
+}
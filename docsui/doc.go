@@ -0,0 +1,2 @@
+// Package docsui serves interactive documentation UI (Swagger UI, Redoc or Scalar) of a spec.
+package docsui
@@ -0,0 +1,200 @@
+package docsui
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// UI identifies documentation UI.
+type UI string
+
+// UI values enumeration.
+const (
+	SwaggerUI = UI("swagger-ui")
+	Redoc     = UI("redoc")
+	Scalar    = UI("scalar")
+)
+
+// cdnURLs are CDN locations of pinned versions of UI assets, they are used with Config.CDN.
+var cdnURLs = map[UI]string{
+	SwaggerUI: "https://cdn.jsdelivr.net/npm/swagger-ui-dist@" + swaggerUIVersion,
+	Redoc:     "https://cdn.jsdelivr.net/npm/redoc@" + redocVersion + "/bundles",
+	Scalar:    "https://cdn.jsdelivr.net/npm/@scalar/api-reference@" + scalarVersion,
+}
+
+//go:embed templates/*.html
+var templates embed.FS
+
+// Config configures documentation handler.
+type Config struct {
+	// Path is a mount path of handler, e.g. "/docs".
+	Path string
+
+	// UI is SwaggerUI by default.
+	UI UI
+
+	// Title of the page, spec title is used by default.
+	Title string
+
+	// Theme is "light" (default) or "dark".
+	Theme string
+
+	// PersistAuthorization keeps credentials entered in UI after page reload.
+	PersistAuthorization bool
+
+	// AssetsURL is a base URL of UI scripts and styles, it overrides assets served by handler.
+	AssetsURL string
+
+	// Assets is a file system with UI scripts and styles (e.g. swagger-ui.css and swagger-ui-bundle.js),
+	// it is served at Path + "/assets/", embedded assets of pinned UI version are used by default.
+	Assets fs.FS
+
+	// CDN enables loading of pinned UI version from jsDelivr CDN instead of embedded assets,
+	// New panics if Integrity does not have hashes of all UI assets.
+	CDN bool
+
+	// Integrity has Subresource Integrity hashes (e.g. "sha384-...") of UI assets by file name
+	// (e.g. "swagger-ui-bundle.js"), browser rejects scripts and styles of AssetsURL or CDN that do not match.
+	//
+	// Hashes of pinned versions are printed by go generate ./docsui.
+	Integrity map[string]string
+
+	// SpecOptions configure spec handler.
+	SpecOptions []openapi.SpecHandlerOption
+}
+
+type page struct {
+	Title                string
+	Theme                string
+	AssetsURL            string
+	Integrity            map[string]string
+	SpecURL              string
+	PersistAuthorization bool
+}
+
+// Handler serves documentation page at Config.Path (with or without trailing slash) and spec
+// at Config.Path + "/openapi.json" and Config.Path + "/openapi.yaml".
+//
+//	http.Handle("/docs/", docsui.New(r.Spec, docsui.Config{Path: "/docs"}))
+//	http.Handle("/docs", docsui.New(r.Spec, docsui.Config{Path: "/docs"}))
+type Handler struct {
+	cfg    Config
+	tpl    *template.Template
+	spec   http.Handler
+	doc    openapi.SpecDocument
+	assets http.Handler
+}
+
+// New creates documentation handler of spec, e.g. *openapi31.Spec of a reflector, it panics on unknown UI.
+//
+// Documentation page responds with an error if UI assets are neither embedded nor configured,
+// assets of pinned UI version are embedded with go generate ./docsui.
+func New(spec openapi.SpecDocument, cfg Config) *Handler {
+	if cfg.UI == "" {
+		cfg.UI = SwaggerUI
+	}
+
+	cfg.Path = strings.TrimSuffix(cfg.Path, "/")
+
+	h := &Handler{
+		tpl:  template.Must(template.ParseFS(templates, "templates/"+string(cfg.UI)+".html")),
+		spec: openapi.NewSpecHandler(spec, cfg.SpecOptions...),
+		doc:  spec,
+	}
+
+	if cfg.AssetsURL == "" && cfg.Assets == nil && !cfg.CDN {
+		cfg.Assets = embeddedAssets(cfg.UI)
+	}
+
+	switch {
+	case cfg.AssetsURL != "":
+	case cfg.Assets != nil:
+		cfg.AssetsURL = cfg.Path + "/assets"
+		h.assets = http.StripPrefix(cfg.AssetsURL, http.FileServer(http.FS(cfg.Assets)))
+	case cfg.CDN:
+		for _, name := range assetFiles[cfg.UI] {
+			if cfg.Integrity[name] == "" {
+				panic(fmt.Sprintf("docsui: missing integrity hash of %s for CDN", name))
+			}
+		}
+
+		cfg.AssetsURL = cdnURLs[cfg.UI]
+	}
+
+	cfg.AssetsURL = strings.TrimSuffix(cfg.AssetsURL, "/")
+	h.cfg = cfg
+
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != h.cfg.Path && !strings.HasPrefix(r.URL.Path, h.cfg.Path+"/") {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	switch strings.TrimPrefix(r.URL.Path, h.cfg.Path) {
+	case "", "/", "/index.html":
+		h.serveIndex(w, r)
+	case "/openapi.json", "/openapi.yaml", "/openapi.yml":
+		h.spec.ServeHTTP(w, r)
+	default:
+		if h.assets != nil && strings.HasPrefix(r.URL.Path, h.cfg.Path+"/assets/") {
+			h.assets.ServeHTTP(w, r)
+
+			return
+		}
+
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	if h.cfg.AssetsURL == "" {
+		http.Error(w, "docsui: assets of "+string(h.cfg.UI)+" are not embedded, run go generate ./docsui"+
+			" or configure Assets, AssetsURL or CDN", http.StatusInternalServerError)
+
+		return
+	}
+
+	p := page{
+		Title:                h.cfg.Title,
+		Theme:                h.cfg.Theme,
+		AssetsURL:            h.cfg.AssetsURL,
+		Integrity:            h.cfg.Integrity,
+		SpecURL:              h.cfg.Path + "/openapi.json",
+		PersistAuthorization: h.cfg.PersistAuthorization,
+	}
+
+	if p.Title == "" {
+		if s, ok := h.doc.(openapi.SpecSchema); ok {
+			p.Title = s.Title()
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := h.tpl.Execute(buf, p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(buf.Bytes()) //nolint:errcheck // Client errors are not handled.
+}
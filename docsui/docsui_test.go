@@ -0,0 +1,92 @@
+package docsui_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/openapi-go/docsui"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func serve(h http.Handler, path string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return rw
+}
+
+func TestNew(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Pets")
+
+	h := docsui.New(r.Spec, docsui.Config{
+		Path: "/docs/", PersistAuthorization: true, Theme: "dark", CDN: true,
+		Integrity: map[string]string{"swagger-ui.css": "sha384-css", "swagger-ui-bundle.js": "sha384-js"},
+	})
+
+	rw := serve(h, "/docs/")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rw.Header().Get("Content-Type"))
+	assert.Contains(t, rw.Body.String(), "<title>Pets</title>")
+	assert.Contains(t, rw.Body.String(), `href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14/swagger-ui.css" integrity="sha384-css" crossorigin="anonymous"`)
+	assert.Contains(t, rw.Body.String(), `src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14/swagger-ui-bundle.js" integrity="sha384-js" crossorigin="anonymous"`)
+	assert.Contains(t, rw.Body.String(), `url: "/docs/openapi.json"`)
+	assert.Contains(t, rw.Body.String(), `persistAuthorization:  true `)
+	assert.Contains(t, rw.Body.String(), `"monokai"`)
+
+	assert.Equal(t, http.StatusOK, serve(h, "/docs").Code)
+
+	rw = serve(h, "/docs/openapi.json")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `{"openapi":"3.1.0","info":{"title":"Pets","version":""}}`, rw.Body.String())
+
+	rw = serve(h, "/docs/openapi.yaml")
+	assert.Equal(t, "application/yaml", rw.Header().Get("Content-Type"))
+
+	assert.Equal(t, http.StatusNotFound, serve(h, "/docs/other").Code)
+	assert.Equal(t, http.StatusNotFound, serve(h, "/openapi.json").Code)
+	assert.Equal(t, http.StatusNotFound, serve(h, "/docsopenapi.json").Code)
+	assert.Equal(t, http.StatusNotFound, serve(h, "/other/docs/").Code)
+
+	h = docsui.New(r.Spec, docsui.Config{UI: docsui.Redoc, Title: "API", AssetsURL: "/assets/redoc/"})
+
+	rw = serve(h, "/")
+	assert.Contains(t, rw.Body.String(), "<title>API</title>")
+	assert.Contains(t, rw.Body.String(), `src="/assets/redoc/redoc.standalone.js">`)
+	assert.Contains(t, rw.Body.String(), `Redoc.init("/openapi.json"`)
+
+	h = docsui.New(r.Spec, docsui.Config{
+		UI: docsui.Scalar, Path: "/docs", CDN: true,
+		Integrity: map[string]string{"dist/browser/standalone.js": "sha384-js"},
+	})
+
+	rw = serve(h, "/docs")
+	assert.Contains(t, rw.Body.String(), `data-url="/docs/openapi.json"`)
+	assert.Contains(t, rw.Body.String(), `src="https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.24.0/dist/browser/standalone.js" integrity="sha384-js"`)
+
+	assert.PanicsWithValue(t, "docsui: missing integrity hash of redoc.standalone.js for CDN", func() {
+		docsui.New(r.Spec, docsui.Config{UI: docsui.Redoc, CDN: true})
+	})
+}
+
+func TestNew_assets(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	h := docsui.New(r.Spec, docsui.Config{Path: "/docs", Assets: fstest.MapFS{
+		"swagger-ui.css":       {Data: []byte("body {}")},
+		"swagger-ui-bundle.js": {Data: []byte("var SwaggerUIBundle;")},
+	}})
+
+	rw := serve(h, "/docs")
+	assert.Contains(t, rw.Body.String(), `href="/docs/assets/swagger-ui.css"`)
+	assert.Contains(t, rw.Body.String(), `src="/docs/assets/swagger-ui-bundle.js"`)
+
+	rw = serve(h, "/docs/assets/swagger-ui-bundle.js")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "var SwaggerUIBundle;", rw.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, serve(h, "/docs/assets/redoc.standalone.js").Code)
+}
@@ -0,0 +1,75 @@
+// Package main downloads UI assets to be embedded by docsui package,
+// Subresource Integrity hashes of downloaded files are printed for docsui.Config.Integrity.
+//
+//	go run ./internal/fetchassets -dir assets/redoc -url https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles redoc.standalone.js
+package main
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func main() {
+	dir := flag.String("dir", "", "destination directory")
+	baseURL := flag.String("url", "", "base URL of files")
+
+	flag.Parse()
+
+	if *dir == "" || *baseURL == "" || flag.NArg() == 0 {
+		log.Fatal("usage: fetchassets -dir <destination> -url <base URL> <file>...")
+	}
+
+	for _, name := range flag.Args() {
+		data, err := fetch(*baseURL+"/"+name, filepath.Join(*dir, filepath.FromSlash(name)))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		sum := sha512.Sum384(data)
+		fmt.Printf("%s sha384-%s\n", name, base64.StdEncoding.EncodeToString(sum[:]))
+	}
+}
+
+// fetch downloads file to destination path and returns its content.
+func fetch(url, dest string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s for %s", resp.Status, url)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return nil, err
+	}
+
+	return data, os.WriteFile(dest, data, 0o600)
+}
@@ -0,0 +1,2 @@
+// Package editor provides JSON-RPC server to integrate spec tooling with editor plugins.
+package editor
@@ -0,0 +1,354 @@
+package editor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/lint"
+)
+
+// Handler serves JSON-RPC method, result is marshaled to JSON.
+type Handler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// Error is a JSON-RPC error, it can be returned by Handler to control error code.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Method names of built-in handlers.
+const (
+	MethodResolvePointer  = "openapi/resolvePointer"
+	MethodPositions       = "openapi/positions"
+	MethodLint            = "openapi/lint"
+	MethodDiff            = "openapi/diff"
+	MethodValidateOverlay = "openapi/validateOverlay"
+)
+
+// DefaultMaxMessageSize is a default limit of request message size in bytes.
+const DefaultMaxMessageSize = 16 << 20
+
+// Server serves JSON-RPC 2.0 requests framed with Content-Length headers (as in Language Server Protocol).
+type Server struct {
+	// Spec provides generated spec, e.g. *openapi31.Spec of a reflector.
+	Spec json.Marshaler
+
+	// MaxMessageSize limits Content-Length of request message, default DefaultMaxMessageSize.
+	// Larger messages are skipped and answered with CodeInvalidRequest error.
+	MaxMessageSize int
+
+	// LintRules are used by MethodLint, lint.DefaultRules are used by default.
+	LintRules []lint.Rule
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+}
+
+// NewServer creates JSON-RPC server with built-in handlers.
+func NewServer(spec json.Marshaler) *Server {
+	s := &Server{Spec: spec}
+
+	s.Handle(MethodResolvePointer, s.resolvePointer)
+	s.Handle(MethodPositions, positions)
+	s.Handle(MethodLint, s.lint)
+	s.Handle(MethodDiff, s.diff)
+	s.Handle(MethodValidateOverlay, s.validateOverlay)
+
+	return s
+}
+
+// Handle registers method handler.
+func (s *Server) Handle(method string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[string]Handler)
+	}
+
+	s.handlers[method] = h
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// MarshalJSON emits result, even if it is nil, unless response has an error.
+func (r response) MarshalJSON() ([]byte, error) {
+	if r.Error != nil {
+		return json.Marshal(struct {
+			JSONRPC string          `json:"jsonrpc"`
+			ID      json.RawMessage `json:"id"`
+			Error   *Error          `json:"error"`
+		}{JSONRPC: r.JSONRPC, ID: r.ID, Error: r.Error})
+	}
+
+	type plain response
+
+	return json.Marshal(plain(r))
+}
+
+// Serve reads requests from r and writes responses to w until r is exhausted or context is canceled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	tr := textproto.NewReader(bufio.NewReader(r))
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		body, err := readMessage(tr, s.maxMessageSize())
+
+		var resp *response
+
+		switch {
+		case errors.Is(err, errMessageTooLarge):
+			resp = &response{
+				JSONRPC: "2.0",
+				ID:      json.RawMessage("null"),
+				Error:   &Error{Code: CodeInvalidRequest, Message: err.Error()},
+			}
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		default:
+			resp = s.serveMessage(ctx, body)
+		}
+
+		if resp == nil {
+			continue
+		}
+
+		if err := writeMessage(w, resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) serveMessage(ctx context.Context, body []byte) *response {
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return &response{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &Error{Code: CodeParseError, Message: err.Error()},
+		}
+	}
+
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+
+	if len(req.ID) == 0 {
+		resp.ID = json.RawMessage("null")
+	}
+
+	s.mu.Lock()
+	h, found := s.handlers[req.Method]
+	s.mu.Unlock()
+
+	switch {
+	case req.JSONRPC != "2.0" || req.Method == "":
+		resp.Error = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+	case !found:
+		resp.Error = &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	default:
+		res, err := h(ctx, req.Params)
+		if err != nil {
+			var rpcErr *Error
+			if !errors.As(err, &rpcErr) {
+				rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+			}
+
+			resp.Error = rpcErr
+		} else {
+			resp.Result = res
+		}
+	}
+
+	// Notifications are not answered.
+	if len(req.ID) == 0 && resp.Error == nil {
+		return nil
+	}
+
+	return resp
+}
+
+func (s *Server) maxMessageSize() int {
+	if s.MaxMessageSize > 0 {
+		return s.MaxMessageSize
+	}
+
+	return DefaultMaxMessageSize
+}
+
+var errMessageTooLarge = errors.New("message is too large")
+
+// readMessage reads message body, body that exceeds maxSize is skipped.
+func readMessage(tr *textproto.Reader, maxSize int) ([]byte, error) {
+	header, err := tr.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+
+	if length > maxSize {
+		if _, err := io.CopyN(io.Discard, tr.R, int64(length)); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: %d bytes exceed limit %d", errMessageTooLarge, length, maxSize)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(tr.R, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func writeMessage(w io.Writer, resp *response) error {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+
+	_, err = w.Write(body)
+
+	return err
+}
+
+func invalidParams(err error) error {
+	return &Error{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+// ResolvePointerParams describes parameters of MethodResolvePointer.
+type ResolvePointerParams struct {
+	Pointer string `json:"pointer"`
+}
+
+// ResolvePointerResult describes result of MethodResolvePointer.
+type ResolvePointerResult struct {
+	Found    bool             `json:"found"`
+	Value    interface{}      `json:"value,omitempty"`
+	Position openapi.Position `json:"position"`
+}
+
+// resolvePointer checks if JSON pointer (e.g. from an overlay file) exists in generated spec.
+func (s *Server) resolvePointer(_ context.Context, params json.RawMessage) (interface{}, error) {
+	var p ResolvePointerParams
+
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	doc, index, err := s.generated()
+	if err != nil {
+		return nil, err
+	}
+
+	res := ResolvePointerResult{}
+
+	if v, err := openapi.ResolvePointer(doc, p.Pointer); err == nil {
+		res.Found = true
+		res.Value = v
+	}
+
+	res.Position, _ = index.Lookup(p.Pointer)
+
+	return res, nil
+}
+
+// generated returns decoded generated spec and position index of its indented JSON.
+func (s *Server) generated() (map[string]interface{}, openapi.PositionIndex, error) {
+	if s.Spec == nil {
+		return nil, nil, errors.New("spec is not available")
+	}
+
+	data, err := s.Spec.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var doc map[string]interface{}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	indented, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index, err := openapi.IndexJSON(indented)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, index, nil
+}
+
+// PositionsParams describes parameters of MethodPositions.
+type PositionsParams struct {
+	// Text is a content of JSON or YAML document.
+	Text string `json:"text"`
+}
+
+// positions builds position index of a hand-edited document.
+func positions(_ context.Context, params json.RawMessage) (interface{}, error) {
+	var p PositionsParams
+
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(p.Text), "{") {
+		return openapi.IndexJSON([]byte(p.Text))
+	}
+
+	return openapi.IndexYAML([]byte(p.Text))
+}
@@ -0,0 +1,192 @@
+package editor_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/editor"
+	"github.com/swaggest/openapi-go/lint"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func frame(t *testing.T, messages ...string) io.Reader {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+
+	for _, m := range messages {
+		_, err := fmt.Fprintf(buf, "Content-Length: %d\r\n\r\n%s", len(m), m)
+		require.NoError(t, err)
+	}
+
+	return buf
+}
+
+func responses(t *testing.T, r io.Reader) []string {
+	t.Helper()
+
+	tr := textproto.NewReader(bufio.NewReader(r))
+
+	var res []string
+
+	for {
+		h, err := tr.ReadMIMEHeader()
+		if errors.Is(err, io.EOF) {
+			return res
+		}
+
+		require.NoError(t, err)
+
+		l, err := strconv.Atoi(h.Get("Content-Length"))
+		require.NoError(t, err)
+
+		body := make([]byte, l)
+		_, err = io.ReadFull(tr.R, body)
+		require.NoError(t, err)
+
+		res = append(res, string(body))
+	}
+}
+
+func TestServer_Serve(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Test")
+
+	s := editor.NewServer(r.Spec)
+	s.Handle("custom/echo", func(_ context.Context, params json.RawMessage) (interface{}, error) {
+		return params, nil
+	})
+	s.Handle("custom/fail", func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+	s.Handle("custom/nil", func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+		return nil, nil
+	})
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, s.Serve(context.Background(), frame(t,
+		`{"jsonrpc":"2.0","id":1,"method":"openapi/resolvePointer","params":{"pointer":"#/info/title"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"openapi/resolvePointer","params":{"pointer":"/info/foo"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"openapi/positions","params":{"text":"openapi: 3.1.0\ninfo:\n  title: Test\n"}}`,
+		`{"jsonrpc":"2.0","method":"custom/echo","params":{"a":1}}`,
+		`{"jsonrpc":"2.0","id":"4","method":"custom/echo","params":{"a":1}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"custom/fail"}`,
+		`{"jsonrpc":"2.0","id":6,"method":"unknown"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"openapi/positions","params":[]}`,
+		`{"jsonrpc":"2.0","id":8,"method":"custom/nil"}`,
+		`{`,
+	), out))
+
+	res := responses(t, out)
+	require.Len(t, res, 9)
+
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":1,"result":{"found":true,"value":"Test","position":{"line":3,"column":5}}}`), []byte(res[0]))
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":2,"result":{"found":false,"position":{"line":2,"column":3}}}`), []byte(res[1]))
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":3,"result":{"":{"line":1,"column":1},"/openapi":{"line":1,"column":1},"/info":{"line":2,"column":1},"/info/title":{"line":3,"column":3}}}`), []byte(res[2]))
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":"4","result":{"a":1}}`), []byte(res[3]))
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":5,"error":{"code":-32603,"message":"failed"}}`), []byte(res[4]))
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":6,"error":{"code":-32601,"message":"method not found: unknown"}}`), []byte(res[5]))
+	assert.Contains(t, res[6], `"code":-32602`)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":8,"result":null}`, res[7])
+	assert.Contains(t, res[8], `"code":-32700`)
+}
+
+func TestServer_Serve_messageSize(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Test")
+
+	s := editor.NewServer(r.Spec)
+	s.MaxMessageSize = 100
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, s.Serve(context.Background(), frame(t,
+		`{"jsonrpc":"2.0","id":1,"method":"openapi/positions","params":{"text":"`+strings.Repeat("a", 100)+`"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"openapi/resolvePointer","params":{"pointer":"/info/title"}}`,
+	), out))
+
+	res := responses(t, out)
+	require.Len(t, res, 2)
+
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"message is too large: 174 bytes exceed limit 100"}}`), []byte(res[0]))
+	assert.Contains(t, res[1], `"value":"Test"`)
+}
+
+type listReq struct {
+	Limit int `query:"limit" minimum:"1"`
+}
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func TestServer_Serve_validation(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Test")
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+	oc.AddReqStructure(listReq{})
+	oc.AddRespStructure([]user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	s := editor.NewServer(r.Spec)
+	s.LintRules = []lint.Rule{lint.OperationSummary}
+
+	baseline := `openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: OK
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: OK
+`
+
+	diffParams, err := json.Marshal(editor.TextParams{Text: baseline})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, s.Serve(context.Background(), frame(t,
+		`{"jsonrpc":"2.0","id":1,"method":"openapi/lint"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"openapi/diff","params":`+string(diffParams)+`}`,
+		`{"jsonrpc":"2.0","id":3,"method":"openapi/validateOverlay","params":{"text":"{\n  \"/paths/~1users/get/description\": \"Lists users.\",\n  \"/paths/~1orders/get/summary\": \"Lists orders.\",\n  \"info\": {}\n}"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"openapi/diff","params":{"text":"- a"}}`,
+	), out))
+
+	res := responses(t, out)
+	require.Len(t, res, 4)
+
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":1,"result":[
+	  {"rule":"operation-summary","severity":"warning","message":"<ignore-diff>","pointer":"/paths/~1users/get","position":{"line":21,"column":7}}
+	]}`), []byte(res[0]), res[0])
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":2,"result":[
+	  {"rule":"<ignore-diff>","severity":"error","message":"<ignore-diff>","pointer":"/paths/~1users~1{id}/get","position":{"line":11,"column":5}}
+	]}`), []byte(res[1]), res[1])
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","id":3,"result":[
+	  {"rule":"overlay-target","severity":"error","message":"target is not found in generated spec: /paths/~1orders/get/summary","pointer":"/~1paths~1~01orders~1get~1summary","position":{"line":3,"column":3}},
+	  {"rule":"overlay-target","severity":"error","message":"invalid JSON pointer: info","pointer":"/info","position":{"line":4,"column":3}}
+	]}`), []byte(res[2]), res[2])
+	assert.Contains(t, res[3], `"code":-32602`)
+}
@@ -0,0 +1,205 @@
+package editor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/lint"
+	"github.com/swaggest/openapi-go/openapidiff"
+	"github.com/swaggest/openapi-go/report"
+)
+
+// Diagnostic is a finding with position of its pointer in a document.
+type Diagnostic struct {
+	report.Finding
+
+	Position openapi.Position `json:"position"`
+}
+
+// TextParams describes parameters of methods that check a JSON or YAML document.
+type TextParams struct {
+	// Text is a content of JSON or YAML document.
+	Text string `json:"text"`
+}
+
+// lint checks generated spec with LintRules, diagnostics are positioned in indented JSON of spec.
+func (s *Server) lint(_ context.Context, _ json.RawMessage) (interface{}, error) {
+	doc, index, err := s.generated()
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := lint.Run(doc, s.LintRules...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Diagnostic, 0, len(findings))
+
+	for _, f := range findings {
+		pos, _ := index.Lookup(f.Pointer)
+		res = append(res, Diagnostic{Finding: f, Position: pos})
+	}
+
+	return res, nil
+}
+
+// diff reports breaking changes of generated spec against a baseline document, e.g. a released spec.
+//
+// Removals are positioned in the baseline document, other changes are positioned in indented JSON of spec.
+func (s *Server) diff(_ context.Context, params json.RawMessage) (interface{}, error) {
+	var p TextParams
+
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	baseline, baselineIndex, err := decodeText(p.Text)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	doc, index, err := s.generated()
+	if err != nil {
+		return nil, err
+	}
+
+	violations, err := openapidiff.Diff(baseline, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Diagnostic, 0, len(violations))
+
+	for _, v := range violations {
+		pos, found := index.Lookup(v.Pointer)
+		if _, err := openapi.ResolvePointer(doc, v.Pointer); err != nil || !found {
+			pos, _ = baselineIndex.Lookup(v.Pointer)
+		}
+
+		res = append(res, Diagnostic{Finding: v.Finding(), Position: pos})
+	}
+
+	return res, nil
+}
+
+// RuleOverlayTarget is a rule of MethodValidateOverlay findings.
+const RuleOverlayTarget = "overlay-target"
+
+// validateOverlay checks that JSON pointers of overlay document target generated spec.
+//
+// Overlay is an object with JSON pointers as keys and values to apply at them, e.g.
+// {"/paths/~1users/get/description": "Lists users."}, a pointer can target an existing value
+// or a new member of an existing object. Diagnostics are positioned in overlay document.
+func (s *Server) validateOverlay(_ context.Context, params json.RawMessage) (interface{}, error) {
+	var p TextParams
+
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	overlay, overlayIndex, err := decodeText(p.Text)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	doc, _, err := s.generated()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []Diagnostic{}
+
+	for _, pointer := range internal.SortedKeys(overlay) {
+		var message string
+
+		switch {
+		case pointer != "" && !strings.HasPrefix(pointer, "/"):
+			message = "invalid JSON pointer: " + pointer
+		case !overlayTargetExists(doc, pointer):
+			message = "target is not found in generated spec: " + pointer
+		default:
+			continue
+		}
+
+		key := "/" + openapi.PointerToken(pointer)
+		pos, _ := overlayIndex.Lookup(key)
+
+		res = append(res, Diagnostic{
+			Finding: report.Finding{
+				Rule:     RuleOverlayTarget,
+				Severity: report.SeverityError,
+				Message:  message,
+				Pointer:  key,
+			},
+			Position: pos,
+		})
+	}
+
+	return res, nil
+}
+
+// overlayTargetExists checks if pointer resolves, or if it adds a member to an existing object.
+func overlayTargetExists(doc interface{}, pointer string) bool {
+	if _, err := openapi.ResolvePointer(doc, pointer); err == nil {
+		return true
+	}
+
+	pos := strings.LastIndex(pointer, "/")
+	if pos < 0 {
+		return false
+	}
+
+	parent, err := openapi.ResolvePointer(doc, pointer[:pos])
+	if err != nil {
+		return false
+	}
+
+	_, ok := parent.(map[string]interface{})
+
+	return ok
+}
+
+type rawDocument map[string]interface{}
+
+func (d *rawDocument) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*map[string]interface{})(d))
+}
+
+// decodeText decodes JSON or YAML object and indexes its positions.
+func decodeText(text string) (map[string]interface{}, openapi.PositionIndex, error) {
+	const location = "document"
+
+	var doc rawDocument
+
+	l := openapi.Loader{
+		ReadLocation: func(l string) ([]byte, error) {
+			if l != location {
+				return nil, errors.New("external references are not supported")
+			}
+
+			return []byte(text), nil
+		},
+	}
+
+	if err := l.Load(location, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		index openapi.PositionIndex
+		err   error
+	)
+
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		index, err = openapi.IndexJSON([]byte(text))
+	} else {
+		index, err = openapi.IndexYAML([]byte(text))
+	}
+
+	return doc, index, err
+}
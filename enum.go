@@ -0,0 +1,8 @@
+package openapi
+
+// EnumDescriber is implemented by enum types to describe their values, e.g. status codes.
+//
+// Descriptions are keyed by enum values.
+type EnumDescriber interface {
+	EnumDescriptions() map[interface{}]string
+}
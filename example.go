@@ -0,0 +1,516 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExampleGenerator creates payloads that conform to JSON Schema in simple map form (as in openapi31.MediaType).
+//
+// Values respect types, formats, enums, patterns, numeric and length limits of schemas, examples and defaults
+// are used as is. Payloads are deterministic unless Rand is set.
+//
+//	g := openapi.ExampleGenerator{Rand: rand.New(rand.NewSource(seed)), Resolve: r.Spec.ResolveSchemaRef}
+//	payload := g.Generate(schema)
+type ExampleGenerator struct {
+	// Rand enables random payloads for property-based testing, examples and defaults are ignored.
+	// Optional object properties are randomly omitted.
+	Rand *rand.Rand
+
+	// Resolve returns schema of a reference, e.g. "#/components/schemas/User", optional.
+	Resolve func(ref string) (map[string]interface{}, bool)
+}
+
+// GenerateExample creates deterministic payload of JSON Schema in simple map form.
+func GenerateExample(schema map[string]interface{}) interface{} {
+	return ExampleGenerator{}.Generate(schema)
+}
+
+// Generate creates payload of JSON Schema in simple map form, nil is returned for unresolved
+// or recursive references.
+func (g ExampleGenerator) Generate(schema map[string]interface{}) interface{} {
+	return g.generate(schema, map[string]bool{})
+}
+
+var exampleFormats = map[string]string{
+	"date-time": "2006-01-02T15:04:05Z",
+	"date":      "2006-01-02",
+	"time":      "15:04:05Z",
+	"duration":  "PT1H",
+	"email":     "user@example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+}
+
+func (g ExampleGenerator) generate(schema map[string]interface{}, stack map[string]bool) interface{} {
+	if ref, ok := schema["$ref"].(string); ok {
+		if stack[ref] || g.Resolve == nil {
+			return nil
+		}
+
+		s, found := g.Resolve(ref)
+		if !found {
+			return nil
+		}
+
+		stack[ref] = true
+		defer delete(stack, ref)
+
+		return g.generate(s, stack)
+	}
+
+	if g.Rand == nil {
+		for _, kw := range []string{"example", "default"} {
+			if v, ok := schema[kw]; ok {
+				return v
+			}
+		}
+
+		if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+			return examples[0]
+		}
+	}
+
+	if v, ok := schema["const"]; ok {
+		return v
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[g.intn(len(enum))]
+	}
+
+	for _, kw := range []string{"oneOf", "anyOf"} {
+		if variants := subschemas(schema[kw]); len(variants) > 0 {
+			return g.generate(variants[g.intn(len(variants))], stack)
+		}
+	}
+
+	if allOf := subschemas(schema["allOf"]); len(allOf) > 0 {
+		res := map[string]interface{}{}
+		rest := make(map[string]interface{}, len(schema))
+
+		for k, v := range schema {
+			if k != "allOf" {
+				rest[k] = v
+			}
+		}
+
+		for _, s := range append(allOf, rest) {
+			if obj, ok := g.generate(s, stack).(map[string]interface{}); ok {
+				for k, v := range obj {
+					res[k] = v
+				}
+			}
+		}
+
+		return res
+	}
+
+	switch g.schemaType(schema) {
+	case "object":
+		return g.object(schema, stack)
+	case "array":
+		return g.array(schema, stack)
+	case "string":
+		return g.string(schema)
+	case "integer":
+		return integer(g.number(schema, true))
+	case "number":
+		return g.number(schema, false)
+	case "boolean":
+		return g.Rand == nil || g.Rand.Intn(2) == 1
+	}
+
+	return nil
+}
+
+func (g ExampleGenerator) intn(n int) int {
+	if g.Rand == nil {
+		return 0
+	}
+
+	return g.Rand.Intn(n)
+}
+
+func subschemas(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{}) //nolint:errcheck // Missing subschemas are empty.
+
+	res := make([]map[string]interface{}, 0, len(items))
+
+	for _, item := range items {
+		if s, ok := item.(map[string]interface{}); ok {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+// schemaType returns non-null type of schema, schemas with properties are objects.
+func (g ExampleGenerator) schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		var types []string
+
+		for _, item := range t {
+			if s := fmt.Sprint(item); s != "null" {
+				types = append(types, s)
+			}
+		}
+
+		if len(types) > 0 {
+			return types[g.intn(len(types))]
+		}
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+
+	return ""
+}
+
+func (g ExampleGenerator) object(schema map[string]interface{}, stack map[string]bool) interface{} {
+	res := map[string]interface{}{}
+	props, _ := schema["properties"].(map[string]interface{}) //nolint:errcheck // Missing properties are empty.
+
+	required := map[string]bool{}
+
+	switch req := schema["required"].(type) {
+	case []interface{}:
+		for _, name := range req {
+			required[fmt.Sprint(name)] = true
+		}
+	case []string:
+		for _, name := range req {
+			required[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+
+	// Sorting keeps random sequence stable for a seed.
+	sort.Strings(names)
+
+	for _, name := range names {
+		if g.Rand != nil && !required[name] && g.Rand.Intn(2) == 0 {
+			continue
+		}
+
+		prop, _ := props[name].(map[string]interface{}) //nolint:errcheck // Boolean schemas have no payload.
+
+		// Recursive and unresolved properties are omitted.
+		if v := g.generate(prop, stack); v != nil {
+			res[name] = v
+		}
+	}
+
+	return res
+}
+
+func (g ExampleGenerator) array(schema map[string]interface{}, stack map[string]bool) interface{} {
+	minItems, maxItems := limits(schema, "minItems", "maxItems")
+
+	n := int(math.Max(minItems, 1))
+	if g.Rand != nil {
+		n = int(minItems) + g.Rand.Intn(4)
+	}
+
+	if float64(n) > maxItems {
+		n = int(maxItems)
+	}
+
+	items, _ := schema["items"].(map[string]interface{}) //nolint:errcheck // Missing items are any values.
+
+	// Recursive items are omitted.
+	if ref, ok := items["$ref"].(string); ok && stack[ref] {
+		return []interface{}{}
+	}
+
+	res := make([]interface{}, 0, n)
+
+	for i := 0; i < n; i++ {
+		res = append(res, g.generate(items, stack))
+	}
+
+	return res
+}
+
+func (g ExampleGenerator) string(schema map[string]interface{}) string {
+	if format, ok := schema["format"].(string); ok {
+		if s, ok := exampleFormats[format]; ok {
+			return s
+		}
+	}
+
+	minLength, maxLength := limits(schema, "minLength", "maxLength")
+	minLength = math.Min(minLength, maxExampleLength)
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := syntax.Parse(pattern, syntax.Perl); err == nil {
+			return g.patternString(re.Simplify(), minLength, maxLength)
+		}
+	}
+
+	s := "string"
+
+	if g.Rand != nil {
+		n := int(minLength) + g.Rand.Intn(11)
+		b := make([]byte, n)
+
+		for i := range b {
+			b[i] = byte('a' + g.Rand.Intn(26))
+		}
+
+		s = string(b)
+	}
+
+	for float64(len(s)) < minLength {
+		s += "s"
+	}
+
+	if float64(len(s)) > maxLength {
+		s = s[:int(maxLength)]
+	}
+
+	return s
+}
+
+const (
+	// maxPatternAttempts limits random strings of pattern that are checked against length limits.
+	maxPatternAttempts = 10
+
+	// maxExampleLength limits length of generated strings, larger minLength is not satisfied.
+	maxExampleLength = 1024
+)
+
+// patternString creates a string that matches regular expression within length limits,
+// limits are ignored if pattern can not satisfy them.
+func (g ExampleGenerator) patternString(re *syntax.Regexp, minLength, maxLength float64) string {
+	fits := func(s string) bool {
+		l := float64(utf8.RuneCountInString(s))
+
+		return l >= minLength && l <= maxLength
+	}
+
+	if g.Rand != nil {
+		for i := 0; i < maxPatternAttempts; i++ {
+			b := strings.Builder{}
+			g.regexp(&b, re, 0)
+
+			if s := b.String(); fits(s) {
+				return s
+			}
+		}
+
+		g.Rand = nil
+	}
+
+	// Minimal string is extended with extra repetitions until it is long enough.
+	var s string
+
+	for extra := 0; extra <= int(minLength); extra++ {
+		b := strings.Builder{}
+		g.regexp(&b, re, extra)
+
+		s = b.String()
+		if l := utf8.RuneCountInString(s); fits(s) || float64(l) > maxLength || l > maxExampleLength {
+			break
+		}
+	}
+
+	return s
+}
+
+// regexp writes a string that matches regular expression, extra repetitions are added to minimal ones.
+func (g ExampleGenerator) regexp(b *strings.Builder, re *syntax.Regexp, extra int) {
+	switch re.Op { //nolint:exhaustive // Anchors and empty matches produce no output.
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		g.charClass(b, re.Rune)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte('a')
+	case syntax.OpCapture:
+		g.regexp(b, re.Sub[0], extra)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regexp(b, sub, extra)
+		}
+	case syntax.OpAlternate:
+		g.regexp(b, re.Sub[g.intn(len(re.Sub))], extra)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := re.Min, re.Max
+
+		switch re.Op { //nolint:exhaustive // Repetitions only.
+		case syntax.OpStar:
+			lo, hi = 0, -1
+		case syntax.OpPlus:
+			lo, hi = 1, -1
+		case syntax.OpQuest:
+			lo, hi = 0, 1
+		}
+
+		n := lo
+		if g.Rand != nil {
+			if hi < 0 || hi > lo+3 {
+				hi = lo + 3
+			}
+
+			n = lo + g.Rand.Intn(hi-lo+1)
+		}
+
+		if n += extra; hi >= 0 && n > hi {
+			n = hi
+		}
+
+		for i := 0; i < n; i++ {
+			g.regexp(b, re.Sub[0], extra)
+		}
+	}
+}
+
+// charClass writes a rune of class ranges, printable ASCII runes are preferred.
+func (g ExampleGenerator) charClass(b *strings.Builder, ranges []rune) {
+	var printable []rune
+
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < '!' {
+			lo = '!'
+		}
+
+		if hi > '~' {
+			hi = '~'
+		}
+
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+
+	if len(printable) > 0 {
+		ranges = printable
+	}
+
+	if len(ranges) < 2 {
+		return
+	}
+
+	i := 2 * g.intn(len(ranges)/2)
+	lo, hi := ranges[i], ranges[i+1]
+	b.WriteRune(lo + rune(g.intn(int(hi-lo)+1)))
+}
+
+func (g ExampleGenerator) number(schema map[string]interface{}, integer bool) float64 {
+	lo, hi := math.Inf(-1), math.Inf(1)
+
+	if v, ok := number(schema["minimum"]); ok {
+		lo = v
+	}
+
+	if v, ok := number(schema["maximum"]); ok {
+		hi = v
+	}
+
+	step := 0.0
+	if integer {
+		step = 1
+	}
+
+	// OpenAPI 3.0 has boolean exclusive limits, JSON Schema has numeric ones.
+	if v, ok := number(schema["exclusiveMinimum"]); ok {
+		lo = math.Max(lo, v+math.Max(step, 0.5))
+	} else if schema["exclusiveMinimum"] == true {
+		lo += math.Max(step, 0.5)
+	}
+
+	if v, ok := number(schema["exclusiveMaximum"]); ok {
+		hi = math.Min(hi, v-math.Max(step, 0.5))
+	} else if schema["exclusiveMaximum"] == true {
+		hi -= math.Max(step, 0.5)
+	}
+
+	if math.IsInf(lo, -1) {
+		lo = math.Min(0, hi)
+	}
+
+	if math.IsInf(hi, 1) {
+		hi = lo + 100
+	}
+
+	v := lo
+	if g.Rand != nil {
+		v = lo + g.Rand.Float64()*(hi-lo)
+	}
+
+	if m, ok := number(schema["multipleOf"]); ok && m > 0 {
+		v = math.Ceil(v/m) * m
+	}
+
+	if integer {
+		v = math.Ceil(v)
+	}
+
+	return v
+}
+
+// integer converts number to int64, values out of int64 range are clamped.
+func integer(v float64) int64 {
+	switch {
+	case math.IsNaN(v):
+		return 0
+	case v >= math.MaxInt64:
+		return math.MaxInt64
+	case v <= math.MinInt64:
+		return math.MinInt64
+	}
+
+	return int64(v)
+}
+
+// limits returns non-negative lower and upper limits of schema, missing upper limit is infinite.
+func limits(schema map[string]interface{}, minKey, maxKey string) (float64, float64) {
+	lo, hi := 0.0, math.Inf(1)
+
+	if v, ok := number(schema[minKey]); ok {
+		lo = v
+	}
+
+	if v, ok := number(schema[maxKey]); ok {
+		hi = v
+	}
+
+	return lo, hi
+}
+
+// number converts numeric schema keyword, maps that are not decoded from JSON can have integers.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,114 @@
+package openapi_test
+
+import (
+	"math"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+)
+
+var exampleSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"code", "count"},
+	"properties": map[string]interface{}{
+		"code":    map[string]interface{}{"type": "string", "pattern": `^[A-Z]{2}-\d{3}$`},
+		"count":   map[string]interface{}{"type": "integer", "exclusiveMinimum": 10.0, "maximum": 40.0, "multipleOf": 5.0},
+		"ratio":   map[string]interface{}{"type": []interface{}{"null", "number"}, "minimum": 0.5, "maximum": 1.0},
+		"status":  map[string]interface{}{"type": "string", "enum": []interface{}{"active", "blocked"}},
+		"name":    map[string]interface{}{"type": "string", "minLength": 8.0, "maxLength": 12.0},
+		"created": map[string]interface{}{"type": "string", "format": "date-time"},
+		"tags":    map[string]interface{}{"type": "array", "minItems": 2.0, "maxItems": 3.0, "items": map[string]interface{}{"type": "string"}},
+		"owner":   map[string]interface{}{"$ref": "#/components/schemas/User"},
+		"label":   map[string]interface{}{"type": "string", "example": "Primary"},
+		"slug":    map[string]interface{}{"type": "string", "pattern": `^[a-z]+\d?$`, "minLength": 4.0, "maxLength": 6.0},
+	},
+}
+
+func TestGenerateExample(t *testing.T) {
+	assertjson.EqMarshal(t, `{
+	  "code":"AA-000","count":15,"ratio":0.5,"status":"active","name":"stringss",
+	  "created":"2006-01-02T15:04:05Z","tags":["string","string"],"label":"Primary","slug":"aaa0"
+	}`, openapi.GenerateExample(exampleSchema))
+
+	g := openapi.ExampleGenerator{
+		Resolve: func(ref string) (map[string]interface{}, bool) {
+			return map[string]interface{}{
+				"properties": map[string]interface{}{
+					"name":    map[string]interface{}{"type": "string"},
+					"manager": map[string]interface{}{"$ref": ref},
+				},
+			}, true
+		},
+	}
+
+	assertjson.EqMarshal(t, `{"name":"string"}`, g.Generate(map[string]interface{}{"$ref": "#/components/schemas/User"}))
+
+	g.Resolve = func(ref string) (map[string]interface{}, bool) {
+		return map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name":     map[string]interface{}{"type": "string"},
+				"children": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": ref}},
+			},
+		}, true
+	}
+
+	assertjson.EqMarshal(t, `{"name":"string","children":[]}`, g.Generate(map[string]interface{}{"$ref": "#/components/schemas/Node"}))
+}
+
+func TestGenerateExample_limits(t *testing.T) {
+	s, ok := openapi.GenerateExample(map[string]interface{}{"type": "string", "minLength": 1e18}).(string)
+	require.True(t, ok)
+	assert.Len(t, s, 1024)
+
+	s, ok = openapi.GenerateExample(map[string]interface{}{"type": "string", "pattern": "^a+$", "minLength": 1e18}).(string)
+	require.True(t, ok)
+	assert.Len(t, s, 1024)
+
+	assert.Equal(t, int64(math.MaxInt64), openapi.GenerateExample(map[string]interface{}{"type": "integer", "minimum": 1e30}))
+	assert.Equal(t, int64(math.MinInt64), openapi.GenerateExample(map[string]interface{}{"type": "integer", "maximum": -1e30}))
+}
+
+func TestExampleGenerator_Generate_rand(t *testing.T) {
+	g := openapi.ExampleGenerator{Rand: rand.New(rand.NewSource(1))} //nolint:gosec // Tests are reproducible.
+	code := regexp.MustCompile(`^[A-Z]{2}-\d{3}$`)
+	slug := regexp.MustCompile(`^[a-z]{3,6}\d?$`)
+
+	for i := 0; i < 100; i++ {
+		v, ok := g.Generate(exampleSchema).(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Regexp(t, code, v["code"])
+
+		count, ok := v["count"].(int64)
+		require.True(t, ok)
+		assert.True(t, count > 10 && count <= 40 && count%5 == 0, count)
+
+		if ratio, ok := v["ratio"]; ok {
+			assert.True(t, ratio.(float64) >= 0.5 && ratio.(float64) <= 1, ratio)
+		}
+
+		if status, ok := v["status"]; ok {
+			assert.Contains(t, []interface{}{"active", "blocked"}, status)
+		}
+
+		if name, ok := v["name"]; ok {
+			assert.True(t, len(name.(string)) >= 8 && len(name.(string)) <= 12, name)
+		}
+
+		if s, ok := v["slug"]; ok {
+			assert.Regexp(t, slug, s)
+			assert.True(t, len(s.(string)) >= 4 && len(s.(string)) <= 6, s)
+		}
+
+		if tags, ok := v["tags"]; ok {
+			assert.True(t, len(tags.([]interface{})) >= 2 && len(tags.([]interface{})) <= 3, tags)
+		}
+
+		assert.NotContains(t, v, "owner")
+	}
+}
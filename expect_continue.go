@@ -0,0 +1,29 @@
+package openapi
+
+import "net/http"
+
+type expectContinueHeader struct {
+	Expect string `header:"Expect" enum:"100-continue" description:"Asks server to confirm that request body is acceptable before it is sent."`
+}
+
+// ExpectContinue returns a Trait of upload operation that supports `Expect: 100-continue` flow.
+//
+// It documents optional "Expect" request header, "417 Expectation Failed" response and interim
+// "100 Continue" response in "x-interim-responses" extension, because OpenAPI responses are final.
+//
+//	openapi.ExpectContinue().Apply(oc)
+func ExpectContinue() Trait {
+	return Trait{
+		Request: []interface{}{expectContinueHeader{}},
+		Responses: []TraitResponse{
+			{Options: []ContentOption{WithHTTPStatus(http.StatusExpectationFailed)}},
+		},
+		Extensions: map[string]interface{}{
+			"x-interim-responses": map[string]interface{}{
+				"100": map[string]interface{}{
+					"description": "Continue, request body can be sent.",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// FreeFormStyle defines reflection of free-form values, such as json.RawMessage and map[string]interface{}.
+type FreeFormStyle int
+
+// FreeFormStyle values enumeration.
+const (
+	// FreeFormAny reflects free-form values as schema without constraints: {}.
+	FreeFormAny FreeFormStyle = iota
+
+	// FreeFormObject reflects free-form values as objects: {"type":"object","additionalProperties":true}.
+	FreeFormObject
+
+	// FreeFormForbidden fails reflection of free-form values, unless their types have registered type mapping.
+	FreeFormForbidden
+)
+
+var typeOfRawMessage = reflect.TypeOf(json.RawMessage{})
+
+// FreeForm is a jsonschema.ReflectContext option to control reflection of free-form values.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.FreeForm(openapi.FreeFormObject))
+func FreeForm(style FreeFormStyle) func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if params.Processed || !params.Value.IsValid() {
+			return false, nil
+		}
+
+		// Value type is checked instead of schema type to respect type mapping.
+		t := refl.DeepIndirect(params.Value.Type())
+		if t != typeOfRawMessage && !isFreeFormMap(t) {
+			return false, nil
+		}
+
+		switch style {
+		case FreeFormAny:
+			params.Schema.Type = nil
+		case FreeFormObject:
+			nullable := params.Schema.HasType(jsonschema.Null)
+			additionalProperties := true
+
+			params.Schema.Type = nil
+			params.Schema.AddType(jsonschema.Object)
+			params.Schema.WithAdditionalProperties(jsonschema.SchemaOrBool{TypeBoolean: &additionalProperties})
+
+			if nullable {
+				params.Schema.AddType(jsonschema.Null)
+			}
+		case FreeFormForbidden:
+			return true, fmt.Errorf("free-form value at %s requires registered type mapping",
+				strings.Join(params.Context.Path, "."))
+		}
+
+		return true, nil
+	})
+}
+
+func isFreeFormMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.Interface &&
+		t.Elem().NumMethod() == 0
+}
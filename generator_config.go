@@ -0,0 +1,23 @@
+package openapi
+
+// GeneratorConfig describes effective reflection options of a spec.
+//
+// It can be emitted as "x-generator-config" extension, so that consumers and future regenerations
+// can check that a document is produced with the same configuration.
+type GeneratorConfig struct {
+	// Defaults are names of built-in reflection hooks, in order of installation.
+	Defaults []string `json:"defaults,omitempty"`
+
+	NullStrategy          string `json:"nullStrategy,omitempty"`
+	MaxRecursionDepth     int    `json:"maxRecursionDepth"`
+	KeepContentTypeParams bool   `json:"keepContentTypeParams"`
+	EnumOneOf             bool   `json:"enumOneOf"`
+	DefaultTags           bool   `json:"defaultTags"`
+	SharedComponents      bool   `json:"sharedComponents"`
+
+	// TypeMappings are Go type names of mapping destinations by source type names.
+	TypeMappings map[string]string `json:"typeMappings,omitempty"`
+
+	// InlinedTypes are Go type names of definitions that are always inlined.
+	InlinedTypes []string `json:"inlinedTypes,omitempty"`
+}
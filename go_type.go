@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"go/token"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// GoTypeExtension is a vendor extension with Go type of schema, e.g. "github.com/acme/pets.Pet".
+const GoTypeExtension = "x-go-type"
+
+// GoTypes is a jsonschema.ReflectContext option to add "x-go-type" with import path and name
+// of originating Go type to schemas of named types, so that generated clients can reuse them.
+//
+// Types of main packages, test packages, instances of generic types and structures
+// with parameter or header fields (that are not plain bodies) are not exposed.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.GoTypes)
+func GoTypes(rc *jsonschema.ReflectContext) {
+	jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || !params.Value.IsValid() || params.Schema == nil {
+			return false, nil
+		}
+
+		t := refl.DeepIndirect(params.Value.Type())
+		if hasParamFields(t) {
+			return false, nil
+		}
+
+		if goType := GoTypeName(t); goType != "" {
+			params.Schema.WithExtraPropertiesItem(GoTypeExtension, goType)
+		}
+
+		return false, nil
+	})(rc)
+}
+
+// GoTypeName returns importable name of a type, e.g. "github.com/acme/pets.Pet",
+// or empty string if type can not be referenced from another package.
+func GoTypeName(t reflect.Type) string {
+	name, pkg := t.Name(), t.PkgPath()
+
+	if name == "" || pkg == "" || pkg == "main" || strings.HasSuffix(pkg, "_test") ||
+		strings.Contains(name, "[") || !token.IsExported(name) {
+		return ""
+	}
+
+	return pkg + "." + name
+}
+
+var paramTags = []string{"path", "query", "header", "cookie", "formData", "form"}
+
+func hasParamFields(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		for _, tag := range paramTags {
+			if _, ok := f.Tag.Lookup(tag); ok {
+				return true
+			}
+		}
+
+		if f.Anonymous && hasParamFields(refl.DeepIndirect(f.Type)) {
+			return true
+		}
+	}
+
+	return false
+}
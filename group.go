@@ -0,0 +1,105 @@
+package openapi
+
+// GroupOption configures defaults of Group.
+type GroupOption func(g *Group)
+
+// Group is a Reflector that applies shared defaults to every operation context it creates.
+//
+//	pets := r.Group(
+//		openapi.WithTags("Pets"),
+//		openapi.WithSecurity("bearerAuth"),
+//		openapi.WithRespStructure(errResp{}, http.StatusInternalServerError),
+//	)
+//
+//	oc, err := pets.NewOperationContext(http.MethodGet, "/pets")
+type Group struct {
+	Reflector
+
+	// Defaults are applied to new operation contexts.
+	Defaults Trait
+}
+
+// NewGroup creates Group of reflector, groups can be nested to inherit defaults.
+func NewGroup(r Reflector, options ...GroupOption) Group {
+	g := Group{Reflector: r}
+
+	for _, option := range options {
+		option(&g)
+	}
+
+	return g
+}
+
+// NewOperationContext initializes operation context with defaults of group.
+func (g Group) NewOperationContext(method, pathPattern string) (OperationContext, error) {
+	oc, err := g.Reflector.NewOperationContext(method, pathPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	g.Defaults.Apply(oc)
+
+	return oc, nil
+}
+
+// Group creates nested group, operations receive defaults of parent group first.
+func (g Group) Group(options ...GroupOption) Group {
+	return NewGroup(g, options...)
+}
+
+// WithTags is a GroupOption, it adds tags to operations.
+func WithTags(tags ...string) GroupOption {
+	return func(g *Group) {
+		g.Defaults.Tags = append(g.Defaults.Tags, tags...)
+	}
+}
+
+// WithSecurity is a GroupOption, it adds security requirement to operations,
+// multiple requirements are alternatives.
+func WithSecurity(securityName string, scopes ...string) GroupOption {
+	return func(g *Group) {
+		if g.Defaults.Security == nil {
+			g.Defaults.Security = make(map[string][]string)
+		}
+
+		g.Defaults.Security[securityName] = scopes
+	}
+}
+
+// WithReqStructure is a GroupOption, it adds shared request structure to operations, e.g. common headers.
+func WithReqStructure(i interface{}) GroupOption {
+	return func(g *Group) {
+		g.Defaults.Request = append(g.Defaults.Request, i)
+	}
+}
+
+// WithRespStructure is a GroupOption, it adds shared response structure with HTTP status to operations.
+func WithRespStructure(o interface{}, httpStatus int, options ...ContentOption) GroupOption {
+	return func(g *Group) {
+		g.Defaults.Responses = append(g.Defaults.Responses, TraitResponse{
+			Structure: o,
+			Options:   append([]ContentOption{WithHTTPStatus(httpStatus)}, options...),
+		})
+	}
+}
+
+// WithTrait is a GroupOption, it adds settings of a trait to operations.
+func WithTrait(t Trait) GroupOption {
+	return func(g *Group) {
+		g.Defaults.Request = append(g.Defaults.Request, t.Request...)
+		g.Defaults.Responses = append(g.Defaults.Responses, t.Responses...)
+		g.Defaults.Tags = append(g.Defaults.Tags, t.Tags...)
+
+		for name, scopes := range t.Security {
+			WithSecurity(name, scopes...)(g)
+		}
+
+		for key, value := range t.Extensions {
+			if g.Defaults.Extensions == nil {
+				g.Defaults.Extensions = make(map[string]interface{})
+			}
+
+			g.Defaults.Extensions[key] = value
+		}
+	}
+}
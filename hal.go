@@ -0,0 +1,51 @@
+package openapi
+
+import "github.com/swaggest/jsonschema-go"
+
+// HALJSONMediaType is a media type of HAL documents.
+const HALJSONMediaType = "application/hal+json"
+
+// HALLinks is a "_links" member of HAL resource, values are links or arrays of links by relation.
+//
+// Response structures with HALLinks field have "application/hal+json" media type by default.
+//
+//	type order struct {
+//		Links    openapi.HALLinks `json:"_links"`
+//		Embedded struct {
+//			Items []item `json:"items"`
+//		} `json:"_embedded"`
+//		Total float64 `json:"total"`
+//	}
+//
+// See https://datatracker.ietf.org/doc/html/draft-kelly-json-hal.
+type HALLinks map[string]HALLink
+
+// HALLink is a link object of HAL resource.
+type HALLink struct {
+	Href        string `json:"href" required:"true" description:"URI or URI Template of target resource."`
+	Templated   bool   `json:"templated,omitempty" description:"Indicates that href is a URI Template."`
+	Type        string `json:"type,omitempty" description:"Media type of target resource."`
+	Deprecation string `json:"deprecation,omitempty" format:"uri" description:"URL with deprecation notice of link."`
+	Name        string `json:"name,omitempty" description:"Secondary key to select link of the same relation."`
+	Profile     string `json:"profile,omitempty" format:"uri"`
+	Title       string `json:"title,omitempty"`
+	Hreflang    string `json:"hreflang,omitempty"`
+}
+
+// PrepareJSONSchema allows arrays of links.
+func (HALLinks) PrepareJSONSchema(s *jsonschema.Schema) error {
+	if s.AdditionalProperties == nil {
+		return nil
+	}
+
+	link := *s.AdditionalProperties
+
+	s.WithAdditionalProperties((&jsonschema.Schema{}).WithOneOf(
+		link,
+		(&jsonschema.Schema{}).WithType(jsonschema.Array.Type()).WithItems(
+			*(&jsonschema.Items{}).WithSchemaOrBool(link),
+		).ToSchemaOrBool(),
+	).ToSchemaOrBool())
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package chi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	gochi "github.com/go-chi/chi/v5"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// OperationSetup is implemented by handlers that describe their operations,
+// e.g. with request and response structures.
+type OperationSetup interface {
+	SetupOperation(oc openapi.OperationContext) error
+}
+
+// Collector adds operations of router to reflector.
+//
+//	if err := chi.NewCollector(reflector).Collect(router); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Route patterns are used as is, regexps of path parameters (e.g. "{id:[0-9]+}") become patterns of
+// parameter schemas and trailing "*" becomes openapi.WildcardParameter.
+// Path parameters that are not declared by structures of OperationSetup are added as strings,
+// TagNamespace and ParameterTags of openapi3 and openapi31 reflectors are respected.
+type Collector struct {
+	Reflector openapi.Reflector
+}
+
+// NewCollector creates collector of operations.
+func NewCollector(r openapi.Reflector) *Collector {
+	return &Collector{Reflector: r}
+}
+
+// Collect adds operations of all routes of router, including mounted subrouters.
+func (c *Collector) Collect(r gochi.Routes) error {
+	return gochi.Walk(r, c.Walk)
+}
+
+// Walk adds operation of route to reflector, it is a chi.WalkFunc.
+//
+// Handlers with inline middlewares (chi.ChainHandler) are unwrapped to check OperationSetup of endpoint,
+// methods that are not supported by OpenAPI (CONNECT) are skipped.
+func (c *Collector) Walk(method, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+	if strings.EqualFold(method, http.MethodConnect) {
+		return nil
+	}
+
+	oc, err := c.Reflector.NewOperationContext(method, route)
+	if err != nil {
+		return err
+	}
+
+	if s, ok := endpoint(handler).(OperationSetup); ok {
+		if err := s.SetupOperation(oc); err != nil {
+			return fmt.Errorf("setup operation %s %s: %w", method, route, err)
+		}
+	}
+
+	if params := undeclaredPathParams(oc, route, c.pathTags()); params != nil {
+		oc.AddReqStructure(params)
+	}
+
+	return c.Reflector.AddOperation(oc)
+}
+
+// endpoint unwraps handlers of inline middlewares.
+func endpoint(h http.Handler) http.Handler {
+	if ch, ok := h.(*gochi.ChainHandler); ok && ch.Endpoint != nil {
+		return endpoint(ch.Endpoint)
+	}
+
+	return h
+}
+
+// pathTags returns field tags of path parameters in order of precedence.
+func (c *Collector) pathTags() []string {
+	var (
+		namespace string
+		tags      = []string{string(openapi.InPath)}
+	)
+
+	switch r := c.Reflector.(type) {
+	case *openapi3.Reflector:
+		namespace = r.TagNamespace
+		tags = append(tags, r.ParameterTags[openapi.InPath]...)
+	case *openapi31.Reflector:
+		namespace = r.TagNamespace
+		tags = append(tags, r.ParameterTags[openapi.InPath]...)
+	}
+
+	return internal.NamespacedTags(namespace, tags...)
+}
+
+// undeclaredPathParams returns structure with string fields of route parameters that are missing
+// in request structures, or nil if all parameters are declared.
+func undeclaredPathParams(oc openapi.OperationContext, route string, tags []string) interface{} {
+	declared := map[string]bool{}
+
+	for _, cu := range oc.Request() {
+		pathParams(reflect.TypeOf(cu.Structure), tags, declared)
+	}
+
+	_, _, names, err := openapi.SanitizeMethodPath(oc.Method(), route)
+	if err != nil {
+		return nil
+	}
+
+	var fields []reflect.StructField
+
+	for _, name := range names {
+		if declared[name] {
+			continue
+		}
+
+		declared[name] = true
+
+		fields = append(fields, reflect.StructField{
+			Name: "P" + strconv.Itoa(len(fields)),
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(`path:"` + name + `"`),
+		})
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Elem().Interface()
+}
+
+// pathParams collects names of path parameters of structure and its embedded structures,
+// name is taken from the first of tags that field has.
+func pathParams(t reflect.Type, tags []string, names map[string]bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if name, ok := lookupTag(f.Tag, tags); ok {
+			names[strings.Split(name, ",")[0]] = true
+
+			continue
+		}
+
+		if f.Anonymous {
+			pathParams(f.Type, tags, names)
+		}
+	}
+}
+
+func lookupTag(tag reflect.StructTag, names []string) (string, bool) {
+	for _, name := range names {
+		if v, ok := tag.Lookup(name); ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
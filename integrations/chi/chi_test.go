@@ -0,0 +1,147 @@
+package chi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	gochi "github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/integrations/chi"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type getPet struct {
+	http.HandlerFunc
+}
+
+func (getPet) SetupOperation(oc openapi.OperationContext) error {
+	oc.SetID("getPet")
+	oc.AddReqStructure(struct {
+		ID int `path:"id"`
+	}{})
+	oc.AddRespStructure(struct {
+		Name string `json:"name"`
+	}{})
+
+	return nil
+}
+
+type failing struct {
+	http.HandlerFunc
+}
+
+func (failing) SetupOperation(_ openapi.OperationContext) error {
+	return errors.New("failed")
+}
+
+func TestCollector_Collect(t *testing.T) {
+	r := openapi31.NewReflector()
+	c := chi.NewCollector(r)
+	nop := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+	mw := func(next http.Handler) http.Handler { return next }
+
+	router := gochi.NewRouter()
+	router.Route("/pets", func(pets gochi.Router) {
+		pets.With(mw).Method(http.MethodGet, "/{id:[0-9]+}", getPet{})
+		pets.Delete("/{id}/photos/{photoID}", nop)
+	})
+	router.Mount("/static", func() http.Handler {
+		static := gochi.NewRouter()
+		static.Get("/*", nop)
+		static.Connect("/*", nop)
+
+		return static
+	}())
+
+	require.NoError(t, c.Collect(router))
+
+	failingRouter := gochi.NewRouter()
+	failingRouter.Method(http.MethodPost, "/pets", failing{})
+
+	assert.EqualError(t, chi.NewCollector(openapi31.NewReflector()).Collect(failingRouter),
+		"setup operation POST /pets: failed")
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+	    "/pets/{id}":{
+	      "get":{
+	        "operationId":"getPet",
+	        "parameters":[
+	          {
+	            "name":"id","in":"path","required":true,
+	            "schema":{"pattern":"^[0-9]+$","type":"integer"}
+	          }
+	        ],
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "content":{
+	              "application/json":{
+	                "schema":{"properties":{"name":{"type":"string"}},"type":"object"}
+	              }
+	            }
+	          }
+	        }
+	      }
+	    },
+	    "/pets/{id}/photos/{photoID}":{
+	      "delete":{
+	        "parameters":[
+	          {"name":"id","in":"path","required":true,"schema":{"type":"string"}},
+	          {"name":"photoID","in":"path","required":true,"schema":{"type":"string"}}
+	        ],
+	        "responses":{"204":{"description":"No Content"}}
+	      }
+	    },
+	    "/static/{wildcard}":{
+	      "get":{
+	        "parameters":[
+	          {
+	            "name":"wildcard","in":"path","required":true,"schema":{"type":"string"},
+	            "x-wildcard":true
+	          }
+	        ],
+	        "responses":{"204":{"description":"No Content"}}
+	      }
+	    }
+	  }
+	}`, r.SpecSchema())
+}
+
+type getArticle struct {
+	http.HandlerFunc
+}
+
+func (getArticle) SetupOperation(oc openapi.OperationContext) error {
+	oc.AddReqStructure(struct {
+		ID   int    `oas-path:"id" path:"articleID"`
+		Slug string `uri:"slug"`
+	}{})
+
+	return nil
+}
+
+func TestCollector_Collect_tags(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.TagNamespace = "oas"
+	r.ParameterTags = map[openapi.In][]string{openapi.InPath: {"uri"}}
+
+	router := gochi.NewRouter()
+	router.Method(http.MethodGet, "/articles/{id}/{slug}", getArticle{})
+
+	require.NoError(t, chi.NewCollector(r).Collect(router))
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{"name":"id","in":"path","required":true,"schema":{"type":"integer"}},
+		{"name":"slug","in":"path","required":true,"schema":{"type":"string"}}
+	  ],
+	  "responses":{"204":{"description":"No Content"}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/articles/{id}/{slug}"].MapOfOperationValues["get"])
+}
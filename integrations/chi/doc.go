@@ -0,0 +1,2 @@
+// Package chi registers operations of routes of go-chi router in reflector.
+package chi
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// Base64Bytes reflects []byte fields tagged with `contentEncoding:"base64"` as strings.
+//
+// Encoding is described with contentEncoding in OpenAPI 3.1, or with format "byte" in OpenAPI 3.0 (asFormat).
+func Base64Bytes(asFormat bool) func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		s := params.PropertySchema
+
+		if !params.Processed || s.ContentEncoding == nil || *s.ContentEncoding != "base64" {
+			return nil
+		}
+
+		t := params.Field.Type
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8 {
+			return nil
+		}
+
+		nullable := s.HasType(jsonschema.Null)
+
+		s.Type = nil
+		s.Items = nil
+		s.AddType(jsonschema.String)
+
+		if nullable {
+			s.AddType(jsonschema.Null)
+		}
+
+		if asFormat {
+			s.ContentEncoding = nil
+			s.ContentMediaType = nil
+			s.WithFormat("byte")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+const componentsPrefix = "#/components/"
+
+// ReachableComponents returns local references (e.g. "#/components/schemas/Foo") of components
+// that are reachable from spec outside of components, directly or through other components.
+//
+// Security schemes are referenced by names in security requirements, they are not tracked.
+func ReachableComponents(spec interface{}) (map[string]bool, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	components, _ := doc["components"].(map[string]interface{}) //nolint:errcheck // Missing components are fine.
+	delete(doc, "components")
+
+	reachable := map[string]bool{}
+
+	var queue []string
+
+	collectRefs(doc, func(ref string) {
+		if !reachable[ref] {
+			reachable[ref] = true
+			queue = append(queue, ref)
+		}
+	})
+
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+
+		kind, name, ok := splitComponentRef(ref)
+		if !ok {
+			continue
+		}
+
+		items, _ := components[kind].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+		collectRefs(items[name], func(ref string) {
+			if !reachable[ref] {
+				reachable[ref] = true
+				queue = append(queue, ref)
+			}
+		})
+	}
+
+	return reachable, nil
+}
+
+// OrphanComponents returns sorted local references of components that are not reachable from spec
+// outside of components, security schemes are not accounted.
+func OrphanComponents(spec interface{}) ([]string, error) {
+	reachable, err := ReachableComponents(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	components, _ := doc["components"].(map[string]interface{}) //nolint:errcheck // Missing components are fine.
+
+	var orphans []string
+
+	for _, kind := range SortedKeys(components) {
+		if kind == "securitySchemes" {
+			continue
+		}
+
+		for _, name := range SortedKeys(components[kind]) {
+			if ref := componentsPrefix + kind + "/" + openapi.PointerToken(name); !reachable[ref] {
+				orphans = append(orphans, ref)
+			}
+		}
+	}
+
+	return orphans, nil
+}
+
+// RemoveComponents deletes components by local references from Components structure of openapi3 or openapi31.
+//
+// Component maps are found by JSON field names, openapi3 keeps maps in a single field of a structure.
+func RemoveComponents(components interface{}, refs []string) {
+	v := reflect.Indirect(reflect.ValueOf(components))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, ref := range refs {
+		kind, name, ok := splitComponentRef(ref)
+		if !ok {
+			continue
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0] != kind {
+				continue
+			}
+
+			f := v.Field(i)
+			m := reflect.Indirect(f)
+			wrapper := m
+
+			if m.IsValid() && m.Kind() == reflect.Struct && m.NumField() > 0 {
+				m = m.Field(0)
+			}
+
+			if m.IsValid() && m.Kind() == reflect.Map && !m.IsNil() {
+				m.SetMapIndex(reflect.ValueOf(name), reflect.Value{})
+
+				// Emptied wrapper of map is removed, so that it is not marshaled as empty object.
+				if m.Len() == 0 && f.Kind() == reflect.Ptr && wrapper.Kind() == reflect.Struct && emptyFields(wrapper, 1) {
+					f.Set(reflect.Zero(f.Type()))
+				}
+			}
+		}
+	}
+}
+
+// emptyFields checks if fields of structure starting from index are zero.
+func emptyFields(v reflect.Value, from int) bool {
+	for i := from; i < v.NumField(); i++ {
+		if !v.Field(i).IsZero() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UnreachableComponents returns sorted references that were reachable before, but not after.
+func UnreachableComponents(before, after map[string]bool) []string {
+	names := map[string]bool{}
+
+	for ref := range before {
+		if !after[ref] {
+			names[ref] = true
+		}
+	}
+
+	return sortedNames(names)
+}
+
+func splitComponentRef(ref string) (kind, name string, ok bool) {
+	if !strings.HasPrefix(ref, componentsPrefix) {
+		return "", "", false
+	}
+
+	parts := strings.Split(ref[len(componentsPrefix):], "/")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	return openapi.UnescapePointerToken(parts[0]), openapi.UnescapePointerToken(parts[1]), true
+}
+
+func collectRefs(v interface{}, found func(ref string)) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, item := range x {
+			if ref, ok := item.(string); ok && k == "$ref" && strings.HasPrefix(ref, componentsPrefix) {
+				found(ref)
+
+				continue
+			}
+
+			collectRefs(item, found)
+		}
+	case []interface{}:
+		for _, item := range x {
+			collectRefs(item, found)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+)
+
+var typeOfConditionalRequirer = reflect.TypeOf((*openapi.ConditionalRequirer)(nil)).Elem()
+
+// Conditions reflects openapi.ConditionalRequirer with if/then/else and `dependentRequired` field tags.
+func Conditions() func(rc *jsonschema.ReflectContext) {
+	interceptSchema := jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || !params.Value.IsValid() {
+			return false, nil
+		}
+
+		t := params.Value.Type()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		var cr openapi.ConditionalRequirer
+
+		switch {
+		case t.Implements(typeOfConditionalRequirer):
+			cr = reflect.Zero(t).Interface().(openapi.ConditionalRequirer) //nolint:errcheck
+		case reflect.PtrTo(t).Implements(typeOfConditionalRequirer):
+			cr = reflect.New(t).Interface().(openapi.ConditionalRequirer) //nolint:errcheck
+		default:
+			return false, nil
+		}
+
+		conditions := cr.ConditionalRequired()
+		if len(conditions) == 1 {
+			applyCondition(params.Schema, conditions[0])
+
+			return false, nil
+		}
+
+		for _, c := range conditions {
+			s := jsonschema.Schema{}
+			applyCondition(&s, c)
+			params.Schema.AllOf = append(params.Schema.AllOf, s.ToSchemaOrBool())
+		}
+
+		return false, nil
+	})
+
+	interceptProp := jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		tag := params.Field.Tag.Get("dependentRequired")
+		if !params.Processed || tag == "" {
+			return nil
+		}
+
+		dependentRequired, ok := params.ParentSchema.ExtraProperties["dependentRequired"].(map[string][]string)
+		if !ok {
+			dependentRequired = map[string][]string{}
+			params.ParentSchema.WithExtraPropertiesItem("dependentRequired", dependentRequired)
+		}
+
+		for _, name := range strings.Split(tag, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				dependentRequired[params.Name] = append(dependentRequired[params.Name], name)
+			}
+		}
+
+		return nil
+	})
+
+	return func(rc *jsonschema.ReflectContext) {
+		interceptSchema(rc)
+		interceptProp(rc)
+	}
+}
+
+func applyCondition(schema *jsonschema.Schema, c openapi.RequiredIf) {
+	cond := jsonschema.Schema{}
+	names := make([]string, 0, len(c.If))
+
+	for name, value := range c.If {
+		cond.WithPropertiesItem(name, (&jsonschema.Schema{}).WithConst(value).ToSchemaOrBool())
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	cond.Required = names
+
+	schema.WithIf(cond.ToSchemaOrBool())
+
+	if len(c.Then) > 0 {
+		schema.WithThen((&jsonschema.Schema{Required: c.Then}).ToSchemaOrBool())
+	}
+
+	if len(c.Else) > 0 {
+		schema.WithElse((&jsonschema.Schema{Required: c.Else}).ToSchemaOrBool())
+	}
+}
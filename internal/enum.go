@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/refl"
+)
+
+var typeOfEnumDescriber = reflect.TypeOf((*openapi.EnumDescriber)(nil)).Elem()
+
+// EnumDescriptions adds descriptions of enum values as x-enum-descriptions or as oneOf.
+type EnumDescriptions struct {
+	byType map[reflect.Type]map[interface{}]string
+}
+
+// Add registers descriptions of enum values for type of sample.
+func (ed *EnumDescriptions) Add(sample interface{}, descriptions map[interface{}]string) {
+	if ed.byType == nil {
+		ed.byType = make(map[reflect.Type]map[interface{}]string)
+	}
+
+	ed.byType[refl.DeepIndirect(reflect.TypeOf(sample))] = descriptions
+}
+
+// Option returns reflection hook to be added to default options of jsonschema.Reflector.
+//
+// Values are described with oneOf of const schemas instead of x-enum-descriptions if oneOf returns true.
+func (ed *EnumDescriptions) Option(oneOf func() bool) func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || len(params.Schema.Enum) == 0 || params.Schema.ReflectType == nil {
+			return false, nil
+		}
+
+		descriptions := ed.descriptions(refl.DeepIndirect(params.Schema.ReflectType))
+		if len(descriptions) == 0 {
+			return false, nil
+		}
+
+		// Enum values and description keys can be of different types, e.g. int and a named int.
+		byValue := make(map[string]string, len(descriptions))
+		for v, d := range descriptions {
+			byValue[fmt.Sprint(v)] = d
+		}
+
+		s := params.Schema
+
+		if oneOf() {
+			for _, v := range s.Enum {
+				item := jsonschema.Schema{}
+				item.WithConst(v)
+
+				if d, ok := byValue[fmt.Sprint(v)]; ok {
+					item.WithDescription(d)
+				}
+
+				s.OneOf = append(s.OneOf, item.ToSchemaOrBool())
+			}
+
+			s.Enum = nil
+
+			return false, nil
+		}
+
+		enumDescriptions := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			enumDescriptions = append(enumDescriptions, byValue[fmt.Sprint(v)])
+		}
+
+		s.WithExtraPropertiesItem("x-enum-descriptions", enumDescriptions)
+
+		return false, nil
+	})
+}
+
+func (ed *EnumDescriptions) descriptions(t reflect.Type) map[interface{}]string {
+	if d, ok := ed.byType[t]; ok {
+		return d
+	}
+
+	switch {
+	case t.Implements(typeOfEnumDescriber):
+		return reflect.Zero(t).Interface().(openapi.EnumDescriber).EnumDescriptions() //nolint:errcheck
+	case reflect.PtrTo(t).Implements(typeOfEnumDescriber):
+		return reflect.New(t).Interface().(openapi.EnumDescriber).EnumDescriptions() //nolint:errcheck
+	}
+
+	return nil
+}
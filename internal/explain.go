@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// constraintKeywords are printed by ExplainSchema in this order.
+var constraintKeywords = []string{
+	"format", "pattern", "minLength", "maxLength", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"multipleOf", "minItems", "maxItems", "uniqueItems", "minProperties", "maxProperties", "const", "default",
+	"nullable", "readOnly", "writeOnly", "deprecated",
+}
+
+// ExplainSchema describes schema component of spec with resolved references as indented text,
+// followed by JSON pointers of places where component is referenced.
+func ExplainSchema(spec interface{}, name string) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", err
+	}
+
+	components, _ := doc["components"].(map[string]interface{})  //nolint:errcheck // Missing components are fine.
+	schemas, _ := components["schemas"].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+	schema, ok := schemas[name]
+	if !ok {
+		return "", fmt.Errorf("schema not found: %s", name)
+	}
+
+	e := explainer{schemas: schemas, stack: map[string]bool{name: true}}
+
+	e.line(0, name+": "+e.summary(schema))
+	e.children(schema, 1)
+
+	var usages []string
+
+	collectRefPointers("#", doc, func(pointer, ref string) {
+		if ref == componentsSchemas+openapi.PointerToken(name) {
+			usages = append(usages, pointer)
+		}
+	})
+
+	sort.Strings(usages)
+
+	if len(usages) == 0 {
+		e.line(0, "Not referenced.")
+	} else {
+		e.line(0, "Referenced by:")
+
+		for _, u := range usages {
+			e.line(1, u)
+		}
+	}
+
+	return e.b.String(), nil
+}
+
+type explainer struct {
+	schemas map[string]interface{}
+	stack   map[string]bool
+	b       strings.Builder
+}
+
+func (e *explainer) line(depth int, s string) {
+	e.b.WriteString(strings.Repeat("  ", depth))
+	e.b.WriteString(s)
+	e.b.WriteString("\n")
+}
+
+// resolve returns schema with reference replaced by component and name of component.
+func (e *explainer) resolve(v interface{}) (schema map[string]interface{}, name string) {
+	schema, _ = v.(map[string]interface{}) //nolint:errcheck // Boolean schemas have no details.
+
+	ref, ok := schema["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, componentsSchemas) {
+		return schema, ""
+	}
+
+	_, name, _ = splitComponentRef(ref)
+	s, _ := e.schemas[name].(map[string]interface{}) //nolint:errcheck // Missing component has no details.
+
+	return s, name
+}
+
+func (e *explainer) typeName(s map[string]interface{}, name string) string {
+	var t string
+
+	switch v := s["type"].(type) {
+	case string:
+		t = v
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			types = append(types, fmt.Sprint(item))
+		}
+
+		t = strings.Join(types, "|")
+	default:
+		if _, ok := s["properties"]; ok {
+			t = "object"
+		} else {
+			t = "any"
+		}
+	}
+
+	if items, ok := s["items"]; ok {
+		items, itemsName := e.resolve(items)
+		t = strings.Replace(t, "array", "array of "+e.typeName(items, itemsName), 1)
+	}
+
+	if name != "" {
+		t += " (" + name + ")"
+	}
+
+	return t
+}
+
+// summary describes type and constraints of schema in one line.
+func (e *explainer) summary(v interface{}) string {
+	s, name := e.resolve(v)
+	parts := []string{e.typeName(s, name)}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		values := make([]string, 0, len(enum))
+		for _, item := range enum {
+			values = append(values, fmt.Sprint(item))
+		}
+
+		parts = append(parts, "enum: "+strings.Join(values, ", "))
+	}
+
+	for _, kw := range constraintKeywords {
+		if c, ok := s[kw]; ok {
+			parts = append(parts, fmt.Sprintf("%s: %v", kw, c))
+		}
+	}
+
+	res := strings.Join(parts, ", ")
+
+	if d, ok := s["description"].(string); ok && d != "" {
+		res += " - " + d
+	}
+
+	return res
+}
+
+// children describes properties, items and subschemas of schema.
+func (e *explainer) children(v interface{}, depth int) {
+	s, name := e.resolve(v)
+	if name != "" {
+		if e.stack[name] {
+			e.line(depth, "(recursive "+name+")")
+
+			return
+		}
+
+		e.stack[name] = true
+		defer delete(e.stack, name)
+	}
+
+	required := map[string]bool{}
+
+	if req, ok := s["required"].([]interface{}); ok {
+		for _, r := range req {
+			required[fmt.Sprint(r)] = true
+		}
+	}
+
+	props, _ := s["properties"].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+	for _, prop := range SortedKeys(props) {
+		summary := e.summary(props[prop])
+		if required[prop] {
+			summary = "required " + summary
+		}
+
+		e.line(depth, prop+": "+summary)
+		e.children(props[prop], depth+1)
+	}
+
+	if items, ok := s["items"]; ok {
+		e.children(items, depth)
+	}
+
+	if ap, ok := s["additionalProperties"].(map[string]interface{}); ok {
+		e.line(depth, "additional properties: "+e.summary(ap))
+		e.children(ap, depth+1)
+	}
+
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		variants, ok := s[kw].([]interface{})
+		if !ok {
+			continue
+		}
+
+		e.line(depth, kw+":")
+
+		for _, variant := range variants {
+			e.line(depth+1, "- "+e.summary(variant))
+			e.children(variant, depth+2)
+		}
+	}
+}
+
+func collectRefPointers(pointer string, v interface{}, found func(pointer, ref string)) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := x["$ref"].(string); ok {
+			found(pointer, ref)
+		}
+
+		for _, k := range SortedKeys(x) {
+			collectRefPointers(pointer+"/"+openapi.PointerToken(k), x[k], found)
+		}
+	case []interface{}:
+		for i, item := range x {
+			collectRefPointers(fmt.Sprintf("%s/%d", pointer, i), item, found)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+var extensionKey = regexp.MustCompile(`(?:^|,)\s*([A-Za-z0-9_.\-]+)=`)
+
+// VendorExtensions adds x- properties declared with `x:"go-name=UserID,faker=uuid"` field tags.
+//
+// Keys are prefixed with "x-" if necessary, values are decoded as JSON if possible and used as strings otherwise.
+// Tag of property applies to property schema, tag of `_` field applies to structure schema.
+func VendorExtensions() func(rc *jsonschema.ReflectContext) {
+	interceptProp := jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if params.Processed {
+			addExtensions(params.PropertySchema, params.Field.Tag.Get("x"))
+		}
+
+		return nil
+	})
+
+	interceptSchema := jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || !params.Value.IsValid() {
+			return false, nil
+		}
+
+		t := refl.DeepIndirect(params.Value.Type())
+		if t.Kind() != reflect.Struct {
+			return false, nil
+		}
+
+		if f, ok := t.FieldByName("_"); ok {
+			addExtensions(params.Schema, f.Tag.Get("x"))
+		}
+
+		return false, nil
+	})
+
+	return func(rc *jsonschema.ReflectContext) {
+		interceptProp(rc)
+		interceptSchema(rc)
+	}
+}
+
+func addExtensions(s *jsonschema.Schema, tag string) {
+	if tag == "" {
+		return
+	}
+
+	matches := extensionKey.FindAllStringSubmatchIndex(tag, -1)
+
+	for i, m := range matches {
+		end := len(tag)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+
+		key := tag[m[2]:m[3]]
+		if !strings.HasPrefix(key, "x-") {
+			key = "x-" + key
+		}
+
+		s.WithExtraPropertiesItem(key, TagValue(tag[m[1]:end]))
+	}
+}
+
+// TagValue decodes JSON value of a field tag, raw string is returned if it is not a valid JSON.
+func TagValue(raw string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+
+	return value
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// FieldFilters keeps predicates that exclude struct fields from reflection.
+type FieldFilters struct {
+	filters []func(field reflect.StructField) bool
+}
+
+// Add registers predicate, fields for which it returns true are excluded.
+func (ff *FieldFilters) Add(exclude func(field reflect.StructField) bool) {
+	ff.filters = append(ff.filters, exclude)
+}
+
+// Option returns reflection hook that skips excluded fields of bodies, parameters and headers.
+func (ff *FieldFilters) Option() func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if params.Processed || len(ff.filters) == 0 {
+			return nil
+		}
+
+		for _, exclude := range ff.filters {
+			if !exclude(params.Field) {
+				continue
+			}
+
+			return skipProperty(params)
+		}
+
+		return nil
+	})
+}
+
+// skipProperty removes property from required properties of parent, as they are registered before interception,
+// and returns jsonschema.ErrSkipProperty.
+func skipProperty(params jsonschema.InterceptPropParams) error {
+	if p := params.ParentSchema; p != nil {
+		for i, name := range p.Required {
+			if name == params.Name {
+				p.Required = append(p.Required[:i:i], p.Required[i+1:]...)
+
+				break
+			}
+		}
+	}
+
+	return jsonschema.ErrSkipProperty
+}
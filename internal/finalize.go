@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/report"
+)
+
+// Rules of registration checks.
+const (
+	RuleOrphanTag                = "orphan-tag"
+	RuleUnusedSecurityScheme     = "unused-security-scheme"
+	RuleUndeclaredSecurityScheme = "undeclared-security-scheme"
+	RuleDuplicateOperationID     = "duplicate-operation-id"
+	RuleComponentConflict        = "component-conflict"
+)
+
+// RegisteredOperation describes ID, tags and security requirements of an operation.
+type RegisteredOperation struct {
+	// Pointer is a JSON pointer to operation, e.g. "/paths/~1users/get".
+	Pointer  string
+	ID       string
+	Tags     []string
+	Security []map[string][]string
+}
+
+// OperationPointer returns JSON pointer to operation of a path.
+func OperationPointer(path, method string) string {
+	return "/paths/" + openapi.PointerToken(path) + "/" + method
+}
+
+// CheckRegistrations reports declared tags without operations, security schemes that are never required,
+// security requirements of undeclared schemes and operation IDs used more than once.
+func CheckRegistrations(
+	tags []string,
+	securitySchemes map[string]bool,
+	security []map[string][]string,
+	operations []RegisteredOperation,
+) []report.Finding {
+	var findings []report.Finding
+
+	usedTags := map[string]bool{}
+	usedSchemes := map[string]bool{}
+
+	checkSecurity := func(pointer string, security []map[string][]string) {
+		for i, requirement := range security {
+			names := make(map[string]bool, len(requirement))
+			for name := range requirement {
+				names[name] = true
+			}
+
+			for _, name := range sortedNames(names) {
+				usedSchemes[name] = true
+
+				if !securitySchemes[name] {
+					findings = append(findings, report.Finding{
+						Rule:     RuleUndeclaredSecurityScheme,
+						Severity: report.SeverityError,
+						Message:  "security scheme " + strconv.Quote(name) + " is not declared in components",
+						Pointer:  pointer + "/security/" + strconv.Itoa(i),
+					})
+				}
+			}
+		}
+	}
+
+	checkSecurity("", security)
+
+	ids := map[string]string{}
+
+	for _, op := range operations {
+		if op.ID != "" {
+			if first, ok := ids[op.ID]; ok {
+				findings = append(findings, report.Finding{
+					Rule:     RuleDuplicateOperationID,
+					Severity: report.SeverityError,
+					Message:  "operation ID " + strconv.Quote(op.ID) + " is already used by " + first,
+					Pointer:  op.Pointer + "/operationId",
+				})
+			} else {
+				ids[op.ID] = op.Pointer
+			}
+		}
+
+		for _, tag := range op.Tags {
+			usedTags[tag] = true
+		}
+
+		checkSecurity(op.Pointer, op.Security)
+	}
+
+	for i, tag := range tags {
+		if !usedTags[tag] {
+			findings = append(findings, report.Finding{
+				Rule:     RuleOrphanTag,
+				Severity: report.SeverityWarning,
+				Message:  "tag " + strconv.Quote(tag) + " is not used by any operation",
+				Pointer:  "/tags/" + strconv.Itoa(i),
+			})
+		}
+	}
+
+	for _, name := range sortedNames(securitySchemes) {
+		if !usedSchemes[name] {
+			findings = append(findings, report.Finding{
+				Rule:     RuleUnusedSecurityScheme,
+				Severity: report.SeverityWarning,
+				Message:  "security scheme " + strconv.Quote(name) + " is not required by any operation",
+				Pointer:  "/components/securitySchemes/" + openapi.PointerToken(name),
+			})
+		}
+	}
+
+	return findings
+}
+
+// CheckComponentConflicts reports names of shared schema components that were added with different schemas.
+func CheckComponentConflicts(names []string) []report.Finding {
+	findings := make([]report.Finding, 0, len(names))
+
+	for _, name := range names {
+		findings = append(findings, report.Finding{
+			Rule:     RuleComponentConflict,
+			Severity: report.SeverityError,
+			Message:  "schema component " + strconv.Quote(name) + " conflicts with a different schema of the same name",
+			Pointer:  "/components/schemas/" + openapi.PointerToken(name),
+		})
+	}
+
+	return findings
+}
+
+func sortedNames(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
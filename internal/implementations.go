@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// Implementations keeps implementations of interfaces to resolve embedded interfaces of structures.
+type Implementations struct {
+	impls map[reflect.Type]interface{}
+}
+
+// Add registers implementation sample of interface, iface is a pointer to interface, e.g. new(Auditor).
+func (im *Implementations) Add(iface, impl interface{}) {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("pointer to interface expected, %T received", iface))
+	}
+
+	if im.impls == nil {
+		im.impls = make(map[reflect.Type]interface{})
+	}
+
+	im.impls[t.Elem()] = impl
+}
+
+// Option returns reflection hook that adds implementations of embedded interfaces to allOf of structure schema.
+//
+// Embedded interfaces without field tags and with registered implementations are resolved,
+// implementations are referenced if definitions are collected, otherwise they are inlined.
+func (im *Implementations) Option(r *jsonschema.Reflector) func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || len(im.impls) == 0 || !params.Value.IsValid() {
+			return false, nil
+		}
+
+		t := params.Value.Type()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return false, nil
+		}
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+
+			impl, ok := im.impls[f.Type]
+			if !ok || !f.Anonymous || f.Tag.Get(params.Context.PropertyNameTag) != "" {
+				continue
+			}
+
+			s, err := r.Reflect(impl, func(rc *jsonschema.ReflectContext) {
+				rc.Context = params.Context.Context
+				rc.PropertyNameTag = params.Context.PropertyNameTag
+				rc.DefinitionsPrefix = params.Context.DefinitionsPrefix
+				rc.CollectDefinitions = params.Context.CollectDefinitions
+				rc.RootRef = rc.CollectDefinitions != nil
+				rc.InlineRefs = rc.CollectDefinitions == nil
+			})
+			if err != nil {
+				return false, fmt.Errorf("implementation of embedded %s: %w", f.Type, err)
+			}
+
+			s.ReflectType = nil
+			params.Schema.AllOf = append(params.Schema.AllOf, s.ToSchemaOrBool())
+		}
+
+		return false, nil
+	})
+}
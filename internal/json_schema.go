@@ -20,6 +20,8 @@ const (
 	tagFormData = "formData"
 	tagForm     = "form"
 	tagHeader   = "header"
+	tagMsgpack  = "msgpack"
+	tagCBOR     = "cbor"
 
 	componentsSchemas = "#/components/schemas/"
 )
@@ -57,37 +59,42 @@ func ReflectRequestBody(
 		}
 	}
 
-	hasTaggedFields := refl.HasTaggedFields(input, tag)
-	for _, t := range additionalTags {
-		if hasTaggedFields {
-			break
+	// Checking for default options that allow tag-less JSON and namespace of tags.
+	isProcessWithoutTags := false
+	ns := ""
+
+	_, err = r.Reflect("", func(rc *jsonschema.ReflectContext) {
+		isProcessWithoutTags = rc.ProcessWithoutTags
+		ns = tagNamespaceOf(rc)
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("BUG: %w", err)
+	}
+
+	hasTaggedFields := hasAnyTaggedFields(input, NamespacedTags(ns, append([]string{tag}, additionalTags...)...)...)
+
+	// Reflection hooks can name untagged fields, e.g. openapi.PropertyNames.
+	if !hasTaggedFields && tag == tagJSON && !isProcessWithoutTags && !refl.IsSliceOrMap(input) {
+		s, err := r.Reflect(input, jsonschema.PropertyNameTag(tag, additionalTags...), sanitizeDefName)
+		if err != nil {
+			return nil, false, err
 		}
 
-		hasTaggedFields = refl.HasTaggedFields(input, t)
+		hasTaggedFields = len(s.Properties) > 0
 	}
 
 	// Form data can not have map or array as body.
-	if !hasTaggedFields && len(mapping) == 0 && tag != tagJSON {
+	if !hasTaggedFields && len(mapping) == 0 && tag != tagJSON && tag != tagMsgpack && tag != tagCBOR {
 		return nil, false, nil
 	}
 
 	// If `formData` is defined on a request body `json` is ignored.
 	if tag == tagJSON &&
-		(refl.HasTaggedFields(input, tagFormData) || refl.HasTaggedFields(input, tagForm)) &&
+		hasAnyTaggedFields(input, NamespacedTags(ns, tagFormData, tagForm)...) &&
 		!forceJSONRequestBody {
 		return nil, false, nil
 	}
 
-	// Checking for default options that allow tag-less JSON.
-	isProcessWithoutTags := false
-
-	_, err = r.Reflect("", func(rc *jsonschema.ReflectContext) {
-		isProcessWithoutTags = rc.ProcessWithoutTags
-	})
-	if err != nil {
-		return nil, false, fmt.Errorf("BUG: %w", err)
-	}
-
 	// JSON can be a map or array without field tags.
 	if !hasTaggedFields && len(mapping) == 0 && !refl.IsSliceOrMap(input) &&
 		refl.FindEmbeddedSliceOrMap(input) == nil && !isProcessWithoutTags {
@@ -105,12 +112,13 @@ func ReflectRequestBody(
 			if tag != tagJSON {
 				v := reflect.New(t).Interface()
 
-				if refl.HasTaggedFields(v, tag) {
+				if hasAnyTaggedFields(v, NamespacedTags(ns, tag)...) {
 					return definitionPrefix + defaultDefName
 				}
 
 				for _, at := range additionalTags {
-					if refl.HasTaggedFields(v, at) {
+					// Fallback to JSON tags reuses JSON definitions.
+					if at != tagJSON && hasAnyTaggedFields(v, NamespacedTags(ns, at)...) {
 						return definitionPrefix + defaultDefName
 					}
 				}
@@ -187,16 +195,56 @@ func ReflectJSONResponse(
 	r *jsonschema.Reflector,
 	output interface{},
 	reflOptions ...func(rc *jsonschema.ReflectContext),
+) (schema *jsonschema.Schema, err error) {
+	return reflectResponse(r, output, nil, reflOptions...)
+}
+
+// ReflectResponse reflects JSON schema of response with content type.
+//
+// Structured binary responses, e.g. MessagePack or CBOR, use field tags of the format
+// with fallback to json tags (see BinaryBodyTag), definitions of structures with tags
+// of the format are prefixed with title of tag, e.g. "Msgpack".
+func ReflectResponse(
+	r *jsonschema.Reflector,
+	output interface{},
+	contentType string,
+	reflOptions ...func(rc *jsonschema.ReflectContext),
+) (schema *jsonschema.Schema, err error) {
+	tag := BinaryBodyTag(contentType)
+	if tag == "" {
+		return ReflectJSONResponse(r, output, reflOptions...)
+	}
+
+	return reflectResponse(r, output, []func(rc *jsonschema.ReflectContext){
+		jsonschema.PropertyNameTag(tag, tagJSON),
+		jsonschema.InterceptDefName(func(t reflect.Type, defaultDefName string) string {
+			if refl.HasTaggedFields(reflect.New(t).Interface(), tag) {
+				return strings.ToUpper(tag[:1]) + tag[1:] + defaultDefName
+			}
+
+			return defaultDefName
+		}),
+	}, reflOptions...)
+}
+
+func reflectResponse(
+	r *jsonschema.Reflector,
+	output interface{},
+	tagOptions []func(rc *jsonschema.ReflectContext),
+	reflOptions ...func(rc *jsonschema.ReflectContext),
 ) (schema *jsonschema.Schema, err error) {
 	if output == nil {
 		return nil, nil
 	}
 
+	output = sequenceAsSlice(output)
+
 	// Check if output structure exposes meaningful schema.
-	if hasJSONBody, err := hasJSONBody(r, output); err == nil && !hasJSONBody {
+	if hasJSONBody, err := hasJSONBody(r, output, tagOptions...); err == nil && !hasJSONBody {
 		return nil, nil
 	}
 
+	reflOptions = append(reflOptions, tagOptions...)
 	reflOptions = append(reflOptions,
 		jsonschema.RootRef,
 		sanitizeDefName,
@@ -210,8 +258,8 @@ func ReflectJSONResponse(
 	return &sch, nil
 }
 
-func hasJSONBody(r *jsonschema.Reflector, output interface{}) (bool, error) {
-	schema, err := r.Reflect(output, sanitizeDefName)
+func hasJSONBody(r *jsonschema.Reflector, output interface{}, tagOptions ...func(rc *jsonschema.ReflectContext)) (bool, error) {
+	schema, err := r.Reflect(output, append(tagOptions, sanitizeDefName)...)
 	if err != nil {
 		return false, err
 	}
@@ -250,6 +298,8 @@ func ReflectResponseHeader(
 		return jsonschema.Schema{}, nil
 	}
 
+	output = sequenceAsSlice(output)
+
 	return r.Reflect(output,
 		func(rc *jsonschema.ReflectContext) {
 			rc.ProcessWithoutTags = false
@@ -297,3 +347,46 @@ func ReflectParametersIn(
 		jsonschema.InterceptProp(interceptProp),
 	)
 }
+
+// IsRequired checks if property is listed as required in parent schema.
+func IsRequired(params jsonschema.InterceptPropParams) bool {
+	if params.ParentSchema == nil {
+		return false
+	}
+
+	for _, name := range params.ParentSchema.Required {
+		if name == params.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sequenceAsSlice replaces iterator (iter.Seq[T]) or channel of T with a slice of T.
+func sequenceAsSlice(v interface{}) interface{} {
+	t := reflect.TypeOf(v)
+
+	var elem reflect.Type
+
+	switch t.Kind() { //nolint:exhaustive // Other kinds are not sequences.
+	case reflect.Chan:
+		elem = t.Elem()
+	case reflect.Func:
+		// Iterator is func(yield func(T) bool).
+		if t.NumIn() == 1 && t.NumOut() == 0 {
+			yield := t.In(0)
+
+			if yield.Kind() == reflect.Func && yield.NumIn() == 1 && yield.NumOut() == 1 &&
+				yield.Out(0).Kind() == reflect.Bool {
+				elem = yield.In(0)
+			}
+		}
+	}
+
+	if elem == nil {
+		return v
+	}
+
+	return reflect.Zero(reflect.SliceOf(elem)).Interface()
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// MapKeyNames adds propertyNames schema to maps with keys of a formatted or patterned type (e.g. UUID).
+func MapKeyNames(r *jsonschema.Reflector) func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || !params.Value.IsValid() || params.Schema.PropertyNames != nil {
+			return false, nil
+		}
+
+		t := params.Value.Type()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Map || t.Key().PkgPath() == "" {
+			return false, nil
+		}
+
+		keySchema, err := r.Reflect(reflect.Zero(t.Key()).Interface(), jsonschema.InlineRefs)
+		if err != nil {
+			return false, err
+		}
+
+		if keySchema.Format == nil && keySchema.Pattern == nil {
+			return false, nil
+		}
+
+		keySchema.ReflectType = nil
+		params.Schema.WithPropertyNames(keySchema.ToSchemaOrBool())
+
+		return false, nil
+	})
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"mime"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// IsJSONMediaType checks if content type is "application/json" or has "+json" structured syntax suffix,
+// e.g. "application/vnd.company.v2+json", parameters are ignored.
+func IsJSONMediaType(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// BinaryBodyTag returns field tag of structured binary media type, "msgpack" for MessagePack
+// (e.g. "application/x-msgpack") or "cbor" for CBOR (e.g. "application/cbor"),
+// empty tag is returned for other media types.
+func BinaryBodyTag(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+
+	switch {
+	case mt == "application/x-msgpack", mt == "application/msgpack", strings.HasSuffix(mt, "+msgpack"):
+		return tagMsgpack
+	case mt == "application/cbor", strings.HasSuffix(mt, "+cbor"):
+		return tagCBOR
+	}
+
+	return ""
+}
+
+var halLinksType = reflect.TypeOf(openapi.HALLinks{})
+
+// HasHALLinks checks if structure has a field of openapi.HALLinks type, fields of embedded structures are checked too.
+func HasHALLinks(structure interface{}) bool {
+	return hasHALLinks(reflect.TypeOf(structure))
+}
+
+func hasHALLinks(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.Type == halLinksType || (f.Anonymous && hasHALLinks(f.Type)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchMediaType finds declared media type of content type, media ranges (e.g. "image/*") are supported.
+func MatchMediaType(content map[string]interface{}, contentType string) (map[string]interface{}, bool) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	keys := SortedKeys(content)
+
+	// Exact media types take precedence over ranges.
+	sort.SliceStable(keys, func(i, j int) bool {
+		return !strings.Contains(keys[i], "*") && strings.Contains(keys[j], "*")
+	})
+
+	for _, key := range keys {
+		declared, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			continue
+		}
+
+		if declared == mt || declared == "*/*" ||
+			(strings.HasSuffix(declared, "/*") && strings.HasPrefix(mt, strings.TrimSuffix(declared, "*"))) {
+			media, _ := content[key].(map[string]interface{}) //nolint:errcheck // Empty media type accepts any body.
+
+			return media, true
+		}
+	}
+
+	return nil, false
+}
@@ -1,7 +1,14 @@
 // Package internal keeps reusable internal code.
 package internal
 
-import "github.com/swaggest/openapi-go"
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/swaggest/openapi-go"
+)
 
 // NewOperationContext creates OperationContext.
 func NewOperationContext(method, pathPattern string) *OperationContext {
@@ -20,6 +27,9 @@ type OperationContext struct {
 
 	isProcessingResponse bool
 	processingIn         openapi.In
+
+	forbidUnknown map[openapi.In]bool
+	view          string
 }
 
 // Method returns HTTP method of an operation.
@@ -62,6 +72,32 @@ func (o *OperationContext) ProcessingIn() openapi.In {
 	return o.processingIn
 }
 
+// SetForbidUnknownParams explicitly controls forbidden unknown parameters in location.
+func (o *OperationContext) SetForbidUnknownParams(in openapi.In, forbid bool) {
+	if o.forbidUnknown == nil {
+		o.forbidUnknown = make(map[openapi.In]bool)
+	}
+
+	o.forbidUnknown[in] = forbid
+}
+
+// ForbidUnknownParams returns explicit setting of unknown parameters in location.
+func (o *OperationContext) ForbidUnknownParams(in openapi.In) (forbid, isSet bool) {
+	forbid, isSet = o.forbidUnknown[in]
+
+	return forbid, isSet
+}
+
+// SetView selects a view of schemas.
+func (o *OperationContext) SetView(view string) {
+	o.view = view
+}
+
+// View returns selected view of schemas.
+func (o *OperationContext) View() string {
+	return o.view
+}
+
 // SetMethod sets HTTP method of an operation.
 func (o *OperationContext) SetMethod(method string) {
 	o.method = method
@@ -105,3 +141,60 @@ func (o *OperationContext) AddRespStructure(s interface{}, options ...openapi.Co
 
 	o.resp = append(o.resp, c)
 }
+
+// AddSunsetHeaders adds "Deprecation" and "Sunset" headers to every response of operation
+// with openapi.SunsetExtension.
+func AddSunsetHeaders(oc openapi.OperationContext) {
+	e, ok := oc.(openapi.OperationExtender)
+	if !ok {
+		return
+	}
+
+	value, _ := e.Extensions()[openapi.SunsetExtension].(string) //nolint:errcheck // Missing value is empty.
+
+	sunset, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return
+	}
+
+	addResponseHeaders(oc, headersStructure(
+		reflect.StructField{
+			Name: "Deprecation", Type: reflect.TypeOf(""),
+			Tag: `header:"Deprecation" description:"Indicates that operation is deprecated."`,
+		},
+		reflect.StructField{
+			Name: "Sunset", Type: reflect.TypeOf(""),
+			Tag: reflect.StructTag(`header:"Sunset" example:` + strconv.Quote(sunset.UTC().Format(http.TimeFormat)) +
+				` description:"Date after which operation is no longer available."`),
+		},
+	))
+}
+
+// headersStructure creates structure dynamically to have headers with values known at run time.
+func headersStructure(fields ...reflect.StructField) interface{} {
+	return reflect.New(reflect.StructOf(fields)).Elem().Interface()
+}
+
+// addResponseHeaders adds headers structure to every response added before.
+func addResponseHeaders(oc openapi.OperationContext, headers interface{}) {
+	seen := map[int]bool{}
+
+	for _, cu := range oc.Response() {
+		status := cu.HTTPStatus
+		if status == 0 && !cu.IsDefault {
+			status = http.StatusOK
+		}
+
+		if seen[status] {
+			continue
+		}
+
+		seen[status] = true
+		isDefault := cu.IsDefault
+
+		oc.AddRespStructure(headers, func(h *openapi.ContentUnit) {
+			h.HTTPStatus = status
+			h.IsDefault = isDefault
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// parameterStyles lists applicable locations of parameter serialization styles.
+var parameterStyles = map[string][]openapi.In{
+	"matrix":         {openapi.InPath},
+	"label":          {openapi.InPath},
+	"simple":         {openapi.InPath, openapi.InHeader},
+	"form":           {openapi.InQuery, openapi.InCookie},
+	"spaceDelimited": {openapi.InQuery},
+	"pipeDelimited":  {openapi.InQuery},
+	"deepObject":     {openapi.InQuery},
+}
+
+// CheckParameterStyle validates serialization style of parameter in location.
+func CheckParameterStyle(style string, in openapi.In) error {
+	locations, ok := parameterStyles[style]
+	if !ok {
+		return fmt.Errorf("unknown style %s", style)
+	}
+
+	names := make([]string, 0, len(locations))
+
+	for _, l := range locations {
+		if l == in {
+			return nil
+		}
+
+		names = append(names, string(l))
+	}
+
+	return fmt.Errorf("style %s is only applicable to %s parameters", style, strings.Join(names, " and "))
+}
+
+// ExplodeByDefault tells if parameter with serialization style is exploded when explode is not set.
+func ExplodeByDefault(style string) bool {
+	return style == "form"
+}
+
+// ParameterValue converts raw values of parameter to a JSON value of schema type, array items are
+// exploded values or comma-separated parts of a single value.
+//
+// Resolve follows references of schema and its items.
+func ParameterValue(schema map[string]interface{}, values []string, resolve func(v interface{}) map[string]interface{}) interface{} {
+	schema = resolve(schema)
+
+	if !hasType(schema, "array") {
+		return scalarValue(schema, values[0])
+	}
+
+	if len(values) == 1 {
+		values = strings.Split(values[0], ",")
+	}
+
+	items := resolve(schema["items"])
+	res := make([]interface{}, 0, len(values))
+
+	for _, v := range values {
+		res = append(res, scalarValue(items, v))
+	}
+
+	return res
+}
+
+func scalarValue(schema map[string]interface{}, value string) interface{} {
+	switch {
+	case hasType(schema, "integer") || hasType(schema, "number"):
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case hasType(schema, "boolean"):
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
+
+// hasType checks if schema type is t, or if type array has t.
+func hasType(schema map[string]interface{}, t string) bool {
+	switch st := schema["type"].(type) {
+	case string:
+		return st == t
+	case []interface{}:
+		for _, item := range st {
+			if item == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// DefaultMaxRecursionDepth is a default number of expansions of a recursive type in inlined schema.
+const DefaultMaxRecursionDepth = 1
+
+type recursionItem struct {
+	depth int
+	t     reflect.Type
+}
+
+// LimitRecursion stops expansion of recursive types in inlined schemas, where cycles can not be expressed with references.
+//
+// Type is expanded up to maxDepth times on a branch, deeper occurrences are reflected as empty schemas.
+// Schemas with references are not affected, cycles are resolved with self-referencing definitions there.
+func LimitRecursion(maxDepth func() int) func(rc *jsonschema.ReflectContext) {
+	return func(rc *jsonschema.ReflectContext) {
+		var stack []recursionItem
+
+		jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+			if params.Processed || !rc.InlineRefs || !params.Value.IsValid() {
+				return false, nil
+			}
+
+			// Path grows with every nested schema, so its length identifies the branch.
+			depth := len(rc.Path)
+			for len(stack) > 0 && stack[len(stack)-1].depth >= depth {
+				stack = stack[:len(stack)-1]
+			}
+
+			t := refl.DeepIndirect(params.Value.Type())
+
+			limit := maxDepth()
+			if limit <= 0 {
+				limit = DefaultMaxRecursionDepth
+			}
+
+			seen := 0
+
+			for _, item := range stack {
+				if item.t == t {
+					seen++
+				}
+			}
+
+			if seen >= limit {
+				*params.Schema = jsonschema.Schema{}
+
+				return true, nil
+			}
+
+			stack = append(stack, recursionItem{depth: depth, t: t})
+
+			return false, nil
+		})(rc)
+	}
+}
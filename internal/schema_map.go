@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+var (
+	schemaMapKeywords = []string{
+		"items", "additionalProperties", "not", "if", "then", "else", "contains",
+		"propertyNames", "unevaluatedItems", "unevaluatedProperties", "additionalItems",
+	}
+	schemaMapListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaMapMapKeywords  = []string{"properties", "patternProperties", "dependentSchemas", "$defs", "definitions"}
+)
+
+// WalkSchemaMap calls f for a JSON Schema in simple map form and for all its subschemas.
+//
+// Subschemas are visited before their parents, so f can restructure a schema
+// without having its result visited again.
+func WalkSchemaMap(sm map[string]interface{}, f func(sm map[string]interface{})) {
+	for _, k := range schemaMapKeywords {
+		if s, ok := sm[k].(map[string]interface{}); ok {
+			WalkSchemaMap(s, f)
+		}
+	}
+
+	for _, k := range schemaMapListKeywords {
+		if l, ok := sm[k].([]interface{}); ok {
+			for _, item := range l {
+				if s, ok := item.(map[string]interface{}); ok {
+					WalkSchemaMap(s, f)
+				}
+			}
+		}
+	}
+
+	for _, k := range schemaMapMapKeywords {
+		if m, ok := sm[k].(map[string]interface{}); ok {
+			for _, item := range m {
+				if s, ok := item.(map[string]interface{}); ok {
+					WalkSchemaMap(s, f)
+				}
+			}
+		}
+	}
+
+	f(sm)
+}
+
+// ResolveLocalRef follows local references (e.g. "#/components/schemas/User") of a value
+// in decoded JSON document, nil is returned for unresolved references.
+func ResolveLocalRef(doc map[string]interface{}, v interface{}) map[string]interface{} {
+	for i := 0; i < 32; i++ {
+		m, _ := v.(map[string]interface{}) //nolint:errcheck // Boolean schemas have no details.
+
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return m
+		}
+
+		v, _ = openapi.ResolvePointer(doc, ref) //nolint:errcheck // Missing target is nil.
+	}
+
+	return nil
+}
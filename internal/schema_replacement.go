@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// SchemaReplacements keeps functions that define schemas of types instead of reflection.
+type SchemaReplacements struct {
+	byType map[reflect.Type]func(s *jsonschema.Schema)
+}
+
+// Add registers schema replacement for type of sample.
+func (sr *SchemaReplacements) Add(sample interface{}, replace func(s *jsonschema.Schema)) {
+	if sr.byType == nil {
+		sr.byType = make(map[reflect.Type]func(s *jsonschema.Schema))
+	}
+
+	sr.byType[refl.DeepIndirect(reflect.TypeOf(sample))] = replace
+}
+
+// Option returns reflection hook that stops reflection of registered types and applies replacement to schema.
+//
+// Named types keep their definitions, so replaced schema is shared by all usages.
+func (sr *SchemaReplacements) Option() func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if params.Processed || len(sr.byType) == 0 || params.Schema.ReflectType == nil {
+			return false, nil
+		}
+
+		replace, ok := sr.byType[refl.DeepIndirect(params.Schema.ReflectType)]
+		if !ok {
+			return false, nil
+		}
+
+		replace(params.Schema)
+
+		return true, nil
+	})
+}
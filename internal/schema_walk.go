@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// SortedKeys returns sorted keys of a map with string keys, other values give nil.
+func SortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	keys := make([]string, 0, v.Len())
+
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// VisitSchemaMap calls f for a JSON Schema in simple map form and for all its subschemas with JSON pointers.
+//
+// Unlike WalkSchemaMap, parents are visited before their subschemas, so f can rewrite subschemas
+// that are visited next. Walk is sorted and stops on the first error of f.
+func VisitSchemaMap(pointer string, schema map[string]interface{}, f func(pointer string, schema map[string]interface{}) error) error {
+	if schema == nil {
+		return nil
+	}
+
+	if err := f(pointer, schema); err != nil {
+		return err
+	}
+
+	for _, kw := range schemaMapKeywords {
+		if s, ok := schema[kw].(map[string]interface{}); ok {
+			if err := VisitSchemaMap(pointer+"/"+openapi.PointerToken(kw), s, f); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, kw := range schemaMapListKeywords {
+		items, _ := schema[kw].([]interface{}) //nolint:errcheck // Type is checked by access.
+
+		for i, item := range items {
+			if s, ok := item.(map[string]interface{}); ok {
+				if err := VisitSchemaMap(pointer+"/"+kw+"/"+strconv.Itoa(i), s, f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, kw := range schemaMapMapKeywords {
+		items, _ := schema[kw].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+		for _, name := range SortedKeys(items) {
+			if s, ok := items[name].(map[string]interface{}); ok {
+				if err := VisitSchemaMap(pointer+"/"+openapi.PointerToken(kw)+"/"+openapi.PointerToken(name), s, f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"encoding/json"
+
+	"github.com/swaggest/openapi-go"
+)
+
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+}
+
+// SpecStats collects statistics of spec.
+func SpecStats(spec interface{}) (openapi.SpecStats, error) {
+	stats := openapi.SpecStats{OperationsByMethod: map[string]int{}}
+
+	orphans, err := OrphanComponents(spec)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.OrphanComponents = orphans
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return stats, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return stats, err
+	}
+
+	components, _ := doc["components"].(map[string]interface{})  //nolint:errcheck // Missing components are fine.
+	schemas, _ := components["schemas"].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+	stats.Schemas = len(schemas)
+
+	described, exampled := 0, 0
+	paths, _ := doc["paths"].(map[string]interface{}) //nolint:errcheck // Missing paths are empty.
+
+	for _, path := range SortedKeys(paths) {
+		pathItem, _ := paths[path].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+		for method, op := range pathItem {
+			op, ok := op.(map[string]interface{})
+			if !ok || !httpMethods[method] {
+				continue
+			}
+
+			stats.Operations++
+			stats.OperationsByMethod[method]++
+
+			if op["summary"] != nil || op["description"] != nil {
+				described++
+			}
+
+			if hasExamples(components, op, map[string]bool{}) {
+				exampled++
+			}
+		}
+	}
+
+	if stats.Operations > 0 {
+		stats.DescribedOperations = 100 * float64(described) / float64(stats.Operations)
+		stats.ExampledOperations = 100 * float64(exampled) / float64(stats.Operations)
+	}
+
+	return stats, nil
+}
+
+// hasExamples checks if value or referenced components have examples.
+func hasExamples(components map[string]interface{}, v interface{}, visited map[string]bool) bool {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if x["example"] != nil || x["examples"] != nil {
+			return true
+		}
+
+		for k, item := range x {
+			if k != "$ref" {
+				if hasExamples(components, item, visited) {
+					return true
+				}
+
+				continue
+			}
+
+			ref, _ := item.(string) //nolint:errcheck // Type is checked by access.
+
+			kind, name, ok := splitComponentRef(ref)
+			if !ok || visited[ref] {
+				continue
+			}
+
+			visited[ref] = true
+			items, _ := components[kind].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+			if hasExamples(components, items[name], visited) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range x {
+			if hasExamples(components, item, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// ComponentStats accounts schema components by Go packages of reflected types.
+type ComponentStats struct {
+	packages map[string]string // Package paths by component names.
+}
+
+// Add accounts schema component.
+func (cs *ComponentStats) Add(name string, schema jsonschema.SchemaOrBool) {
+	if cs.packages == nil {
+		cs.packages = make(map[string]string)
+	}
+
+	pkgPath := ""
+
+	if schema.TypeObject != nil && schema.TypeObject.ReflectType != nil {
+		if t := refl.DeepIndirect(schema.TypeObject.ReflectType); t != nil {
+			pkgPath = t.PkgPath()
+		}
+	}
+
+	cs.packages[name] = pkgPath
+}
+
+// ByPackage returns number of schema components by package path.
+//
+// Components of unknown origin are counted with empty package path.
+func (cs *ComponentStats) ByPackage() map[string]int {
+	res := make(map[string]int)
+
+	for _, pkgPath := range cs.packages {
+		res[pkgPath]++
+	}
+
+	return res
+}
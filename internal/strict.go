@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/refl"
+)
+
+var (
+	typeOfJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typeOfTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// CheckFieldTags returns error if request or response structures of operation have exported fields
+// without tags of parameters or body, tags of namespace and additional parameter tags are recognized.
+func CheckFieldTags(oc openapi.OperationContext, namespace string, parameterTags map[openapi.In][]string) error {
+	reqTags := []string{
+		tagJSON, tagFormData, tagForm, tagMsgpack, tagCBOR,
+		string(openapi.InPath), string(openapi.InQuery), string(openapi.InHeader), string(openapi.InCookie),
+	}
+
+	for _, tags := range parameterTags {
+		reqTags = append(reqTags, tags...)
+	}
+
+	reqTags = NamespacedTags(namespace, reqTags...)
+	respTags := NamespacedTags(namespace, tagJSON, tagMsgpack, tagCBOR, tagHeader)
+
+	var untagged []string
+
+	for _, cu := range oc.Request() {
+		untagged = append(untagged, UntaggedFields(cu.Structure, reqTags...)...)
+	}
+
+	for _, cu := range oc.Response() {
+		untagged = append(untagged, UntaggedFields(cu.Structure, respTags...)...)
+	}
+
+	if len(untagged) > 0 {
+		return errors.New("untagged fields: " + strings.Join(untagged, ", "))
+	}
+
+	return nil
+}
+
+// UntaggedFields returns names of exported fields that have none of tags, e.g. "User.Email".
+//
+// Fields of embedded structures and of structures of properties that are defined in the same package
+// as structure are checked too, types with custom marshaling are skipped.
+func UntaggedFields(structure interface{}, tags ...string) []string {
+	t := reflect.TypeOf(structure)
+	if t == nil {
+		return nil
+	}
+
+	t = refl.DeepIndirect(t)
+
+	var res []string
+
+	untaggedFields(t, t.PkgPath(), tags, map[reflect.Type]bool{}, &res)
+	sort.Strings(res)
+
+	return res
+}
+
+func untaggedFields(t reflect.Type, pkgPath string, tags []string, visited map[reflect.Type]bool, res *[]string) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || visited[t] || t.PkgPath() != pkgPath ||
+		t.Implements(typeOfJSONMarshaler) || reflect.PtrTo(t).Implements(typeOfJSONMarshaler) ||
+		t.Implements(typeOfTextMarshaler) || reflect.PtrTo(t).Implements(typeOfTextMarshaler) {
+		return
+	}
+
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tagged := false
+
+		for _, tag := range tags {
+			if _, ok := f.Tag.Lookup(tag); ok {
+				tagged = true
+
+				break
+			}
+		}
+
+		switch {
+		case f.Anonymous && !tagged:
+			untaggedFields(f.Type, refl.DeepIndirect(f.Type).PkgPath(), tags, visited, res)
+		case f.PkgPath != "" || f.Name == "_":
+			continue
+		case !tagged:
+			name := f.Name
+			if t.Name() != "" {
+				name = t.Name() + "." + name
+			}
+
+			*res = append(*res, name)
+		default:
+			untaggedFields(f.Type, pkgPath, tags, visited, res)
+		}
+	}
+}
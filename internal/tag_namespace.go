@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+type tagNamespaceCtxKey struct{}
+
+// TagNamespace returns reflection hook that makes field tags of namespace take precedence over standard tags.
+//
+// Tag named as namespace takes precedence over json tag, and "<namespace>-<tag>" over other tags,
+// e.g. "oas-query" over "query", standard tags are used as fallback.
+func TagNamespace(namespace func() string) func(rc *jsonschema.ReflectContext) {
+	return func(rc *jsonschema.ReflectContext) {
+		ns := namespace()
+		if ns == "" {
+			return
+		}
+
+		rc.Context = context.WithValue(rc.Context, tagNamespaceCtxKey{}, ns)
+
+		// Property name tags are configured by options after defaults, so they are replaced on root schema.
+		jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+			c := params.Context
+			if params.Processed || len(c.Path) != 1 || c.PropertyNameTag == ns || strings.HasPrefix(c.PropertyNameTag, ns+"-") {
+				return false, nil
+			}
+
+			tags := NamespacedTags(ns, append([]string{c.PropertyNameTag}, c.PropertyNameAdditionalTags...)...)
+
+			c.PropertyNameTag = tags[0]
+			c.PropertyNameAdditionalTags = tags[1:]
+
+			return false, nil
+		})(rc)
+	}
+}
+
+// NamespacedTags returns tags of namespace followed by standard tags, namespace can be empty.
+func NamespacedTags(namespace string, tags ...string) []string {
+	if namespace == "" {
+		return tags
+	}
+
+	res := make([]string, 0, 2*len(tags))
+
+	for _, tag := range tags {
+		if tag == tagJSON {
+			res = append(res, namespace)
+		} else {
+			res = append(res, namespace+"-"+tag)
+		}
+	}
+
+	return append(res, tags...)
+}
+
+func tagNamespaceOf(rc *jsonschema.ReflectContext) string {
+	ns, _ := rc.Value(tagNamespaceCtxKey{}).(string) //nolint:errcheck // Empty namespace is default.
+
+	return ns
+}
+
+// hasAnyTaggedFields checks if structure has fields with any of tags.
+func hasAnyTaggedFields(i interface{}, tags ...string) bool {
+	for _, tag := range tags {
+		if refl.HasTaggedFields(i, tag) {
+			return true
+		}
+	}
+
+	return false
+}
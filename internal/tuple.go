@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+)
+
+var typeOfTupleDeclarer = reflect.TypeOf((*openapi.TupleDeclarer)(nil)).Elem()
+
+// Tuples reflects fixed-size arrays with minItems/maxItems and openapi.TupleDeclarer with prefixItems,
+// if enabled returns true.
+//
+// Item schemas of tuples are inlined. Values of such types are not nullable, unlike slices.
+func Tuples(r *jsonschema.Reflector, enabled func() bool) func(rc *jsonschema.ReflectContext) {
+	interceptNullability := jsonschema.InterceptNullability(func(params jsonschema.InterceptNullabilityParams) {
+		if !params.NullAdded || params.Type.Kind() == reflect.Ptr {
+			return
+		}
+
+		if _, ok := tupleDeclarer(params.Type); ok || params.Type.Kind() == reflect.Array {
+			params.Schema.RemoveType(jsonschema.Null)
+		}
+	})
+
+	interceptSchema := jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Value.IsValid() {
+			return false, nil
+		}
+
+		t := params.Value.Type()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if !params.Processed {
+			td, ok := tupleDeclarer(t)
+			if !ok {
+				return false, nil
+			}
+
+			return true, reflectTuple(r, td, params.Schema)
+		}
+
+		if t.Kind() == reflect.Array && params.Schema.HasType(jsonschema.Array) {
+			params.Schema.WithMinItems(int64(t.Len()))
+			params.Schema.WithMaxItems(int64(t.Len()))
+		}
+
+		return false, nil
+	})
+
+	return func(rc *jsonschema.ReflectContext) {
+		if !enabled() {
+			return
+		}
+
+		interceptSchema(rc)
+		interceptNullability(rc)
+	}
+}
+
+func tupleDeclarer(t reflect.Type) (openapi.TupleDeclarer, bool) {
+	if t.Implements(typeOfTupleDeclarer) {
+		return reflect.Zero(t).Interface().(openapi.TupleDeclarer), true //nolint:errcheck
+	}
+
+	if reflect.PtrTo(t).Implements(typeOfTupleDeclarer) {
+		return reflect.New(t).Interface().(openapi.TupleDeclarer), true //nolint:errcheck
+	}
+
+	return nil, false
+}
+
+func reflectTuple(r *jsonschema.Reflector, td openapi.TupleDeclarer, schema *jsonschema.Schema) error {
+	items := td.TupleItems()
+	prefixItems := make([]jsonschema.SchemaOrBool, 0, len(items))
+
+	for i, item := range items {
+		s, err := r.Reflect(item, jsonschema.InlineRefs)
+		if err != nil {
+			return fmt.Errorf("tuple item %d: %w", i, err)
+		}
+
+		s.ReflectType = nil
+		prefixItems = append(prefixItems, s.ToSchemaOrBool())
+	}
+
+	schema.AddType(jsonschema.Array)
+	schema.WithMinItems(int64(len(items)))
+	schema.WithMaxItems(int64(len(items)))
+	schema.WithExtraPropertiesItem("prefixItems", prefixItems)
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+)
+
+// TypeRegistry keeps type mappings and inlined definitions of jsonschema.Reflector to describe them.
+type TypeRegistry struct {
+	mappings []typeMapping
+	inlined  []interface{}
+}
+
+type typeMapping struct {
+	src, dst interface{}
+}
+
+// AddTypeMapping creates substitution link between types of src and dst, mapped schema is customized with options.
+//
+// Sample dst value with options is reflected into an inlined schema, configured jsonschema.Schema
+// is used as a shared definition.
+func (tr *TypeRegistry) AddTypeMapping(r *jsonschema.Reflector, src, dst interface{}, options ...openapi.TypeMappingOption) {
+	if len(options) == 0 {
+		tr.addTypeMapping(r, src, dst)
+
+		return
+	}
+
+	var schema jsonschema.Schema
+
+	switch d := dst.(type) {
+	case jsonschema.Schema:
+		schema = d
+	case *jsonschema.Schema:
+		schema = *d
+	default:
+		s, err := r.Reflect(dst, jsonschema.InlineRefs)
+		if err != nil {
+			panic(fmt.Sprintf("failed to reflect type mapping %T: %s", dst, err))
+		}
+
+		schema = s
+		schema.ReflectType = nil
+
+		tr.InlineDefinition(r, src)
+	}
+
+	for _, o := range options {
+		o(&schema)
+	}
+
+	tr.addTypeMapping(r, src, schema)
+}
+
+func (tr *TypeRegistry) addTypeMapping(r *jsonschema.Reflector, src, dst interface{}) {
+	r.AddTypeMapping(src, dst)
+	tr.mappings = append(tr.mappings, typeMapping{src: src, dst: dst})
+}
+
+// InlineDefinition enables schema inlining for a type of given sample.
+func (tr *TypeRegistry) InlineDefinition(r *jsonschema.Reflector, sample interface{}) {
+	r.InlineDefinition(sample)
+	tr.inlined = append(tr.inlined, sample)
+}
+
+// Describe returns Go type names of mapped and inlined types.
+func (tr *TypeRegistry) Describe() (mappings map[string]string, inlined []string) {
+	for _, m := range tr.mappings {
+		if mappings == nil {
+			mappings = make(map[string]string, len(tr.mappings))
+		}
+
+		mappings[reflect.TypeOf(m.src).String()] = reflect.TypeOf(m.dst).String()
+	}
+
+	for _, sample := range tr.inlined {
+		inlined = append(inlined, reflect.TypeOf(sample).String())
+	}
+
+	return mappings, inlined
+}
@@ -0,0 +1,538 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// ValueError describes a mismatch of value and JSON Schema.
+type ValueError struct {
+	// Pointer is a JSON pointer to the mismatched part of value, empty for the whole value.
+	Pointer string
+	Message string
+
+	// Schema is a failed schema, it is nil for errors that are not caused by a value, e.g. unsupported keyword.
+	Schema map[string]interface{}
+
+	// Value is a mismatched value.
+	Value interface{}
+
+	// Missing is true if value is absent, e.g. a required property.
+	Missing bool
+}
+
+// maxValidationDepth limits nesting of schemas, e.g. with cyclic references that do not consume value.
+const maxValidationDepth = 256
+
+// unsupportedKeywords are keywords that can not be checked by ValidateValue.
+var unsupportedKeywords = []string{"$dynamicRef", "$recursiveRef", "unevaluatedItems", "unevaluatedProperties"}
+
+// ValidateValue checks value decoded from JSON against JSON Schema in simple map form,
+// resolve returns schemas of references, e.g. "#/components/schemas/User".
+//
+// Keywords of OpenAPI 3.0 and 3.1 schemas are supported except for dynamic references and
+// unevaluated items and properties, schemas with such keywords fail validation.
+// Siblings of "$ref" are checked together with the referenced schema, unknown formats are not checked.
+func ValidateValue(schema map[string]interface{}, value interface{}, resolve func(ref string) (map[string]interface{}, bool)) []ValueError {
+	v := validator{resolve: resolve}
+	v.validate("", schema, value)
+
+	return v.errors
+}
+
+type validator struct {
+	resolve func(ref string) (map[string]interface{}, bool)
+	errors  []ValueError
+	depth   int
+}
+
+func (v *validator) fail(pointer string, schema map[string]interface{}, value interface{}, format string, args ...interface{}) {
+	v.errors = append(v.errors, ValueError{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+		Schema:  schema,
+		Value:   value,
+	})
+}
+
+// matches checks value without collecting errors.
+func (v *validator) matches(schema map[string]interface{}, value interface{}) bool {
+	sub := validator{resolve: v.resolve, depth: v.depth}
+	sub.validate("", schema, value)
+
+	return len(sub.errors) == 0
+}
+
+func (v *validator) validate(pointer string, schema map[string]interface{}, value interface{}) {
+	if v.depth >= maxValidationDepth {
+		v.fail(pointer, nil, nil, "schema nesting exceeds %d levels, possibly a cyclic reference", maxValidationDepth)
+
+		return
+	}
+
+	v.depth++
+	defer func() { v.depth-- }()
+
+	for _, kw := range unsupportedKeywords {
+		if _, ok := schema[kw]; ok {
+			v.fail(pointer, nil, nil, "unsupported schema keyword %s", kw)
+
+			return
+		}
+	}
+
+	if value == nil && schema["nullable"] == true {
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		if !v.validateRef(pointer, ref, value) || len(schema) == 1 {
+			return
+		}
+	}
+
+	if !v.validType(pointer, schema, value) {
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		v.fail(pointer, schema, value, "value %s is not one of enum %s", jsonText(value), jsonText(enum))
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		v.fail(pointer, schema, value, "value %s does not match const %s", jsonText(value), jsonText(c))
+	}
+
+	v.validateComposition(pointer, schema, value)
+	v.validateConditional(pointer, schema, value)
+
+	switch x := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(pointer, schema, x)
+	case []interface{}:
+		v.validateArray(pointer, schema, x)
+	case string:
+		v.validateString(pointer, schema, x)
+	case float64:
+		v.validateNumber(pointer, schema, x)
+	}
+}
+
+// validateRef checks value against referenced schema, it returns false if reference is not resolved.
+func (v *validator) validateRef(pointer, ref string, value interface{}) bool {
+	if v.resolve == nil {
+		return false
+	}
+
+	s, found := v.resolve(ref)
+	if !found {
+		v.fail(pointer, nil, value, "unresolved reference %s", ref)
+
+		return false
+	}
+
+	v.validate(pointer, s, value)
+
+	return true
+}
+
+func jsonType(value interface{}) string {
+	switch x := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func (v *validator) validType(pointer string, schema map[string]interface{}, value interface{}) bool {
+	var types []string
+
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			types = append(types, fmt.Sprint(item))
+		}
+	default:
+		return true
+	}
+
+	actual := jsonType(value)
+
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	v.fail(pointer, schema, value, "expected type %s, got %s %s", strings.Join(types, "|"), actual, jsonText(value))
+
+	return false
+}
+
+func subschemaMaps(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{}) //nolint:errcheck // Missing subschemas are empty.
+
+	res := make([]map[string]interface{}, 0, len(items))
+
+	for _, item := range items {
+		if s, ok := item.(map[string]interface{}); ok {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+func (v *validator) validateComposition(pointer string, schema map[string]interface{}, value interface{}) {
+	for _, s := range subschemaMaps(schema["allOf"]) {
+		v.validate(pointer, s, value)
+	}
+
+	if anyOf := subschemaMaps(schema["anyOf"]); len(anyOf) > 0 {
+		matched := false
+
+		for _, s := range anyOf {
+			if v.matches(s, value) {
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			v.fail(pointer, schema, value, "value does not match any of anyOf schemas")
+		}
+	}
+
+	if oneOf := subschemaMaps(schema["oneOf"]); len(oneOf) > 0 {
+		matched := 0
+
+		for _, s := range oneOf {
+			if v.matches(s, value) {
+				matched++
+			}
+		}
+
+		if matched != 1 {
+			v.fail(pointer, schema, value, "value matches %d of oneOf schemas, exactly one expected", matched)
+		}
+	}
+
+	if not, ok := schema["not"].(map[string]interface{}); ok && v.matches(not, value) {
+		v.fail(pointer, schema, value, "value matches schema of not")
+	}
+}
+
+func (v *validator) validateConditional(pointer string, schema map[string]interface{}, value interface{}) {
+	cond, ok := schema["if"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	branch := "else"
+	if v.matches(cond, value) {
+		branch = "then"
+	}
+
+	if s, ok := schema[branch].(map[string]interface{}); ok {
+		v.validate(pointer, s, value)
+	}
+}
+
+func (v *validator) validateObject(pointer string, schema map[string]interface{}, value map[string]interface{}) {
+	props, _ := schema["properties"].(map[string]interface{})           //nolint:errcheck // Missing properties are empty.
+	patterns, _ := schema["patternProperties"].(map[string]interface{}) //nolint:errcheck // Missing patterns are empty.
+
+	if n, ok := schema["minProperties"].(float64); ok && float64(len(value)) < n {
+		v.fail(pointer, schema, value, "expected at least %v properties, got %d", n, len(value))
+	}
+
+	if n, ok := schema["maxProperties"].(float64); ok && float64(len(value)) > n {
+		v.fail(pointer, schema, value, "expected at most %v properties, got %d", n, len(value))
+	}
+
+	for _, name := range stringItems(schema["required"]) {
+		v.requireProperty(pointer, schema, value, name, "required property is missing")
+	}
+
+	if dependent, ok := schema["dependentRequired"].(map[string]interface{}); ok {
+		for _, name := range SortedKeys(dependent) {
+			if _, ok := value[name]; !ok {
+				continue
+			}
+
+			for _, req := range stringItems(dependent[name]) {
+				v.requireProperty(pointer, schema, value, req, "property is required by "+name)
+			}
+		}
+	}
+
+	if dependent, ok := schema["dependentSchemas"].(map[string]interface{}); ok {
+		for _, name := range SortedKeys(dependent) {
+			if _, ok := value[name]; !ok {
+				continue
+			}
+
+			if s, ok := dependent[name].(map[string]interface{}); ok {
+				v.validate(pointer, s, value)
+			}
+		}
+	}
+
+	names, _ := schema["propertyNames"].(map[string]interface{}) //nolint:errcheck // Missing schema accepts any name.
+
+	for _, name := range SortedKeys(value) {
+		p := pointer + "/" + openapi.PointerToken(name)
+
+		if names != nil && !v.matches(names, name) {
+			v.fail(p, names, name, "property name %q does not match propertyNames schema", name)
+		}
+
+		additional := true
+
+		if prop, ok := props[name]; ok {
+			additional = false
+
+			if s, ok := prop.(map[string]interface{}); ok {
+				v.validate(p, s, value[name])
+			}
+		}
+
+		for _, pattern := range SortedKeys(patterns) {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(name) {
+				additional = false
+
+				if s, ok := patterns[pattern].(map[string]interface{}); ok {
+					v.validate(p, s, value[name])
+				}
+			}
+		}
+
+		if !additional {
+			continue
+		}
+
+		switch ap := schema["additionalProperties"].(type) {
+		case bool:
+			if !ap {
+				v.fail(p, schema, value[name], "additional property is not allowed")
+			}
+		case map[string]interface{}:
+			v.validate(p, ap, value[name])
+		}
+	}
+}
+
+func (v *validator) requireProperty(pointer string, schema, value map[string]interface{}, name, message string) {
+	if _, ok := value[name]; ok {
+		return
+	}
+
+	props, _ := schema["properties"].(map[string]interface{}) //nolint:errcheck // Missing properties are empty.
+	prop, _ := props[name].(map[string]interface{})           //nolint:errcheck // Property schema is optional.
+
+	v.errors = append(v.errors, ValueError{
+		Pointer: pointer + "/" + openapi.PointerToken(name),
+		Message: message,
+		Schema:  prop,
+		Missing: true,
+	})
+}
+
+func stringItems(v interface{}) []string {
+	items, _ := v.([]interface{}) //nolint:errcheck // Missing names are empty.
+
+	res := make([]string, 0, len(items))
+	for _, item := range items {
+		res = append(res, fmt.Sprint(item))
+	}
+
+	return res
+}
+
+func (v *validator) validateArray(pointer string, schema map[string]interface{}, value []interface{}) {
+	if n, ok := schema["minItems"].(float64); ok && float64(len(value)) < n {
+		v.fail(pointer, schema, value, "expected at least %v items, got %d", n, len(value))
+	}
+
+	if n, ok := schema["maxItems"].(float64); ok && float64(len(value)) > n {
+		v.fail(pointer, schema, value, "expected at most %v items, got %d", n, len(value))
+	}
+
+	if schema["uniqueItems"] == true {
+		for i := 1; i < len(value); i++ {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(value[i], value[j]) {
+					v.fail(fmt.Sprintf("%s/%d", pointer, i), schema, value[i], "item duplicates item %d", j)
+
+					break
+				}
+			}
+		}
+	}
+
+	// Tuple items are defined with prefixItems in JSON Schema 2020-12 and with items array in earlier drafts.
+	prefix := subschemaMaps(schema["prefixItems"])
+	rest := schema["items"]
+
+	if _, ok := schema["prefixItems"]; !ok {
+		if tuple, ok := schema["items"].([]interface{}); ok {
+			prefix = subschemaMaps(tuple)
+			rest = schema["additionalItems"]
+		}
+	}
+
+	for i, item := range value {
+		p := fmt.Sprintf("%s/%d", pointer, i)
+
+		if i < len(prefix) {
+			v.validate(p, prefix[i], item)
+
+			continue
+		}
+
+		switch items := rest.(type) {
+		case bool:
+			if !items {
+				v.fail(p, schema, item, "additional item is not allowed")
+			}
+		case map[string]interface{}:
+			v.validate(p, items, item)
+		}
+	}
+
+	v.validateContains(pointer, schema, value)
+}
+
+func (v *validator) validateContains(pointer string, schema map[string]interface{}, value []interface{}) {
+	contains, ok := schema["contains"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	matched := 0
+
+	for _, item := range value {
+		if v.matches(contains, item) {
+			matched++
+		}
+	}
+
+	minContains := 1.0
+	if n, ok := schema["minContains"].(float64); ok {
+		minContains = n
+	}
+
+	if float64(matched) < minContains {
+		v.fail(pointer, schema, value, "expected at least %v items matching contains schema, got %d", minContains, matched)
+	}
+
+	if n, ok := schema["maxContains"].(float64); ok && float64(matched) > n {
+		v.fail(pointer, schema, value, "expected at most %v items matching contains schema, got %d", n, matched)
+	}
+}
+
+var (
+	uuidFormat = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	formats    = map[string]func(s string) bool{
+		"date-time": func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil },
+		"date":      func(s string) bool { _, err := time.Parse("2006-01-02", s); return err == nil },
+		"email":     func(s string) bool { i := strings.Index(s, "@"); return i > 0 && i < len(s)-1 },
+		"uuid":      uuidFormat.MatchString,
+	}
+)
+
+func (v *validator) validateString(pointer string, schema map[string]interface{}, value string) {
+	l := utf8.RuneCountInString(value)
+
+	if n, ok := schema["minLength"].(float64); ok && float64(l) < n {
+		v.fail(pointer, schema, value, "expected at least %v characters, got %d", n, l)
+	}
+
+	if n, ok := schema["maxLength"].(float64); ok && float64(l) > n {
+		v.fail(pointer, schema, value, "expected at most %v characters, got %d", n, l)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(value) {
+			v.fail(pointer, schema, value, "value %q does not match pattern %s", value, pattern)
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok {
+		if check, ok := formats[format]; ok && !check(value) {
+			v.fail(pointer, schema, value, "value %q is not a valid %s", value, format)
+		}
+	}
+}
+
+func (v *validator) validateNumber(pointer string, schema map[string]interface{}, value float64) {
+	exclusiveMin, _ := schema["exclusiveMinimum"].(bool) //nolint:errcheck // OpenAPI 3.0 flag is optional.
+	exclusiveMax, _ := schema["exclusiveMaximum"].(bool) //nolint:errcheck // OpenAPI 3.0 flag is optional.
+
+	if n, ok := schema["minimum"].(float64); ok && (value < n || (exclusiveMin && value == n)) {
+		v.fail(pointer, schema, value, "value %v is less than minimum %v", value, n)
+	}
+
+	if n, ok := schema["maximum"].(float64); ok && (value > n || (exclusiveMax && value == n)) {
+		v.fail(pointer, schema, value, "value %v is greater than maximum %v", value, n)
+	}
+
+	if n, ok := schema["exclusiveMinimum"].(float64); ok && value <= n {
+		v.fail(pointer, schema, value, "value %v is not greater than exclusive minimum %v", value, n)
+	}
+
+	if n, ok := schema["exclusiveMaximum"].(float64); ok && value >= n {
+		v.fail(pointer, schema, value, "value %v is not less than exclusive maximum %v", value, n)
+	}
+
+	if n, ok := schema["multipleOf"].(float64); ok && n > 0 {
+		if q := value / n; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(pointer, schema, value, "value %v is not a multiple of %v", value, n)
+		}
+	}
+}
+
+func containsValue(items []interface{}, value interface{}) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonText(v interface{}) string {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(j)
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+)
+
+const tagView = "view"
+
+// Views returns reflection hook that applies view of operation context to schemas.
+//
+// Fields with `view` tag are skipped unless tag lists the view, definitions of types that
+// depend on such fields receive view suffix, e.g. UserAdminView.
+func Views() func(rc *jsonschema.ReflectContext) {
+	return func(rc *jsonschema.ReflectContext) {
+		jsonschema.InterceptDefName(func(t reflect.Type, defaultDefName string) string {
+			view := viewOf(rc)
+			if view == "" || !hasViewFields(t) {
+				return defaultDefName
+			}
+
+			return defaultDefName + strings.ToUpper(view[:1]) + view[1:] + "View"
+		})(rc)
+
+		jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+			if params.Processed {
+				return nil
+			}
+
+			view := viewOf(rc)
+			if view == "" {
+				return nil
+			}
+
+			tag, ok := params.Field.Tag.Lookup(tagView)
+			if !ok {
+				return nil
+			}
+
+			for _, v := range strings.Split(tag, ",") {
+				if strings.TrimSpace(v) == view {
+					return nil
+				}
+			}
+
+			return skipProperty(params)
+		})(rc)
+	}
+}
+
+func viewOf(rc *jsonschema.ReflectContext) string {
+	if oc, ok := openapi.OperationCtx(rc); ok {
+		if v, ok := oc.(openapi.OperationViewer); ok {
+			return v.View()
+		}
+	}
+
+	return ""
+}
+
+var viewTypes sync.Map // map[reflect.Type]bool
+
+// hasViewFields checks if type or types of its fields have fields with view tag.
+func hasViewFields(t reflect.Type) bool {
+	if v, ok := viewTypes.Load(t); ok {
+		return v.(bool) //nolint:errcheck // Only bool values are stored.
+	}
+
+	res := dependsOnView(t, map[reflect.Type]bool{})
+	viewTypes.Store(t, res)
+
+	return res
+}
+
+func dependsOnView(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if _, ok := f.Tag.Lookup(tagView); ok || dependsOnView(f.Type, visited) {
+			return true
+		}
+	}
+
+	return false
+}
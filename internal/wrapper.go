@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+)
+
+// Wrappers reflects instances of registered wrapper types as their wrapped types.
+type Wrappers struct {
+	wrappers map[string]wrapper // Wrappers by type names.
+}
+
+type wrapper struct {
+	field    string // Name of a field that holds wrapped value.
+	nullable bool
+}
+
+// sqlNullWrappers are nullable wrappers of database/sql.
+var sqlNullWrappers = map[string]wrapper{
+	"database/sql.NullString":  {field: "String", nullable: true},
+	"database/sql.NullInt64":   {field: "Int64", nullable: true},
+	"database/sql.NullInt32":   {field: "Int32", nullable: true},
+	"database/sql.NullInt16":   {field: "Int16", nullable: true},
+	"database/sql.NullByte":    {field: "Byte", nullable: true},
+	"database/sql.NullFloat64": {field: "Float64", nullable: true},
+	"database/sql.NullBool":    {field: "Bool", nullable: true},
+	"database/sql.NullTime":    {field: "Time", nullable: true},
+	"database/sql.Null":        {field: "V", nullable: true},
+}
+
+var (
+	typeOfNullWrapper = reflect.TypeOf((*openapi.NullWrapper)(nil)).Elem()
+	typeOfTime        = reflect.TypeOf(time.Time{})
+)
+
+// Add registers wrapper type of sample with the name of a field that holds wrapped value.
+//
+// Sample can be any instance of a generic type, other instances of the same generic type are also matched.
+func (w *Wrappers) Add(sample interface{}, field string, nullable bool) {
+	if w.wrappers == nil {
+		w.wrappers = make(map[string]wrapper)
+	}
+
+	w.wrappers[wrapperName(reflect.TypeOf(sample))] = wrapper{field: field, nullable: nullable}
+}
+
+// Options returns reflection hooks of wrappers to be added to default options of jsonschema.Reflector.
+func (w *Wrappers) Options(r *jsonschema.Reflector) []func(rc *jsonschema.ReflectContext) {
+	return []func(rc *jsonschema.ReflectContext){
+		// Definition name is requested before type mapping is checked, so that
+		// mapping of a wrapper instance can be added just in time.
+		jsonschema.InterceptDefName(func(t reflect.Type, defaultDefName string) string {
+			if wrapped, _ := w.wrapped(t); wrapped != nil {
+				r.AddTypeMapping(reflect.Zero(t).Interface(), reflect.Zero(wrapped).Interface())
+			}
+
+			return defaultDefName
+		}),
+		jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+			if params.Processed || params.Schema.ReflectType == nil {
+				return false, nil
+			}
+
+			wrapped, nullable := w.wrapped(params.Schema.ReflectType)
+
+			// Shared definitions of named types are not changed, same as with pointers.
+			if nullable && (wrapped.PkgPath() == "" || wrapped == typeOfTime) {
+				params.Schema.AddType(jsonschema.Null)
+			}
+
+			return false, nil
+		}),
+	}
+}
+
+func (w *Wrappers) wrapped(t reflect.Type) (wrapped reflect.Type, nullable bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Implements(typeOfNullWrapper) {
+		if sample := reflect.Zero(t).Interface().(openapi.NullWrapper).NullWrapped(); sample != nil { //nolint:errcheck
+			return reflect.TypeOf(sample), true
+		}
+
+		return nil, false
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	name := wrapperName(t)
+
+	wr, ok := w.wrappers[name]
+	if !ok {
+		wr, ok = sqlNullWrappers[name]
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	f, ok := t.FieldByName(wr.field)
+	if !ok || f.Type.Kind() == reflect.Interface {
+		return nil, false
+	}
+
+	return f.Type, wr.nullable
+}
+
+// wrapperName returns type name without type parameters.
+func wrapperName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	name := t.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+
+	return t.PkgPath() + "." + name
+}
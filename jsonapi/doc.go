@@ -0,0 +1,4 @@
+// Package jsonapi provides generic envelopes of JSON:API documents to reflect schemas of compliant services.
+//
+// See https://jsonapi.org/format/.
+package jsonapi
@@ -0,0 +1,115 @@
+package jsonapi
+
+import "github.com/swaggest/jsonschema-go"
+
+// MediaType is a media type of JSON:API documents.
+const MediaType = "application/vnd.api+json"
+
+// Typed is implemented by attributes to declare type of resource, e.g. "articles".
+type Typed interface {
+	ResourceType() string
+}
+
+// Resource is a resource object with attributes of type T.
+//
+// If T implements Typed, "type" member of resource is constrained to the type of resource.
+type Resource[T any] struct {
+	Type          string                  `json:"type" required:"true"`
+	ID            string                  `json:"id,omitempty"`
+	Attributes    T                       `json:"attributes"`
+	Relationships map[string]Relationship `json:"relationships,omitempty"`
+	Links         Links                   `json:"links,omitempty"`
+	Meta          map[string]interface{}  `json:"meta,omitempty"`
+}
+
+// PrepareJSONSchema constrains resource type.
+func (Resource[T]) PrepareJSONSchema(s *jsonschema.Schema) error {
+	var attributes T
+
+	if typed, ok := interface{}(attributes).(Typed); ok {
+		if p, ok := s.Properties["type"]; ok && p.TypeObject != nil {
+			p.TypeObject.WithEnum(typed.ResourceType())
+		}
+	}
+
+	return nil
+}
+
+// Identifier is a resource identifier object, it references a resource in relationships.
+type Identifier struct {
+	Type string                 `json:"type" required:"true"`
+	ID   string                 `json:"id" required:"true"`
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Relationship is a relationship object, its data is null, a resource identifier
+// or an array of resource identifiers, e.g. *Identifier or []Identifier.
+type Relationship struct {
+	Data  interface{}            `json:"data,omitempty"`
+	Links Links                  `json:"links,omitempty"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
+}
+
+// PrepareJSONSchema describes data member of relationship.
+func (Relationship) PrepareJSONSchema(s *jsonschema.Schema) error {
+	var r jsonschema.Reflector
+
+	id, err := r.Reflect(Identifier{}, jsonschema.InlineRefs)
+	if err != nil {
+		return err
+	}
+
+	id.ReflectType = nil
+
+	s.WithPropertiesItem("data", (&jsonschema.Schema{}).WithOneOf(
+		(&jsonschema.Schema{}).WithType(jsonschema.Null.Type()).ToSchemaOrBool(),
+		id.ToSchemaOrBool(),
+		(&jsonschema.Schema{}).WithType(jsonschema.Array.Type()).WithItems(
+			*(&jsonschema.Items{}).WithSchemaOrBool(id.ToSchemaOrBool()),
+		).ToSchemaOrBool(),
+	).ToSchemaOrBool())
+
+	return nil
+}
+
+// Links is a links object, values are URLs.
+type Links map[string]string
+
+// Document is a top level document with primary data of type T,
+// e.g. Resource[Article] or []Resource[Article].
+type Document[T any] struct {
+	Data     T                      `json:"data" required:"true"`
+	Included []Resource[any]        `json:"included,omitempty"`
+	Links    Links                  `json:"links,omitempty"`
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+	JSONAPI  *Version               `json:"jsonapi,omitempty"`
+}
+
+// Version describes implementation of server.
+type Version struct {
+	Version string `json:"version,omitempty" example:"1.1"`
+}
+
+// ErrorDocument is a top level document with errors.
+type ErrorDocument struct {
+	Errors []Error                `json:"errors" required:"true" minItems:"1"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Error is an error object.
+type Error struct {
+	ID     string                 `json:"id,omitempty"`
+	Status string                 `json:"status,omitempty" description:"HTTP status code."`
+	Code   string                 `json:"code,omitempty" description:"Application specific error code."`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *ErrorSource           `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// ErrorSource references source of error in request.
+type ErrorSource struct {
+	Pointer   string `json:"pointer,omitempty" description:"JSON Pointer to value in request document."`
+	Parameter string `json:"parameter,omitempty" description:"Name of query parameter."`
+	Header    string `json:"header,omitempty" description:"Name of request header."`
+}
@@ -0,0 +1,155 @@
+package jsonapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/jsonapi"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type article struct {
+	Title string `json:"title" required:"true"`
+}
+
+func (article) ResourceType() string {
+	return "articles"
+}
+
+func TestDocument(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/articles")
+	require.NoError(t, err)
+	oc.AddRespStructure(jsonapi.Document[[]jsonapi.Resource[article]]{}, openapi.WithContentType(jsonapi.MediaType))
+	oc.AddRespStructure(jsonapi.ErrorDocument{}, openapi.WithContentType(jsonapi.MediaType),
+		openapi.WithHTTPStatus(http.StatusBadRequest))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/articles":{
+		  "get":{
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/vnd.api+json":{
+					"schema":{
+					  "$ref":"#/components/schemas/JsonapiDocumentGithubComSwaggestOpenapiGoJsonapiResourceGithubComSwaggestOpenapiGoJsonapiTestArticle"
+					}
+				  }
+				}
+			  },
+			  "400":{
+				"description":"Bad Request",
+				"content":{"application/vnd.api+json":{"schema":{"$ref":"#/components/schemas/JsonapiErrorDocument"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "JsonapiDocumentGithubComSwaggestOpenapiGoJsonapiResourceGithubComSwaggestOpenapiGoJsonapiTestArticle":{
+			"properties":{
+			  "data":{
+				"items":{"$ref":"#/components/schemas/JsonapiResourceGithubComSwaggestOpenapiGoJsonapiTestArticle"},
+				"type":["array","null"]
+			  },
+			  "included":{"items":{"$ref":"#/components/schemas/JsonapiResourceInterface"},"type":"array"},
+			  "jsonapi":{"$ref":"#/components/schemas/JsonapiVersion"},
+			  "links":{"$ref":"#/components/schemas/JsonapiLinks"},
+			  "meta":{"additionalProperties":{},"type":"object"}
+			},
+			"required":["data"],"type":"object"
+		  },
+		  "JsonapiError":{
+			"properties":{
+			  "code":{"description":"Application specific error code.","type":"string"},
+			  "detail":{"type":"string"},"id":{"type":"string"},
+			  "meta":{"additionalProperties":{},"type":"object"},
+			  "source":{"$ref":"#/components/schemas/JsonapiErrorSource"},
+			  "status":{"description":"HTTP status code.","type":"string"},
+			  "title":{"type":"string"}
+			},
+			"type":"object"
+		  },
+		  "JsonapiErrorDocument":{
+			"properties":{
+			  "errors":{"items":{"$ref":"#/components/schemas/JsonapiError"},"minItems":1,"type":["array","null"]},
+			  "meta":{"additionalProperties":{},"type":"object"}
+			},
+			"required":["errors"],"type":"object"
+		  },
+		  "JsonapiErrorSource":{
+			"properties":{
+			  "header":{"description":"Name of request header.","type":"string"},
+			  "parameter":{"description":"Name of query parameter.","type":"string"},
+			  "pointer":{"description":"JSON Pointer to value in request document.","type":"string"}
+			},
+			"type":"object"
+		  },
+		  "JsonapiLinks":{"additionalProperties":{"type":"string"},"type":"object"},
+		  "JsonapiRelationship":{
+			"properties":{
+			  "data":{
+				"oneOf":[
+				  {"type":"null"},
+				  {
+					"properties":{
+					  "id":{"type":"string"},"meta":{"additionalProperties":{},"type":"object"},
+					  "type":{"type":"string"}
+					},
+					"required":["type","id"],"type":"object"
+				  },
+				  {
+					"items":{
+					  "properties":{
+						"id":{"type":"string"},"meta":{"additionalProperties":{},"type":"object"},
+						"type":{"type":"string"}
+					  },
+					  "required":["type","id"],"type":"object"
+					},
+					"type":"array"
+				  }
+				]
+			  },
+			  "links":{"$ref":"#/components/schemas/JsonapiLinks"},
+			  "meta":{"additionalProperties":{},"type":"object"}
+			},
+			"type":"object"
+		  },
+		  "JsonapiResourceGithubComSwaggestOpenapiGoJsonapiTestArticle":{
+			"properties":{
+			  "attributes":{"$ref":"#/components/schemas/JsonapiTestArticle"},
+			  "id":{"type":"string"},"links":{"$ref":"#/components/schemas/JsonapiLinks"},
+			  "meta":{"additionalProperties":{},"type":"object"},
+			  "relationships":{
+				"additionalProperties":{"$ref":"#/components/schemas/JsonapiRelationship"},"type":"object"
+			  },
+			  "type":{"enum":["articles"],"type":"string"}
+			},
+			"required":["type"],"type":"object"
+		  },
+		  "JsonapiResourceInterface":{
+			"properties":{
+			  "attributes":{},"id":{"type":"string"},"links":{"$ref":"#/components/schemas/JsonapiLinks"},
+			  "meta":{"additionalProperties":{},"type":"object"},
+			  "relationships":{
+				"additionalProperties":{"$ref":"#/components/schemas/JsonapiRelationship"},"type":"object"
+			  },
+			  "type":{"type":"string"}
+			},
+			"required":["type"],"type":"object"
+		  },
+		  "JsonapiTestArticle":{"properties":{"title":{"type":"string"}},"required":["title"],"type":"object"},
+		  "JsonapiVersion":{"properties":{"version":{"examples":["1.1"],"type":"string"}},"type":"object"}
+		}
+	  }
+	}`, r.Spec)
+}
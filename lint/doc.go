@@ -0,0 +1,5 @@
+// Package lint checks specs against configurable rules and reports findings with JSON pointers.
+//
+// Rules of AWSAPIGatewayRules, GCPAPIGatewayRules and KongRules report features that are dropped
+// or transformed on import to API gateways.
+package lint
@@ -0,0 +1,161 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/report"
+)
+
+// AWSAPIGatewayRules returns rules that report features of spec that are rejected, dropped or transformed
+// on import to AWS API Gateway REST API.
+//
+// Findings are a machine-readable compatibility report, so that pipelines can fail on unacceptable loss.
+//
+//	findings, err := lint.Run(r.Spec, lint.AWSAPIGatewayRules()...)
+func AWSAPIGatewayRules() []Rule {
+	return []Rule{
+		{
+			Name:     "aws-openapi-version",
+			Severity: report.SeverityError,
+			Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+				if v, _ := doc["openapi"].(string); strings.HasPrefix(v, "3.1") { //nolint:errcheck // Missing version is empty.
+					found("/openapi", "AWS API Gateway imports OpenAPI 2.0 and 3.0 documents, not "+v+".")
+				}
+			},
+		},
+		{
+			Name:     "aws-unsupported-keyword",
+			Severity: report.SeverityWarning,
+			Check: keywords(func(keyword string, value interface{}) string {
+				switch keyword {
+				case "discriminator", "example", "exclusiveMinimum", "exclusiveMaximum":
+					return "Keyword " + keyword + " is dropped by AWS API Gateway."
+				}
+
+				return draft4Keyword(keyword, value, "AWS API Gateway models")
+			}),
+		},
+	}
+}
+
+// GCPAPIGatewayRules returns rules that report features of spec that are dropped or transformed
+// on conversion to OpenAPI 2.0 for GCP API Gateway API configs.
+//
+// Findings are a machine-readable compatibility report, so that pipelines can fail on unacceptable loss.
+//
+//	findings, err := lint.Run(r.Spec, lint.GCPAPIGatewayRules()...)
+func GCPAPIGatewayRules() []Rule {
+	return []Rule{
+		{
+			Name:     "gcp-openapi-version",
+			Severity: report.SeverityInfo,
+			Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+				if v, _ := doc["openapi"].(string); v != "" { //nolint:errcheck // Missing version is empty.
+					found("/openapi", "GCP API Gateway API config uses OpenAPI 2.0, document "+v+" is converted.")
+				}
+			},
+		},
+		{
+			Name:     "gcp-openapi2-feature",
+			Severity: report.SeverityWarning,
+			Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+				if servers, _ := doc["servers"].([]interface{}); len(servers) > 1 { //nolint:errcheck // Missing servers are empty.
+					found("/servers", "OpenAPI 2.0 has single host and base path, servers other than first are dropped.")
+				}
+
+				keywords(func(keyword string, value interface{}) string {
+					switch keyword {
+					case "oneOf", "anyOf", "not", "nullable", "callbacks", "links":
+						return "Keyword " + keyword + " is not available in OpenAPI 2.0 and is dropped."
+					case "in":
+						if value == "cookie" {
+							return "Cookie parameters are not available in OpenAPI 2.0 and are dropped."
+						}
+					case "type":
+						if _, ok := value.([]interface{}); ok {
+							return "Multiple types are not available in OpenAPI 2.0 and are dropped."
+						}
+					}
+
+					return ""
+				})(doc, found)
+			},
+		},
+	}
+}
+
+// KongRules returns rules that report features of spec that are dropped or transformed
+// on conversion to Kong Gateway declarative configuration.
+//
+// Findings are a machine-readable compatibility report, so that pipelines can fail on unacceptable loss.
+//
+//	findings, err := lint.Run(r.Spec, lint.KongRules()...)
+func KongRules() []Rule {
+	return []Rule{
+		{
+			Name:     "kong-regex-route",
+			Severity: report.SeverityInfo,
+			Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+				paths, _ := doc["paths"].(map[string]interface{}) //nolint:errcheck // Missing paths are empty.
+
+				for _, path := range internal.SortedKeys(paths) {
+					if strings.Contains(path, "{") {
+						found("/paths/"+openapi.PointerToken(path), "Path parameters are converted to regular expression route.")
+					}
+				}
+			},
+		},
+		{
+			Name:     "kong-unsupported-keyword",
+			Severity: report.SeverityWarning,
+			Check: keywords(func(keyword string, value interface{}) string {
+				return draft4Keyword(keyword, value, "Kong request validator")
+			}),
+		},
+	}
+}
+
+// keywords returns check that reports keywords of spec objects with messages of message func,
+// empty message skips keyword.
+func keywords(message func(keyword string, value interface{}) string) func(
+	doc map[string]interface{}, found func(pointer, message string),
+) {
+	return func(doc map[string]interface{}, found func(pointer, message string)) {
+		objects("", doc, false, func(pointer string, obj map[string]interface{}) {
+			for _, k := range internal.SortedKeys(obj) {
+				if m := message(k, obj[k]); m != "" {
+					found(pointer+"/"+openapi.PointerToken(k), m)
+				}
+			}
+		})
+	}
+}
+
+// draft4Keywords are schema keywords introduced after JSON Schema draft 4.
+var draft4Keywords = map[string]bool{
+	"const":                 true,
+	"contains":              true,
+	"propertyNames":         true,
+	"if":                    true,
+	"then":                  true,
+	"else":                  true,
+	"dependentRequired":     true,
+	"dependentSchemas":      true,
+	"prefixItems":           true,
+	"unevaluatedItems":      true,
+	"unevaluatedProperties": true,
+	"$defs":                 true,
+}
+
+// draft4Keyword returns message for schema keyword that is not supported by JSON Schema draft 4 validation of target.
+func draft4Keyword(keyword string, value interface{}, target string) string {
+	_, number := value.(float64)
+
+	if draft4Keywords[keyword] || (number && (keyword == "exclusiveMinimum" || keyword == "exclusiveMaximum")) {
+		return "Keyword " + keyword + " is not supported by JSON Schema draft 4 of " + target + " and is dropped."
+	}
+
+	return ""
+}
@@ -0,0 +1,95 @@
+package lint
+
+import (
+	"encoding/json"
+
+	"github.com/swaggest/openapi-go/report"
+)
+
+// Rule is a named check of spec document.
+type Rule struct {
+	Name     string
+	Severity report.Severity
+
+	// Check calls found with JSON pointer and message for every issue of spec document,
+	// document is a decoded JSON value of spec.
+	Check func(doc map[string]interface{}, found func(pointer, message string))
+}
+
+// WithSeverity returns a copy of rule with another severity.
+func (r Rule) WithSeverity(severity report.Severity) Rule {
+	r.Severity = severity
+
+	return r
+}
+
+// DefaultRules are used by Run if rules are not provided.
+func DefaultRules() []Rule {
+	return []Rule{OperationSummary, OperationID, ClientErrorResponse, SchemaDescription, NoInlineEnum}
+}
+
+// Run checks spec with rules and returns findings, DefaultRules are used if rules are not provided.
+//
+// Spec can be openapi3.Spec, openapi31.Spec or any other value that is marshaled to OpenAPI document.
+func Run(spec interface{}, rules ...Rule) ([]report.Finding, error) {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var findings []report.Finding
+
+	for _, rule := range rules {
+		rule := rule
+
+		rule.Check(doc, func(pointer, message string) {
+			findings = append(findings, report.Finding{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Message:  message,
+				Pointer:  pointer,
+			})
+		})
+	}
+
+	return findings, nil
+}
+
+// TestingT is a subset of testing.TB.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Assert runs rules against spec in a test and fails it on findings of error or warning severity.
+//
+//	func TestSpec(t *testing.T) {
+//		lint.Assert(t, r.Spec)
+//	}
+func Assert(t TestingT, spec interface{}, rules ...Rule) {
+	t.Helper()
+
+	findings, err := Run(spec, rules...)
+	if err != nil {
+		t.Errorf("lint spec: %s", err)
+
+		return
+	}
+
+	for _, f := range findings {
+		if f.Severity == report.SeverityInfo {
+			continue
+		}
+
+		t.Errorf("%s %s: %s (%s)", f.Severity, f.Rule, f.Message, f.Pointer)
+	}
+}
@@ -0,0 +1,232 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/lint"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/report"
+)
+
+type pet struct {
+	Name   string `json:"name"`
+	Status string `json:"status" enum:"available,sold"`
+}
+
+type petRequest struct {
+	ID string `path:"id"`
+}
+
+type apiError struct {
+	Message string `json:"message"`
+}
+
+func TestRun(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets/{id}")
+	require.NoError(t, err)
+
+	oc.SetID("getPet")
+	oc.AddReqStructure(petRequest{})
+	oc.AddRespStructure(pet{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+
+	oc.SetSummary("Create pet.")
+	oc.AddReqStructure(pet{})
+	oc.AddRespStructure(apiError{}, openapi.WithHTTPStatus(http.StatusBadRequest))
+	require.NoError(t, r.AddOperation(oc))
+
+	findings, err := lint.Run(r.Spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []report.Finding{
+		{
+			Rule: "operation-summary", Severity: report.SeverityWarning,
+			Message: "Operation should have summary.", Pointer: "/paths/~1pets~1{id}/get",
+		},
+		{
+			Rule: "operation-id", Severity: report.SeverityWarning,
+			Message: "Operation should have operationId.", Pointer: "/paths/~1pets/post",
+		},
+		{
+			Rule: "client-error-response", Severity: report.SeverityWarning,
+			Message: "Operation should declare 4xx response.", Pointer: "/paths/~1pets~1{id}/get/responses",
+		},
+		{
+			Rule: "schema-description", Severity: report.SeverityInfo,
+			Message: "Schema should have description.", Pointer: "/components/schemas/LintTestApiError",
+		},
+		{
+			Rule: "schema-description", Severity: report.SeverityInfo,
+			Message: "Schema should have description.", Pointer: "/components/schemas/LintTestPet",
+		},
+		{
+			Rule: "no-inline-enum", Severity: report.SeverityWarning,
+			Message: "Enum should be defined as component schema.", Pointer: "/components/schemas/LintTestPet/properties/status",
+		},
+	}, findings)
+
+	findings, err = lint.Run(r.Spec, lint.NoInlineEnum.WithSeverity(report.SeverityError))
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, report.SeverityError, findings[0].Severity)
+}
+
+type recorder struct {
+	errors []string
+}
+
+func (*recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets")
+	require.NoError(t, err)
+
+	oc.SetSummary("List pets.")
+	require.NoError(t, r.AddOperation(oc))
+
+	rec := &recorder{}
+	lint.Assert(rec, r.Spec, lint.OperationSummary, lint.OperationID)
+
+	assert.Equal(t, []string{"warning operation-id: Operation should have operationId. (/paths/~1pets/get)"}, rec.errors)
+
+	lint.Assert(t, r.Spec, lint.OperationSummary)
+}
+
+func TestNoInlineEnum(t *testing.T) {
+	findings, err := lint.Run(map[string]interface{}{
+		"servers": []interface{}{map[string]interface{}{
+			"url":       "https://{env}.example.com",
+			"variables": map[string]interface{}{"env": map[string]interface{}{"default": "api", "enum": []interface{}{"api"}}},
+		}},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Status": map[string]interface{}{"type": "string", "enum": []interface{}{"a"}},
+				"Item": map[string]interface{}{
+					"type":    "object",
+					"example": map[string]interface{}{"enum": []interface{}{"a"}},
+					"properties": map[string]interface{}{
+						"example": map[string]interface{}{"type": "string", "enum": []interface{}{"a"}},
+						"servers": map[string]interface{}{"type": "string", "enum": []interface{}{"a"}},
+						"x-kind":  map[string]interface{}{"type": "string", "enum": []interface{}{"a"}},
+						"enum":    map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}, lint.NoInlineEnum)
+	require.NoError(t, err)
+
+	pointers := make([]string, 0, len(findings))
+	for _, f := range findings {
+		pointers = append(pointers, f.Pointer)
+	}
+
+	assert.Equal(t, []string{
+		"/components/schemas/Item/properties/example",
+		"/components/schemas/Item/properties/servers",
+		"/components/schemas/Item/properties/x-kind",
+	}, pointers)
+}
+
+func TestGatewayRules(t *testing.T) {
+	doc := json.RawMessage(`{
+	  "openapi":"3.1.0",
+	  "servers":[{"url":"https://a.example.com"},{"url":"https://b.example.com"}],
+	  "paths":{
+		"/pets/{id}":{
+		  "get":{
+			"parameters":[
+			  {"name":"id","in":"path","required":true,"schema":{"type":"string"}},
+			  {"name":"session","in":"cookie","schema":{"type":"string"}}
+			],
+			"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Pet":{
+			"type":"object","example":{"kind":"cat"},
+			"discriminator":{"propertyName":"kind"},
+			"properties":{
+			  "kind":{"const":"cat"},
+			  "age":{"type":["integer","null"],"exclusiveMinimum":0},
+			  "const":{"oneOf":[{"type":"string"},{"type":"integer"}]}
+			}
+		  }
+		}
+	  }
+	}`)
+
+	for name, tc := range map[string]struct {
+		rules    []lint.Rule
+		expected []string
+	}{
+		"aws": {
+			rules: lint.AWSAPIGatewayRules(),
+			expected: []string{
+				"error aws-openapi-version /openapi: AWS API Gateway imports OpenAPI 2.0 and 3.0 documents, not 3.1.0.",
+				"warning aws-unsupported-keyword /components/schemas/Pet/discriminator: " +
+					"Keyword discriminator is dropped by AWS API Gateway.",
+				"warning aws-unsupported-keyword /components/schemas/Pet/example: Keyword example is dropped by AWS API Gateway.",
+				"warning aws-unsupported-keyword /components/schemas/Pet/properties/age/exclusiveMinimum: " +
+					"Keyword exclusiveMinimum is dropped by AWS API Gateway.",
+				"warning aws-unsupported-keyword /components/schemas/Pet/properties/kind/const: " +
+					"Keyword const is not supported by JSON Schema draft 4 of AWS API Gateway models and is dropped.",
+			},
+		},
+		"gcp": {
+			rules: lint.GCPAPIGatewayRules(),
+			expected: []string{
+				"info gcp-openapi-version /openapi: GCP API Gateway API config uses OpenAPI 2.0, document 3.1.0 is converted.",
+				"warning gcp-openapi2-feature /servers: " +
+					"OpenAPI 2.0 has single host and base path, servers other than first are dropped.",
+				"warning gcp-openapi2-feature /components/schemas/Pet/properties/age/type: " +
+					"Multiple types are not available in OpenAPI 2.0 and are dropped.",
+				"warning gcp-openapi2-feature /components/schemas/Pet/properties/const/oneOf: " +
+					"Keyword oneOf is not available in OpenAPI 2.0 and is dropped.",
+				"warning gcp-openapi2-feature /paths/~1pets~1{id}/get/parameters/1/in: " +
+					"Cookie parameters are not available in OpenAPI 2.0 and are dropped.",
+			},
+		},
+		"kong": {
+			rules: lint.KongRules(),
+			expected: []string{
+				"info kong-regex-route /paths/~1pets~1{id}: Path parameters are converted to regular expression route.",
+				"warning kong-unsupported-keyword /components/schemas/Pet/properties/age/exclusiveMinimum: " +
+					"Keyword exclusiveMinimum is not supported by JSON Schema draft 4 of Kong request validator and is dropped.",
+				"warning kong-unsupported-keyword /components/schemas/Pet/properties/kind/const: " +
+					"Keyword const is not supported by JSON Schema draft 4 of Kong request validator and is dropped.",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			findings, err := lint.Run(doc, tc.rules...)
+			require.NoError(t, err)
+
+			messages := make([]string, 0, len(findings))
+			for _, f := range findings {
+				messages = append(messages, fmt.Sprintf("%s %s %s: %s", f.Severity, f.Rule, f.Pointer, f.Message))
+			}
+
+			assert.Equal(t, tc.expected, messages)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/report"
+)
+
+var methods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+}
+
+// OperationSummary requires operations to have summary.
+var OperationSummary = Rule{
+	Name:     "operation-summary",
+	Severity: report.SeverityWarning,
+	Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+		operations(doc, func(pointer string, op map[string]interface{}) {
+			if s, _ := op["summary"].(string); s == "" { //nolint:errcheck // Missing summary is empty.
+				found(pointer, "Operation should have summary.")
+			}
+		})
+	},
+}
+
+// OperationID requires operations to have operationId.
+var OperationID = Rule{
+	Name:     "operation-id",
+	Severity: report.SeverityWarning,
+	Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+		operations(doc, func(pointer string, op map[string]interface{}) {
+			if id, _ := op["operationId"].(string); id == "" { //nolint:errcheck // Missing ID is empty.
+				found(pointer, "Operation should have operationId.")
+			}
+		})
+	},
+}
+
+// ClientErrorResponse requires operations to declare a 4xx or default response.
+var ClientErrorResponse = Rule{
+	Name:     "client-error-response",
+	Severity: report.SeverityWarning,
+	Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+		operations(doc, func(pointer string, op map[string]interface{}) {
+			responses, _ := op["responses"].(map[string]interface{}) //nolint:errcheck // Missing responses are empty.
+
+			for status := range responses {
+				if status == "default" || strings.HasPrefix(strings.ToUpper(status), "4") {
+					return
+				}
+			}
+
+			found(pointer+"/responses", "Operation should declare 4xx response.")
+		})
+	},
+}
+
+// SchemaDescription requires component schemas to have description.
+var SchemaDescription = Rule{
+	Name:     "schema-description",
+	Severity: report.SeverityInfo,
+	Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+		components, _ := doc["components"].(map[string]interface{})  //nolint:errcheck // Missing components are fine.
+		schemas, _ := components["schemas"].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+		for _, name := range internal.SortedKeys(schemas) {
+			schema, ok := schemas[name].(map[string]interface{})
+			if !ok || schema["$ref"] != nil {
+				continue
+			}
+
+			if d, _ := schema["description"].(string); d == "" { //nolint:errcheck // Missing description is empty.
+				found("/components/schemas/"+openapi.PointerToken(name), "Schema should have description.")
+			}
+		}
+	},
+}
+
+// NoInlineEnum requires enums to be defined as component schemas, so that they are shared by name.
+var NoInlineEnum = Rule{
+	Name:     "no-inline-enum",
+	Severity: report.SeverityWarning,
+	Check: func(doc map[string]interface{}, found func(pointer, message string)) {
+		inlineEnums(doc, found)
+	},
+}
+
+// nameMaps are keywords of objects with arbitrary names as keys, e.g. property names.
+var nameMaps = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"dependentSchemas":  true,
+	"$defs":             true,
+	"definitions":       true,
+	"paths":             true,
+	"webhooks":          true,
+	"callbacks":         true,
+	"schemas":           true,
+	"responses":         true,
+	"parameters":        true,
+	"requestBodies":     true,
+	"headers":           true,
+	"content":           true,
+	"encoding":          true,
+	"links":             true,
+	"pathItems":         true,
+}
+
+// inlineEnums reports enums outside of component schemas.
+func inlineEnums(doc map[string]interface{}, found func(pointer, message string)) {
+	objects("", doc, false, func(pointer string, obj map[string]interface{}) {
+		if _, ok := obj["enum"].([]interface{}); ok && !isComponentSchema(pointer) {
+			found(pointer, "Enum should be defined as component schema.")
+		}
+	})
+}
+
+// objects calls f with JSON pointers and objects of v that have keywords as keys,
+// names are true if keys of v are names and not keywords.
+func objects(pointer string, v interface{}, names bool, f func(pointer string, obj map[string]interface{})) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if !names {
+			f(pointer, x)
+		}
+
+		for _, k := range internal.SortedKeys(x) {
+			// Server variables have enums of values that are not schemas, examples are values too.
+			if !names && (k == "servers" || k == "examples" || k == "example" || strings.HasPrefix(k, "x-")) {
+				continue
+			}
+
+			objects(pointer+"/"+openapi.PointerToken(k), x[k], !names && nameMaps[k], f)
+		}
+	case []interface{}:
+		for i, item := range x {
+			objects(pointer+"/"+strconv.Itoa(i), item, false, f)
+		}
+	}
+}
+
+func isComponentSchema(pointer string) bool {
+	name := strings.TrimPrefix(pointer, "/components/schemas/")
+
+	return name != pointer && !strings.Contains(name, "/")
+}
+
+// operations calls f with JSON pointers and operations of paths.
+func operations(doc map[string]interface{}, f func(pointer string, op map[string]interface{})) {
+	paths, _ := doc["paths"].(map[string]interface{}) //nolint:errcheck // Missing paths are empty.
+
+	for _, path := range internal.SortedKeys(paths) {
+		pathItem, _ := paths[path].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+		for _, method := range internal.SortedKeys(pathItem) {
+			op, ok := pathItem[method].(map[string]interface{})
+			if !ok || !methods[method] {
+				continue
+			}
+
+			f("/paths/"+openapi.PointerToken(path)+"/"+method, op)
+		}
+	}
+}
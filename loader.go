@@ -0,0 +1,600 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Default limits of Loader.
+const (
+	DefaultMaxDocumentSize = 16 << 20
+	DefaultMaxRefDepth     = 64
+	DefaultMaxAnchors      = 1000
+	DefaultMaxValues       = 1 << 20
+)
+
+// Loader reads OpenAPI documents and bundles external references into components.
+//
+// Limits protect against excessive resource usage when loading untrusted documents,
+// zero values enable default limits. AllowedHosts and BaseDir limit locations that references can target.
+type Loader struct {
+	// ReadLocation reads document by location, default reads local files and http(s) URLs.
+	//
+	// Custom reader is responsible for limiting amount of read data, MaxDocumentSize is checked after reading.
+	ReadLocation func(location string) ([]byte, error)
+
+	// MaxDocumentSize limits size of every loaded document in bytes, default DefaultMaxDocumentSize.
+	MaxDocumentSize int
+
+	// MaxRefDepth limits depth of nested external references, default DefaultMaxRefDepth.
+	MaxRefDepth int
+
+	// MaxAnchors limits total number of YAML anchors and aliases in every loaded document, default DefaultMaxAnchors.
+	MaxAnchors int
+
+	// MaxValues limits total number of values in bundled document, default DefaultMaxValues.
+	//
+	// Values of references that can not be placed in components are copied for every reference.
+	MaxValues int
+
+	// AllowedHosts lists hosts (with port if it is not default) that references of remote documents can target,
+	// e.g. "schemas.example.com".
+	//
+	// References of remote document are resolved relative to its URL and are limited to the origin
+	// (scheme and host) of the document by default, references to local files are rejected.
+	AllowedHosts []string
+
+	// BaseDir limits local files that references can target to the directory and its subdirectories,
+	// references of local documents to URLs are limited to AllowedHosts then.
+	//
+	// Local references are not limited if BaseDir is empty.
+	BaseDir string
+}
+
+// Load reads JSON or YAML document from location into spec (e.g. *openapi31.Spec).
+//
+// References to other files or URLs are resolved, referenced values are added
+// to the components of the root document and references are replaced with local ones.
+func (l Loader) Load(location string, spec json.Unmarshaler) error {
+	b := bundler{
+		loader:   l,
+		docs:     map[string]interface{}{},
+		bundled:  map[string]string{},
+		occupied: map[string]bool{},
+	}
+
+	data, err := b.bundle(location)
+	if err != nil {
+		return err
+	}
+
+	return spec.UnmarshalJSON(data)
+}
+
+func (l Loader) read(location string) ([]byte, error) {
+	if l.ReadLocation != nil {
+		data, err := l.ReadLocation(location)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) > l.maxDocumentSize() {
+			return nil, fmt.Errorf("document size exceeds %d bytes", l.maxDocumentSize())
+		}
+
+		return data, nil
+	}
+
+	if !isURL(location) {
+		f, err := os.Open(filepath.Clean(location))
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			_ = f.Close() //nolint:errcheck
+		}()
+
+		return l.readLimited(f)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s for %s", resp.Status, location)
+	}
+
+	return l.readLimited(resp.Body)
+}
+
+func (l Loader) readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(l.maxDocumentSize())+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > l.maxDocumentSize() {
+		return nil, fmt.Errorf("document size exceeds %d bytes", l.maxDocumentSize())
+	}
+
+	return data, nil
+}
+
+func (l Loader) maxDocumentSize() int {
+	if l.MaxDocumentSize > 0 {
+		return l.MaxDocumentSize
+	}
+
+	return DefaultMaxDocumentSize
+}
+
+func (l Loader) maxRefDepth() int {
+	if l.MaxRefDepth > 0 {
+		return l.MaxRefDepth
+	}
+
+	return DefaultMaxRefDepth
+}
+
+func (l Loader) maxValues() int {
+	if l.MaxValues > 0 {
+		return l.MaxValues
+	}
+
+	return DefaultMaxValues
+}
+
+func (l Loader) maxAnchors() int {
+	if l.MaxAnchors > 0 {
+		return l.MaxAnchors
+	}
+
+	return DefaultMaxAnchors
+}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// componentKinds maps reference context to components section.
+var componentKinds = map[string]string{
+	"schema":               "schemas",
+	"schemas":              "schemas",
+	"properties":           "schemas",
+	"items":                "schemas",
+	"additionalProperties": "schemas",
+	"allOf":                "schemas",
+	"anyOf":                "schemas",
+	"oneOf":                "schemas",
+	"not":                  "schemas",
+	"parameters":           "parameters",
+	"responses":            "responses",
+	"requestBody":          "requestBodies",
+	"requestBodies":        "requestBodies",
+	"headers":              "headers",
+	"examples":             "examples",
+	"links":                "links",
+	"callbacks":            "callbacks",
+	"securitySchemes":      "securitySchemes",
+}
+
+var componentNameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+
+type bundler struct {
+	loader     Loader
+	location   string
+	components map[string]map[string]interface{} // Bundled components by kind and name.
+
+	docs     map[string]interface{} // Loaded documents by location.
+	bundled  map[string]string      // Local references by absolute external references.
+	occupied map[string]bool        // Component references in use.
+	depth    int                    // Depth of currently bundled external reference.
+	values   int                    // Number of values in bundled document.
+}
+
+func (b *bundler) bundle(location string) ([]byte, error) {
+	doc, err := b.load(location)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("object expected in %s, %T received", location, doc)
+	}
+
+	b.location = location
+	b.components = map[string]map[string]interface{}{}
+
+	if components, ok := root["components"].(map[string]interface{}); ok {
+		for kind, items := range components {
+			if items, ok := items.(map[string]interface{}); ok {
+				for name := range items {
+					b.occupied["#/components/"+kind+"/"+name] = true
+				}
+			}
+		}
+	}
+
+	v, err := b.walk(root, location, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bundled := v.(map[string]interface{}) //nolint:errcheck // Walk preserves object type.
+
+	if len(b.components) > 0 {
+		components, ok := bundled["components"].(map[string]interface{})
+		if !ok {
+			components = map[string]interface{}{}
+			bundled["components"] = components
+		}
+
+		for kind, items := range b.components {
+			existing, ok := components[kind].(map[string]interface{})
+			if !ok {
+				existing = map[string]interface{}{}
+				components[kind] = existing
+			}
+
+			for name, item := range items {
+				existing[name] = item
+			}
+		}
+	}
+
+	return json.Marshal(bundled)
+}
+
+func (b *bundler) load(location string) (interface{}, error) {
+	if doc, ok := b.docs[location]; ok {
+		return doc, nil
+	}
+
+	data, err := b.loader.read(location)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", location, err)
+	}
+
+	var (
+		n   yaml.Node
+		doc interface{}
+	)
+
+	// YAML decoder also reads JSON.
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", location, err)
+	}
+
+	if anchors := countAnchors(&n); anchors > b.loader.maxAnchors() {
+		return nil, fmt.Errorf("decode %s: %d anchors and aliases exceed limit %d", location, anchors, b.loader.maxAnchors())
+	}
+
+	if err := n.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", location, err)
+	}
+
+	doc = normalizeDocument(doc)
+	b.docs[location] = doc
+
+	return doc, nil
+}
+
+// walk makes a copy of value with external references bundled.
+func (b *bundler) walk(v interface{}, base string, isRoot bool, keys []string) (interface{}, error) {
+	b.values++
+	if b.values > b.loader.maxValues() {
+		return nil, fmt.Errorf("bundled document exceeds %d values", b.loader.maxValues())
+	}
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := x["$ref"].(string); ok && (!isRoot || !strings.HasPrefix(ref, "#")) {
+			return b.bundleRef(x, ref, base, keys)
+		}
+
+		res := make(map[string]interface{}, len(x))
+
+		for k, item := range x {
+			item, err := b.walk(item, base, isRoot, append(keys, k))
+			if err != nil {
+				return nil, err
+			}
+
+			res[k] = item
+		}
+
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(x))
+
+		for i, item := range x {
+			item, err := b.walk(item, base, isRoot, append(keys, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			res[i] = item
+		}
+
+		return res, nil
+	default:
+		return v, nil
+	}
+}
+
+func (b *bundler) bundleRef(refHolder map[string]interface{}, ref, base string, keys []string) (interface{}, error) {
+	location, fragment := ref, ""
+	if pos := strings.Index(ref, "#"); pos >= 0 {
+		location, fragment = ref[:pos], ref[pos+1:]
+	}
+
+	if location == "" {
+		location = base
+	} else {
+		resolved, err := b.loader.resolveLocation(base, location)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s in %s: %w", ref, base, err)
+		}
+
+		location = resolved
+	}
+
+	// Root document components are referenced locally.
+	if location == b.location && strings.HasPrefix(fragment, "/components/") {
+		return replaceRef(refHolder, "#"+fragment), nil
+	}
+
+	absRef := location + "#" + fragment
+
+	if localRef, ok := b.bundled[absRef]; ok {
+		return replaceRef(refHolder, localRef), nil
+	}
+
+	if b.depth >= b.loader.maxRefDepth() {
+		return nil, fmt.Errorf("resolve %s: reference depth exceeds %d", absRef, b.loader.maxRefDepth())
+	}
+
+	b.depth++
+	defer func() { b.depth-- }()
+
+	doc, err := b.load(location)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := ResolvePointer(doc, fragment)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", absRef, err)
+	}
+
+	kind, name := componentPlacement(location, fragment, keys)
+
+	// Values that can not be placed in components are inlined.
+	if kind == "" {
+		return b.walk(target, location, false, keys)
+	}
+
+	localRef := b.componentRef(kind, name)
+	b.bundled[absRef] = localRef
+
+	bundled, err := b.walk(target, location, false, []string{kind})
+	if err != nil {
+		return nil, err
+	}
+
+	if b.components[kind] == nil {
+		b.components[kind] = map[string]interface{}{}
+	}
+
+	b.components[kind][strings.TrimPrefix(localRef, "#/components/"+kind+"/")] = bundled
+
+	return replaceRef(refHolder, localRef), nil
+}
+
+// componentRef reserves unique component reference.
+func (b *bundler) componentRef(kind, name string) string {
+	name = componentNameSanitizer.ReplaceAllString(name, "")
+	if name == "" {
+		name = kind
+	}
+
+	ref := "#/components/" + kind + "/" + name
+
+	for i := 2; b.occupied[ref]; i++ {
+		ref = "#/components/" + kind + "/" + name + strconv.Itoa(i)
+	}
+
+	b.occupied[ref] = true
+
+	return ref
+}
+
+func replaceRef(refHolder map[string]interface{}, localRef string) map[string]interface{} {
+	res := make(map[string]interface{}, len(refHolder))
+
+	for k, v := range refHolder {
+		res[k] = v
+	}
+
+	res["$ref"] = localRef
+
+	return res
+}
+
+// componentPlacement determines components section and name for a referenced value.
+func componentPlacement(location, fragment string, keys []string) (kind, name string) {
+	tokens := strings.Split(strings.TrimPrefix(fragment, "/"), "/")
+
+	if fragment != "" {
+		name = UnescapePointerToken(tokens[len(tokens)-1])
+	} else {
+		name = strings.TrimSuffix(path.Base(location), path.Ext(location))
+	}
+
+	if len(tokens) == 3 && tokens[0] == "components" {
+		return tokens[1], name
+	}
+
+	if len(tokens) == 2 && (tokens[0] == "definitions" || tokens[0] == "$defs") {
+		return "schemas", name
+	}
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		if kind, ok := componentKinds[keys[i]]; ok {
+			// Parameters, responses and headers can have properties named like schema keywords.
+			if i > 0 && keys[i-1] == "properties" {
+				continue
+			}
+
+			return kind, name
+		}
+	}
+
+	return "", name
+}
+
+// resolveLocation resolves location of reference relative to location of base document.
+func (l Loader) resolveLocation(base, location string) (string, error) {
+	if !isURL(base) {
+		if isURL(location) {
+			if u, err := url.Parse(location); l.BaseDir != "" && (err != nil || !l.allowedHost(u.Host)) {
+				return "", errors.New("remote reference is not allowed")
+			}
+
+			return location, nil
+		}
+
+		if !filepath.IsAbs(location) {
+			location = filepath.Join(filepath.Dir(base), filepath.FromSlash(location))
+		}
+
+		if l.BaseDir != "" && !l.inBaseDir(location) {
+			return "", fmt.Errorf("location is outside of %s", l.BaseDir)
+		}
+
+		return location, nil
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+
+	// other schemes (e.g. "file:") and volume names (e.g. "C:") are not allowed.
+	if filepath.VolumeName(location) != "" || (r.Scheme != "" && !isURL(location)) {
+		return "", errors.New("local reference in remote document is not allowed")
+	}
+
+	res := u.ResolveReference(r)
+
+	if (res.Scheme != u.Scheme || !strings.EqualFold(res.Host, u.Host)) && !l.allowedHost(res.Host) {
+		return "", fmt.Errorf("origin %s://%s is not allowed", res.Scheme, res.Host)
+	}
+
+	return res.String(), nil
+}
+
+// inBaseDir checks if location is in BaseDir, symbolic links of existing files are evaluated.
+func (l Loader) inBaseDir(location string) bool {
+	dir, err := filepath.Abs(l.BaseDir)
+	if err != nil {
+		return false
+	}
+
+	location, err = filepath.Abs(location)
+	if err != nil {
+		return false
+	}
+
+	if d, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = d
+	}
+
+	if loc, err := filepath.EvalSymlinks(location); err == nil {
+		location = loc
+	}
+
+	rel, err := filepath.Rel(dir, location)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (l Loader) allowedHost(host string) bool {
+	for _, h := range l.AllowedHosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countAnchors counts anchors and aliases in YAML node tree.
+func countAnchors(n *yaml.Node) int {
+	cnt := 0
+
+	if n.Anchor != "" || n.Kind == yaml.AliasNode {
+		cnt++
+	}
+
+	for _, c := range n.Content {
+		cnt += countAnchors(c)
+	}
+
+	return cnt
+}
+
+// normalizeDocument converts YAML maps with non-string keys (e.g. response codes) to JSON-compatible maps.
+func normalizeDocument(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+
+		for k, item := range x {
+			m[fmt.Sprint(k)] = normalizeDocument(item)
+		}
+
+		return m
+	case map[string]interface{}:
+		for k, item := range x {
+			x[k] = normalizeDocument(item)
+		}
+	case []interface{}:
+		for i, item := range x {
+			x[i] = normalizeDocument(item)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,224 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+)
+
+type rawDoc map[string]interface{}
+
+func (d *rawDoc) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*map[string]interface{})(d))
+}
+
+func TestLoader_Load(t *testing.T) {
+	docs := map[string]string{
+		"https://example.com/api/openapi.json": `{"paths":{"/":{"get":{"responses":{"200":{
+			"description":"OK","content":{"application/json":{"schema":{"$ref":"../schemas/pet.json"}}}
+		}}}}}}`,
+		"https://example.com/schemas/pet.json": `{"type":"object","properties":{"tag":{"$ref":"#/$defs/Tag"}},
+			"$defs":{"Tag":{"type":"string"}}}`,
+	}
+
+	l := openapi.Loader{
+		ReadLocation: func(location string) ([]byte, error) {
+			if d, ok := docs[location]; ok {
+				return []byte(d), nil
+			}
+
+			return nil, errors.New("not found")
+		},
+	}
+
+	var d rawDoc
+
+	require.NoError(t, l.Load("https://example.com/api/openapi.json", &d))
+	assertjson.EqMarshal(t, `{
+	  "paths":{"/":{"get":{"responses":{"200":{
+		"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/pet"}}}
+	  }}}}},
+	  "components":{"schemas":{
+		"pet":{"$defs":{"Tag":{"type":"string"}},"properties":{"tag":{"$ref":"#/components/schemas/Tag"}},"type":"object"},
+		"Tag":{"type":"string"}
+	  }}
+	}`, d)
+}
+
+func TestLoader_Load_remoteReferences(t *testing.T) {
+	var read []string
+
+	docs := map[string]string{
+		"https://example.com/api/local.json":     `{"info":{"$ref":"/etc/passwd"}}`,
+		"https://example.com/api/file.json":      `{"info":{"$ref":"file:///etc/passwd"}}`,
+		"https://example.com/api/cross.json":     `{"info":{"$ref":"http://internal.example/info.json"}}`,
+		"https://example.com/api/downgrade.json": `{"info":{"$ref":"http://example.com/info.json"}}`,
+		"http://internal.example/info.json":      `{"title":"Internal"}`,
+	}
+
+	l := openapi.Loader{
+		ReadLocation: func(location string) ([]byte, error) {
+			read = append(read, location)
+
+			if d, ok := docs[location]; ok {
+				return []byte(d), nil
+			}
+
+			return nil, errors.New("not found")
+		},
+	}
+
+	var d rawDoc
+
+	// Absolute path is resolved on the host of remote document, local file is not read.
+	assert.EqualError(t, l.Load("https://example.com/api/local.json", &d),
+		"read https://example.com/etc/passwd: not found")
+	assert.Equal(t, []string{"https://example.com/api/local.json", "https://example.com/etc/passwd"}, read)
+
+	assert.EqualError(t, l.Load("https://example.com/api/file.json", &d),
+		"resolve file:///etc/passwd in https://example.com/api/file.json: "+
+			"local reference in remote document is not allowed")
+	assert.EqualError(t, l.Load("https://example.com/api/cross.json", &d),
+		"resolve http://internal.example/info.json in https://example.com/api/cross.json: "+
+			"origin http://internal.example is not allowed")
+	assert.EqualError(t, l.Load("https://example.com/api/downgrade.json", &d),
+		"resolve http://example.com/info.json in https://example.com/api/downgrade.json: "+
+			"origin http://example.com is not allowed")
+
+	l.AllowedHosts = []string{"internal.example"}
+
+	require.NoError(t, l.Load("https://example.com/api/cross.json", &d))
+	assertjson.EqMarshal(t, `{"info":{"title":"Internal"}}`, d)
+}
+
+func TestLoader_Load_limits(t *testing.T) {
+	docs := map[string]string{
+		"big.json": `{"info":{"description":"` + strings.Repeat("a", 100) + `"}}`,
+		"anchors.yaml": `
+a: &a [1, 2]
+b: [*a, *a, *a]
+`,
+	}
+
+	// Every schema references the next one in a separate file.
+	for i := 0; i < 10; i++ {
+		docs["s"+strconv.Itoa(i)+".json"] = `{"items":{"$ref":"s` + strconv.Itoa(i+1) + `.json"}}`
+	}
+
+	docs["s10.json"] = `{"type":"string"}`
+
+	// Values of references that can not be placed in components are copied.
+	docs["copies.json"] = `{"x-a":{"$ref":"values.json"},"x-b":{"$ref":"values.json"}}`
+	docs["values.json"] = `[1,2,3,4,5,6,7,8,9]`
+
+	l := openapi.Loader{
+		ReadLocation: func(location string) ([]byte, error) {
+			if d, ok := docs[location]; ok {
+				return []byte(d), nil
+			}
+
+			return nil, errors.New("not found")
+		},
+		MaxDocumentSize: 100,
+		MaxRefDepth:     5,
+		MaxAnchors:      3,
+	}
+
+	var d rawDoc
+
+	assert.EqualError(t, l.Load("big.json", &d), "read big.json: document size exceeds 100 bytes")
+	assert.EqualError(t, l.Load("anchors.yaml", &d), "decode anchors.yaml: 4 anchors and aliases exceed limit 3")
+	assert.EqualError(t, l.Load("s0.json", &d), "resolve s6.json#: reference depth exceeds 5")
+
+	l.MaxRefDepth = 0
+	require.NoError(t, l.Load("s0.json", &d))
+
+	l.MaxValues = 15
+	assert.EqualError(t, l.Load("copies.json", &d), "bundled document exceeds 15 values")
+}
+
+func TestLoader_Load_baseDir(t *testing.T) {
+	dir := t.TempDir()
+	specs := filepath.Join(dir, "specs")
+
+	require.NoError(t, os.Mkdir(specs, 0o700))
+
+	for name, data := range map[string]string{
+		"secret.json":              `{"title":"Secret"}`,
+		"specs/info.json":          `{"title":"Info"}`,
+		"specs/openapi.json":       `{"info":{"$ref":"info.json"}}`,
+		"specs/parent.json":        `{"info":{"$ref":"../secret.json"}}`,
+		"specs/absolute.json":      `{"info":{"$ref":"` + filepath.ToSlash(filepath.Join(dir, "secret.json")) + `"}}`,
+		"specs/remote.json":        `{"info":{"$ref":"https://example.com/info.json"}}`,
+		"specs/nested/escape.json": `{"info":{"$ref":"../../secret.json"}}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+
+	l := openapi.Loader{BaseDir: specs}
+
+	var d rawDoc
+
+	require.NoError(t, l.Load(filepath.Join(specs, "openapi.json"), &d))
+	assertjson.EqMarshal(t, `{"info":{"title":"Info"}}`, d)
+
+	for _, name := range []string{"parent.json", "absolute.json", "nested/escape.json"} {
+		err := l.Load(filepath.Join(specs, name), &d)
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "location is outside of "+specs, name)
+	}
+
+	err := l.Load(filepath.Join(specs, "remote.json"), &d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remote reference is not allowed")
+
+	// Local references are not limited without BaseDir.
+	require.NoError(t, openapi.Loader{}.Load(filepath.Join(specs, "parent.json"), &d))
+	assertjson.EqMarshal(t, `{"info":{"title":"Secret"}}`, d)
+}
+
+func FuzzLoader_Load(f *testing.F) {
+	f.Add([]byte(`{"paths":{"/":{"get":{"responses":{"200":{"$ref":"other.yaml#/components/responses/ok"}}}}}}`))
+	f.Add([]byte(`{"components":{"schemas":{"a":{"$ref":"#/components/schemas/a"}}}}`))
+	f.Add([]byte("a: &a [1, 2]\nb: [*a, *a]\nc: {$ref: 'root.yaml#/b/0'}\n"))
+	f.Add([]byte(`{"items":{"$ref":"other.yaml#/missing~1path/0"}}`))
+
+	other := `components:
+  responses:
+    ok:
+      description: OK
+      content:
+        application/json:
+          schema: {$ref: "root.yaml#/components/schemas/a"}
+`
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		l := openapi.Loader{
+			ReadLocation: func(location string) ([]byte, error) {
+				switch location {
+				case "root.yaml":
+					return data, nil
+				case "other.yaml":
+					return []byte(other), nil
+				}
+
+				return nil, errors.New("not found")
+			},
+			MaxDocumentSize: 1 << 16,
+		}
+
+		var d rawDoc
+
+		_ = l.Load("root.yaml", &d) //nolint:errcheck // Only panics and hangs are of interest.
+	})
+}
@@ -0,0 +1,2 @@
+// Package mock serves example payloads of spec operations for frontend development against unfinished backends.
+package mock
@@ -0,0 +1,376 @@
+package mock
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// Request headers to select response.
+const (
+	// StatusHeader selects declared response status, e.g. "404", successful response is served by default.
+	StatusHeader = "X-Mock-Status"
+
+	// ExampleHeader selects named example of response media type, the first example is served by default.
+	ExampleHeader = "X-Mock-Example"
+)
+
+// Handler serves responses of spec operations.
+//
+// Payload is an example of response media type, or it is generated from response schema
+// with openapi.ExampleGenerator. Media type is negotiated with Accept header.
+//
+// Path parameter matches a single path segment, or remaining segments if it has "x-wildcard" extension.
+//
+//	h, err := mock.NewHandler(r.Spec)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	log.Fatal(http.ListenAndServe("localhost:8080", h))
+type Handler struct {
+	// ValidateRequests enables checking of request parameters and body against operation,
+	// invalid requests are answered with 400 Bad Request and a list of mismatches.
+	//
+	// Dependencies between query parameters of "x-parameters-schema" extension are checked too.
+	ValidateRequests bool
+
+	doc    map[string]interface{}
+	routes []route
+}
+
+type route struct {
+	path       string
+	segments   []string
+	params     int
+	wildcard   bool // Last segment is a catch-all parameter that matches remaining segments.
+	parameters []interface{}
+	operations map[string]map[string]interface{}
+}
+
+// NewHandler creates handler of spec, e.g. *openapi31.Spec of a reflector.
+//
+// Spec is captured at creation, operations added later are not served.
+func NewHandler(spec json.Marshaler) (*Handler, error) {
+	data, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handler{}
+	if err := json.Unmarshal(data, &h.doc); err != nil {
+		return nil, err
+	}
+
+	paths, _ := h.doc["paths"].(map[string]interface{}) //nolint:errcheck // Missing paths are empty.
+
+	for _, path := range internal.SortedKeys(paths) {
+		pathItem, _ := paths[path].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+		rt := route{path: path, segments: strings.Split(path, "/"), operations: map[string]map[string]interface{}{}}
+		rt.parameters, _ = pathItem["parameters"].([]interface{}) //nolint:errcheck // Missing parameters are empty.
+
+		for _, s := range rt.segments {
+			if strings.HasPrefix(s, "{") {
+				rt.params++
+			}
+		}
+
+		for method, op := range pathItem {
+			if op, ok := op.(map[string]interface{}); ok && method != "parameters" && !strings.HasPrefix(method, "x-") {
+				rt.operations[strings.ToUpper(method)] = op
+				params, _ := op["parameters"].([]interface{}) //nolint:errcheck // Missing parameters are empty.
+				rt.wildcard = rt.wildcard || h.hasWildcard(rt.segments, params)
+			}
+		}
+
+		rt.wildcard = rt.wildcard || h.hasWildcard(rt.segments, rt.parameters)
+
+		h.routes = append(h.routes, rt)
+	}
+
+	// Static segments take precedence over path parameters, and path parameters over catch-all ones.
+	sort.SliceStable(h.routes, func(i, j int) bool {
+		if h.routes[i].wildcard != h.routes[j].wildcard {
+			return !h.routes[i].wildcard
+		}
+
+		return h.routes[i].params < h.routes[j].params
+	})
+
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(r.URL.Path, "/")
+
+	var (
+		matched *route
+		allowed = map[string]bool{}
+	)
+
+	for i := range h.routes {
+		rt := &h.routes[i]
+		if !rt.match(segments) {
+			continue
+		}
+
+		if _, ok := rt.operations[r.Method]; ok {
+			matched = rt
+
+			break
+		}
+
+		for method := range rt.operations {
+			allowed[method] = true
+		}
+	}
+
+	if matched == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(internal.SortedKeys(allowed), ", "))
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		http.NotFound(w, r)
+
+		return
+	}
+
+	op := matched.operations[r.Method]
+
+	if h.ValidateRequests {
+		if mismatches, status := h.checkRequest(r, matched, segments, op); len(mismatches) > 0 {
+			http.Error(w, "request does not match spec:\n  "+strings.Join(mismatches, "\n  "), status)
+
+			return
+		}
+	}
+
+	h.serveOperation(w, r, op)
+}
+
+// hasWildcard checks if last path segment is a parameter with "x-wildcard" extension.
+func (h *Handler) hasWildcard(segments []string, params []interface{}) bool {
+	last := segments[len(segments)-1]
+
+	for _, p := range params {
+		param := h.resolve(p)
+		name, _ := param["name"].(string) //nolint:errcheck // Parameter without name is ignored.
+
+		if param["in"] == "path" && param["x-wildcard"] == true && "{"+name+"}" == last {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rt *route) match(segments []string) bool {
+	if len(segments) != len(rt.segments) && (!rt.wildcard || len(segments) < len(rt.segments)) {
+		return false
+	}
+
+	for i, s := range rt.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if segments[i] == "" {
+				return false
+			}
+
+			continue
+		}
+
+		if s != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (h *Handler) serveOperation(w http.ResponseWriter, r *http.Request, op map[string]interface{}) {
+	responses, _ := op["responses"].(map[string]interface{}) //nolint:errcheck // Missing responses are empty.
+
+	status, key := selectStatus(responses, r.Header.Get(StatusHeader))
+	if key == "" {
+		http.Error(w, "response status is not declared: "+r.Header.Get(StatusHeader), http.StatusBadRequest)
+
+		return
+	}
+
+	resp := h.resolve(responses[key])
+	content, _ := resp["content"].(map[string]interface{}) //nolint:errcheck // Missing content is empty.
+
+	if len(content) == 0 {
+		w.WriteHeader(status)
+
+		return
+	}
+
+	contentType := negotiate(internal.SortedKeys(content), r.Header.Get("Accept"))
+	if contentType == "" {
+		http.Error(w, "none of response media types is acceptable", http.StatusNotAcceptable)
+
+		return
+	}
+
+	media, _ := content[contentType].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+	payload := h.example(media, r.Header.Get(ExampleHeader))
+
+	var body []byte
+
+	if s, ok := payload.(string); ok && !internal.IsJSONMediaType(contentType) {
+		body = []byte(s)
+	} else {
+		var err error
+
+		if body, err = json.Marshal(payload); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(body) //nolint:errcheck // Client errors are not handled.
+}
+
+// selectStatus returns HTTP status and response key, requested status or
+// the lowest successful status is selected.
+func selectStatus(responses map[string]interface{}, requested string) (int, string) {
+	if requested != "" {
+		status, err := strconv.Atoi(requested)
+		if err != nil || status < 100 || status > 599 {
+			return 0, ""
+		}
+
+		for _, key := range []string{requested, requested[:1] + "XX", "default"} {
+			if _, ok := responses[key]; ok {
+				return status, key
+			}
+		}
+
+		return 0, ""
+	}
+
+	keys := internal.SortedKeys(responses)
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, "2") {
+			return statusOf(key), key
+		}
+	}
+
+	if _, ok := responses["default"]; ok {
+		return http.StatusOK, "default"
+	}
+
+	if len(keys) > 0 {
+		return statusOf(keys[0]), keys[0]
+	}
+
+	return 0, ""
+}
+
+func statusOf(key string) int {
+	if status, err := strconv.Atoi(strings.ReplaceAll(strings.ToUpper(key), "XX", "00")); err == nil {
+		return status
+	}
+
+	return http.StatusOK
+}
+
+// negotiate returns the first media type that is accepted with the highest quality.
+func negotiate(mediaTypes []string, accept string) string {
+	if accept == "" {
+		return mediaTypes[0]
+	}
+
+	type accepted struct {
+		rng string
+		q   float64
+	}
+
+	var ranges []accepted
+
+	for _, part := range strings.Split(accept, ",") {
+		rng, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			ranges = append(ranges, accepted{rng: rng, q: q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	for _, a := range ranges {
+		for _, mt := range mediaTypes {
+			base, _, err := mime.ParseMediaType(mt)
+			if err != nil {
+				base = mt
+			}
+
+			if a.rng == "*/*" || a.rng == base ||
+				(strings.HasSuffix(a.rng, "/*") && strings.HasPrefix(base, strings.TrimSuffix(a.rng, "*"))) {
+				return mt
+			}
+		}
+	}
+
+	return ""
+}
+
+// example returns named or the first example of media type, or generates payload from schema.
+func (h *Handler) example(media map[string]interface{}, name string) interface{} {
+	examples, _ := media["examples"].(map[string]interface{}) //nolint:errcheck // Missing examples are empty.
+
+	if name != "" {
+		if ex, ok := examples[name]; ok {
+			return h.resolve(ex)["value"]
+		}
+	}
+
+	if ex, ok := media["example"]; ok {
+		return ex
+	}
+
+	if keys := internal.SortedKeys(examples); len(keys) > 0 {
+		return h.resolve(examples[keys[0]])["value"]
+	}
+
+	schema, _ := media["schema"].(map[string]interface{}) //nolint:errcheck // Missing schema gives no payload.
+
+	return openapi.ExampleGenerator{Resolve: h.resolveRef}.Generate(schema)
+}
+
+func (h *Handler) resolveRef(ref string) (map[string]interface{}, bool) {
+	schema := h.resolve(map[string]interface{}{"$ref": ref})
+
+	return schema, schema != nil
+}
+
+// resolve follows local references of value.
+func (h *Handler) resolve(v interface{}) map[string]interface{} {
+	return internal.ResolveLocalRef(h.doc, v)
+}
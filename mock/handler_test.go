@@ -0,0 +1,259 @@
+package mock_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/mock"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type user struct {
+	ID      int       `json:"id" minimum:"1"`
+	Name    string    `json:"name" example:"Jane"`
+	Role    string    `json:"role" enum:"admin,guest"`
+	Created time.Time `json:"created"`
+	Tags    []string  `json:"tags"`
+	Manager *user     `json:"manager,omitempty"`
+}
+
+type userReq struct {
+	ID int `path:"id"`
+}
+
+type apiError struct {
+	Message string `json:"message"`
+}
+
+func newHandler(t *testing.T) *mock.Handler {
+	t.Helper()
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(userReq{})
+	oc.AddRespStructure(user{})
+	oc.AddRespStructure(apiError{}, openapi.WithHTTPStatus(http.StatusNotFound))
+	oc.AddRespStructure("", openapi.WithContentType("text/plain"))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/users/me")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(apiError{}, openapi.WithHTTPStatus(http.StatusUnauthorized))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodDelete, "/users/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(userReq{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	require.NoError(t, r.AddOperation(oc))
+
+	h, err := mock.NewHandler(r.Spec)
+	require.NoError(t, err)
+
+	return h
+}
+
+func serve(h http.Handler, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	return rw
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	h := newHandler(t)
+
+	rw := serve(h, http.MethodGet, "/users/123", map[string]string{"Accept": "application/json"})
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	assertjson.Equal(t, []byte(`{
+	  "id":1,"name":"Jane","role":"admin","created":"2006-01-02T15:04:05Z","tags":["string"]
+	}`), rw.Body.Bytes())
+
+	rw = serve(h, http.MethodGet, "/users/123", map[string]string{"Accept": "text/*"})
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "text/plain", rw.Header().Get("Content-Type"))
+	assert.Equal(t, "string", rw.Body.String())
+
+	rw = serve(h, http.MethodGet, "/users/123", map[string]string{"Accept": "image/png"})
+	assert.Equal(t, http.StatusNotAcceptable, rw.Code)
+
+	rw = serve(h, http.MethodGet, "/users/123", map[string]string{mock.StatusHeader: "404"})
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assertjson.Equal(t, []byte(`{"message":"string"}`), rw.Body.Bytes())
+
+	rw = serve(h, http.MethodGet, "/users/123", map[string]string{mock.StatusHeader: "500"})
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+
+	rw = serve(h, http.MethodGet, "/users/me", nil)
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+	rw = serve(h, http.MethodDelete, "/users/123", nil)
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Empty(t, rw.Body.String())
+
+	rw = serve(h, http.MethodPost, "/users/123", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Equal(t, "DELETE, GET", rw.Header().Get("Allow"))
+
+	rw = serve(h, http.MethodGet, "/orders", nil)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestHandler_ServeHTTP_status(t *testing.T) {
+	h, err := mock.NewHandler(json.RawMessage(`{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/items/{id}":{"get":{"responses":{"default":{"description":"Error"}}},"put":{"responses":{}}},
+		"/items/{name}":{"put":{"responses":{}}}
+	  }
+	}`))
+	require.NoError(t, err)
+
+	rw := serve(h, http.MethodGet, "/items/1", map[string]string{mock.StatusHeader: "503"})
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	for _, status := range []string{"1", "-5", "99", "600", "5000"} {
+		rw = serve(h, http.MethodGet, "/items/1", map[string]string{mock.StatusHeader: status})
+		assert.Equal(t, http.StatusBadRequest, rw.Code, status)
+	}
+
+	rw = serve(h, http.MethodPost, "/items/1", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Equal(t, "GET, PUT", rw.Header().Get("Allow"))
+}
+
+func TestHandler_ServeHTTP_examples(t *testing.T) {
+	h, err := mock.NewHandler(json.RawMessage(`{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{"/status":{"get":{"responses":{"200":{
+		"description":"OK",
+		"content":{"application/json":{"examples":{
+		  "down":{"$ref":"#/components/examples/down"},
+		  "up":{"value":{"status":"up"}}
+		}}}
+	  }}}}},
+	  "components":{"examples":{"down":{"value":{"status":"down"}}}}
+	}`))
+	require.NoError(t, err)
+
+	rw := serve(h, http.MethodGet, "/status", nil)
+	assertjson.Equal(t, []byte(`{"status":"down"}`), rw.Body.Bytes())
+
+	rw = serve(h, http.MethodGet, "/status", map[string]string{mock.ExampleHeader: "up"})
+	assertjson.Equal(t, []byte(`{"status":"up"}`), rw.Body.Bytes())
+}
+
+func TestHandler_ServeHTTP_validateRequests(t *testing.T) {
+	h, err := mock.NewHandler(json.RawMessage(`{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/items":{
+		  "parameters":[{"name":"X-Tenant","in":"header","required":true,"schema":{"type":"string"}}],
+		  "get":{
+			"parameters":[
+			  {"name":"ids","in":"query","schema":{"type":"array","items":{"type":"integer"}}},
+			  {"name":"kinds","in":"query","schema":{"type":"array","items":{"$ref":"#/components/schemas/Kind"}}}
+			],
+			"responses":{"204":{"description":"OK"}}
+		  },
+		  "post":{
+			"requestBody":{"required":true,"content":{"application/json":{"schema":{"type":"object"}}}},
+			"responses":{"204":{"description":"OK"}}
+		  }
+		},
+		"/files/{path}":{
+		  "get":{
+			"parameters":[{"name":"path","in":"path","required":true,"x-wildcard":true,"schema":{"type":"string","pattern":"\\.txt$"}}],
+			"responses":{"204":{"description":"OK"}}
+		  }
+		}
+	  },
+	  "components":{"schemas":{"Kind":{"type":"integer","enum":[1,2]}}}
+	}`))
+	require.NoError(t, err)
+
+	h.ValidateRequests = true
+
+	rw := serve(h, http.MethodGet, "/items?ids=1&ids=3&kinds=1,2", map[string]string{"X-Tenant": "a"})
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+
+	rw = serve(h, http.MethodGet, "/items?kinds=3", map[string]string{"X-Tenant": "a"})
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), "query kinds/0: ")
+	assert.NotContains(t, rw.Body.String(), "expected type")
+
+	rw = serve(h, http.MethodGet, "/files/a/b/c.txt", nil)
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+
+	rw = serve(h, http.MethodGet, "/files/a/b/c.bin", nil)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), "path path: ")
+
+	rw = serve(h, http.MethodGet, "/items?ids=1,a", nil)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, `request does not match spec:
+  query ids/1: expected type integer, got string "a"
+  header X-Tenant: required parameter is missing
+`, rw.Body.String())
+
+	rw = serve(h, http.MethodPost, "/items", map[string]string{"X-Tenant": "a"})
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, "request does not match spec:\n  body: required body is missing\n", rw.Body.String())
+}
+
+func TestHandler_ServeHTTP_validateQueryDependencies(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type listReq struct {
+		Cursor  string `query:"cursor"`
+		Page    int    `query:"page"`
+		Sort    string `query:"sort"`
+		SortDir string `query:"sort_dir"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(listReq{},
+		openapi.WithOneOfQueryParams("cursor", "page"),
+		openapi.WithDependentQueryParams("sort_dir", "sort"),
+	)
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	require.NoError(t, r.AddOperation(oc))
+
+	h, err := mock.NewHandler(r.Spec)
+	require.NoError(t, err)
+
+	h.ValidateRequests = true
+
+	assert.Equal(t, http.StatusNoContent, serve(h, http.MethodGet, "/items?cursor=abc&sort=name&sort_dir=asc", nil).Code)
+	assert.Equal(t, http.StatusNoContent, serve(h, http.MethodGet, "/items?page=2", nil).Code)
+
+	for query, mismatch := range map[string]string{
+		"":                    "query parameters: value matches 0 of oneOf schemas, exactly one expected",
+		"cursor=abc&page=2":   "query parameters: value matches 2 of oneOf schemas, exactly one expected",
+		"page=2&sort_dir=asc": "query sort: property is required by sort_dir",
+	} {
+		rw := serve(h, http.MethodGet, "/items?"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, rw.Code, query)
+		assert.Equal(t, "request does not match spec:\n  "+mismatch+"\n", rw.Body.String(), query)
+	}
+}
@@ -0,0 +1,172 @@
+package mock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// maxRequestBody limits size of request body that is checked by Handler.ValidateRequests.
+const maxRequestBody = 16 << 20
+
+// checkRequest returns mismatches of request and operation, and HTTP status to report them.
+func (h *Handler) checkRequest(r *http.Request, rt *route, segments []string, op map[string]interface{}) ([]string, int) {
+	params, _ := op["parameters"].([]interface{}) //nolint:errcheck // Missing parameters are empty.
+
+	var mismatches []string
+
+	// Operation parameters override path item parameters with the same location and name.
+	declared := map[string]bool{}
+
+	for _, p := range append(append([]interface{}{}, params...), rt.parameters...) {
+		param := h.resolve(p)
+		name, _ := param["name"].(string) //nolint:errcheck // Parameter without name is ignored.
+		in, _ := param["in"].(string)     //nolint:errcheck // Parameter without location is ignored.
+
+		if name == "" || declared[in+" "+name] {
+			continue
+		}
+
+		declared[in+" "+name] = true
+		mismatches = append(mismatches, h.checkParameter(r, rt, segments, in, name, param)...)
+	}
+
+	mismatches = append(mismatches, h.checkQueryDependencies(r, op)...)
+
+	bodyMismatches, status := h.checkBody(r, h.resolve(op["requestBody"]))
+	if len(bodyMismatches) > 0 && status != http.StatusBadRequest {
+		return bodyMismatches, status
+	}
+
+	return append(mismatches, bodyMismatches...), http.StatusBadRequest
+}
+
+func (h *Handler) checkParameter(r *http.Request, rt *route, segments []string, in, name string, param map[string]interface{}) []string {
+	var values []string
+
+	switch in {
+	case "path":
+		for i, s := range rt.segments {
+			switch {
+			case s != "{"+name+"}":
+			case rt.wildcard && i == len(rt.segments)-1:
+				values = []string{strings.Join(segments[i:], "/")}
+			default:
+				values = []string{segments[i]}
+			}
+		}
+	case "query":
+		values = r.URL.Query()[name]
+	case "header":
+		values = r.Header.Values(name)
+	case "cookie":
+		if c, err := r.Cookie(name); err == nil {
+			values = []string{c.Value}
+		}
+	}
+
+	if len(values) == 0 {
+		if param["required"] == true {
+			return []string{in + " " + name + ": required parameter is missing"}
+		}
+
+		return nil
+	}
+
+	schema, _ := param["schema"].(map[string]interface{}) //nolint:errcheck // Missing schema accepts any value.
+	if schema == nil {
+		return nil
+	}
+
+	var mismatches []string
+
+	for _, e := range internal.ValidateValue(schema, internal.ParameterValue(schema, values, h.resolve), h.resolveRef) {
+		mismatches = append(mismatches, in+" "+name+e.Pointer+": "+e.Message)
+	}
+
+	return mismatches
+}
+
+// checkQueryDependencies checks query parameters against "x-parameters-schema" extension of operation,
+// it declares dependencies between parameters, e.g. mutually exclusive "cursor" and "page".
+func (h *Handler) checkQueryDependencies(r *http.Request, op map[string]interface{}) []string {
+	schema, _ := op["x-parameters-schema"].(map[string]interface{}) //nolint:errcheck // Missing schema has no dependencies.
+	if schema == nil {
+		return nil
+	}
+
+	query := r.URL.Query()
+	params := make(map[string]interface{}, len(query))
+
+	for name, values := range query {
+		params[name] = values[0]
+	}
+
+	var mismatches []string
+
+	for _, e := range internal.ValidateValue(schema, params, h.resolveRef) {
+		name := "parameters"
+		if e.Pointer != "" {
+			name = openapi.UnescapePointerToken(strings.TrimPrefix(e.Pointer, "/"))
+		}
+
+		mismatches = append(mismatches, "query "+name+": "+e.Message)
+	}
+
+	return mismatches
+}
+
+func (h *Handler) checkBody(r *http.Request, requestBody map[string]interface{}) ([]string, int) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody+1))
+	if err != nil {
+		return []string{"read body: " + err.Error()}, http.StatusBadRequest
+	}
+
+	if len(body) > maxRequestBody {
+		return []string{"body is too large"}, http.StatusRequestEntityTooLarge
+	}
+
+	if len(body) == 0 {
+		if requestBody["required"] == true {
+			return []string{"body: required body is missing"}, http.StatusBadRequest
+		}
+
+		return nil, http.StatusBadRequest
+	}
+
+	content, _ := requestBody["content"].(map[string]interface{}) //nolint:errcheck // Missing content is empty.
+	if len(content) == 0 {
+		return []string{"body is not declared"}, http.StatusBadRequest
+	}
+
+	contentType := r.Header.Get("Content-Type")
+
+	media, ok := internal.MatchMediaType(content, contentType)
+	if !ok {
+		return []string{"content type " + strconv.Quote(contentType) + " is not declared, expected one of " +
+			strings.Join(internal.SortedKeys(content), ", ")}, http.StatusUnsupportedMediaType
+	}
+
+	schema, _ := media["schema"].(map[string]interface{}) //nolint:errcheck // Missing schema accepts any body.
+	if schema == nil || !internal.IsJSONMediaType(contentType) {
+		return nil, http.StatusBadRequest
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{"invalid JSON body: " + err.Error()}, http.StatusBadRequest
+	}
+
+	var mismatches []string
+
+	for _, e := range internal.ValidateValue(schema, value, h.resolveRef) {
+		mismatches = append(mismatches, "body"+e.Pointer+": "+e.Message)
+	}
+
+	return mismatches, http.StatusBadRequest
+}
@@ -0,0 +1,53 @@
+package openapi
+
+import "strings"
+
+// MountedReflector is a Reflector scoped to a path prefix, it helps to compose a document
+// of sub-APIs that are defined in separate modules.
+//
+//	v1 := r.Mount("/v1")
+//	admin := v1.Mount("/admin", "Admin")
+//
+//	oc, err := admin.NewOperationContext(http.MethodGet, "/users") // GET /v1/admin/users, tagged "Admin".
+type MountedReflector struct {
+	Reflector
+
+	// Prefix is prepended to path patterns of new operation contexts.
+	Prefix string
+
+	// Tags are set to new operation contexts, so that DefaultTags of reflector are not applied.
+	Tags []string
+}
+
+// NewOperationContext initializes operation context with prefixed path pattern and mount tags.
+func (m MountedReflector) NewOperationContext(method, pathPattern string) (OperationContext, error) {
+	oc, err := m.Reflector.NewOperationContext(method, joinPath(m.Prefix, pathPattern))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.Tags) > 0 {
+		oc.SetTags(m.Tags...)
+	}
+
+	return oc, nil
+}
+
+// Mount returns a nested mount, prefixes are joined and tags are appended.
+func (m MountedReflector) Mount(prefix string, tags ...string) MountedReflector {
+	return MountedReflector{
+		Reflector: m.Reflector,
+		Prefix:    joinPath(m.Prefix, prefix),
+		Tags:      append(append([]string(nil), m.Tags...), tags...),
+	}
+}
+
+func joinPath(prefix, pathPattern string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	if pathPattern == "" || (pathPattern == "/" && prefix != "") {
+		return prefix
+	}
+
+	return prefix + pathPattern
+}
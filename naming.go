@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// SnakeCase converts Go field name to snake case, e.g. "UserID" to "user_id".
+func SnakeCase(fieldName string) string {
+	words := fieldNameWords(fieldName)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+
+	return strings.Join(words, "_")
+}
+
+// CamelCase converts Go field name to lower camel case, e.g. "UserID" to "userId".
+func CamelCase(fieldName string) string {
+	words := fieldNameWords(fieldName)
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if i > 0 {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		}
+
+		words[i] = w
+	}
+
+	return strings.Join(words, "")
+}
+
+// fieldNameWords splits field name into words, acronyms are kept as words, e.g. "HTTPServerID" to HTTP, Server, ID.
+func fieldNameWords(name string) []string {
+	var (
+		words []string
+		start int
+	)
+
+	runes := []rune(name)
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+
+		switch {
+		case cur == '_':
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+
+			start = i + 1
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)),
+			unicode.IsUpper(cur) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+// PropertyNames is a jsonschema.ReflectContext option to derive names of properties and parameters
+// from names of fields with a naming convention, e.g. SnakeCase or CamelCase.
+//
+// Fields of bodies without json tags or with empty names in tags, e.g. `json:",omitempty"`, receive names
+// of convention instead of Go names, fields of parameters receive names if their tags have empty names,
+// e.g. `query:""`.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.PropertyNames(openapi.SnakeCase))
+func PropertyNames(naming func(fieldName string) string) func(rc *jsonschema.ReflectContext) {
+	return func(rc *jsonschema.ReflectContext) {
+		var (
+			base     map[string]string
+			mappings = map[reflect.Type]map[string]string{}
+			started  bool
+		)
+
+		// Mapping of property names is shared by all structures of reflection,
+		// so it is switched to mapping of structure that is being walked.
+		use := func(t reflect.Type) {
+			if t == nil {
+				return
+			}
+
+			t = refl.DeepIndirect(t)
+			if t.Kind() != reflect.Struct {
+				return
+			}
+
+			m, ok := mappings[t]
+			if !ok {
+				m = make(map[string]string, len(base))
+				for k, v := range base {
+					m[k] = v
+				}
+
+				tags := append([]string{rc.PropertyNameTag}, rc.PropertyNameAdditionalTags...)
+				addNamedFields(m, t, tags, naming)
+
+				mappings[t] = m
+			}
+
+			rc.PropertyNameMapping = m
+		}
+
+		jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+			if params.Processed {
+				return false, nil
+			}
+
+			if !started {
+				started = true
+				base = rc.PropertyNameMapping
+			}
+
+			use(params.Schema.ReflectType)
+
+			return false, nil
+		})(rc)
+
+		jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+			if params.Processed && params.ParentSchema != nil {
+				use(params.ParentSchema.ReflectType)
+			}
+
+			return nil
+		})(rc)
+	}
+}
+
+// addNamedFields adds names of convention to mapping for fields of structure, including embedded ones.
+func addNamedFields(mapping map[string]string, t reflect.Type, tags []string, naming func(fieldName string) string) {
+	isBody := false
+
+	for _, tag := range tags {
+		if tag == "json" {
+			isBody = true
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if _, ok := mapping[f.Name]; ok || f.Name == "_" {
+			continue
+		}
+
+		tag, found := "", false
+
+		for _, name := range tags {
+			if tag, found = f.Tag.Lookup(name); found {
+				break
+			}
+		}
+
+		if f.Anonymous && tag == "" {
+			if ft := refl.DeepIndirect(f.Type); ft.Kind() == reflect.Struct {
+				addNamedFields(mapping, ft, tags, naming)
+
+				continue
+			}
+		}
+
+		if f.PkgPath != "" || strings.Split(tag, ",")[0] != "" {
+			continue
+		}
+
+		// Fields of parameters are not properties of body.
+		if !found && (!isBody || hasParameterTag(f)) {
+			continue
+		}
+
+		// Tag has empty name, so it only has options, e.g. ",omitempty".
+		mapping[f.Name] = naming(f.Name) + tag
+	}
+}
+
+func hasParameterTag(f reflect.StructField) bool {
+	for _, tag := range []string{"path", "query", "header", "cookie", "form", "formData", "file"} {
+		if _, ok := f.Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+
+	return false
+}
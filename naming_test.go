@@ -0,0 +1,98 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"ID":            "id",
+		"UserID":        "user_id",
+		"HTTPServerID":  "http_server_id",
+		"FirstName":     "first_name",
+		"Address2Line":  "address2_line",
+		"Already_Snake": "already_snake",
+	} {
+		assert.Equal(t, expected, openapi.SnakeCase(name), name)
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"ID":           "id",
+		"UserID":       "userId",
+		"HTTPServerID": "httpServerId",
+		"FirstName":    "firstName",
+	} {
+		assert.Equal(t, expected, openapi.CamelCase(name), name)
+	}
+}
+
+func TestPropertyNames(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.PropertyNames(openapi.SnakeCase))
+
+	type Audit struct {
+		CreatedAt string
+		UpdatedBy string `json:"editor"`
+	}
+
+	type address struct {
+		StreetName string
+		ZipCode    string `json:",omitempty"`
+	}
+
+	type user struct {
+		Audit
+		UserID    int    `path:"user_id"`
+		RequestID string `header:""`
+		FirstName string `required:"true"`
+		Address   address
+		Internal  string `json:"-"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/users/{user_id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/users/{user_id}":{
+		  "put":{
+			"parameters":[
+			  {"name":"user_id","in":"path","required":true,"schema":{"type":"integer"}},
+			  {"name":"request_id","in":"header","schema":{"type":"string"}}
+			],
+			"requestBody":{
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiGoTestUser"}}}
+			},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "OpenapiGoTestAddress":{
+			"properties":{"street_name":{"type":"string"},"zip_code":{"type":"string"}},
+			"type":"object"
+		  },
+		  "OpenapiGoTestUser":{
+			"properties":{
+			  "address":{"$ref":"#/components/schemas/OpenapiGoTestAddress"},
+			  "created_at":{"type":"string"},"editor":{"type":"string"},"first_name":{"type":"string"}
+			},
+			"required":["first_name"],"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi3"
 )
 
@@ -93,3 +94,17 @@ paths:
 
 	require.NoError(t, s.UnmarshalYAML([]byte(spec)))
 }
+
+func TestSpec_MarshalIndexedJSON(t *testing.T) {
+	s := openapi3.Spec{Openapi: "3.0.3"}
+	s.Info.WithTitle("Foo").WithVersion("1.0")
+
+	data, index, err := s.MarshalIndexedJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"title": "Foo"`)
+	require.Equal(t, openapi.Position{Line: 4, Column: 5}, index["/info/title"])
+
+	_, index, err = s.MarshalIndexedYAML()
+	require.NoError(t, err)
+	require.Equal(t, openapi.Position{Line: 3, Column: 3}, index["/info/title"])
+}
@@ -1,13 +1,17 @@
 package openapi3
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
 )
 
 // ToParameterOrRef exposes Parameter in general form.
@@ -24,7 +28,9 @@ func (p *PathItem) WithOperation(method string, operation Operation) *PathItem {
 	return p.WithMapOfOperationValuesItem(strings.ToLower(method), operation)
 }
 
-func (o *Operation) validatePathParams(pathParams map[string]bool) error {
+// validatePathParams checks parameters of operation against placeholders of path,
+// inherited parameters of path item can define placeholders too.
+func (o *Operation) validatePathParams(pathParams map[string]bool, inherited []ParameterOrRef) error {
 	paramIndex := make(map[string]bool, len(o.Parameters))
 
 	var errs []string
@@ -49,6 +55,12 @@ func (o *Operation) validatePathParams(pathParams map[string]bool) error {
 		paramIndex[p.Parameter.Name+string(p.Parameter.In)] = true
 	}
 
+	for _, p := range inherited {
+		if p.Parameter != nil && p.Parameter.In == ParameterInPath {
+			paramIndex[p.Parameter.Name+string(ParameterInPath)] = true
+		}
+	}
+
 	for pathParam := range pathParams {
 		if !paramIndex[pathParam+string(ParameterInPath)] {
 			errs = append(errs, "undefined path parameter: "+pathParam)
@@ -83,7 +95,7 @@ func (s *Spec) SetupOperation(method, path string, setup ...func(*Operation) err
 		pathParamsMap[p] = true
 	}
 
-	if err := operation.validatePathParams(pathParamsMap); err != nil {
+	if err := operation.validatePathParams(pathParamsMap, pathItem.Parameters); err != nil {
 		return err
 	}
 
@@ -94,6 +106,237 @@ func (s *Spec) SetupOperation(method, path string, setup ...func(*Operation) err
 	return nil
 }
 
+// pathParameters returns inline path parameters of operation.
+func (o *Operation) pathParameters() []ParameterOrRef {
+	var res []ParameterOrRef
+
+	for _, p := range o.Parameters {
+		if p.Parameter != nil && p.Parameter.In == ParameterInPath {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+// setPathPatterns sets router regexps as patterns of inline path parameter schemas, unless patterns are defined.
+func (o *Operation) setPathPatterns(patterns map[string]string) {
+	for _, p := range o.Parameters {
+		if p.Parameter == nil || p.Parameter.In != ParameterInPath {
+			continue
+		}
+
+		pattern, ok := patterns[p.Parameter.Name]
+		if !ok {
+			continue
+		}
+
+		s := p.Parameter.Schema
+		if s == nil || s.Schema == nil || s.Schema.Pattern != nil {
+			continue
+		}
+
+		s.Schema.WithPattern(pattern)
+	}
+}
+
+// setWildcards marks catch-all path parameters with "x-wildcard" extension.
+func (o *Operation) setWildcards(names []string) {
+	for _, p := range o.Parameters {
+		if p.Parameter == nil || p.Parameter.In != ParameterInPath {
+			continue
+		}
+
+		for _, name := range names {
+			if p.Parameter.Name != name {
+				continue
+			}
+
+			p.Parameter.WithMapOfAnythingItem("x-wildcard", true)
+
+			if p.Parameter.Schema == nil {
+				p.Parameter.WithSchema(SchemaOrRef{Schema: (&Schema{}).WithType(SchemaTypeString)})
+			}
+		}
+	}
+}
+
+// AddPathParameters sets parameters of path item, they are shared by all operations of the path.
+//
+// Parameter with the same name and location is replaced.
+func (s *Spec) AddPathParameters(path string, params ...ParameterOrRef) error {
+	_, path, pathParams, err := openapi.SanitizeMethodPath(http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+
+	placeholders := make(map[string]bool, len(pathParams))
+	for _, p := range pathParams {
+		placeholders[p] = true
+	}
+
+	pathItem := s.Paths.MapOfPathItemValues[path]
+
+	for _, p := range params {
+		if p.Parameter != nil && p.Parameter.In == ParameterInPath && !placeholders[p.Parameter.Name] {
+			return errors.New("missing path parameter placeholder in url: " + p.Parameter.Name)
+		}
+
+		pathItem.Parameters = setParameter(pathItem.Parameters, p)
+	}
+
+	s.Paths.WithMapOfPathItemValuesItem(path, pathItem)
+
+	return nil
+}
+
+// HoistCommonParameters moves parameters that are identical in all operations of the path to path item.
+//
+// Parameters of path item are moved back to operations first, so that parameters that are no longer
+// common are kept in operations. Parameters of a path with single operation are only hoisted
+// if they were already defined in path item.
+func (s *Spec) HoistCommonParameters(path string) {
+	pathItem, ok := s.Paths.MapOfPathItemValues[path]
+	if !ok {
+		return
+	}
+
+	shared := make(map[string]bool, len(pathItem.Parameters))
+	for _, p := range pathItem.Parameters {
+		shared[p.key()] = true
+	}
+
+	s.lowerParameters(path, nil)
+	pathItem = s.Paths.MapOfPathItemValues[path]
+
+	methods := pathItem.methods()
+	if len(methods) == 0 {
+		return
+	}
+
+	first := pathItem.MapOfOperationValues[methods[0]]
+
+	for _, p := range first.Parameters {
+		if len(methods) == 1 && !shared[p.key()] {
+			continue
+		}
+
+		common := true
+
+		for _, method := range methods[1:] {
+			params := pathItem.MapOfOperationValues[method].Parameters
+			if i := p.indexIn(params); i == -1 || !reflect.DeepEqual(p, params[i]) {
+				common = false
+
+				break
+			}
+		}
+
+		if common {
+			pathItem.Parameters = append(pathItem.Parameters, p)
+		}
+	}
+
+	for _, p := range pathItem.Parameters {
+		for _, method := range methods {
+			op := pathItem.MapOfOperationValues[method]
+			i := p.indexIn(op.Parameters)
+			op.Parameters = append(op.Parameters[:i], op.Parameters[i+1:]...)
+
+			if len(op.Parameters) == 0 {
+				op.Parameters = nil
+			}
+
+			pathItem.MapOfOperationValues[method] = op
+		}
+	}
+
+	s.Paths.MapOfPathItemValues[path] = pathItem
+}
+
+// lowerParameters moves parameters of path item to operations of the path, except parameters with keep keys.
+//
+// Parameters of operations take precedence over moved parameters with the same name and location.
+func (s *Spec) lowerParameters(path string, keep map[string]bool) {
+	pathItem, ok := s.Paths.MapOfPathItemValues[path]
+	if !ok {
+		return
+	}
+
+	methods := pathItem.methods()
+	kept := pathItem.Parameters[:0]
+
+	for _, p := range pathItem.Parameters {
+		if keep[p.key()] || len(methods) == 0 {
+			kept = append(kept, p)
+
+			continue
+		}
+
+		for _, method := range methods {
+			op := pathItem.MapOfOperationValues[method]
+
+			if p.indexIn(op.Parameters) == -1 {
+				op.Parameters = append(op.Parameters, p)
+				pathItem.MapOfOperationValues[method] = op
+			}
+		}
+	}
+
+	if len(kept) == 0 {
+		kept = nil
+	}
+
+	pathItem.Parameters = kept
+	s.Paths.MapOfPathItemValues[path] = pathItem
+}
+
+// methods returns methods of operations in walk order.
+func (p PathItem) methods() []string {
+	var methods []string
+
+	for _, method := range operationMethods {
+		if _, ok := p.MapOfOperationValues[method]; ok {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
+
+// key identifies parameter by location and name, or reference.
+func (p ParameterOrRef) key() string {
+	if p.Parameter != nil {
+		return string(p.Parameter.In) + ":" + p.Parameter.Name
+	}
+
+	if p.ParameterReference != nil {
+		return p.ParameterReference.Ref
+	}
+
+	return ""
+}
+
+func (p ParameterOrRef) indexIn(params []ParameterOrRef) int {
+	for i, item := range params {
+		if item.key() == p.key() {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func setParameter(params []ParameterOrRef, p ParameterOrRef) []ParameterOrRef {
+	if i := p.indexIn(params); i != -1 {
+		params[i] = p
+
+		return params
+	}
+
+	return append(params, p)
+}
+
 // AddOperation validates and sets operation by path and method.
 //
 // It will fail if operation with method and path already exists.
@@ -105,6 +348,11 @@ func (s *Spec) AddOperation(method, path string, operation Operation) error {
 		return fmt.Errorf("operation already exists: %s %s", method, path)
 	}
 
+	return s.ReplaceOperation(method, path, operation)
+}
+
+// ReplaceOperation validates and sets operation by path and method, existing operation is replaced.
+func (s *Spec) ReplaceOperation(method, path string, operation Operation) error {
 	// Add "No Content" response if there are no responses configured.
 	if len(operation.Responses.MapOfResponseOrRefValues) == 0 && operation.Responses.Default == nil {
 		operation.Responses.WithMapOfResponseOrRefValuesItem(strconv.Itoa(http.StatusNoContent), ResponseOrRef{
@@ -121,6 +369,241 @@ func (s *Spec) AddOperation(method, path string, operation Operation) error {
 	})
 }
 
+// DeleteOperation removes operation by method and path, path item without operations is removed too.
+//
+// Components that are no longer referenced after removal are removed too,
+// unreferenced components that were added independently are kept.
+func (s *Spec) DeleteOperation(method, path string) error {
+	method = strings.ToLower(method)
+	pathItem, found := s.Paths.MapOfPathItemValues[path]
+
+	if _, ok := pathItem.MapOfOperationValues[method]; !found || !ok {
+		return fmt.Errorf("operation not found: %s %s", method, path)
+	}
+
+	return s.deleteWithComponents(func() {
+		delete(pathItem.MapOfOperationValues, method)
+
+		if len(pathItem.MapOfOperationValues) == 0 {
+			delete(s.Paths.MapOfPathItemValues, path)
+		}
+	})
+}
+
+// DeletePath removes path item with all its operations.
+//
+// Components that are no longer referenced after removal are removed too,
+// unreferenced components that were added independently are kept.
+func (s *Spec) DeletePath(path string) error {
+	if _, ok := s.Paths.MapOfPathItemValues[path]; !ok {
+		return fmt.Errorf("path not found: %s", path)
+	}
+
+	return s.deleteWithComponents(func() {
+		delete(s.Paths.MapOfPathItemValues, path)
+	})
+}
+
+func (s *Spec) deleteWithComponents(del func()) error {
+	before, err := internal.ReachableComponents(s)
+	if err != nil {
+		return err
+	}
+
+	del()
+
+	after, err := internal.ReachableComponents(s)
+	if err != nil {
+		return err
+	}
+
+	if s.Components != nil {
+		internal.RemoveComponents(s.Components, internal.UnreachableComponents(before, after))
+	}
+
+	return nil
+}
+
+// PruneUnusedComponents removes components that are not referenced from paths, webhooks
+// or other parts of spec outside of components, directly or through other components,
+// e.g. after filtering or merging specs. Security schemes are kept.
+//
+// Local references of removed components are returned.
+func (s *Spec) PruneUnusedComponents() ([]string, error) {
+	orphans, err := internal.OrphanComponents(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Components != nil {
+		internal.RemoveComponents(s.Components, orphans)
+	}
+
+	return orphans, nil
+}
+
+// ResolveSchemaRef returns schema component of a local reference in simple map form,
+// e.g. "#/components/schemas/User".
+func (s *Spec) ResolveSchemaRef(ref string) (map[string]interface{}, bool) {
+	if !strings.HasPrefix(ref, componentsSchemas) || s.Components == nil || s.Components.Schemas == nil {
+		return nil, false
+	}
+
+	name := openapi.UnescapePointerToken(ref[len(componentsSchemas):])
+	schema, ok := s.Components.Schemas.MapOfSchemaOrRefValues[name]
+	if !ok {
+		return nil, false
+	}
+
+	return schemaMap(&schema)
+}
+
+// schemaMap converts schema to simple map form.
+func schemaMap(schema *SchemaOrRef) (map[string]interface{}, bool) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, false
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, false
+	}
+
+	return m, true
+}
+
+// FillMissingExamples sets generated examples to media types of operation request bodies and responses
+// that have no examples, e.g. to show payloads in documentation. References to schema components are
+// resolved with ResolveSchemaRef, unless generator has Resolve.
+func (s *Spec) FillMissingExamples(g openapi.ExampleGenerator) {
+	if g.Resolve == nil {
+		g.Resolve = s.ResolveSchemaRef
+	}
+
+	fill := func(content map[string]MediaType) {
+		for _, ct := range internal.SortedKeys(content) {
+			mt := content[ct]
+			if mt.Example != nil || len(mt.Examples) > 0 || mt.Schema == nil {
+				continue
+			}
+
+			schema, ok := schemaMap(mt.Schema)
+			if !ok {
+				continue
+			}
+
+			example := g.Generate(schema)
+			mt.Example = &example
+			content[ct] = mt
+		}
+	}
+
+	_ = s.WalkOperations(func(_, _ string, op *Operation) error { //nolint:errcheck // Callback has no errors.
+		if op.RequestBody != nil && op.RequestBody.RequestBody != nil {
+			fill(op.RequestBody.RequestBody.Content)
+		}
+
+		if op.Responses.Default != nil && op.Responses.Default.Response != nil {
+			fill(op.Responses.Default.Response.Content)
+		}
+
+		for _, status := range internal.SortedKeys(op.Responses.MapOfResponseOrRefValues) {
+			if resp := op.Responses.MapOfResponseOrRefValues[status].Response; resp != nil {
+				fill(resp.Content)
+			}
+		}
+		return nil
+	})
+}
+
+// operationMethods are HTTP methods of PathItem operations in walk order.
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// WalkOperations calls f for every operation of spec, sorted by path and method.
+//
+// Method is lower case, changes of operation are kept in spec. Walk stops on the first error of f.
+func (s *Spec) WalkOperations(f func(method, path string, op *Operation) error) error {
+	paths := make([]string, 0, len(s.Paths.MapOfPathItemValues))
+	for path := range s.Paths.MapOfPathItemValues {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pi := s.Paths.MapOfPathItemValues[path]
+
+		for _, method := range operationMethods {
+			op, ok := pi.MapOfOperationValues[method]
+			if !ok {
+				continue
+			}
+
+			err := f(method, path, &op)
+			pi.MapOfOperationValues[method] = op
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetAnchors sets "x-anchor" extension with stable deep link identifiers to operations and schema components
+// that have none, e.g. "operation/getUsersId" or "schema/User", for documentation portals.
+func (s *Spec) SetAnchors() {
+	_ = s.WalkOperations(func(method, path string, op *Operation) error { //nolint:errcheck // Callback has no errors.
+		if _, ok := op.MapOfAnything[openapi.XAnchor]; !ok {
+			id := ""
+			if op.ID != nil {
+				id = *op.ID
+			}
+
+			op.WithMapOfAnythingItem(openapi.XAnchor, openapi.OperationAnchor(method, path, id))
+		}
+
+		return nil
+	})
+
+	if s.Components == nil || s.Components.Schemas == nil {
+		return
+	}
+
+	for name, schema := range s.Components.Schemas.MapOfSchemaOrRefValues {
+		if schema.Schema == nil {
+			continue
+		}
+
+		if _, ok := schema.Schema.MapOfAnything[openapi.XAnchor]; !ok {
+			schema.Schema.WithMapOfAnythingItem(openapi.XAnchor, openapi.SchemaAnchor(name))
+		}
+	}
+}
+
+// ExplainSchema describes schema component with resolved references as indented text for humans,
+// e.g. to debug reflection or to quote in code review, places where component is referenced are listed too.
+//
+// Missing component is described with an error message.
+func (s *Spec) ExplainSchema(name string) string {
+	text, err := internal.ExplainSchema(s, name)
+	if err != nil {
+		return err.Error()
+	}
+
+	return text
+}
+
+// Stats returns statistics of operations, schemas and documentation coverage,
+// empty statistics are returned if spec fails to marshal.
+func (s *Spec) Stats() openapi.SpecStats {
+	stats, _ := internal.SpecStats(s) //nolint:errcheck // Spec is expected to marshal.
+
+	return stats
+}
+
 // UnknownParamIsForbidden indicates forbidden unknown parameters.
 func (o Operation) UnknownParamIsForbidden(in ParameterIn) bool {
 	f, ok := o.MapOfAnything[xForbidUnknown+string(in)].(bool)
@@ -128,7 +611,10 @@ func (o Operation) UnknownParamIsForbidden(in ParameterIn) bool {
 	return f && ok
 }
 
-var _ openapi.SpecSchema = &Spec{}
+var (
+	_ openapi.SpecSchema   = &Spec{}
+	_ openapi.SpecExtender = &Spec{}
+)
 
 // Title returns service title.
 func (s *Spec) Title() string {
@@ -164,6 +650,65 @@ func (s *Spec) SetVersion(v string) {
 	s.Info.Version = v
 }
 
+// SetExtension sets vendor extension of the document, "x-" prefix is added to key if it is missing.
+func (s *Spec) SetExtension(key string, value interface{}) {
+	s.WithMapOfAnythingItem(extensionKey(key), value)
+}
+
+// SetInfoExtension sets vendor extension of document info, e.g. "x-logo".
+func (s *Spec) SetInfoExtension(key string, value interface{}) {
+	s.Info.WithMapOfAnythingItem(extensionKey(key), value)
+}
+
+// SetTagExtension sets vendor extension of a tag, tag is added if it is missing.
+func (s *Spec) SetTagExtension(tag string, key string, value interface{}) {
+	for i := range s.Tags {
+		if s.Tags[i].Name == tag {
+			s.Tags[i].WithMapOfAnythingItem(extensionKey(key), value)
+
+			return
+		}
+	}
+
+	t := Tag{Name: tag}
+	t.WithMapOfAnythingItem(extensionKey(key), value)
+
+	s.Tags = append(s.Tags, t)
+}
+
+func extensionKey(key string) string {
+	if !strings.HasPrefix(key, "x-") {
+		return "x-" + key
+	}
+
+	return key
+}
+
+// SetSecuritySchemeExtension sets vendor extension of a security scheme, missing scheme is ignored.
+func (s *Spec) SetSecuritySchemeExtension(securityName string, key string, value interface{}) {
+	if s.Components == nil || s.Components.SecuritySchemes == nil {
+		return
+	}
+
+	ss := s.Components.SecuritySchemes.MapOfSecuritySchemeOrRefValues[securityName].SecurityScheme
+	if ss == nil {
+		return
+	}
+
+	key = extensionKey(key)
+
+	switch {
+	case ss.APIKeySecurityScheme != nil:
+		ss.APIKeySecurityScheme.WithMapOfAnythingItem(key, value)
+	case ss.HTTPSecurityScheme != nil:
+		ss.HTTPSecurityScheme.WithMapOfAnythingItem(key, value)
+	case ss.OAuth2SecurityScheme != nil:
+		ss.OAuth2SecurityScheme.WithMapOfAnythingItem(key, value)
+	case ss.OpenIDConnectSecurityScheme != nil:
+		ss.OpenIDConnectSecurityScheme.WithMapOfAnythingItem(key, value)
+	}
+}
+
 // SetHTTPBasicSecurity sets security definition.
 func (s *Spec) SetHTTPBasicSecurity(securityName string, description string) {
 	s.ComponentsEns().SecuritySchemesEns().WithMapOfSecuritySchemeOrRefValuesItem(
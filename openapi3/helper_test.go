@@ -1,11 +1,14 @@
 package openapi3_test
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi3"
 )
 
@@ -100,3 +103,277 @@ func TestSpec_SetupOperation_uncleanPath(t *testing.T) {
 	  }
 	}`), s)
 }
+
+func TestSpec_FillMissingExamples(t *testing.T) {
+	type User struct {
+		Name string `json:"name" pattern:"^[a-z]{3}$"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.AddRespStructure([]User{})
+	require.NoError(t, r.AddOperation(oc))
+
+	r.Spec.FillMissingExamples(openapi.ExampleGenerator{})
+
+	assertjson.EqMarshal(t, `{
+	  "200":{
+		"description":"OK",
+		"content":{
+		  "application/json":{
+			"schema":{"type":"array","items":{"$ref":"#/components/schemas/Openapi3TestUser"}},
+			"example":[{"name":"aaa"}]
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["get"].Responses)
+}
+
+func TestSpec_WalkOperations(t *testing.T) {
+	s := openapi3.Spec{}
+
+	for _, mp := range [][2]string{
+		{http.MethodPost, "/users"}, {http.MethodGet, "/users"}, {http.MethodGet, "/orders"},
+	} {
+		require.NoError(t, s.AddOperation(mp[0], mp[1], openapi3.Operation{}))
+	}
+
+	var visited []string
+
+	require.NoError(t, s.WalkOperations(func(method, path string, op *openapi3.Operation) error {
+		visited = append(visited, method+" "+path)
+		op.WithSummary("Summary of " + method + " " + path)
+
+		return nil
+	}))
+
+	assert.Equal(t, []string{"get /orders", "get /users", "post /users"}, visited)
+
+	op := s.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["post"]
+	assert.Equal(t, "Summary of post /users", *op.Summary)
+
+	stop := errors.New("stop")
+	visited = nil
+
+	assert.Equal(t, stop, s.WalkOperations(func(method, path string, _ *openapi3.Operation) error {
+		visited = append(visited, method+" "+path)
+
+		return stop
+	}))
+	assert.Equal(t, []string{"get /orders"}, visited)
+}
+
+func TestSpec_WalkSchemas(t *testing.T) {
+	type Pet struct {
+		Name    string   `json:"name" example:"Rex"`
+		Aliases []string `json:"aliases"`
+	}
+
+	type req struct {
+		Limit int `query:"limit" example:"10"`
+		Pet
+	}
+
+	type resp struct {
+		Total int   `header:"X-Total"`
+		Pets  []Pet `json:"pets"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	var pointers []string
+
+	require.NoError(t, r.Spec.WalkSchemas(func(pointer string, schema *openapi3.SchemaOrRef) error {
+		pointers = append(pointers, pointer)
+
+		if schema.Schema != nil {
+			schema.Schema.Example = nil
+			schema.Schema.WithMapOfAnythingItem("x-visited", true)
+		}
+
+		return nil
+	}))
+
+	assert.Equal(t, []string{
+		"#/components/schemas/Openapi3TestPet",
+		"#/components/schemas/Openapi3TestPet/properties/aliases",
+		"#/components/schemas/Openapi3TestPet/properties/aliases/items",
+		"#/components/schemas/Openapi3TestPet/properties/name",
+		"#/components/schemas/Openapi3TestReq",
+		"#/components/schemas/Openapi3TestReq/properties/aliases",
+		"#/components/schemas/Openapi3TestReq/properties/aliases/items",
+		"#/components/schemas/Openapi3TestReq/properties/name",
+		"#/components/schemas/Openapi3TestResp",
+		"#/components/schemas/Openapi3TestResp/properties/pets",
+		"#/components/schemas/Openapi3TestResp/properties/pets/items",
+		"#/paths/~1pets/post/parameters/0/schema",
+		"#/paths/~1pets/post/requestBody/content/application~1json/schema",
+		"#/paths/~1pets/post/responses/200/headers/X-Total/schema",
+		"#/paths/~1pets/post/responses/200/content/application~1json/schema",
+	}, pointers)
+
+	assertjson.EqMarshal(t, `{"type":"string","x-visited":true}`,
+		r.Spec.Components.Schemas.MapOfSchemaOrRefValues["Openapi3TestPet"].Schema.Properties["name"])
+
+	stop := errors.New("stop")
+	pointers = nil
+
+	assert.Equal(t, stop, r.Spec.WalkSchemas(func(pointer string, _ *openapi3.SchemaOrRef) error {
+		pointers = append(pointers, pointer)
+
+		return stop
+	}))
+	assert.Equal(t, []string{"#/components/schemas/Openapi3TestPet"}, pointers)
+}
+
+func TestSpec_SetExtension(t *testing.T) {
+	s := openapi3.Spec{Openapi: "3.0.3"}
+	s.Info.Title = "Pets"
+
+	s.SetExtension("x-audience", "public")
+	s.SetInfoExtension("logo", map[string]interface{}{"url": "https://example.com/logo.png"})
+	s.SetTagExtension("Pets", "x-displayName", "Pet store")
+	s.SetTagExtension("Pets", "x-order", 1)
+	s.SetAPIKeySecurity("apiKey", "X-API-Key", openapi.InHeader, "")
+	s.SetSecuritySchemeExtension("apiKey", "x-deprecated", true)
+	s.SetSecuritySchemeExtension("missing", "x-deprecated", true)
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"Pets","version":"","x-logo":{"url":"https://example.com/logo.png"}},
+	  "tags":[{"name":"Pets","x-displayName":"Pet store","x-order":1}],
+	  "paths":{},
+	  "components":{"securitySchemes":{"apiKey":{"type":"apiKey","name":"X-API-Key","in":"header","description":"","x-deprecated":true}}},
+	  "x-audience":"public"
+	}`, s)
+}
+
+func TestSpec_DeleteOperation(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type User struct {
+		Address Address `json:"address"`
+	}
+
+	type Order struct {
+		Address Address `json:"address"`
+	}
+
+	r := openapi3.NewReflector()
+	r.Spec.ComponentsEns().SchemasEns().WithMapOfSchemaOrRefValuesItem("Manual", openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)})
+
+	for path, resp := range map[string]interface{}{"/users": User{}, "/orders": Order{}} {
+		oc, err := r.NewOperationContext(http.MethodGet, path)
+		require.NoError(t, err)
+
+		oc.AddRespStructure(resp)
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	require.EqualError(t, r.Spec.DeleteOperation(http.MethodPost, "/users"), "operation not found: post /users")
+	require.NoError(t, r.Spec.DeleteOperation(http.MethodGet, "/users"))
+
+	assertjson.EqMarshal(t, `{
+	  "schemas":{
+		"Manual":{"type":"string"},
+		"Openapi3TestAddress":{"properties":{"city":{"type":"string"}},"type":"object"},
+		"Openapi3TestOrder":{
+		  "properties":{"address":{"$ref":"#/components/schemas/Openapi3TestAddress"}},"type":"object"
+		}
+	  }
+	}`, r.Spec.Components)
+
+	require.EqualError(t, r.Spec.DeletePath("/users"), "path not found: /users")
+	require.NoError(t, r.Spec.DeletePath("/orders"))
+
+	assertjson.EqMarshal(t, `{"schemas":{"Manual":{"type":"string"}}}`, r.Spec.Components)
+	assertjson.EqMarshal(t, `{}`, r.Spec.Paths)
+}
+
+func TestSpec_ExplainSchema(t *testing.T) {
+	type Owner struct {
+		Name  string   `json:"name" minLength:"1" description:"Full name."`
+		Pets  []string `json:"pets"`
+		Owner *Owner   `json:"owner,omitempty"`
+	}
+
+	type Pet struct {
+		ID     int64             `json:"id" required:"true"`
+		Status string            `json:"status" enum:"available,sold"`
+		Owner  Owner             `json:"owner"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets")
+	require.NoError(t, err)
+
+	oc.AddRespStructure([]Pet{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, `Openapi3TestPet: object
+  id: required integer
+  labels: object, nullable: true
+    additional properties: string
+  owner: object (Openapi3TestOwner)
+    name: string, minLength: 1 - Full name.
+    owner: object (Openapi3TestOwner)
+      (recursive Openapi3TestOwner)
+    pets: array of string, nullable: true
+  status: string, enum: available, sold
+Referenced by:
+  #/paths/~1pets/get/responses/200/content/application~1json/schema/items
+`, r.Spec.ExplainSchema("Openapi3TestPet"))
+
+	assert.Equal(t, "schema not found: Foo", r.Spec.ExplainSchema("Foo"))
+}
+
+func TestSpec_PruneUnusedComponents(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi3.NewReflector()
+	r.Spec.SetHTTPBasicSecurity("basic", "Admin access.")
+	r.Spec.ComponentsEns().SchemasEns().
+		WithMapOfSchemaOrRefValuesItem("Manual", openapi3.SchemaOrRef{
+			SchemaReference: &openapi3.SchemaReference{Ref: "#/components/schemas/Nested"},
+		}).
+		WithMapOfSchemaOrRefValuesItem("Nested", openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)})
+	r.Spec.ComponentsEns().ParametersEns().WithMapOfParameterOrRefValuesItem("Tenant", openapi3.ParameterOrRef{
+		Parameter: &openapi3.Parameter{Name: "X-Tenant", In: openapi3.ParameterInHeader},
+	})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(User{})
+	require.NoError(t, r.AddOperation(oc))
+
+	removed, err := r.Spec.PruneUnusedComponents()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"#/components/parameters/Tenant",
+		"#/components/schemas/Manual",
+		"#/components/schemas/Nested",
+	}, removed)
+
+	assertjson.EqMarshal(t, `{
+	  "schemas":{"Openapi3TestUser":{"properties":{"name":{"type":"string"}},"type":"object"}},
+	  "securitySchemes":{"basic":{"description":"Admin access.","type":"http","scheme":"basic"}}
+	}`, r.Spec.Components)
+}
@@ -0,0 +1,39 @@
+package openapi3
+
+import (
+	"encoding/json"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// MarshalIndexedJSON produces indented JSON bytes together with positions of values by JSON pointers.
+//
+// Position index allows reporting lint findings and validation errors with line and column.
+func (s *Spec) MarshalIndexedJSON() ([]byte, openapi.PositionIndex, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index, err := openapi.IndexJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, index, nil
+}
+
+// MarshalIndexedYAML produces YAML bytes together with positions of values by JSON pointers.
+func (s *Spec) MarshalIndexedYAML() ([]byte, openapi.PositionIndex, error) {
+	data, err := s.MarshalYAML()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index, err := openapi.IndexYAML(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, index, nil
+}
@@ -6,12 +6,14 @@ import (
 	"net/http"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/swaggest/jsonschema-go"
 	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/report"
 	"github.com/swaggest/refl"
 )
 
@@ -19,29 +21,304 @@ import (
 type Reflector struct {
 	jsonschema.Reflector
 	Spec *Spec
+
+	// MaxRecursionDepth limits expansions of a recursive type in inlined schemas, such as parameters and headers,
+	// default 1. Deeper occurrences are reflected as empty schemas.
+	MaxRecursionDepth int
+
+	// KeepContentTypeParams disables removal of parameters from response content types,
+	// e.g. "application/json; charset=utf-8" is used as is.
+	KeepContentTypeParams bool
+
+	// DefaultTags derives tags of operations that have none, e.g. openapi.TagsFromPackage.
+	DefaultTags openapi.TagResolver
+
+	// EnumOneOf describes enum values with oneOf of const schemas, instead of x-enum-descriptions.
+	EnumOneOf bool
+
+	// DefaultOperationID derives IDs of operations that have none, e.g. openapi.OperationIDFromMethodPath.
+	DefaultOperationID openapi.OperationIDResolver
+
+	// ReplaceOperations enables upsert mode, existing operations are replaced instead of failing,
+	// e.g. to rebuild spec incrementally in a dev server with hot reload.
+	// Schema components of replaced operations are kept.
+	ReplaceOperations bool
+
+	// HoistParameters moves parameters that are identical in all operations of a path to path item.
+	HoistParameters bool
+
+	// Anchors enables "x-anchor" extension with stable deep link identifiers of operations and schema components,
+	// see Spec.SetAnchors.
+	Anchors bool
+
+	// ComponentsStore is an optional pool of schema components shared with other reflectors.
+	ComponentsStore *openapi.ComponentsStore
+
+	// TagNamespace is an optional name of field tags that take precedence over standard tags, e.g. "oas",
+	// for structures with json tags owned by storage serialization.
+	//
+	// Tag named as namespace is used instead of json tag and "<namespace>-<tag>" instead of other tags,
+	// e.g. `oas:"name" oas-query:"limit" oas-header:"X-Trace"`, standard tags are used as fallback.
+	TagNamespace string
+
+	// ParameterTags are additional field tags of parameters by location, used as fallback to standard tags,
+	// e.g. {openapi.InPath: {"uri"}} for router binders that do not use path tags.
+	//
+	// Query parameters also use form tags.
+	ParameterTags map[openapi.In][]string
+
+	// StrictTags makes AddOperation fail if request or response structures have exported fields
+	// without tags of parameters or body, to catch fields that are silently missing in spec.
+	StrictTags bool
+
+	componentStats    internal.ComponentStats
+	componentsErr     error
+	types             internal.TypeRegistry
+	enums             internal.EnumDescriptions
+	replacements      internal.SchemaReplacements
+	fieldFilters      internal.FieldFilters
+	wrappers          internal.Wrappers
+	impls             internal.Implementations
+	defaultResponses  openapi.DefaultResponses
+	interceptors      []OperationInterceptor
+	postInterceptors  []OperationInterceptor
+	declaredParams    map[string]map[string]bool // Keys of parameters added with AddPathParameters by paths.
+	defaultsInstalled bool
 }
 
 // NewReflector creates an instance of OpenAPI 3.0 reflector.
 func NewReflector() *Reflector {
 	r := &Reflector{}
 	r.SpecEns()
+	r.installDefaults()
 
 	return r
 }
 
+// AddTypeMapping creates substitution link between types of src and dst when reflecting JSON Schema.
+//
+// Destination can be a sample value or a configured jsonschema.Schema, options customize mapped schema.
+//
+//	r.AddTypeMapping(uuid.UUID{}, "", openapi.WithFormat("uuid"))
+func (r *Reflector) AddTypeMapping(src, dst interface{}, options ...openapi.TypeMappingOption) {
+	r.types.AddTypeMapping(&r.Reflector, src, dst, options...)
+}
+
+// InlineDefinition enables schema inlining for a type of given sample.
+//
+// Inlined schema is used instead of a reference to a shared definition.
+func (r *Reflector) InlineDefinition(sample interface{}) {
+	r.types.InlineDefinition(&r.Reflector, sample)
+}
+
+// Reset clears Spec and reflection caches to build another spec with the same configuration.
+//
+// Default options, type mappings, inlined definitions and wrappers are retained, including ones added
+// directly to jsonschema.Reflector. Definition names of reflected types are retained too, so that types
+// keep their component names in the next spec.
+func (r *Reflector) Reset() {
+	r.Spec = &Spec{Openapi: r.SpecEns().Openapi}
+	r.componentStats = internal.ComponentStats{}
+	r.declaredParams = nil
+}
+
+// AddTransparentWrapper registers generic wrapper type (e.g. Optional[T]) to be reflected as its wrapped type.
+//
+// Sample can be any instance of the generic type, field is a name of struct field that holds wrapped value.
+// Schemas of wrapped types keep their own title, description and examples.
+//
+//	r.AddTransparentWrapper(Optional[int]{}, "Value")
+func (r *Reflector) AddTransparentWrapper(sample interface{}, field string) {
+	r.installDefaults()
+	r.wrappers.Add(sample, field, false)
+}
+
+// AddNullWrapper registers wrapper type (e.g. null.String) to be reflected as nullable schema of its wrapped type.
+//
+// Types of database/sql (e.g. sql.NullString) and types that implement openapi.NullWrapper
+// are recognized without registration.
+//
+//	r.AddNullWrapper(pgtype.Text{}, "String")
+func (r *Reflector) AddNullWrapper(sample interface{}, field string) {
+	r.installDefaults()
+	r.wrappers.Add(sample, field, true)
+}
+
+// AddEnumDescriptions registers descriptions of enum values for type of sample.
+//
+// Types can also describe their values by implementing openapi.EnumDescriber.
+func (r *Reflector) AddEnumDescriptions(sample interface{}, descriptions map[interface{}]string) {
+	r.installDefaults()
+	r.enums.Add(sample, descriptions)
+}
+
+// AddImplementation registers implementation of interface to resolve embedded interfaces of structures,
+// iface is a pointer to interface.
+//
+// Structures that embed such interface without field tag have implementation schema in allOf,
+// e.g. to compose response of decorators.
+//
+//	r.AddImplementation(new(Auditable), AuditInfo{})
+func (r *Reflector) AddImplementation(iface, impl interface{}) {
+	r.installDefaults()
+	r.impls.Add(iface, impl)
+}
+
+// ReplaceSchema registers a function that defines schema of type of sample instead of reflection,
+// e.g. to fix schemas of third-party types in one place.
+//
+// Schema of a named type is shared as a definition.
+//
+//	r.ReplaceSchema(decimal.Decimal{}, func(s *jsonschema.Schema) {
+//		s.WithType(jsonschema.String.Type()).WithFormat("decimal")
+//	})
+func (r *Reflector) ReplaceSchema(sample interface{}, replace func(s *jsonschema.Schema)) {
+	r.installDefaults()
+	r.replacements.Add(sample, replace)
+}
+
+// WithFieldFilter adds a predicate that excludes struct fields from schemas of bodies, parameters and headers,
+// fields for which it returns true are skipped.
+//
+//	r.WithFieldFilter(func(f reflect.StructField) bool {
+//		return f.Tag.Get("internal") == "true"
+//	})
+func (r *Reflector) WithFieldFilter(exclude func(field reflect.StructField) bool) {
+	r.installDefaults()
+	r.fieldFilters.Add(exclude)
+}
+
+// OperationInterceptor is called with operation context and reflected operation.
+type OperationInterceptor func(oc openapi.OperationContext, op *Operation) error
+
+// WithOperationInterceptor adds a function that is called before reflected operation is added to spec,
+// operation can be modified and error prevents adding, e.g. to apply naming,
+// mandatory descriptions or security policies in one place.
+//
+//	r.WithOperationInterceptor(func(oc openapi.OperationContext, op *openapi3.Operation) error {
+//		if op.Summary == nil {
+//			return errors.New("missing summary")
+//		}
+//
+//		return nil
+//	})
+func (r *Reflector) WithOperationInterceptor(f OperationInterceptor) {
+	r.interceptors = append(r.interceptors, f)
+}
+
+// WithOperationCustomizer adds a function that modifies every reflected operation before it is added to spec,
+// instead of type-asserting operation context to OperationExposer at every call site.
+//
+//	r.WithOperationCustomizer(func(op *openapi3.Operation) {
+//		op.WithMapOfAnythingItem("x-internal", false)
+//	})
+func (r *Reflector) WithOperationCustomizer(f func(op *Operation)) {
+	r.WithOperationInterceptor(func(_ openapi.OperationContext, op *Operation) error {
+		f(op)
+
+		return nil
+	})
+}
+
+// WithAddedOperationInterceptor adds a function that is called after operation is added to spec,
+// e.g. to collect operations, changes of operation are not applied to spec.
+//
+// Error of function is returned by AddOperation, but operation is kept in spec.
+func (r *Reflector) WithAddedOperationInterceptor(f OperationInterceptor) {
+	r.postInterceptors = append(r.postInterceptors, f)
+}
+
+// AddDefaultResponse adds response structure with HTTP status to every operation
+// that does not declare a response with the same status, status 0 adds default response.
+//
+//	r.AddDefaultResponse(http.StatusUnauthorized, errResp{})
+//	r.AddDefaultResponse(http.StatusInternalServerError, errResp{})
+func (r *Reflector) AddDefaultResponse(httpStatus int, structure interface{}, options ...openapi.ContentOption) {
+	r.defaultResponses.Add(httpStatus, structure, options...)
+}
+
+// installDefaults adds default reflection hooks once.
+func (r *Reflector) installDefaults() {
+	if r.defaultsInstalled {
+		return
+	}
+
+	r.defaultsInstalled = true
+
+	r.DefaultOptions = append(r.DefaultOptions, r.replacements.Option(), r.fieldFilters.Option(), internal.Views())
+	r.DefaultOptions = append(r.DefaultOptions, internal.TagNamespace(func() string {
+		return r.TagNamespace
+	}))
+	r.DefaultOptions = append(r.DefaultOptions, r.wrappers.Options(&r.Reflector)...)
+	r.DefaultOptions = append(r.DefaultOptions, r.impls.Option(&r.Reflector))
+	r.DefaultOptions = append(r.DefaultOptions, internal.LimitRecursion(func() int {
+		return r.MaxRecursionDepth
+	}))
+	r.DefaultOptions = append(r.DefaultOptions, r.enums.Option(func() bool {
+		return r.EnumOneOf
+	}))
+	r.DefaultOptions = append(r.DefaultOptions, internal.Base64Bytes(true), internal.VendorExtensions())
+}
+
+// builtinDefaults are names of reflection hooks added by installDefaults.
+var builtinDefaults = []string{
+	"schemaReplacements", "fieldFilters", "views", "tagNamespace", "wrappers", "implementations", "limitRecursion",
+	"enumDescriptions", "base64Bytes", "vendorExtensions",
+}
+
+// GeneratorConfig returns effective reflection options.
+func (r *Reflector) GeneratorConfig() openapi.GeneratorConfig {
+	maxDepth := r.MaxRecursionDepth
+	if maxDepth <= 0 {
+		maxDepth = internal.DefaultMaxRecursionDepth
+	}
+
+	c := openapi.GeneratorConfig{
+		MaxRecursionDepth:     maxDepth,
+		KeepContentTypeParams: r.KeepContentTypeParams,
+		EnumOneOf:             r.EnumOneOf,
+		DefaultTags:           r.DefaultTags != nil,
+		SharedComponents:      r.ComponentsStore != nil,
+	}
+
+	if r.defaultsInstalled {
+		c.Defaults = builtinDefaults
+	}
+
+	c.TypeMappings, c.InlinedTypes = r.types.Describe()
+
+	return c
+}
+
+// AddGeneratorConfig emits effective reflection options as "x-generator-config" extension of spec.
+func (r *Reflector) AddGeneratorConfig() {
+	r.SpecEns().SetExtension("x-generator-config", r.GeneratorConfig())
+}
+
 // NewOperationContext initializes openapi.OperationContext to be prepared
 // and added later with Reflector.AddOperation.
 func (r *Reflector) NewOperationContext(method, pathPattern string) (openapi.OperationContext, error) {
-	method, pathPattern, pathParams, err := openapi.SanitizeMethodPath(method, pathPattern)
+	oc, err := r.newOperationContext(method, pathPattern)
 	if err != nil {
 		return nil, err
 	}
 
-	pathItem := r.SpecEns().Paths.MapOfPathItemValues[pathPattern]
-	operation, found := pathItem.MapOfOperationValues[method]
+	pathItem := r.SpecEns().Paths.MapOfPathItemValues[oc.PathPattern()]
 
-	if found {
-		return nil, fmt.Errorf("operation already exists: %s %s", method, pathPattern)
+	if _, found := pathItem.MapOfOperationValues[oc.Method()]; found && !r.ReplaceOperations {
+		return nil, fmt.Errorf("operation already exists: %s %s", oc.Method(), oc.PathPattern())
+	}
+
+	return oc, nil
+}
+
+func (r *Reflector) newOperationContext(method, pathPattern string) (operationContext, error) {
+	pathPatterns := openapi.PathParameterPatterns(pathPattern)
+	wildcards := openapi.WildcardPathParameters(pathPattern)
+
+	method, pathPattern, pathParams, err := openapi.SanitizeMethodPath(method, pathPattern)
+	if err != nil {
+		return operationContext{}, err
 	}
 
 	pathParamsMap := make(map[string]bool, len(pathParams))
@@ -51,8 +328,10 @@ func (r *Reflector) NewOperationContext(method, pathPattern string) (openapi.Ope
 
 	oc := operationContext{
 		OperationContext: internal.NewOperationContext(method, pathPattern),
-		op:               &operation,
+		op:               &Operation{},
 		pathParams:       pathParamsMap,
+		pathPatterns:     pathPatterns,
+		wildcards:        wildcards,
 	}
 
 	return oc, nil
@@ -108,9 +387,21 @@ type operationContext struct {
 
 	op *Operation
 
-	pathParams map[string]bool
+	pathParams   map[string]bool
+	pathPatterns map[string]string
+	wildcards    []string
 }
 
+var (
+	_ openapi.OperationViewer        = operationContext{}
+	_ openapi.OperationExtender      = operationContext{}
+	_ openapi.UnknownParamsForbidder = operationContext{}
+	_ openapi.TraitApplier           = operationContext{}
+	_ openapi.RedirectResponder      = operationContext{}
+	_ openapi.ProblemResponder       = operationContext{}
+	_ openapi.RateLimitHeadersAdder  = operationContext{}
+)
+
 // OperationExposer grants access to underlying *Operation.
 type OperationExposer interface {
 	Operation() *Operation
@@ -176,6 +467,32 @@ func (o operationContext) ID() string {
 	return *o.op.ID
 }
 
+func (o operationContext) SetExtension(key string, value interface{}) {
+	o.op.WithMapOfAnythingItem(extensionKey(key), value)
+}
+
+func (o operationContext) Extensions() map[string]interface{} {
+	return o.op.MapOfAnything
+}
+
+func (o operationContext) ApplyTrait(traits ...openapi.Trait) {
+	for _, t := range traits {
+		t.Apply(o)
+	}
+}
+
+func (o operationContext) AddRedirectResponse(httpStatus int, locationDescription string) {
+	openapi.RedirectResponse(httpStatus, locationDescription).Apply(o)
+}
+
+func (o operationContext) AddProblemResponse(httpStatus int, extensions ...interface{}) {
+	openapi.ProblemResponse(httpStatus, extensions...).Apply(o)
+}
+
+func (o operationContext) AddRateLimitHeaders(style openapi.RateLimitStyle, httpStatuses ...int) {
+	openapi.RateLimitHeaders(style, httpStatuses...).Apply(o)
+}
+
 func (o operationContext) UnknownParamsAreForbidden(in openapi.In) bool {
 	return o.op.UnknownParamIsForbidden(ParameterIn(in))
 }
@@ -253,25 +570,195 @@ func (r *Reflector) AddOperation(oc openapi.OperationContext) error {
 		return fmt.Errorf("wrong operation context %T received, %T expected", oc, operationContext{})
 	}
 
+	r.componentsErr = nil
+
+	if r.DefaultTags != nil && len(oc.Tags()) == 0 {
+		if tags := r.DefaultTags(oc); len(tags) > 0 {
+			oc.SetTags(tags...)
+		}
+	}
+
+	r.defaultResponses.Apply(oc)
+
+	if r.DefaultOperationID != nil && oc.ID() == "" {
+		if id := r.DefaultOperationID(oc); id != "" {
+			oc.SetID(id)
+		}
+	}
+
+	if err := r.checkOperationID(oc); err != nil {
+		return err
+	}
+
+	if r.StrictTags {
+		if err := internal.CheckFieldTags(oc, r.TagNamespace, r.ParameterTags); err != nil {
+			return fmt.Errorf("check field tags %s %s: %w", oc.Method(), oc.PathPattern(), err)
+		}
+	}
+
 	if err := r.setupRequest(c.op, oc); err != nil {
 		return fmt.Errorf("setup request %s %s: %w", oc.Method(), oc.PathPattern(), err)
 	}
 
-	if err := c.op.validatePathParams(c.pathParams); err != nil {
+	// Hoisted parameters are moved back to operations, so that new operation does not inherit them.
+	if r.HoistParameters {
+		r.SpecEns().lowerParameters(oc.PathPattern(), r.declaredParams[oc.PathPattern()])
+	}
+
+	pathItem := r.SpecEns().Paths.MapOfPathItemValues[oc.PathPattern()]
+
+	if err := c.op.validatePathParams(c.pathParams, pathItem.Parameters); err != nil {
 		return fmt.Errorf("validate path params %s %s: %w", oc.Method(), oc.PathPattern(), err)
 	}
 
+	c.op.setPathPatterns(c.pathPatterns)
+	c.op.setWildcards(c.wildcards)
+
+	internal.AddSunsetHeaders(oc)
+
 	if err := r.setupResponse(c.op, oc); err != nil {
 		return fmt.Errorf("setup response %s %s: %w", oc.Method(), oc.PathPattern(), err)
 	}
 
-	return r.SpecEns().AddOperation(oc.Method(), oc.PathPattern(), *c.op)
+	if err := r.componentsErr; err != nil {
+		r.componentsErr = nil
+
+		return fmt.Errorf("shared components %s %s: %w", oc.Method(), oc.PathPattern(), err)
+	}
+
+	for _, intercept := range r.interceptors {
+		if err := intercept(oc, c.op); err != nil {
+			return fmt.Errorf("intercept operation %s %s: %w", oc.Method(), oc.PathPattern(), err)
+		}
+	}
+
+	add := r.SpecEns().AddOperation
+	if r.ReplaceOperations {
+		add = r.SpecEns().ReplaceOperation
+	}
+
+	if err := add(oc.Method(), oc.PathPattern(), *c.op); err != nil {
+		return err
+	}
+
+	if r.HoistParameters {
+		r.Spec.HoistCommonParameters(oc.PathPattern())
+	}
+
+	if r.Anchors {
+		r.Spec.SetAnchors()
+	}
+
+	for _, intercept := range r.postInterceptors {
+		if err := intercept(oc, c.op); err != nil {
+			return fmt.Errorf("intercept added operation %s %s: %w", oc.Method(), oc.PathPattern(), err)
+		}
+	}
+
+	return nil
+}
+
+// AddPathParameters reflects parameters of structure and sets them to path item,
+// they are shared by all operations of the path.
+//
+//	r.AddPathParameters("/accounts/{accountID}/users", struct {
+//		AccountID string `path:"accountID"`
+//		Tenant    string `header:"X-Tenant"`
+//	}{})
+func (r *Reflector) AddPathParameters(pathPattern string, structure interface{}) error {
+	c, err := r.newOperationContext(http.MethodGet, pathPattern)
+	if err != nil {
+		return err
+	}
+
+	c.AddReqStructure(structure)
+
+	if err := r.setupRequest(c.op, c); err != nil {
+		return fmt.Errorf("setup path parameters %s: %w", pathPattern, err)
+	}
+
+	c.op.setPathPatterns(c.pathPatterns)
+	c.op.setWildcards(c.wildcards)
+
+	if err := r.SpecEns().AddPathParameters(pathPattern, c.op.Parameters...); err != nil {
+		return err
+	}
+
+	if r.declaredParams == nil {
+		r.declaredParams = make(map[string]map[string]bool)
+	}
+
+	declared := r.declaredParams[c.PathPattern()]
+	if declared == nil {
+		declared = make(map[string]bool)
+		r.declaredParams[c.PathPattern()] = declared
+	}
+
+	for _, p := range c.op.Parameters {
+		declared[p.key()] = true
+	}
+
+	return nil
+}
+
+// AddPreflightOperations adds OPTIONS operations to paths that have none, operations document "Allow" and
+// CORS preflight headers with methods of path (see openapi.CORSPreflight), e.g. for gateways
+// that require preflight documentation.
+//
+// It should be called after other operations are added.
+func (r *Reflector) AddPreflightOperations() error {
+	paths := make([]string, 0, len(r.SpecEns().Paths.MapOfPathItemValues))
+	for path := range r.Spec.Paths.MapOfPathItemValues {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pathItem := r.Spec.Paths.MapOfPathItemValues[path]
+		if _, ok := pathItem.MapOfOperationValues[strings.ToLower(http.MethodOptions)]; ok {
+			continue
+		}
+
+		var (
+			methods    []string
+			pathParams []ParameterOrRef
+		)
+
+		for _, method := range operationMethods {
+			if op, ok := pathItem.MapOfOperationValues[method]; ok {
+				methods = append(methods, method)
+
+				if pathParams == nil {
+					pathParams = op.pathParameters()
+				}
+			}
+		}
+
+		if len(methods) == 0 {
+			continue
+		}
+
+		c, err := r.newOperationContext(http.MethodOptions, path)
+		if err != nil {
+			return err
+		}
+
+		c.op.Parameters = pathParams
+		c.ApplyTrait(openapi.CORSPreflight(methods...))
+
+		if err := r.AddOperation(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (r *Reflector) setupRequest(o *Operation, oc openapi.OperationContext) error {
 	for _, cu := range oc.Request() {
-		switch cu.ContentType {
-		case "":
+		switch {
+		case cu.ContentType == "":
 			if err := joinErrors(
 				r.parseRequestBody(o, oc, cu, mimeFormUrlencoded, oc.Method(), cu.FieldMapping(openapi.InFormData), tagFormData, tagForm),
 				r.parseParameters(o, oc, cu),
@@ -279,14 +766,23 @@ func (r *Reflector) setupRequest(o *Operation, oc openapi.OperationContext) erro
 			); err != nil {
 				return err
 			}
-		case mimeJSON:
+		case internal.IsJSONMediaType(cu.ContentType):
+			// Vendor media types with "+json" suffix keep their name in content map.
 			if err := joinErrors(
 				r.parseParameters(o, oc, cu),
-				r.parseRequestBody(o, oc, cu, mimeJSON, oc.Method(), nil, tagJSON),
+				r.parseRequestBody(o, oc, cu, cu.ContentType, oc.Method(), nil, tagJSON),
+			); err != nil {
+				return err
+			}
+		case internal.BinaryBodyTag(cu.ContentType) != "":
+			// MessagePack and CBOR structures can have msgpack or cbor field tags, json tags are used as fallback.
+			if err := joinErrors(
+				r.parseParameters(o, oc, cu),
+				r.parseRequestBody(o, oc, cu, cu.ContentType, oc.Method(), nil, internal.BinaryBodyTag(cu.ContentType), tagJSON),
 			); err != nil {
 				return err
 			}
-		case mimeFormUrlencoded, mimeMultipart:
+		case cu.ContentType == mimeFormUrlencoded, cu.ContentType == mimeMultipart:
 			if err := joinErrors(
 				r.parseRequestBody(o, oc, cu, mimeFormUrlencoded, oc.Method(), cu.FieldMapping(openapi.InFormData), tagFormData, tagForm),
 				r.parseParameters(o, oc, cu),
@@ -300,6 +796,27 @@ func (r *Reflector) setupRequest(o *Operation, oc openapi.OperationContext) erro
 		if cu.Description != "" && o.RequestBody != nil && o.RequestBody.RequestBody != nil {
 			o.RequestBody.RequestBody.WithDescription(cu.Description)
 		}
+
+		if cu.IsDeprecated && cu.ContentType != "" && o.RequestBody != nil && o.RequestBody.RequestBody != nil {
+			deprecateContentType(o.RequestBody.RequestBody.Content, cu.ContentType)
+		}
+
+		if o.RequestBody != nil && o.RequestBody.RequestBody != nil {
+			rb := o.RequestBody.RequestBody
+			rb.MapOfAnything = setBodySize(rb.MapOfAnything, rb.Content, cu)
+		}
+
+		if err := setQueryParamsSchema(o, cu); err != nil {
+			return err
+		}
+	}
+
+	if f, ok := oc.(openapi.UnknownParamsForbidder); ok {
+		for _, in := range []openapi.In{openapi.InQuery, openapi.InPath, openapi.InCookie, openapi.InHeader} {
+			if forbid, isSet := f.ForbidUnknownParams(in); isSet && forbid {
+				o.WithMapOfAnythingItem(xForbidUnknown+string(in), true)
+			}
+		}
 	}
 
 	return nil
@@ -376,11 +893,13 @@ func (r *Reflector) parseRequestBody(
 	}
 
 	for name, def := range schema.Definitions {
+		def = r.sharedDefinition(name, def)
 		s := SchemaOrRef{}
 
 		s.FromJSONSchema(def)
 
 		r.SpecEns().ComponentsEns().SchemasEns().WithMapOfSchemaOrRefValuesItem(name, s)
+		r.componentStats.Add(name, def)
 	}
 
 	if mime == mimeFormUrlencoded && hasFileUpload {
@@ -396,8 +915,36 @@ const (
 	// xForbidUnknown is a prefix of a vendor extension to indicate forbidden unknown parameters.
 	// It should be used together with ParameterIn as a suffix.
 	xForbidUnknown = "x-forbid-unknown-"
+
+	// xParametersSchema is a vendor extension of operation with JSON Schema of dependencies between query parameters.
+	xParametersSchema = "x-parameters-schema"
 )
 
+func setQueryParamsSchema(o *Operation, cu openapi.ContentUnit) error {
+	schema, params := cu.QueryParamsSchema()
+	if schema == nil {
+		return nil
+	}
+
+	declared := map[string]bool{}
+
+	for _, p := range o.Parameters {
+		if p.Parameter != nil && p.Parameter.In == ParameterInQuery {
+			declared[p.Parameter.Name] = true
+		}
+	}
+
+	for _, name := range params {
+		if !declared[name] {
+			return fmt.Errorf("undefined query parameter in dependencies: %s", name)
+		}
+	}
+
+	o.WithMapOfAnythingItem(xParametersSchema, schema)
+
+	return nil
+}
+
 func (r *Reflector) parseParameters(o *Operation, oc openapi.OperationContext, cu openapi.ContentUnit) error {
 	return joinErrors(r.parseParametersIn(o, oc, cu, openapi.InQuery, tagForm),
 		r.parseParametersIn(o, oc, cu, openapi.InPath),
@@ -417,6 +964,8 @@ func (r *Reflector) parseParametersIn(
 		return nil
 	}
 
+	additionalTags = append(additionalTags, r.ParameterTags[in]...)
+
 	s, err := internal.ReflectParametersIn(
 		r.JSONSchemaReflector(),
 		oc,
@@ -497,12 +1046,42 @@ func (r *Reflector) parseParametersIn(
 				}
 			}
 
+			style := ""
+			refl.ReadStringTag(field.Tag, "style", &style)
+
+			if style != "" {
+				if err := internal.CheckParameterStyle(style, in); err != nil {
+					return fmt.Errorf("%w, %s in %s", err, name, in)
+				}
+
+				// Explicit style resets explode to its default, explode tag can override it.
+				p.WithStyle(style).WithExplode(internal.ExplodeByDefault(style))
+			}
+
 			err := refl.PopulateFieldsFromTags(&p, field.Tag)
 			if err != nil {
 				return err
 			}
 
-			if in == openapi.InPath {
+			if in != openapi.InQuery && (p.AllowReserved != nil || p.AllowEmptyValue != nil) {
+				return fmt.Errorf("allowReserved and allowEmptyValue are only applicable to query parameters, %s in %s", name, in)
+			}
+
+			if example, ok := field.Tag.Lookup("paramExample"); ok {
+				p.WithExample(internal.TagValue(example))
+			}
+
+			for exampleName, value := range c.ParamExamples(in, name) {
+				value := value
+
+				if exampleName == "" {
+					p.WithExample(value)
+				} else {
+					p.WithExamplesItem(exampleName, ExampleOrRef{Example: &Example{Value: &value}})
+				}
+			}
+
+			if in == openapi.InPath || internal.IsRequired(params) {
 				p.WithRequired(true)
 			}
 
@@ -528,6 +1107,12 @@ func (r *Reflector) parseParametersIn(
 		return err
 	}
 
+	if f, ok := oc.(openapi.UnknownParamsForbidder); ok {
+		if _, isSet := f.ForbidUnknownParams(in); isSet {
+			return nil
+		}
+	}
+
 	if s.AdditionalProperties != nil &&
 		s.AdditionalProperties.TypeBoolean != nil &&
 		!*s.AdditionalProperties.TypeBoolean {
@@ -551,8 +1136,41 @@ func (r *Reflector) collectDefinition() func(name string, schema jsonschema.Sche
 			return
 		}
 
+		def := r.sharedDefinition(name, schema.ToSchemaOrBool())
+
 		s := SchemaOrRef{}
-		s.FromJSONSchema(schema.ToSchemaOrBool())
+		s.FromJSONSchema(def)
+
+		r.SpecEns().ComponentsEns().SchemasEns().WithMapOfSchemaOrRefValuesItem(name, s)
+		r.componentStats.Add(name, def)
+	}
+}
+
+// sharedDefinition returns definition stored in ComponentsStore, if it is configured.
+//
+// Conflict with stored definition is kept to fail AddOperation.
+func (r *Reflector) sharedDefinition(name string, def jsonschema.SchemaOrBool) jsonschema.SchemaOrBool {
+	if r.ComponentsStore == nil {
+		return def
+	}
+
+	stored, err := r.ComponentsStore.Add(name, def)
+	if err != nil && r.componentsErr == nil {
+		r.componentsErr = err
+	}
+
+	return stored
+}
+
+// AddStoredComponents adds all schemas of ComponentsStore to Spec components, e.g. to build a merged document.
+func (r *Reflector) AddStoredComponents() {
+	if r.ComponentsStore == nil {
+		return
+	}
+
+	for name, def := range r.ComponentsStore.Schemas() {
+		s := SchemaOrRef{}
+		s.FromJSONSchema(def)
 
 		r.SpecEns().ComponentsEns().SchemasEns().WithMapOfSchemaOrRefValuesItem(name, s)
 	}
@@ -593,6 +1211,10 @@ func (r *Reflector) parseResponseHeader(resp *Response, oc openapi.OperationCont
 				return err
 			}
 
+			if internal.IsRequired(params) {
+				header.WithRequired(true)
+			}
+
 			res[name] = HeaderOrRef{
 				Header: &header,
 			}
@@ -604,7 +1226,14 @@ func (r *Reflector) parseResponseHeader(resp *Response, oc openapi.OperationCont
 		return err
 	}
 
-	resp.Headers = res
+	// Headers of several structures with the same status are merged.
+	if resp.Headers == nil {
+		resp.Headers = res
+	} else {
+		for name, h := range res {
+			resp.Headers[name] = h
+		}
+	}
 
 	if schema.Description != nil && resp.Description == "" {
 		resp.Description = *schema.Description
@@ -619,7 +1248,13 @@ func (r *Reflector) setupResponse(o *Operation, oc openapi.OperationContext) err
 			cu.HTTPStatus = http.StatusOK
 		}
 
-		cu.ContentType = strings.Split(cu.ContentType, ";")[0]
+		if cu.ContentType == "" && internal.HasHALLinks(cu.Structure) {
+			cu.ContentType = openapi.HALJSONMediaType
+		}
+
+		if !r.KeepContentTypeParams {
+			cu.ContentType = strings.Split(cu.ContentType, ";")[0]
+		}
 
 		httpStatus := strconv.Itoa(cu.HTTPStatus)
 		resp := o.Responses.MapOfResponseOrRefValues[httpStatus].Response
@@ -653,6 +1288,18 @@ func (r *Reflector) setupResponse(o *Operation, oc openapi.OperationContext) err
 			if cu.ContentType != "" {
 				r.ensureResponseContentType(resp, cu.ContentType, cu.Format)
 			}
+
+			if cu.IsDeprecated {
+				contentType := cu.ContentType
+				if contentType == "" {
+					contentType = mimeJSON
+				}
+
+				deprecateContentType(resp.Content, contentType)
+			}
+
+			resp.MapOfAnything = setBodySize(resp.MapOfAnything, resp.Content, cu)
+			r.addContentTypeVariants(resp, cu)
 		} else {
 			// Only headers with HEAD method.
 			if err := r.parseResponseHeader(resp, oc, cu); err != nil {
@@ -660,6 +1307,12 @@ func (r *Reflector) setupResponse(o *Operation, oc openapi.OperationContext) err
 			}
 		}
 
+		if cu.CacheControl != nil {
+			if err := r.parseResponseHeader(resp, oc, openapi.ContentUnit{Structure: cu.CacheControl.HeadersStructure()}); err != nil {
+				return err
+			}
+		}
+
 		if cu.Description != "" {
 			resp.Description = cu.Description
 		}
@@ -678,6 +1331,69 @@ func (r *Reflector) setupResponse(o *Operation, oc openapi.OperationContext) err
 	return nil
 }
 
+func (r *Reflector) addContentTypeVariants(resp *Response, cu openapi.ContentUnit) {
+	contentType := cu.ContentType
+	if contentType == "" {
+		contentType = mimeJSON
+	}
+
+	mt, ok := resp.Content[contentType]
+	if !ok {
+		return
+	}
+
+	for _, variant := range cu.ContentTypeVariants {
+		resp.Content[variant] = mt
+	}
+}
+
+const (
+	xMaxBodyBytes     = "x-max-body-bytes"
+	xTypicalBodyBytes = "x-typical-body-bytes"
+)
+
+// setBodySize documents sizes of body with vendor extensions, schema of string media type receives maxLength.
+func setBodySize(ext map[string]interface{}, content map[string]MediaType, cu openapi.ContentUnit) map[string]interface{} {
+	if cu.MaxBodyBytes <= 0 && cu.TypicalBodyBytes <= 0 {
+		return ext
+	}
+
+	if ext == nil {
+		ext = map[string]interface{}{}
+	}
+
+	if cu.MaxBodyBytes > 0 {
+		ext[xMaxBodyBytes] = cu.MaxBodyBytes
+
+		mt, ok := content[cu.ContentType]
+		if ok && mt.Schema != nil && mt.Schema.Schema != nil && mt.Schema.Schema.Type != nil &&
+			*mt.Schema.Schema.Type == SchemaTypeString {
+			mt.Schema.Schema.WithMaxLength(cu.MaxBodyBytes)
+		}
+	}
+
+	if cu.TypicalBodyBytes > 0 {
+		ext[xTypicalBodyBytes] = cu.TypicalBodyBytes
+	}
+
+	return ext
+}
+
+// deprecateContentType marks media type with "x-deprecated" vendor extension.
+func deprecateContentType(content map[string]MediaType, contentType string) {
+	mt, ok := content[contentType]
+	if !ok {
+		return
+	}
+
+	if mt.MapOfAnything == nil {
+		mt.MapOfAnything = map[string]interface{}{}
+	}
+
+	mt.MapOfAnything["x-deprecated"] = true
+	content[contentType] = mt
+}
+
 func (r *Reflector) ensureResponseContentType(resp *Response, contentType string, format string) {
 	if _, ok := resp.Content[contentType]; !ok {
 		if resp.Content == nil {
@@ -689,9 +1405,10 @@ func (r *Reflector) ensureResponseContentType(resp *Response, contentType string
 }
 
 func (r *Reflector) parseJSONResponse(resp *Response, oc openapi.OperationContext, cu openapi.ContentUnit) error {
-	sch, err := internal.ReflectJSONResponse(
+	sch, err := internal.ReflectResponse(
 		r.JSONSchemaReflector(),
 		cu.Structure,
+		cu.ContentType,
 		openapi.WithOperationCtx(oc, true, openapi.InBody),
 		jsonschema.DefinitionsPrefix(componentsSchemas),
 		jsonschema.CollectDefinitions(r.collectDefinition()),
@@ -732,12 +1449,102 @@ func (r *Reflector) parseJSONResponse(resp *Response, oc openapi.OperationContex
 	return nil
 }
 
+// checkOperationID fails if ID of operation is already used by another operation of spec.
+func (r *Reflector) checkOperationID(oc openapi.OperationContext) error {
+	id := oc.ID()
+	if id == "" || r.Spec == nil {
+		return nil
+	}
+
+	return r.Spec.WalkOperations(func(method, path string, op *Operation) error {
+		if r.ReplaceOperations && method == oc.Method() && path == oc.PathPattern() {
+			return nil
+		}
+
+		if op.ID != nil && *op.ID == id {
+			return fmt.Errorf("duplicate operation ID %q of %s %s, already used by %s %s",
+				id, oc.Method(), oc.PathPattern(), method, path)
+		}
+
+		return nil
+	})
+}
+
+// Finalize checks registration consistency of Spec after all operations are added.
+//
+// It reports declared tags that have no operations, security schemes that are never required,
+// security requirements of undeclared schemes and conflicting components of ComponentsStore.
+func (r *Reflector) Finalize() []report.Finding {
+	s := r.SpecEns()
+
+	tags := make([]string, 0, len(s.Tags))
+	for _, t := range s.Tags {
+		tags = append(tags, t.Name)
+	}
+
+	schemes := map[string]bool{}
+	if s.Components != nil && s.Components.SecuritySchemes != nil {
+		for name := range s.Components.SecuritySchemes.MapOfSecuritySchemeOrRefValues {
+			schemes[name] = true
+		}
+	}
+
+	var operations []internal.RegisteredOperation
+
+	_ = s.WalkOperations(func(method, path string, op *Operation) error { //nolint:errcheck // Callback has no errors.
+		id := ""
+		if op.ID != nil {
+			id = *op.ID
+		}
+
+		operations = append(operations, internal.RegisteredOperation{
+			Pointer:  internal.OperationPointer(path, method),
+			ID:       id,
+			Tags:     op.Tags,
+			Security: op.Security,
+		})
+
+		return nil
+	})
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Pointer < operations[j].Pointer
+	})
+
+	findings := internal.CheckRegistrations(tags, schemes, s.Security, operations)
+
+	if r.ComponentsStore != nil {
+		findings = append(findings, internal.CheckComponentConflicts(r.ComponentsStore.Conflicts())...)
+	}
+
+	return findings
+}
+
 // SpecSchema returns OpenAPI spec schema.
 func (r *Reflector) SpecSchema() openapi.SpecSchema {
 	return r.SpecEns()
 }
 
+// Mount returns reflector that registers operations with path prefix and optional tags, e.g. for "/v1" sub-API.
+func (r *Reflector) Mount(prefix string, tags ...string) openapi.MountedReflector {
+	return openapi.MountedReflector{Reflector: r, Prefix: prefix, Tags: tags}
+}
+
+// Group returns reflector that applies shared defaults to operations, e.g. tags, security or error responses.
+func (r *Reflector) Group(options ...openapi.GroupOption) openapi.Group {
+	return openapi.NewGroup(r, options...)
+}
+
+// Stats returns metrics of reflected components.
+func (r *Reflector) Stats() openapi.ReflectorStats {
+	return openapi.ReflectorStats{
+		ComponentsByPackage: r.componentStats.ByPackage(),
+	}
+}
+
 // JSONSchemaReflector provides access to a low-level struct reflector.
 func (r *Reflector) JSONSchemaReflector() *jsonschema.Reflector {
+	r.installDefaults()
+
 	return &r.Reflector
 }
@@ -164,3 +164,113 @@ func Test_Foo(t *testing.T) {
 	  }
 	}`, reflector.Spec)
 }
+
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+type WrappedUser struct {
+	Name string `json:"name"`
+}
+
+func (WrappedUser) Title() string {
+	return "User"
+}
+
+func (WrappedUser) Description() string {
+	return "User account."
+}
+
+func TestReflector_AddTransparentWrapper(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.AddTransparentWrapper(Optional[int]{}, "Value")
+
+	type req struct {
+		Limit Optional[int]          `query:"limit"`
+		User  Optional[WrappedUser]  `json:"user"`
+		Boss  *Optional[WrappedUser] `json:"boss"`
+		Tags  Optional[[]string]     `json:"tags"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/":{
+		  "post":{
+			"parameters":[{"name":"limit","in":"query","schema":{"type":"integer"}}],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}
+			  }
+			},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi3TestReq":{
+			"type":"object",
+			"properties":{
+			  "boss":{"$ref":"#/components/schemas/Openapi3TestWrappedUser"},
+			  "tags":{"type":"array","items":{"type":"string"},"nullable":true},
+			  "user":{"$ref":"#/components/schemas/Openapi3TestWrappedUser"}
+			}
+		  },
+		  "Openapi3TestWrappedUser":{
+			"title":"User","type":"object","properties":{"name":{"type":"string"}},
+			"description":"User account."
+		  }
+		}
+	  }
+	}`, r.SpecSchema())
+}
+
+type Nullable[T any] struct {
+	Value T
+	Valid bool
+}
+
+func (Nullable[T]) NullWrapped() interface{} {
+	var v T
+
+	return v
+}
+
+func TestReflector_nullWrapper(t *testing.T) {
+	r := openapi3.Reflector{}
+
+	type resp struct {
+		Count Nullable[int]         `json:"count"`
+		Tags  Nullable[[]string]    `json:"tags,omitempty"`
+		User  Nullable[WrappedUser] `json:"user"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi3TestResp":{
+		"type":"object",
+		"properties":{
+		  "count":{"type":"integer","nullable":true},
+		  "tags":{"type":"array","items":{"type":"string"},"nullable":true},
+		  "user":{"$ref":"#/components/schemas/Openapi3TestWrappedUser"}
+		}
+	  },
+	  "Openapi3TestWrappedUser":{
+		"title":"User","type":"object","properties":{"name":{"type":"string"}},
+		"description":"User account."
+	  }
+	}`, r.Spec.Components.Schemas.MapOfSchemaOrRefValues)
+}
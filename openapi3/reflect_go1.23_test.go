@@ -0,0 +1,73 @@
+//go:build go1.23
+// +build go1.23
+
+package openapi3_test
+
+import (
+	"iter"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestReflector_AddOperation_sequenceResponse(t *testing.T) {
+	type Item struct {
+		ID int `json:"id"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	var items iter.Seq[Item]
+
+	oc.AddRespStructure(items)
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/events")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(make(chan Item))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/events":{
+		  "get":{
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{
+					"schema":{"items":{"$ref":"#/components/schemas/Openapi3TestItem"},"type":"array"}
+				  }
+				}
+			  }
+			}
+		  }
+		},
+		"/items":{
+		  "get":{
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{
+					"schema":{"items":{"$ref":"#/components/schemas/Openapi3TestItem"},"type":"array"}
+				  }
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{"Openapi3TestItem":{"properties":{"id":{"type":"integer"}},"type":"object"}}
+	  }
+	}`, r.Spec)
+}
@@ -1,11 +1,17 @@
 package openapi3_test
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"reflect"
+	"regexp"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +19,7 @@ import (
 	"github.com/swaggest/jsonschema-go"
 	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/report"
 )
 
 type WeirdResp interface {
@@ -1123,3 +1130,2809 @@ func TestReflector_AddOperation_defName(t *testing.T) {
 	  }
 	}`, r.Spec)
 }
+
+func TestReflector_base64Bytes(t *testing.T) {
+	type resp struct {
+		Avatar []byte `json:"avatar,omitempty" contentEncoding:"base64" contentMediaType:"image/png"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/avatar")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"avatar":{"type":"string","format":"byte"}},"type":"object"
+	}`, r.Spec.Components.Schemas.MapOfSchemaOrRefValues["Openapi3TestResp"])
+}
+
+func TestReflector_vendorExtensions(t *testing.T) {
+	type account struct {
+		_  struct{} `x:"go-name=Account"`
+		ID string   `json:"id" x:"nullable=true"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/account")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(account{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"id":{"type":"string","x-nullable":true}},"type":"object","x-go-name":"Account"
+	}`, r.Spec.Components.Schemas.MapOfSchemaOrRefValues["Openapi3TestAccount"])
+}
+
+func TestOperationContext_SetExtension(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	e, ok := oc.(openapi.OperationExtender)
+	require.True(t, ok)
+
+	e.SetExtension("x-internal", true)
+	e.SetExtension("codegen-request-body-name", "body")
+	assert.Equal(t, map[string]interface{}{"x-internal": true, "x-codegen-request-body-name": "body"}, e.Extensions())
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{"204":{"description":"No Content"}},
+	  "x-codegen-request-body-name":"body","x-internal":true
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["get"])
+}
+
+func TestReflector_Finalize(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.Spec.Tags = []openapi3.Tag{{Name: "Users"}, {Name: "Legacy"}}
+	r.Spec.SetHTTPBasicSecurity("basic", "")
+	r.Spec.SetHTTPBearerTokenSecurity("bearer", "JWT", "")
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetTags("Users")
+	oc.AddSecurity("bearer")
+	oc.AddSecurity("apiKey")
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, []report.Finding{
+		{
+			Rule: "undeclared-security-scheme", Severity: report.SeverityError,
+			Message: `security scheme "apiKey" is not declared in components`, Pointer: "/paths/~1users/get/security/1",
+		},
+		{
+			Rule: "orphan-tag", Severity: report.SeverityWarning,
+			Message: `tag "Legacy" is not used by any operation`, Pointer: "/tags/1",
+		},
+		{
+			Rule: "unused-security-scheme", Severity: report.SeverityWarning,
+			Message: `security scheme "basic" is not required by any operation`, Pointer: "/components/securitySchemes/basic",
+		},
+	}, r.Finalize())
+}
+
+func TestReflector_ComponentsStore(t *testing.T) {
+	store := &openapi.ComponentsStore{}
+
+	users := openapi3.NewReflector()
+	users.ComponentsStore = store
+
+	oc, err := users.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	{
+		type user struct {
+			Name string `json:"name"`
+		}
+
+		oc.AddRespStructure([]user{})
+	}
+
+	require.NoError(t, users.AddOperation(oc))
+
+	teams := openapi3.NewReflector()
+	teams.ComponentsStore = store
+
+	oc, err = teams.NewOperationContext(http.MethodGet, "/teams")
+	require.NoError(t, err)
+
+	{
+		type user struct {
+			Name string `json:"name"`
+		}
+
+		oc.AddRespStructure([]user{})
+	}
+
+	require.NoError(t, teams.AddOperation(oc))
+	assert.Empty(t, teams.Finalize())
+
+	orders := openapi3.NewReflector()
+	orders.ComponentsStore = store
+
+	oc, err = orders.NewOperationContext(http.MethodGet, "/orders")
+	require.NoError(t, err)
+
+	{
+		type user struct {
+			ID int `json:"id"`
+		}
+
+		oc.AddRespStructure([]user{})
+	}
+
+	err = orders.AddOperation(oc)
+	require.ErrorIs(t, err, openapi.ErrComponentConflict)
+	assert.EqualError(t, err, "shared components get /orders: component conflict: Openapi3TestUser has a different schema")
+
+	findings := orders.Finalize()
+	require.Len(t, findings, 1)
+	assert.Equal(t, "component-conflict", findings[0].Rule)
+	assert.Equal(t, "/components/schemas/Openapi3TestUser", findings[0].Pointer)
+
+	merged := openapi3.NewReflector()
+	merged.ComponentsStore = store
+	merged.AddStoredComponents()
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi3TestUser":{"type":"object","properties":{"name":{"type":"string"}}}
+	}`, merged.Spec.Components.Schemas)
+}
+
+func TestReflector_AddOperation_hoistParameters(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.HoistParameters = true
+
+	require.NoError(t, r.AddPathParameters("/items/{id}", struct {
+		Tenant string `header:"X-Tenant"`
+	}{}))
+
+	type itemReq struct {
+		ID      string `path:"id"`
+		Verbose bool   `query:"verbose"`
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPut} {
+		oc, err := r.NewOperationContext(method, "/items/{id}")
+		require.NoError(t, err)
+
+		oc.AddReqStructure(itemReq{})
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	oc, err := r.NewOperationContext(http.MethodDelete, "/items/{id}")
+	require.NoError(t, err)
+	require.EqualError(t, r.AddOperation(oc), "validate path params delete /items/{id}: undefined path parameter: id")
+
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+	}{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{"name":"id","in":"path","required":true,"schema":{"type":"string"}},
+		{"name":"X-Tenant","in":"header","schema":{"type":"string"}}
+	  ],
+	  "get":{
+		"parameters":[{"name":"verbose","in":"query","schema":{"type":"boolean"}}],
+		"responses":{"204":{"description":"No Content"}}
+	  },
+	  "put":{
+		"parameters":[{"name":"verbose","in":"query","schema":{"type":"boolean"}}],
+		"responses":{"204":{"description":"No Content"}}
+	  },
+	  "delete":{"responses":{"204":{"description":"No Content"}}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/items/{id}"])
+}
+
+func TestReflector_AddOperation_bodySize(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/upload")
+	require.NoError(t, err)
+	oc.AddReqStructure(nil, openapi.WithContentType("text/csv"), openapi.WithMaxBodyBytes(1<<20))
+	oc.AddRespStructure(struct {
+		Rows int `json:"rows"`
+	}{}, openapi.WithTypicalBodyBytes(64), openapi.WithMaxBodyBytes(1024))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "requestBody":{
+		"content":{"text/csv":{"schema":{"type":"string","maxLength":1048576}}},
+		"x-max-body-bytes":1048576
+	  },
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "content":{
+			"application/json":{"schema":{"type":"object","properties":{"rows":{"type":"integer"}}}}
+		  },
+		  "x-max-body-bytes":1024,"x-typical-body-bytes":64
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/upload"].MapOfOperationValues["post"])
+}
+
+func TestReflector_AddOperation_cbor(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type reading struct {
+		Sensor string  `cbor:"s" json:"sensor"`
+		Value  float64 `cbor:"v" json:"value"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/readings")
+	require.NoError(t, err)
+	oc.AddReqStructure([]reading{}, openapi.WithContentType("application/cbor"))
+	oc.AddRespStructure(reading{}, openapi.WithContentType("application/cbor"))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/readings":{
+		  "post":{
+			"requestBody":{
+			  "content":{
+				"application/cbor":{
+				  "schema":{"type":"array","items":{"$ref":"#/components/schemas/CborOpenapi3TestReading"}}
+				}
+			  }
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/cbor":{"schema":{"$ref":"#/components/schemas/CborOpenapi3TestReading"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "CborOpenapi3TestReading":{"type":"object","properties":{"s":{"type":"string"},"v":{"type":"number"}}}
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestReflector_AddOperation_cacheControl(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/catalog")
+	require.NoError(t, err)
+	oc.AddRespStructure(struct {
+		Version string   `header:"X-Version"`
+		Items   []string `json:"items"`
+	}{}, openapi.WithCacheControl(openapi.CacheControl{Directives: "public, max-age=3600", Age: true, Expires: true}))
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNotModified), openapi.WithCacheControl(openapi.CacheControl{}))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "get":{
+		"responses":{
+		  "200":{
+			"description":"OK",
+			"headers":{
+			  "Age":{
+				"style":"simple",
+				"description":"Number of seconds the response has been in a shared cache.",
+				"schema":{
+				  "minimum":0,"type":"integer",
+				  "description":"Number of seconds the response has been in a shared cache."
+				}
+			  },
+			  "Cache-Control":{
+				"style":"simple",
+				"description":"Directives for caches along request-response chain.",
+				"schema":{
+				  "type":"string",
+				  "description":"Directives for caches along request-response chain.",
+				  "example":"public, max-age=3600"
+				}
+			  },
+			  "Expires":{
+				"style":"simple",
+				"description":"HTTP date after which the response is considered stale.",
+				"schema":{
+				  "type":"string",
+				  "description":"HTTP date after which the response is considered stale.",
+				  "example":"Wed, 21 Oct 2015 07:28:00 GMT"
+				}
+			  },
+			  "X-Version":{"style":"simple","schema":{"type":"string"}}
+			},
+			"content":{
+			  "application/json":{
+				"schema":{
+				  "type":"object",
+				  "properties":{"items":{"type":"array","items":{"type":"string"},"nullable":true}}
+				}
+			  }
+			}
+		  },
+		  "304":{
+			"description":"Not Modified",
+			"headers":{
+			  "Cache-Control":{
+				"style":"simple",
+				"description":"Directives for caches along request-response chain.",
+				"schema":{"type":"string","description":"Directives for caches along request-response chain."}
+			  }
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/catalog"])
+}
+
+func TestReflector_WithFieldFilter(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.WithFieldFilter(func(f reflect.StructField) bool {
+		return f.Tag.Get("internal") == "true"
+	})
+
+	type req struct {
+		ID      string `path:"id"`
+		Trace   string `header:"X-Trace" internal:"true"`
+		Debug   bool   `query:"debug" internal:"true"`
+		Name    string `json:"name" required:"true"`
+		Shard   int    `json:"shard" required:"true" internal:"true"`
+		Comment string `json:"comment"`
+	}
+
+	type resp struct {
+		Node  string `header:"X-Node" internal:"true"`
+		Name  string `json:"name"`
+		Score int    `json:"score" internal:"true"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/items/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/items/{id}":{
+		  "put":{
+			"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+			"requestBody":{
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}}
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestResp"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi3TestReq":{
+			"required":["name"],"type":"object",
+			"properties":{"comment":{"type":"string"},"name":{"type":"string"}}
+		  },
+		  "Openapi3TestResp":{"type":"object","properties":{"name":{"type":"string"}}}
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestReflector_ParameterTags(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.ParameterTags = map[openapi.In][]string{
+		openapi.InPath:   {"uri"},
+		openapi.InHeader: {"reqHeader"},
+	}
+
+	type req struct {
+		ID     int    `uri:"id"`
+		Locale string `reqHeader:"Accept-Language"`
+		Sort   string `query:"sort"`
+		Page   int    `form:"page"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "get":{
+		"parameters":[
+		  {"name":"sort","in":"query","schema":{"type":"string"}},
+		  {"name":"page","in":"query","schema":{"type":"integer"}},
+		  {"name":"id","in":"path","required":true,"schema":{"type":"integer"}},
+		  {"name":"Accept-Language","in":"header","schema":{"type":"string"}}
+		],
+		"responses":{"204":{"description":"No Content"}}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/items/{id}"])
+}
+
+func TestOperationContext_SetForbidUnknownParams(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		Filter string   `query:"filter"`
+		Trace  string   `header:"X-Trace"`
+		_      struct{} `query:"_" header:"_" additionalProperties:"false"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	f, ok := oc.(openapi.UnknownParamsForbidder)
+	require.True(t, ok)
+
+	oc.AddReqStructure(Req{})
+	f.SetForbidUnknownParams(openapi.InQuery, false)
+	f.SetForbidUnknownParams(openapi.InCookie, true)
+	require.NoError(t, r.AddOperation(oc))
+
+	op := r.Spec.Paths.MapOfPathItemValues["/items"].MapOfOperationValues["get"]
+	assert.False(t, op.UnknownParamIsForbidden(openapi3.ParameterInQuery))
+	assert.True(t, op.UnknownParamIsForbidden(openapi3.ParameterInHeader))
+	assert.True(t, op.UnknownParamIsForbidden(openapi3.ParameterInCookie))
+	assert.False(t, op.UnknownParamIsForbidden(openapi3.ParameterInPath))
+}
+
+func TestOperationContext_AddRedirectResponse(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/docs")
+	require.NoError(t, err)
+
+	rr, ok := oc.(openapi.RedirectResponder)
+	require.True(t, ok)
+	rr.AddRedirectResponse(http.StatusFound, "URL of latest documentation.")
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"302":{
+		  "description":"Found",
+		  "headers":{
+			"Location":{
+			  "style":"simple","description":"URL of latest documentation.","required":true,
+			  "schema":{"type":"string","description":"URL of latest documentation.","format":"uri-reference"}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/docs"].MapOfOperationValues["get"])
+}
+
+func TestOperationContext_AddProblemResponse(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type invalidParams struct {
+		InvalidParams []string `json:"invalidParams"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/users")
+	require.NoError(t, err)
+
+	pr, ok := oc.(openapi.ProblemResponder)
+	require.True(t, ok)
+	pr.AddProblemResponse(http.StatusBadRequest, invalidParams{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"400":{
+		  "description":"Bad Request",
+		  "content":{
+			"application/problem+json":{
+			  "schema":{
+				"type":"object",
+				"properties":{
+				  "detail":{"type":"string","description":"Explanation of problem occurrence."},
+				  "instance":{
+					"type":"string","description":"URI reference that identifies problem occurrence.",
+					"format":"uri-reference"
+				  },
+				  "invalidParams":{"type":"array","items":{"type":"string"},"nullable":true},
+				  "status":{
+					"maximum":599,"minimum":100,"type":"integer",
+					"description":"HTTP status code of problem occurrence."
+				  },
+				  "title":{"type":"string","description":"Short summary of problem type."},
+				  "type":{
+					"type":"string","description":"URI reference that identifies problem type.",
+					"format":"uri-reference","default":"about:blank"
+				  }
+				}
+			  }
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["post"])
+}
+
+func TestOperationContext_AddRateLimitHeaders(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/search")
+	require.NoError(t, err)
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+
+	rl, ok := oc.(openapi.RateLimitHeadersAdder)
+	require.True(t, ok)
+	rl.AddRateLimitHeaders("")
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"204":{
+		  "description":"No Content",
+		  "headers":{
+			"X-RateLimit-Limit":{
+			  "style":"simple","description":"Maximum number of requests in time window.",
+			  "schema":{"minimum":0,"type":"integer","description":"Maximum number of requests in time window."}
+			},
+			"X-RateLimit-Remaining":{
+			  "style":"simple","description":"Number of requests left in current time window.",
+			  "schema":{"minimum":0,"type":"integer","description":"Number of requests left in current time window."}
+			},
+			"X-RateLimit-Reset":{
+			  "style":"simple","description":"Unix time in seconds when quota is restored.",
+			  "schema":{"minimum":0,"type":"integer","description":"Unix time in seconds when quota is restored."}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/search"].MapOfOperationValues["get"])
+}
+
+func TestReflector_AddOperation_queryParamsDependencies(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		Cursor  string `query:"cursor"`
+		Page    int    `query:"page"`
+		Sort    string `query:"sort"`
+		SortDir string `query:"sort_dir"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{},
+		openapi.WithExclusiveQueryParams("cursor", "page"),
+		openapi.WithOneOfQueryParams("sort", "sort_dir"),
+		openapi.WithDependentQueryParams("sort_dir", "sort"),
+	)
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "type":"object","dependentRequired":{"sort_dir":["sort"]},
+	  "allOf":[
+		{"oneOf":[{"required":["sort"]},{"required":["sort_dir"]}]},
+		{
+		  "oneOf":[
+			{"required":["cursor"]},{"required":["page"]},
+			{"not":{"anyOf":[{"required":["cursor"]},{"required":["page"]}]}}
+		  ]
+		}
+	  ]
+	}`, r.Spec.Paths.MapOfPathItemValues["/items"].MapOfOperationValues["get"].MapOfAnything["x-parameters-schema"])
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/other-items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{}, openapi.WithOneOfQueryParams("sort", "order"))
+	assert.EqualError(t, r.AddOperation(oc), "setup request get /other-items: "+
+		"undefined query parameter in dependencies: order")
+}
+
+func TestPointerOptional(t *testing.T) {
+	type req struct {
+		ID       int     `path:"id"`
+		Limit    int     `query:"limit"`
+		Offset   *int    `query:"offset"`
+		Name     string  `json:"name"`
+		Nick     *string `json:"nick"`
+		Comment  string  `json:"comment,omitempty"`
+		Explicit *string `json:"explicit" required:"true"`
+		Opt      string  `json:"opt" required:"false"`
+	}
+
+	type resp struct {
+		XRate  int     `header:"X-Rate"`
+		XTrace *string `header:"X-Trace"`
+		Total  int     `json:"total"`
+	}
+
+	r := openapi3.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.PointerOptional)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/items/{id}":{
+	      "post":{
+	        "parameters":[
+	          {
+	            "name":"limit","in":"query","required":true,
+	            "schema":{"type":"integer"}
+	          },
+	          {
+	            "name":"offset","in":"query",
+	            "schema":{"type":"integer","nullable":true}
+	          },
+	          {
+	            "name":"id","in":"path","required":true,"schema":{"type":"integer"}
+	          }
+	        ],
+	        "requestBody":{
+	          "content":{
+	            "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}
+	          }
+	        },
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "headers":{
+	              "X-Rate":{"style":"simple","required":true,"schema":{"type":"integer"}},
+	              "X-Trace":{"style":"simple","schema":{"type":"string","nullable":true}}
+	            },
+	            "content":{
+	              "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestResp"}}
+	            }
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "Openapi3TestReq":{
+	        "required":["name","explicit"],"type":"object",
+	        "properties":{
+	          "comment":{"type":"string"},
+	          "explicit":{"type":"string","nullable":true},"name":{"type":"string"},
+	          "nick":{"type":"string","nullable":true},"opt":{"type":"string"}
+	        }
+	      },
+	      "Openapi3TestResp":{
+	        "required":["total"],"type":"object",
+	        "properties":{"total":{"type":"integer"}}
+	      }
+	    }
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestSplitReadWriteOnly(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type User struct {
+		ID       int     `json:"id" readOnly:"true" required:"true"`
+		Password string  `json:"password" writeOnly:"true"`
+		Name     string  `json:"name" required:"true"`
+		Address  Address `json:"address"`
+	}
+
+	type Team struct {
+		Members []User `json:"members"`
+	}
+
+	r := openapi3.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.SplitReadWriteOnly)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/teams")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Team{})
+	oc.AddRespStructure(Team{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi3TestAddress":{"type":"object","properties":{"city":{"type":"string"}}},
+	  "Openapi3TestTeamRequest":{
+	    "type":"object",
+	    "properties":{
+	      "members":{
+	        "type":"array",
+	        "items":{"$ref":"#/components/schemas/Openapi3TestUserRequest"},
+	        "nullable":true
+	      }
+	    }
+	  },
+	  "Openapi3TestTeamResponse":{
+	    "type":"object",
+	    "properties":{
+	      "members":{
+	        "type":"array",
+	        "items":{"$ref":"#/components/schemas/Openapi3TestUserResponse"},
+	        "nullable":true
+	      }
+	    }
+	  },
+	  "Openapi3TestUserRequest":{
+	    "required":["name"],"type":"object",
+	    "properties":{
+	      "address":{"$ref":"#/components/schemas/Openapi3TestAddress"},
+	      "name":{"type":"string"},"password":{"type":"string","writeOnly":true}
+	    }
+	  },
+	  "Openapi3TestUserResponse":{
+	    "required":["id","name"],"type":"object",
+	    "properties":{
+	      "address":{"$ref":"#/components/schemas/Openapi3TestAddress"},
+	      "id":{"type":"integer","readOnly":true},"name":{"type":"string"}
+	    }
+	  }
+	}`, r.Spec.Components.Schemas)
+}
+
+func TestReflector_AddTypeMapping(t *testing.T) {
+	type UUID [16]byte
+
+	type Decimal struct {
+		value string
+	}
+
+	type req struct {
+		ID     UUID      `path:"id"`
+		Trace  *UUID     `header:"X-Trace"`
+		Amount Decimal   `json:"amount"`
+		Items  []UUID    `json:"items"`
+		Prices []Decimal `json:"prices"`
+	}
+
+	r := openapi3.NewReflector()
+	r.AddTypeMapping(UUID{}, "",
+		openapi.WithFormat("uuid"),
+		openapi.WithExample("248df4b7-aa70-47b8-a036-33ac447e668d"),
+	)
+	r.AddTypeMapping(Decimal{}, jsonschema.Schema{},
+		openapi.WithDescription("Decimal number."),
+		openapi.WithFormat("decimal"),
+	)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/orders/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/orders/{id}":{
+	      "post":{
+	        "parameters":[
+	          {
+	            "name":"id","in":"path","required":true,
+	            "schema":{
+	              "type":"string","format":"uuid",
+	              "example":"248df4b7-aa70-47b8-a036-33ac447e668d"
+	            }
+	          },
+	          {
+	            "name":"X-Trace","in":"header",
+	            "schema":{
+	              "type":"string","format":"uuid","nullable":true,
+	              "example":"248df4b7-aa70-47b8-a036-33ac447e668d"
+	            }
+	          }
+	        ],
+	        "requestBody":{
+	          "content":{
+	            "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}
+	          }
+	        },
+	        "responses":{"204":{"description":"No Content"}}
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "Openapi3TestDecimal":{"description":"Decimal number.","format":"decimal"},
+	      "Openapi3TestReq":{
+	        "type":"object",
+	        "properties":{
+	          "amount":{"$ref":"#/components/schemas/Openapi3TestDecimal"},
+	          "items":{
+	            "type":"array",
+	            "items":{
+	              "type":"string","format":"uuid",
+	              "example":"248df4b7-aa70-47b8-a036-33ac447e668d"
+	            },
+	            "nullable":true
+	          },
+	          "prices":{
+	            "type":"array",
+	            "items":{"$ref":"#/components/schemas/Openapi3TestDecimal"},
+	            "nullable":true
+	          }
+	        }
+	      }
+	    }
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestReflector_Stats(t *testing.T) {
+	type Item struct {
+		Contact openapi3.Contact `json:"contact"`
+	}
+
+	type resp struct {
+		Items []Item           `json:"items"`
+		Info  openapi3.License `json:"info"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, map[string]int{
+		"github.com/swaggest/openapi-go/openapi3":      2,
+		"github.com/swaggest/openapi-go/openapi3_test": 2,
+	}, r.Stats().ComponentsByPackage)
+}
+
+type nullableAmount struct{}
+
+func (nullableAmount) NullWrapped() interface{} {
+	return float64(0)
+}
+
+type pgText struct {
+	String string
+	Valid  bool
+}
+
+func TestReflector_AddNullWrapper(t *testing.T) {
+	type req struct {
+		Since   sql.NullTime   `query:"since"`
+		Limit   sql.NullInt64  `query:"limit"`
+		Name    sql.NullString `json:"name"`
+		Comment pgText         `json:"comment"`
+		Amount  nullableAmount `json:"amount"`
+	}
+
+	r := openapi3.Reflector{}
+	r.AddNullWrapper(pgText{}, "String")
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/":{
+	      "post":{
+	        "parameters":[
+	          {
+	            "name":"since","in":"query",
+	            "schema":{"type":"string","format":"date-time"}
+	          },
+	          {"name":"limit","in":"query","schema":{"type":"integer"}}
+	        ],
+	        "requestBody":{
+	          "content":{
+	            "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}
+	          }
+	        },
+	        "responses":{"204":{"description":"No Content"}}
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "Openapi3TestReq":{
+	        "type":"object",
+	        "properties":{
+	          "amount":{"type":"number","nullable":true},
+	          "comment":{"type":"string","nullable":true},
+	          "name":{"type":"string","nullable":true}
+	        }
+	      }
+	    }
+	  }
+	}`, r.Spec)
+}
+
+type (
+	UserEmail   string
+	ArticleSlug string
+	UserID      string
+	PlainEmail  string
+)
+
+func (PlainEmail) PrepareJSONSchema(s *jsonschema.Schema) error {
+	s.WithFormat("idn-email")
+
+	return nil
+}
+
+func TestStringFormats(t *testing.T) {
+	type req struct {
+		Email  UserEmail   `query:"email"`
+		Slug   ArticleSlug `json:"slug"`
+		ID     UserID      `json:"id"`
+		Plain  PlainEmail  `json:"plain"`
+		Simple string      `json:"simple"`
+	}
+
+	r := openapi3.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.StringFormats(
+		openapi.StringFormatRule{Name: regexp.MustCompile(`Email$`), Format: "email"},
+		openapi.StringFormatRule{Name: regexp.MustCompile(`Slug$`), Pattern: "^[a-z0-9]+(?:-[a-z0-9]+)*$"},
+		openapi.StringFormatRule{Name: regexp.MustCompile(`ID$`), Format: "ulid"},
+	))
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/":{
+		  "post":{
+			"parameters":[
+			  {"name":"email","in":"query","schema":{"$ref":"#/components/schemas/Openapi3TestUserEmail"}}
+			],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}
+			  }
+			},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi3TestArticleSlug":{"pattern":"^[a-z0-9]+(?:-[a-z0-9]+)*$","type":"string"},
+		  "Openapi3TestPlainEmail":{"format":"idn-email","type":"string"},
+		  "Openapi3TestReq":{
+			"properties":{
+			  "id":{"$ref":"#/components/schemas/Openapi3TestUserID"},
+			  "plain":{"$ref":"#/components/schemas/Openapi3TestPlainEmail"},
+			  "simple":{"type":"string"},"slug":{"$ref":"#/components/schemas/Openapi3TestArticleSlug"}
+			},
+			"type":"object"
+		  },
+		  "Openapi3TestUserEmail":{"format":"email","type":"string"},
+		  "Openapi3TestUserID":{"format":"ulid","type":"string"}
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestFreeForm(t *testing.T) {
+	type Attributes map[string]interface{}
+
+	type req struct {
+		Raw   json.RawMessage        `json:"raw"`
+		Map   map[string]interface{} `json:"map,omitempty"`
+		Attrs *Attributes            `json:"attrs,omitempty"`
+	}
+
+	for style, expected := range map[openapi.FreeFormStyle]string{
+		openapi.FreeFormAny: `{
+		  "properties":{"attrs":{"$ref":"#/components/schemas/Openapi3TestAttributes"},"map":{},"raw":{}},
+		  "type":"object"
+		}`,
+		openapi.FreeFormObject: `{
+		  "properties":{
+			"attrs":{"$ref":"#/components/schemas/Openapi3TestAttributes"},
+			"map":{"additionalProperties":true,"type":"object"},
+			"raw":{"additionalProperties":true,"nullable":true,"type":"object"}
+		  },
+		  "type":"object"
+		}`,
+	} {
+		r := openapi3.NewReflector()
+		r.DefaultOptions = append(r.DefaultOptions, openapi.FreeForm(style))
+
+		oc, err := r.NewOperationContext(http.MethodPost, "/")
+		require.NoError(t, err)
+
+		oc.AddReqStructure(req{})
+		require.NoError(t, r.AddOperation(oc))
+
+		assertjson.EqMarshal(t, expected, r.Spec.Components.Schemas.MapOfSchemaOrRefValues["Openapi3TestReq"])
+	}
+
+	r := openapi3.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.FreeForm(openapi.FreeFormForbidden))
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	assert.EqualError(t, r.AddOperation(oc),
+		"setup request post /: free-form value at #.raw requires registered type mapping")
+
+	r = openapi3.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.FreeForm(openapi.FreeFormForbidden))
+	r.AddTypeMapping(json.RawMessage{}, "")
+	r.AddTypeMapping(map[string]interface{}{}, struct{}{})
+	r.AddTypeMapping(Attributes{}, struct{}{})
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+}
+
+type links struct {
+	Self string `json:"self"`
+}
+
+type treeNode struct {
+	Name     string      `json:"name" query:"name"`
+	Children []treeNode  `json:"children,omitempty" query:"children"`
+	Parent   *treeNode   `json:"parent,omitempty" query:"parent"`
+	Links    listElement `json:"links" query:"links"`
+}
+
+type listElement struct {
+	Value int          `json:"value" query:"value"`
+	Next  *listElement `json:"next,omitempty" query:"next"`
+}
+
+func TestReflector_recursiveTypes(t *testing.T) {
+	type req struct {
+		Filter treeNode `query:"filter"`
+		Body   treeNode `json:"body"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(treeNode{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/":{
+		  "post":{
+			"parameters":[
+			  {
+				"name":"filter","in":"query","style":"deepObject","explode":true,
+				"schema":{"$ref":"#/components/schemas/Openapi3TestTreeNode"}
+			  }
+			],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}
+			  }
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestTreeNode"}}
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi3TestListElement":{
+			"properties":{
+			  "next":{"$ref":"#/components/schemas/Openapi3TestListElement"},"value":{"type":"integer"}
+			},
+			"type":"object"
+		  },
+		  "Openapi3TestReq":{
+			"properties":{"body":{"$ref":"#/components/schemas/Openapi3TestTreeNode"}},"type":"object"
+		  },
+		  "Openapi3TestTreeNode":{
+			"properties":{
+			  "children":{"items":{"$ref":"#/components/schemas/Openapi3TestTreeNode"},"type":"array"},
+			  "links":{"$ref":"#/components/schemas/Openapi3TestListElement"},"name":{"type":"string"},
+			  "parent":{"$ref":"#/components/schemas/Openapi3TestTreeNode"}
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+
+	r = openapi3.NewReflector()
+	r.MaxRecursionDepth = 2
+
+	s, err := r.Reflect(listElement{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"next":{
+		  "properties":{"next":{},"value":{"type":"integer"}},"type":"object"
+		},
+		"value":{"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type money struct {
+	units int64
+	nanos int32
+}
+
+func TestReflector_Reset(t *testing.T) {
+	type money struct {
+		Amount int64
+	}
+
+	type currency struct {
+		Code int
+	}
+
+	type address struct {
+		City string `json:"city"`
+	}
+
+	type order struct {
+		Total    money    `json:"total"`
+		Currency currency `json:"currency"`
+		Address  address  `json:"address"`
+	}
+
+	r := openapi3.NewReflector()
+	r.Spec.Info.Title = "Orders"
+	r.AddTypeMapping(money{}, "", openapi.WithFormat("decimal"))
+	r.DefaultOptions = append(r.DefaultOptions, openapi.PointerOptional)
+
+	// Mappings added directly to jsonschema.Reflector are retained too.
+	r.JSONSchemaReflector().AddTypeMapping(currency{}, "")
+	r.Reflector.InlineDefinition(address{})
+
+	build := func() {
+		oc, err := r.NewOperationContext(http.MethodGet, "/order")
+		require.NoError(t, err)
+
+		oc.AddRespStructure(order{})
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	build()
+
+	expected, err := json.Marshal(r.Spec)
+	require.NoError(t, err)
+
+	r.Reset()
+
+	assertjson.EqMarshal(t, `{"openapi":"3.0.3","info":{"title":"","version":""},"paths":{}}`, r.Spec)
+	assert.Empty(t, r.Stats().ComponentsByPackage)
+
+	r.Spec.Info.Title = "Orders"
+
+	build()
+
+	assertjson.EqMarshal(t, string(expected), r.Spec)
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+	    "address":{"properties":{"city":{"type":"string"}},"required":["city"],"type":"object"},
+	    "currency":{"type":"string"},"total":{"format":"decimal","type":"string"}
+	  },
+	  "required":["total","currency","address"],"type":"object"
+	}`, r.Spec.Components.Schemas.MapOfSchemaOrRefValues["Openapi3TestOrder"])
+}
+
+type currencyCode string
+
+func (currencyCode) Description() string {
+	return "ISO 4217 currency code."
+}
+
+func TestMergeDescriptions(t *testing.T) {
+	type rate struct {
+		Name  string       `json:"name" description:"Display name."`
+		From  currencyCode `json:"from" description:"Source currency."`
+		To    currencyCode `json:"to"`
+		Since time.Time    `json:"since" description:"Start time."`
+	}
+
+	for _, tc := range []struct {
+		merge    openapi.DescriptionMerge
+		from     string
+		since    string
+		expected string
+	}{
+		{merge: openapi.DescriptionOverride, from: "Source currency.", since: "Start time."},
+		{merge: openapi.DescriptionKeep, from: "ISO 4217 currency code.", since: "Point in time."},
+		{merge: openapi.DescriptionAppend, from: "ISO 4217 currency code. Source currency.", since: "Point in time. Start time."},
+		{
+			merge:    openapi.DescriptionConflictError,
+			expected: `conflicting descriptions of from: "ISO 4217 currency code." in type, "Source currency." in field tag`,
+		},
+	} {
+		r := openapi3.NewReflector()
+		r.AddTypeMapping(time.Time{}, "", openapi.WithFormat("date-time"), openapi.WithDescription("Point in time."))
+		r.InlineDefinition(currencyCode(""))
+		r.DefaultOptions = append(r.DefaultOptions, openapi.MergeDescriptions(tc.merge, " "))
+
+		s, err := r.Reflect(rate{})
+		if tc.expected != "" {
+			assert.EqualError(t, err, tc.expected)
+
+			continue
+		}
+
+		require.NoError(t, err)
+
+		assertjson.EqMarshal(t, `{
+		  "properties":{
+			"from":{"description":"`+tc.from+`","type":"string"},
+			"name":{"description":"Display name.","type":"string"},
+			"since":{"description":"`+tc.since+`","format":"date-time","type":"string"},
+			"to":{"description":"ISO 4217 currency code.","type":"string"}
+		  },
+		  "type":"object"
+		}`, s)
+	}
+}
+
+type orderStatus int
+
+func (orderStatus) Enum() []interface{} {
+	return []interface{}{0, 1, 2}
+}
+
+func (orderStatus) EnumDescriptions() map[interface{}]string {
+	return map[interface{}]string{
+		orderStatus(0): "Order is placed.",
+		orderStatus(1): "Order is paid.",
+		orderStatus(2): "Order is shipped.",
+	}
+}
+
+type priority string
+
+func (priority) Enum() []interface{} {
+	return []interface{}{"low", "high"}
+}
+
+func TestReflector_AddEnumDescriptions(t *testing.T) {
+	type req struct {
+		Status   orderStatus `json:"status"`
+		Priority priority    `json:"priority"`
+	}
+
+	r := openapi3.NewReflector()
+	r.AddEnumDescriptions(priority(""), map[interface{}]string{"high": "Processed first."})
+
+	s, err := r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"priority":{"enum":["low","high"],"type":"string","x-enum-descriptions":["","Processed first."]},
+		"status":{
+		  "enum":[0,1,2],"type":"integer",
+		  "x-enum-descriptions":["Order is placed.","Order is paid.","Order is shipped."]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	r = openapi3.NewReflector()
+	r.EnumOneOf = true
+
+	s, err = r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"priority":{"enum":["low","high"],"type":"string"},
+		"status":{
+		  "oneOf":[
+			{"const":0,"description":"Order is placed."},{"const":1,"description":"Order is paid."},
+			{"const":2,"description":"Order is shipped."}
+		  ],
+		  "type":"integer"
+		}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_KeepContentTypeParams(t *testing.T) {
+	type resp struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi3.NewReflector()
+	r.KeepContentTypeParams = true
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/user")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(resp{}, openapi.WithContentType("application/json; charset=utf-8"),
+		openapi.WithContentTypeVariants("application/json; profile=user"))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "content":{
+			"application/json; charset=utf-8":{"schema":{"$ref":"#/components/schemas/Openapi3TestResp"}},
+			"application/json; profile=user":{"schema":{"$ref":"#/components/schemas/Openapi3TestResp"}}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/user"].MapOfOperationValues["get"])
+}
+
+func TestReflector_AddOperation_jsonSuffix(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		ID   int    `query:"id"`
+		Name string `json:"name"`
+	}
+
+	type Resp struct {
+		Name string `json:"name"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/things")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{}, openapi.WithContentType("application/vnd.company.v2+json"))
+	oc.AddRespStructure(Resp{}, openapi.WithContentType("application/problem+json"))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/things":{
+		  "post":{
+			"parameters":[
+			  {"name":"id","in":"query","schema":{"type":"integer"}}
+			],
+			"requestBody":{
+			  "content":{
+				"application/vnd.company.v2+json":{
+				  "schema":{"$ref":"#/components/schemas/Openapi3TestReq"}
+				}
+			  }
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/problem+json":{
+					"schema":{"$ref":"#/components/schemas/Openapi3TestResp"}
+				  }
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi3TestReq":{"properties":{"name":{"type":"string"}},"type":"object"},
+		  "Openapi3TestResp":{"properties":{"name":{"type":"string"}},"type":"object"}
+		}
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestReflector_AddOperation_pathParameterStyle(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		ID     int   `path:"id" style:"matrix"`
+		Coords []int `path:"coords" style:"label" explode:"true"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id}/{coords}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {
+	    "name":"id","in":"path","required":true,"style":"matrix","explode":false,
+	    "schema":{"type":"integer"}
+	  },
+	  {
+	    "name":"coords","in":"path","required":true,"style":"label","explode":true,
+	    "schema":{"type":"array","items":{"type":"integer"}}
+	  }
+	]`, r.Spec.Paths.MapOfPathItemValues["/items/{id}/{coords}"].MapOfOperationValues["get"].Parameters)
+
+	type QueryReq struct {
+		ID int `query:"id" style:"matrix"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(QueryReq{})
+	assert.EqualError(t, r.AddOperation(oc),
+		"setup request get /items: style matrix is only applicable to path parameters, id in query")
+}
+
+func TestAddVersionedReqStructures(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type ThingV1 struct {
+		Name string `json:"name"`
+	}
+
+	type ThingV2 struct {
+		Title string `json:"title"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/thing")
+	require.NoError(t, err)
+
+	openapi.AddVersionedReqStructures(oc, []openapi.ContentVersion{
+		{ContentType: "application/vnd.company.v1+json", Structure: ThingV1{}, Deprecated: true},
+		{ContentType: "application/vnd.company.v2+json", Structure: ThingV2{}},
+	})
+	openapi.AddVersionedRespStructures(oc, []openapi.ContentVersion{
+		{ContentType: "application/vnd.company.v1+json", Structure: ThingV1{}, Deprecated: true},
+		{ContentType: "application/vnd.company.v2+json", Structure: ThingV2{}},
+	}, openapi.WithHTTPStatus(http.StatusCreated))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "requestBody":{
+		"content":{
+		  "application/vnd.company.v1+json":{
+			"schema":{"$ref":"#/components/schemas/Openapi3TestThingV1"},
+			"x-deprecated":true
+		  },
+		  "application/vnd.company.v2+json":{
+			"schema":{"$ref":"#/components/schemas/Openapi3TestThingV2"}
+		  }
+		}
+	  },
+	  "responses":{
+		"201":{
+		  "description":"Created",
+		  "content":{
+			"application/vnd.company.v1+json":{
+			  "schema":{"$ref":"#/components/schemas/Openapi3TestThingV1"},
+			  "x-deprecated":true
+			},
+			"application/vnd.company.v2+json":{
+			  "schema":{"$ref":"#/components/schemas/Openapi3TestThingV2"}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/thing"].MapOfOperationValues["put"])
+}
+
+func TestReflector_AddOperation_allowReserved(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		Callback string `query:"callback" allowReserved:"true"`
+		Verbose  bool   `query:"verbose" allowEmptyValue:"true"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/hooks")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"callback","in":"query","schema":{"type":"string"},"allowReserved":true},
+	  {"name":"verbose","in":"query","schema":{"type":"boolean"},"allowEmptyValue":true}
+	]`, r.Spec.Paths.MapOfPathItemValues["/hooks"].MapOfOperationValues["get"].Parameters)
+
+	type HeaderReq struct {
+		Callback string `header:"X-Callback" allowReserved:"true"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/other-hooks")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(HeaderReq{})
+	assert.EqualError(t, r.AddOperation(oc), "setup request get /other-hooks: "+
+		"allowReserved and allowEmptyValue are only applicable to query parameters, X-Callback in header")
+}
+
+func TestReflector_AddOperation_paramExamples(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		Limit  int    `query:"limit" paramExample:"20"`
+		Sort   string `query:"sort"`
+		Locale string `header:"Accept-Language"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{},
+		openapi.WithParamExample(openapi.InQuery, "sort", "byName", "name"),
+		openapi.WithParamExample(openapi.InQuery, "sort", "newestFirst", "-created_at"),
+		openapi.WithParamExample(openapi.InHeader, "Accept-Language", "", "en-US"),
+	)
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"limit","in":"query","schema":{"type":"integer"},"example":20},
+	  {
+		"name":"sort","in":"query","schema":{"type":"string"},
+		"examples":{"byName":{"value":"name"},"newestFirst":{"value":"-created_at"}}
+	  },
+	  {"name":"Accept-Language","in":"header","schema":{"type":"string"},"example":"en-US"}
+	]`, r.Spec.Paths.MapOfPathItemValues["/items"].MapOfOperationValues["get"].Parameters)
+}
+
+func TestReflector_AddOperation_parameterStyle(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Filter struct {
+		Status string `query:"status"`
+	}
+
+	type Req struct {
+		Filter Filter   `query:"filter" style:"form"`
+		IDs    []int    `query:"ids" style:"pipeDelimited" explode:"true"`
+		Tags   []string `query:"tags" collectionFormat:"csv" style:"spaceDelimited"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {
+	    "name":"filter","in":"query","style":"form","explode":true,
+	    "schema":{"$ref":"#/components/schemas/Openapi3TestFilter"}
+	  },
+	  {
+	    "name":"ids","in":"query","style":"pipeDelimited","explode":true,
+	    "schema":{"type":"array","items":{"type":"integer"}}
+	  },
+	  {
+	    "name":"tags","in":"query","style":"spaceDelimited","explode":false,
+	    "schema":{"type":"array","items":{"type":"string"}}
+	  }
+	]`, r.Spec.Paths.MapOfPathItemValues["/items"].MapOfOperationValues["get"].Parameters)
+
+	type BadReq struct {
+		Trace string `header:"X-Trace" style:"form"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/other-items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(BadReq{})
+	assert.EqualError(t, r.AddOperation(oc), "setup request get /other-items: "+
+		"style form is only applicable to query and cookie parameters, X-Trace in header")
+}
+
+func TestReflector_AddOperation_pathPatterns(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		ID   int    `path:"id"`
+		Code string `path:"code" pattern:"^[A-Z]+$"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id:[0-9]+}/codes/{code:[a-z]+}")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/users/{id}/codes/{code}", oc.PathPattern())
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"id","in":"path","required":true,"schema":{"pattern":"^[0-9]+$","type":"integer"}},
+	  {"name":"code","in":"path","required":true,"schema":{"pattern":"^[A-Z]+$","type":"string"}}
+	]`, r.Spec.Paths.MapOfPathItemValues["/users/{id}/codes/{code}"].MapOfOperationValues["get"].Parameters)
+}
+
+func TestReflector_AddGeneratorConfig(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.DefaultTags = openapi.TagsFromPackage
+	r.AddTypeMapping(time.Duration(0), "", openapi.WithFormat("duration"))
+
+	r.AddGeneratorConfig()
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},"paths":{},
+	  "x-generator-config":{
+	    "defaults":[
+	      "schemaReplacements","fieldFilters","views","tagNamespace","wrappers",
+	      "implementations","limitRecursion","enumDescriptions","base64Bytes",
+	      "vendorExtensions"
+	    ],
+	    "maxRecursionDepth":1,"keepContentTypeParams":false,"enumOneOf":false,
+	    "defaultTags":true,"sharedComponents":false,
+	    "typeMappings":{"time.Duration":"jsonschema.Schema"},
+	    "inlinedTypes":["time.Duration"]
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestReflector_AddOperation_wildcard(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		Path string `path:"path"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/files/{path...}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"path","in":"path","required":true,"schema":{"type":"string"},"x-wildcard":true}
+	]`, r.Spec.Paths.MapOfPathItemValues["/files/{path}"].MapOfOperationValues["get"].Parameters)
+}
+
+func TestReflector_AddOperation_duplicateID(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/people")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	assert.EqualError(t, r.AddOperation(oc),
+		`duplicate operation ID "listUsers" of get /people, already used by get /users`)
+
+	// Operations added to spec directly are checked on finalization.
+	op := openapi3.Operation{}
+	op.WithID("listUsers")
+	require.NoError(t, r.Spec.AddOperation(http.MethodGet, "/people", op))
+	assert.Equal(t, []report.Finding{{
+		Rule:     "duplicate-operation-id",
+		Severity: report.SeverityError,
+		Message:  `operation ID "listUsers" is already used by /paths/~1people/get`,
+		Pointer:  "/paths/~1users/get/operationId",
+	}}, r.Finalize())
+}
+
+func TestReflector_ReplaceOperations(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type Req struct {
+		Limit int `query:"limit"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	_, err = r.NewOperationContext(http.MethodGet, "/users")
+	assert.EqualError(t, err, "operation already exists: get /users")
+
+	r.ReplaceOperations = true
+
+	type ReqV2 struct {
+		Cursor string `query:"cursor"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	oc.AddReqStructure(ReqV2{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "operationId":"listUsers",
+	  "parameters":[{"name":"cursor","in":"query","schema":{"type":"string"}}],
+	  "responses":{"204":{"description":"No Content"}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["get"])
+}
+
+func TestReflector_Mount(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	v1 := r.Mount("/v1/")
+	admin := v1.Mount("/admin", "Admin")
+
+	for _, m := range []openapi.MountedReflector{v1, admin} {
+		for _, path := range []string{"/", "/users/{id}"} {
+			oc, err := m.NewOperationContext(http.MethodGet, path)
+			require.NoError(t, err)
+
+			if path != "/" {
+				oc.AddReqStructure(struct {
+					ID string `path:"id"`
+				}{})
+			}
+
+			require.NoError(t, m.AddOperation(oc))
+		}
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "/v1":{"get":{"responses":{"204":{"description":"No Content"}}}},
+	  "/v1/admin":{"get":{"tags":["Admin"],"responses":{"204":{"description":"No Content"}}}},
+	  "/v1/admin/users/{id}":{
+		"get":{
+		  "tags":["Admin"],"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+		  "responses":{"204":{"description":"No Content"}}
+		}
+	  },
+	  "/v1/users/{id}":{
+		"get":{
+		  "parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+		  "responses":{"204":{"description":"No Content"}}
+		}
+	  }
+	}`, r.Spec.Paths)
+}
+
+func TestReflector_Group(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type errResp struct {
+		Message string `json:"message"`
+	}
+
+	pets := r.Group(
+		openapi.WithTags("Pets"),
+		openapi.WithSecurity("bearerAuth"),
+		openapi.WithRespStructure(errResp{}, http.StatusInternalServerError),
+	)
+	admin := pets.Group(openapi.WithTags("Admin"), openapi.WithTrait(openapi.Trait{
+		Extensions: map[string]interface{}{"internal": true},
+	}))
+
+	for method, g := range map[string]openapi.Group{http.MethodGet: pets, http.MethodDelete: admin} {
+		oc, err := g.NewOperationContext(method, "/pets")
+		require.NoError(t, err)
+		require.NoError(t, g.AddOperation(oc))
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "get":{
+		"tags":["Pets"],
+		"responses":{
+		  "500":{
+			"description":"Internal Server Error",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestErrResp"}}}
+		  }
+		},
+		"security":[{"bearerAuth":[]}]
+	  },
+	  "delete":{
+		"tags":["Pets","Admin"],
+		"responses":{
+		  "500":{
+			"description":"Internal Server Error",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestErrResp"}}}
+		  }
+		},
+		"security":[{"bearerAuth":[]}],"x-internal":true
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/pets"])
+}
+
+type auditable interface {
+	Audit() string
+}
+
+type auditInfo struct {
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type linkable interface {
+	Links() []string
+}
+
+func TestReflector_AddImplementation(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.AddImplementation(new(auditable), auditInfo{})
+	r.AddImplementation(new(linkable), links{})
+
+	type Pet struct {
+		auditable
+		linkable
+
+		Name string `json:"name"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+	}{})
+	oc.AddRespStructure(Pet{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "schemas":{
+		"Openapi3TestAuditInfo":{
+		  "properties":{"createdAt":{"format":"date-time","type":"string"},"createdBy":{"type":"string"}},
+		  "type":"object"
+		},
+		"Openapi3TestLinks":{"properties":{"self":{"type":"string"}},"type":"object"},
+		"Openapi3TestPet":{
+		  "allOf":[
+			{"$ref":"#/components/schemas/Openapi3TestAuditInfo"},
+			{"$ref":"#/components/schemas/Openapi3TestLinks"}
+		  ],
+		  "properties":{"name":{"type":"string"}},"type":"object"
+		}
+	  }
+	}`, r.Spec.Components)
+
+	assert.Panics(t, func() {
+		r.AddImplementation(auditInfo{}, auditInfo{})
+	})
+}
+
+func TestReflector_AddPathParameters(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	require.NoError(t, r.AddPathParameters("/accounts/{accountID:[0-9]+}/users", struct {
+		AccountID int    `path:"accountID"`
+		Tenant    string `header:"X-Tenant"`
+	}{}))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		oc, err := r.NewOperationContext(method, "/accounts/{accountID:[0-9]+}/users")
+		require.NoError(t, err)
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{"name":"accountID","in":"path","required":true,"schema":{"pattern":"^[0-9]+$","type":"integer"}},
+		{"name":"X-Tenant","in":"header","schema":{"type":"string"}}
+	  ],
+	  "get":{"responses":{"204":{"description":"No Content"}}},
+	  "post":{"responses":{"204":{"description":"No Content"}}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/accounts/{accountID}/users"])
+
+	assert.EqualError(t, r.AddPathParameters("/accounts", struct {
+		AccountID int `path:"accountID"`
+	}{}), "missing path parameter placeholder in url: accountID")
+}
+
+func TestReflector_AddDefaultResponse(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type errResp struct {
+		Message string `json:"message"`
+	}
+
+	type notFound struct {
+		Resource string `json:"resource"`
+	}
+
+	r.AddDefaultResponse(http.StatusUnauthorized, errResp{})
+	r.AddDefaultResponse(http.StatusNotFound, errResp{})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets")
+	require.NoError(t, err)
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodDelete, "/pets")
+	require.NoError(t, err)
+	oc.AddRespStructure(notFound{}, openapi.WithHTTPStatus(http.StatusNotFound))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "get":{
+		"responses":{
+		  "204":{"description":"No Content"},
+		  "401":{
+			"description":"Unauthorized",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestErrResp"}}}
+		  },
+		  "404":{
+			"description":"Not Found",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestErrResp"}}}
+		  }
+		}
+	  },
+	  "delete":{
+		"responses":{
+		  "401":{
+			"description":"Unauthorized",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestErrResp"}}}
+		  },
+		  "404":{
+			"description":"Not Found",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestNotFound"}}}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/pets"])
+}
+
+func TestReflector_AddOperation_anchors(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.Anchors = true
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID int `path:"id"`
+	}{})
+	oc.AddRespStructure(user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/users")
+	require.NoError(t, err)
+	oc.SetID("createUser")
+	openapi.Trait{Extensions: map[string]interface{}{openapi.XAnchor: "custom"}}.Apply(oc)
+	oc.AddReqStructure(user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, "operation/getUsersId", r.Spec.Paths.MapOfPathItemValues["/users/{id}"].MapOfOperationValues["get"].MapOfAnything[openapi.XAnchor])
+	assert.Equal(t, "custom", r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["post"].MapOfAnything[openapi.XAnchor])
+	assert.Equal(t, "schema/Openapi3TestUser", r.Spec.Components.Schemas.MapOfSchemaOrRefValues["Openapi3TestUser"].Schema.MapOfAnything[openapi.XAnchor])
+}
+
+func TestReflector_WithOperationInterceptor(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	r.WithOperationInterceptor(func(_ openapi.OperationContext, op *openapi3.Operation) error {
+		if op.Summary == nil {
+			return errors.New("missing summary")
+		}
+
+		return nil
+	})
+	r.WithOperationInterceptor(func(_ openapi.OperationContext, op *openapi3.Operation) error {
+		op.WithSecurity(map[string][]string{"bearerAuth": {}})
+
+		return nil
+	})
+
+	var added []string
+
+	r.WithAddedOperationInterceptor(func(oc openapi.OperationContext, _ *openapi3.Operation) error {
+		added = append(added, oc.Method()+" "+oc.PathPattern())
+
+		return nil
+	})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+	require.EqualError(t, r.AddOperation(oc), "intercept operation get /users: missing summary")
+	assert.NotContains(t, r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues, "get")
+
+	oc.SetSummary("List users")
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, []string{"get /users"}, added)
+	assertjson.EqMarshal(t, `{
+	  "summary":"List users","responses":{"204":{"description":"No Content"}},
+	  "security":[{"bearerAuth":[]}]
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["get"])
+}
+
+func TestReflector_AddPreflightOperations(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type req struct {
+		ID int `path:"id"`
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		oc, err := r.NewOperationContext(method, "/users/{id}")
+		require.NoError(t, err)
+		oc.AddReqStructure(req{})
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	oc, err := r.NewOperationContext(http.MethodOptions, "/custom")
+	require.NoError(t, err)
+	require.NoError(t, r.AddOperation(oc))
+
+	require.NoError(t, r.AddPreflightOperations())
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+	    {"name":"id","in":"path","required":true,"schema":{"type":"integer"}},
+	    {
+	      "name":"Origin","in":"header",
+	      "description":"Origin of cross-origin request.",
+	      "schema":{"type":"string","description":"Origin of cross-origin request."}
+	    },
+	    {
+	      "name":"Access-Control-Request-Method","in":"header",
+	      "description":"Method of cross-origin request.",
+	      "schema":{
+	        "enum":["GET","DELETE","OPTIONS"],"type":"string",
+	        "description":"Method of cross-origin request."
+	      }
+	    },
+	    {
+	      "name":"Access-Control-Request-Headers","in":"header",
+	      "description":"Comma-separated headers of cross-origin request.",
+	      "schema":{
+	        "type":"string",
+	        "description":"Comma-separated headers of cross-origin request."
+	      }
+	    }
+	  ],
+	  "responses":{
+	    "204":{
+	      "description":"No Content",
+	      "headers":{
+	        "Access-Control-Allow-Headers":{
+	          "style":"simple",
+	          "description":"Headers allowed for cross-origin requests.",
+	          "schema":{
+	            "type":"string",
+	            "description":"Headers allowed for cross-origin requests."
+	          }
+	        },
+	        "Access-Control-Allow-Methods":{
+	          "style":"simple",
+	          "description":"Methods allowed for cross-origin requests.",
+	          "schema":{
+	            "type":"string",
+	            "description":"Methods allowed for cross-origin requests.",
+	            "example":"GET, DELETE, OPTIONS"
+	          }
+	        },
+	        "Access-Control-Allow-Origin":{
+	          "style":"simple",
+	          "description":"Origin that is allowed to access resource.",
+	          "schema":{
+	            "type":"string",
+	            "description":"Origin that is allowed to access resource."
+	          }
+	        },
+	        "Access-Control-Max-Age":{
+	          "style":"simple",
+	          "description":"Seconds for which preflight response can be cached.",
+	          "schema":{
+	            "type":"integer",
+	            "description":"Seconds for which preflight response can be cached."
+	          }
+	        },
+	        "Allow":{
+	          "style":"simple","description":"Methods supported by resource.",
+	          "required":true,
+	          "schema":{
+	            "type":"string","description":"Methods supported by resource.",
+	            "example":"GET, DELETE, OPTIONS"
+	          }
+	        }
+	      }
+	    }
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/users/{id}"].MapOfOperationValues["options"])
+	assertjson.EqMarshal(t, `{"responses":{"204":{"description":"No Content"}}}`,
+		r.Spec.Paths.MapOfPathItemValues["/custom"].MapOfOperationValues["options"])
+}
+
+func TestReflector_AddOperation_msgpack(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	type shape struct {
+		Name   string  `msgpack:"n" json:"name"`
+		Points []point `msgpack:"p" json:"points"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/shapes")
+	require.NoError(t, err)
+	oc.AddReqStructure(shape{}, openapi.WithContentType("application/x-msgpack"))
+	oc.AddRespStructure(shape{}, openapi.WithContentType("application/x-msgpack"))
+	oc.AddRespStructure(shape{}, openapi.WithHTTPStatus(http.StatusAccepted))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/shapes":{
+	      "post":{
+	        "requestBody":{
+	          "content":{
+	            "application/x-msgpack":{
+	              "schema":{"$ref":"#/components/schemas/MsgpackOpenapi3TestShape"}
+	            }
+	          }
+	        },
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "content":{
+	              "application/x-msgpack":{
+	                "schema":{"$ref":"#/components/schemas/MsgpackOpenapi3TestShape"}
+	              }
+	            }
+	          },
+	          "202":{
+	            "description":"Accepted",
+	            "content":{
+	              "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestShape"}}
+	            }
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "MsgpackOpenapi3TestShape":{
+	        "type":"object",
+	        "properties":{
+	          "n":{"type":"string"},
+	          "p":{
+	            "type":"array",
+	            "items":{"$ref":"#/components/schemas/Openapi3TestPoint"},
+	            "nullable":true
+	          }
+	        }
+	      },
+	      "Openapi3TestPoint":{
+	        "type":"object",
+	        "properties":{"x":{"type":"integer"},"y":{"type":"integer"}}
+	      },
+	      "Openapi3TestShape":{
+	        "type":"object",
+	        "properties":{
+	          "name":{"type":"string"},
+	          "points":{
+	            "type":"array",
+	            "items":{"$ref":"#/components/schemas/Openapi3TestPoint"},
+	            "nullable":true
+	          }
+	        }
+	      }
+	    }
+	  }
+	}`, r.Spec)
+}
+
+func TestReflector_AddOperation_hal(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type item struct {
+		Links openapi.HALLinks `json:"_links"`
+		SKU   string           `json:"sku"`
+	}
+
+	type order struct {
+		Links    openapi.HALLinks `json:"_links"`
+		Embedded struct {
+			Items []item `json:"items"`
+		} `json:"_embedded"`
+		Total float64 `json:"total"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/orders/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+	}{})
+	oc.AddRespStructure(order{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/orders/{id}":{
+	      "get":{
+	        "parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "content":{
+	              "application/hal+json":{"schema":{"$ref":"#/components/schemas/Openapi3TestOrder"}}
+	            }
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "Openapi3TestItem":{
+	        "type":"object",
+	        "properties":{
+	          "_links":{"$ref":"#/components/schemas/OpenapiGoHALLinks"},
+	          "sku":{"type":"string"}
+	        }
+	      },
+	      "Openapi3TestOrder":{
+	        "type":"object",
+	        "properties":{
+	          "_embedded":{
+	            "type":"object",
+	            "properties":{
+	              "items":{
+	                "type":"array",
+	                "items":{"$ref":"#/components/schemas/Openapi3TestItem"},
+	                "nullable":true
+	              }
+	            }
+	          },
+	          "_links":{"$ref":"#/components/schemas/OpenapiGoHALLinks"},
+	          "total":{"type":"number"}
+	        }
+	      },
+	      "OpenapiGoHALLink":{
+	        "required":["href"],"type":"object",
+	        "properties":{
+	          "deprecation":{
+	            "type":"string",
+	            "description":"URL with deprecation notice of link.","format":"uri"
+	          },
+	          "href":{
+	            "type":"string",
+	            "description":"URI or URI Template of target resource."
+	          },
+	          "hreflang":{"type":"string"},
+	          "name":{
+	            "type":"string",
+	            "description":"Secondary key to select link of the same relation."
+	          },
+	          "profile":{"type":"string","format":"uri"},
+	          "templated":{
+	            "type":"boolean",
+	            "description":"Indicates that href is a URI Template."
+	          },
+	          "title":{"type":"string"},
+	          "type":{"type":"string","description":"Media type of target resource."}
+	        }
+	      },
+	      "OpenapiGoHALLinks":{
+	        "type":"object",
+	        "additionalProperties":{
+	          "oneOf":[
+	            {"$ref":"#/components/schemas/OpenapiGoHALLink"},
+	            {
+	              "type":"array",
+	              "items":{"$ref":"#/components/schemas/OpenapiGoHALLink"}
+	            }
+	          ]
+	        }
+	      }
+	    }
+	  }
+	}`, r.Spec)
+}
+
+func TestSunset(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/v1/users")
+	require.NoError(t, err)
+	openapi.Sunset(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).Apply(oc)
+	oc.AddRespStructure([]string{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNotFound))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+	    "200":{
+	      "description":"OK",
+	      "headers":{
+	        "Deprecation":{
+	          "style":"simple",
+	          "description":"Indicates that operation is deprecated.",
+	          "schema":{
+	            "type":"string",
+	            "description":"Indicates that operation is deprecated."
+	          }
+	        },
+	        "Sunset":{
+	          "style":"simple",
+	          "description":"Date after which operation is no longer available.",
+	          "schema":{
+	            "type":"string",
+	            "description":"Date after which operation is no longer available.",
+	            "example":"Sun, 01 Jun 2025 00:00:00 GMT"
+	          }
+	        }
+	      },
+	      "content":{
+	        "application/json":{"schema":{"type":"array","items":{"type":"string"}}}
+	      }
+	    },
+	    "404":{
+	      "description":"Not Found",
+	      "headers":{
+	        "Deprecation":{
+	          "style":"simple",
+	          "description":"Indicates that operation is deprecated.",
+	          "schema":{
+	            "type":"string",
+	            "description":"Indicates that operation is deprecated."
+	          }
+	        },
+	        "Sunset":{
+	          "style":"simple",
+	          "description":"Date after which operation is no longer available.",
+	          "schema":{
+	            "type":"string",
+	            "description":"Date after which operation is no longer available.",
+	            "example":"Sun, 01 Jun 2025 00:00:00 GMT"
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "deprecated":true,"x-sunset":"2025-06-01T00:00:00Z"
+	}`, r.Spec.Paths.MapOfPathItemValues["/v1/users"].MapOfOperationValues["get"])
+}
+
+func TestReflector_ReplaceSchema(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.ReplaceSchema(money{}, func(s *jsonschema.Schema) {
+		s.WithType(jsonschema.String.Type()).WithFormat("decimal").WithExamples("12.30")
+	})
+
+	type order struct {
+		Total    money  `json:"total"`
+		Discount *money `json:"discount,omitempty"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/orders")
+	require.NoError(t, err)
+	oc.AddReqStructure(order{})
+	oc.AddRespStructure(order{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi3TestMoney":{"type":"string","format":"decimal","example":"12.30"},
+	  "Openapi3TestOrder":{
+	    "type":"object",
+	    "properties":{
+	      "discount":{"$ref":"#/components/schemas/Openapi3TestMoney"},
+	      "total":{"$ref":"#/components/schemas/Openapi3TestMoney"}
+	    }
+	  }
+	}`, r.Spec.Components.Schemas)
+}
+
+func TestReflector_WithOperationCustomizer(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.WithOperationCustomizer(func(op *openapi3.Operation) {
+		if op.Summary == nil && op.ID != nil {
+			op.WithSummary(*op.ID)
+		}
+	})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+	oc.SetID("listItems")
+	oc.AddRespStructure([]string{})
+	require.NoError(t, r.AddOperation(oc))
+
+	require.NoError(t, r.Spec.SetupOperation(http.MethodGet, "/items", func(op *openapi3.Operation) error {
+		require.NotNil(t, op.Summary)
+		assert.Equal(t, "listItems", *op.Summary)
+
+		return nil
+	}))
+}
+
+func TestView(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	type user struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email" required:"true" view:"admin,self"`
+		Notes string `json:"notes" view:"admin"`
+	}
+
+	type team struct {
+		Title   string `json:"title"`
+		Members []user `json:"members"`
+	}
+
+	for path, view := range map[string]string{"/teams": "", "/admin/teams": "admin", "/public/teams": "public"} {
+		oc, err := r.NewOperationContext(http.MethodGet, path)
+		require.NoError(t, err)
+		openapi.View(view).Apply(oc)
+		oc.AddRespStructure(team{})
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi3TestTeam":{
+	    "type":"object",
+	    "properties":{
+	      "members":{
+	        "type":"array","items":{"$ref":"#/components/schemas/Openapi3TestUser"},
+	        "nullable":true
+	      },
+	      "title":{"type":"string"}
+	    }
+	  },
+	  "Openapi3TestTeamAdminView":{
+	    "type":"object",
+	    "properties":{
+	      "members":{
+	        "type":"array",
+	        "items":{"$ref":"#/components/schemas/Openapi3TestUserAdminView"},
+	        "nullable":true
+	      },
+	      "title":{"type":"string"}
+	    }
+	  },
+	  "Openapi3TestTeamPublicView":{
+	    "type":"object",
+	    "properties":{
+	      "members":{
+	        "type":"array",
+	        "items":{"$ref":"#/components/schemas/Openapi3TestUserPublicView"},
+	        "nullable":true
+	      },
+	      "title":{"type":"string"}
+	    }
+	  },
+	  "Openapi3TestUser":{
+	    "required":["email"],"type":"object",
+	    "properties":{
+	      "email":{"type":"string"},"id":{"type":"integer"},
+	      "name":{"type":"string"},"notes":{"type":"string"}
+	    }
+	  },
+	  "Openapi3TestUserAdminView":{
+	    "required":["email"],"type":"object",
+	    "properties":{
+	      "email":{"type":"string"},"id":{"type":"integer"},
+	      "name":{"type":"string"},"notes":{"type":"string"}
+	    }
+	  },
+	  "Openapi3TestUserPublicView":{
+	    "type":"object",
+	    "properties":{"id":{"type":"integer"},"name":{"type":"string"}}
+	  }
+	}`, r.Spec.Components.Schemas)
+}
+
+func TestReflector_TagNamespace(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.TagNamespace = "oas"
+
+	type record struct {
+		ID      int    `json:"_id" oas:"id"`
+		Name    string `json:"name"`
+		Version int    `json:"_v" oas:"-"`
+		Title   string `oas:"title"`
+	}
+
+	type req struct {
+		ID    int    `path:"id"`
+		Trace string `header:"X-Trace" oas-header:"X-Trace-ID"`
+		Limit int    `query:"l" oas-query:"limit"`
+		record
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/records/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(record{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/records/{id}":{
+		  "put":{
+			"parameters":[
+			  {"name":"limit","in":"query","schema":{"type":"integer"}},
+			  {"name":"id","in":"path","required":true,"schema":{"type":"integer"}},
+			  {"name":"X-Trace-ID","in":"header","schema":{"type":"string"}}
+			],
+			"requestBody":{
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}}
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestRecord"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi3TestRecord":{
+			"properties":{"id":{"type":"integer"},"name":{"type":"string"},"title":{"type":"string"}},
+			"type":"object"
+		  },
+		  "Openapi3TestReq":{
+			"properties":{"id":{"type":"integer"},"name":{"type":"string"},"title":{"type":"string"}},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestReflector_StrictTags(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.StrictTags = true
+
+	type address struct {
+		City string `json:"city"`
+		Zip  string
+	}
+
+	type Audit struct {
+		CreatedBy string
+	}
+
+	type req struct {
+		Audit
+		ID      int       `path:"id"`
+		Name    string    `json:"name"`
+		Address address   `json:"address"`
+		Since   time.Time `json:"since"`
+		Comment string
+		_       struct{} `additionalProperties:"false"`
+		local   string
+	}
+
+	type resp struct {
+		Name  string `json:"name"`
+		Trace string `header:"X-Trace"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/items/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(req{local: "skipped"})
+	oc.AddRespStructure(resp{})
+	assert.EqualError(t, r.AddOperation(oc),
+		"check field tags put /items/{id}: untagged fields: Audit.CreatedBy, address.Zip, req.Comment")
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/items/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID int `path:"id"`
+	}{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+}
+
+func TestRequiredByDefault(t *testing.T) {
+	type item struct {
+		Title string  `json:"title"`
+		Note  *string `json:"note,omitempty"`
+	}
+
+	type req struct {
+		ID      int     `path:"id"`
+		Limit   int     `query:"limit"`
+		Name    string  `json:"name"`
+		Nick    *string `json:"nick"`
+		Comment string  `json:"comment,omitempty"`
+		Opt     string  `json:"opt" required:"false"`
+		Items   []item  `json:"items"`
+	}
+
+	type resp struct {
+		XRate int `header:"X-Rate"`
+		Total int `json:"total"`
+	}
+
+	r := openapi3.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.RequiredByDefault)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/items/{id}":{
+	      "post":{
+	        "parameters":[
+	          {"name":"limit","in":"query","schema":{"type":"integer"}},
+	          {
+	            "name":"id","in":"path","required":true,"schema":{"type":"integer"}
+	          }
+	        ],
+	        "requestBody":{
+	          "content":{
+	            "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}
+	          }
+	        },
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "headers":{"X-Rate":{"style":"simple","schema":{"type":"integer"}}},
+	            "content":{
+	              "application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestResp"}}
+	            }
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "Openapi3TestItem":{
+	        "required":["title"],"type":"object",
+	        "properties":{"note":{"type":"string","nullable":true},"title":{"type":"string"}}
+	      },
+	      "Openapi3TestReq":{
+	        "required":["name","nick","items"],"type":"object",
+	        "properties":{
+	          "comment":{"type":"string"},
+	          "items":{
+	            "type":"array",
+	            "items":{"$ref":"#/components/schemas/Openapi3TestItem"},
+	            "nullable":true
+	          },
+	          "name":{"type":"string"},"nick":{"type":"string","nullable":true},
+	          "opt":{"type":"string"}
+	        }
+	      },
+	      "Openapi3TestResp":{
+	        "required":["total"],"type":"object",
+	        "properties":{"total":{"type":"integer"}}
+	      }
+	    }
+	  }
+	}`, r.Spec)
+}
+
+func TestSpec_Stats(t *testing.T) {
+	type req struct {
+		ID string `path:"id" example:"abc"`
+	}
+
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.SetSummary("Get item.")
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(item{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/items")
+	require.NoError(t, err)
+	require.NoError(t, r.AddOperation(oc))
+
+	r.Spec.ComponentsEns().SchemasEns().WithMapOfSchemaOrRefValuesItem("Unused", openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)})
+
+	assertjson.EqMarshal(t, `{
+	  "operations":2,"operationsByMethod":{"get":1,"post":1},"schemas":2,
+	  "describedOperations":50,"exampledOperations":50,
+	  "orphanComponents":["#/components/schemas/Unused"]
+	}`, r.Spec.Stats())
+}
+
+func TestGoTypes(t *testing.T) {
+	type req struct {
+		ID    int       `path:"id"`
+		Since time.Time `json:"since"`
+	}
+
+	r := openapi3.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.GoTypes)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/checks/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(report.Finding{})
+	require.NoError(t, r.AddOperation(oc))
+
+	// Types of test package are not exposed.
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/checks/{id}":{
+		  "post":{
+			"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"integer"}}],
+			"requestBody":{
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestReq"}}}
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/ReportFinding"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi3TestReq":{
+			"properties":{"since":{"type":"string","format":"date-time"}},"type":"object"
+		  },
+		  "ReportFinding":{
+			"properties":{
+			  "message":{"type":"string"},"pointer":{"type":"string"},"rule":{"type":"string"},
+			  "severity":{"type":"string","x-go-type":"github.com/swaggest/openapi-go/report.Severity"}
+			},
+			"type":"object","x-go-type":"github.com/swaggest/openapi-go/report.Finding"
+		  }
+		}
+	  }
+	}`, r.SpecSchema())
+}
@@ -0,0 +1,233 @@
+package openapi3
+
+import (
+	"strconv"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// SchemaVisitor is called by Spec.WalkSchemas for every schema with its JSON pointer,
+// e.g. "#/paths/~1pets/get/responses/200/content/application~1json/schema".
+//
+// Schema can be changed or replaced in place, e.g. to add vendor extension or to remove examples.
+type SchemaVisitor func(pointer string, schema *SchemaOrRef) error
+
+// WalkSchemas calls visit for every schema of components, parameters, headers, request bodies and responses.
+//
+// Subschemas (e.g. properties or items) are visited after their parent.
+// Walk is sorted by names and stops on the first error of visit.
+func (s *Spec) WalkSchemas(visit SchemaVisitor) error {
+	w := schemaWalker{visit: visit}
+
+	if c := s.Components; c != nil {
+		if err := w.components(c); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range internal.SortedKeys(s.Paths.MapOfPathItemValues) {
+		pi := s.Paths.MapOfPathItemValues[path]
+
+		if err := w.parameters("#/paths/"+openapi.PointerToken(path)+"/parameters", pi.Parameters); err != nil {
+			return err
+		}
+	}
+
+	return s.WalkOperations(func(method, path string, op *Operation) error {
+		return w.operation("#/paths/"+openapi.PointerToken(path)+"/"+method, op)
+	})
+}
+
+type schemaWalker struct {
+	visit SchemaVisitor
+}
+
+func (w schemaWalker) schema(pointer string, s *SchemaOrRef) error {
+	if s == nil {
+		return nil
+	}
+
+	if err := w.visit(pointer, s); err != nil {
+		return err
+	}
+
+	sc := s.Schema
+	if sc == nil {
+		return nil
+	}
+
+	if err := w.schema(pointer+"/not", sc.Not); err != nil {
+		return err
+	}
+
+	for _, l := range []struct {
+		keyword string
+		items   []SchemaOrRef
+	}{{"allOf", sc.AllOf}, {"anyOf", sc.AnyOf}, {"oneOf", sc.OneOf}} {
+		for i := range l.items {
+			if err := w.schema(pointer+"/"+l.keyword+"/"+strconv.Itoa(i), &l.items[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := w.schema(pointer+"/items", sc.Items); err != nil {
+		return err
+	}
+
+	for _, name := range internal.SortedKeys(sc.Properties) {
+		p := sc.Properties[name]
+		err := w.schema(pointer+"/properties/"+openapi.PointerToken(name), &p)
+		sc.Properties[name] = p
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if sc.AdditionalProperties != nil {
+		return w.schema(pointer+"/additionalProperties", sc.AdditionalProperties.SchemaOrRef)
+	}
+
+	return nil
+}
+
+func (w schemaWalker) components(c *Components) error {
+	if c.Schemas != nil {
+		schemas := c.Schemas.MapOfSchemaOrRefValues
+
+		for _, name := range internal.SortedKeys(schemas) {
+			s := schemas[name]
+			err := w.schema("#/components/schemas/"+openapi.PointerToken(name), &s)
+			schemas[name] = s
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.Parameters != nil {
+		for _, name := range internal.SortedKeys(c.Parameters.MapOfParameterOrRefValues) {
+			p := c.Parameters.MapOfParameterOrRefValues[name].Parameter
+			if err := w.parameter("#/components/parameters/"+openapi.PointerToken(name), p); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.Headers != nil {
+		for _, name := range internal.SortedKeys(c.Headers.MapOfHeaderOrRefValues) {
+			h := c.Headers.MapOfHeaderOrRefValues[name].Header
+			if err := w.header("#/components/headers/"+openapi.PointerToken(name), h); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.RequestBodies != nil {
+		for _, name := range internal.SortedKeys(c.RequestBodies.MapOfRequestBodyOrRefValues) {
+			if rb := c.RequestBodies.MapOfRequestBodyOrRefValues[name].RequestBody; rb != nil {
+				if err := w.content("#/components/requestBodies/"+openapi.PointerToken(name)+"/content", rb.Content); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if c.Responses != nil {
+		for _, name := range internal.SortedKeys(c.Responses.MapOfResponseOrRefValues) {
+			r := c.Responses.MapOfResponseOrRefValues[name].Response
+			if err := w.response("#/components/responses/"+openapi.PointerToken(name), r); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w schemaWalker) operation(pointer string, op *Operation) error {
+	if err := w.parameters(pointer+"/parameters", op.Parameters); err != nil {
+		return err
+	}
+
+	if op.RequestBody != nil && op.RequestBody.RequestBody != nil {
+		if err := w.content(pointer+"/requestBody/content", op.RequestBody.RequestBody.Content); err != nil {
+			return err
+		}
+	}
+
+	if op.Responses.Default != nil {
+		if err := w.response(pointer+"/responses/default", op.Responses.Default.Response); err != nil {
+			return err
+		}
+	}
+
+	for _, status := range internal.SortedKeys(op.Responses.MapOfResponseOrRefValues) {
+		if err := w.response(pointer+"/responses/"+status, op.Responses.MapOfResponseOrRefValues[status].Response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w schemaWalker) parameters(pointer string, params []ParameterOrRef) error {
+	for i, p := range params {
+		if err := w.parameter(pointer+"/"+strconv.Itoa(i), p.Parameter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w schemaWalker) parameter(pointer string, p *Parameter) error {
+	if p == nil {
+		return nil
+	}
+
+	if err := w.schema(pointer+"/schema", p.Schema); err != nil {
+		return err
+	}
+
+	return w.content(pointer+"/content", p.Content)
+}
+
+func (w schemaWalker) header(pointer string, h *Header) error {
+	if h == nil {
+		return nil
+	}
+
+	if err := w.schema(pointer+"/schema", h.Schema); err != nil {
+		return err
+	}
+
+	return w.content(pointer+"/content", h.Content)
+}
+
+func (w schemaWalker) response(pointer string, r *Response) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, name := range internal.SortedKeys(r.Headers) {
+		if err := w.header(pointer+"/headers/"+openapi.PointerToken(name), r.Headers[name].Header); err != nil {
+			return err
+		}
+	}
+
+	return w.content(pointer+"/content", r.Content)
+}
+
+func (w schemaWalker) content(pointer string, content map[string]MediaType) error {
+	for _, ct := range internal.SortedKeys(content) {
+		if err := w.schema(pointer+"/"+openapi.PointerToken(ct)+"/schema", content[ct].Schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
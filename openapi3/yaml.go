@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 
+	"github.com/swaggest/openapi-go"
 	"gopkg.in/yaml.v2"
 )
 
@@ -45,6 +47,11 @@ func (s *Spec) MarshalYAML() ([]byte, error) {
 	return yaml.Marshal(yaml.MapSlice(v))
 }
 
+// Handler returns http.Handler that serves spec as JSON or YAML, see openapi.SpecHandler.
+func (s *Spec) Handler(options ...openapi.SpecHandlerOption) http.Handler {
+	return openapi.NewSpecHandler(s, options...)
+}
+
 type orderedMap []yaml.MapItem
 
 func (om *orderedMap) UnmarshalJSON(data []byte) error {
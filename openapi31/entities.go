@@ -1189,18 +1189,20 @@ func (r *Reference) UnmarshalJSON(data []byte) error {
 
 // Parameter structure is generated from "#/$defs/parameter".
 type Parameter struct {
-	Name          string                        `json:"name"` // Required.
-	In            ParameterIn                   `json:"in"`   // Required.
-	Description   *string                       `json:"description,omitempty"`
-	Required      *bool                         `json:"required,omitempty"`
-	Deprecated    *bool                         `json:"deprecated,omitempty"`
-	Schema        map[string]interface{}        `json:"schema,omitempty"`
-	Content       map[string]MediaType          `json:"content,omitempty"`
-	Style         *ParameterStyle               `json:"style,omitempty"`
-	Explode       *bool                         `json:"explode,omitempty"`
-	Example       *interface{}                  `json:"example,omitempty"`
-	Examples      map[string]ExampleOrReference `json:"examples,omitempty"`
-	MapOfAnything map[string]interface{}        `json:"-"` // Key must match pattern: `^x-`.
+	Name            string                        `json:"name"` // Required.
+	In              ParameterIn                   `json:"in"`   // Required.
+	Description     *string                       `json:"description,omitempty"`
+	Required        *bool                         `json:"required,omitempty"`
+	Deprecated      *bool                         `json:"deprecated,omitempty"`
+	Schema          map[string]interface{}        `json:"schema,omitempty"`
+	Content         map[string]MediaType          `json:"content,omitempty"`
+	Style           *ParameterStyle               `json:"style,omitempty"`
+	Explode         *bool                         `json:"explode,omitempty"`
+	AllowEmptyValue *bool                         `json:"allowEmptyValue,omitempty"`
+	AllowReserved   *bool                         `json:"allowReserved,omitempty"`
+	Example         *interface{}                  `json:"example,omitempty"`
+	Examples        map[string]ExampleOrReference `json:"examples,omitempty"`
+	MapOfAnything   map[string]interface{}        `json:"-"` // Key must match pattern: `^x-`.
 }
 
 // WithName sets Name value.
@@ -1279,6 +1281,18 @@ func (p *Parameter) WithExplode(val bool) *Parameter {
 	return p
 }
 
+// WithAllowEmptyValue sets AllowEmptyValue value.
+func (p *Parameter) WithAllowEmptyValue(val bool) *Parameter {
+	p.AllowEmptyValue = &val
+	return p
+}
+
+// WithAllowReserved sets AllowReserved value.
+func (p *Parameter) WithAllowReserved(val bool) *Parameter {
+	p.AllowReserved = &val
+	return p
+}
+
 // WithExample sets Example value.
 func (p *Parameter) WithExample(val interface{}) *Parameter {
 	p.Example = &val
@@ -1331,6 +1345,8 @@ var knownKeysParameter = []string{
 	"content",
 	"style",
 	"explode",
+	"allowEmptyValue",
+	"allowReserved",
 	"example",
 	"examples",
 }
@@ -5519,6 +5535,9 @@ type ParameterStyle string
 
 // ParameterStyle values enumeration.
 const (
+	ParameterStyleMatrix         = ParameterStyle("matrix")
+	ParameterStyleLabel          = ParameterStyle("label")
+	ParameterStyleSimple         = ParameterStyle("simple")
 	ParameterStyleForm           = ParameterStyle("form")
 	ParameterStyleSpaceDelimited = ParameterStyle("spaceDelimited")
 	ParameterStylePipeDelimited  = ParameterStyle("pipeDelimited")
@@ -5528,6 +5547,9 @@ const (
 // MarshalJSON encodes JSON.
 func (i ParameterStyle) MarshalJSON() ([]byte, error) {
 	switch i {
+	case ParameterStyleMatrix:
+	case ParameterStyleLabel:
+	case ParameterStyleSimple:
 	case ParameterStyleForm:
 	case ParameterStyleSpaceDelimited:
 	case ParameterStylePipeDelimited:
@@ -5552,6 +5574,9 @@ func (i *ParameterStyle) UnmarshalJSON(data []byte) error {
 	v := ParameterStyle(ii)
 
 	switch v {
+	case ParameterStyleMatrix:
+	case ParameterStyleLabel:
+	case ParameterStyleSimple:
 	case ParameterStyleForm:
 	case ParameterStyleSpaceDelimited:
 	case ParameterStylePipeDelimited:
@@ -1,11 +1,14 @@
 package openapi31_test
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
@@ -100,3 +103,294 @@ func TestSpec_SetupOperation_uncleanPath(t *testing.T) {
 	  }
 	}`), s)
 }
+
+func TestSpec_SetExtension(t *testing.T) {
+	s := openapi31.Spec{Openapi: "3.1.0"}
+	s.Info.Title = "Pets"
+
+	s.SetExtension("x-audience", "public")
+	s.SetInfoExtension("logo", map[string]interface{}{"url": "https://example.com/logo.png"})
+	s.SetTagExtension("Pets", "x-displayName", "Pet store")
+	s.SetTagExtension("Pets", "x-order", 1)
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0",
+	  "info":{"title":"Pets","version":"","x-logo":{"url":"https://example.com/logo.png"}},
+	  "tags":[{"name":"Pets","x-displayName":"Pet store","x-order":1}],
+	  "x-audience":"public"
+	}`, s)
+}
+
+func TestSpec_DeleteOperation(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type User struct {
+		Address Address `json:"address"`
+	}
+
+	type Order struct {
+		Address Address `json:"address"`
+	}
+
+	r := openapi31.NewReflector()
+	r.Spec.ComponentsEns().WithSchemasItem("Manual", map[string]interface{}{"type": "string"})
+
+	for path, resp := range map[string]interface{}{"/users": User{}, "/orders": Order{}} {
+		oc, err := r.NewOperationContext(http.MethodGet, path)
+		require.NoError(t, err)
+
+		oc.AddRespStructure(resp)
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	require.EqualError(t, r.Spec.DeleteOperation(http.MethodPost, "/users"), "operation not found: post /users")
+	require.NoError(t, r.Spec.DeleteOperation(http.MethodGet, "/users"))
+
+	assertjson.EqMarshal(t, `{
+	  "schemas":{
+		"Manual":{"type":"string"},
+		"Openapi31TestAddress":{"properties":{"city":{"type":"string"}},"type":"object"},
+		"Openapi31TestOrder":{
+		  "properties":{"address":{"$ref":"#/components/schemas/Openapi31TestAddress"}},"type":"object"
+		}
+	  }
+	}`, r.Spec.Components)
+
+	require.EqualError(t, r.Spec.DeletePath("/users"), "path not found: /users")
+	require.NoError(t, r.Spec.DeletePath("/orders"))
+
+	assertjson.EqMarshal(t, `{"schemas":{"Manual":{"type":"string"}}}`, r.Spec.Components)
+	assertjson.EqMarshal(t, `{}`, r.Spec.Paths)
+}
+
+func TestSpec_PruneUnusedComponents(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi31.NewReflector()
+	r.Spec.SetHTTPBasicSecurity("basic", "Admin access.")
+	r.Spec.ComponentsEns().
+		WithSchemasItem("Manual", map[string]interface{}{"$ref": "#/components/schemas/Nested"}).
+		WithSchemasItem("Nested", map[string]interface{}{"type": "string"}).
+		WithParametersItem("Tenant", openapi31.ParameterOrReference{
+			Parameter: &openapi31.Parameter{Name: "X-Tenant", In: openapi31.ParameterInHeader},
+		})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(User{})
+	require.NoError(t, r.AddOperation(oc))
+
+	removed, err := r.Spec.PruneUnusedComponents()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"#/components/parameters/Tenant",
+		"#/components/schemas/Manual",
+		"#/components/schemas/Nested",
+	}, removed)
+
+	assertjson.EqMarshal(t, `{
+	  "schemas":{"Openapi31TestUser":{"properties":{"name":{"type":"string"}},"type":"object"}},
+	  "securitySchemes":{"basic":{"description":"Admin access.","type":"http","scheme":"basic"}}
+	}`, r.Spec.Components)
+}
+
+func TestSpec_FillMissingExamples(t *testing.T) {
+	type User struct {
+		Name  string `json:"name" minLength:"3"`
+		Email string `json:"email" format:"email"`
+		Role  string `json:"role" enum:"admin,guest"`
+	}
+
+	type apiError struct {
+		Message string `json:"message" example:"Not found."`
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/users")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(User{})
+	oc.AddRespStructure(User{}, openapi.WithHTTPStatus(http.StatusCreated))
+	oc.AddRespStructure(apiError{}, openapi.WithHTTPStatus(http.StatusConflict))
+	require.NoError(t, r.AddOperation(oc))
+
+	r.Spec.FillMissingExamples(openapi.ExampleGenerator{})
+
+	assertjson.EqMarshal(t, `{
+	  "requestBody":{
+		"content":{
+		  "application/json":{
+			"schema":{"$ref":"#/components/schemas/Openapi31TestUser"},
+			"example":{"email":"user@example.com","name":"string","role":"admin"}
+		  }
+		}
+	  },
+	  "responses":{
+		"201":{
+		  "description":"Created",
+		  "content":{
+			"application/json":{
+			  "schema":{"$ref":"#/components/schemas/Openapi31TestUser"},
+			  "example":{"email":"user@example.com","name":"string","role":"admin"}
+			}
+		  }
+		},
+		"409":{
+		  "description":"Conflict",
+		  "content":{
+			"application/json":{
+			  "schema":{"$ref":"#/components/schemas/Openapi31TestApiError"},
+			  "example":{"message":"Not found."}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].Post)
+}
+
+func TestSpec_WalkOperations(t *testing.T) {
+	s := openapi31.Spec{}
+
+	for _, mp := range [][2]string{
+		{http.MethodPost, "/users"}, {http.MethodGet, "/users"}, {http.MethodGet, "/orders"},
+	} {
+		require.NoError(t, s.AddOperation(mp[0], mp[1], openapi31.Operation{}))
+	}
+
+	var visited []string
+
+	require.NoError(t, s.WalkOperations(func(method, path string, op *openapi31.Operation) error {
+		visited = append(visited, method+" "+path)
+		op.WithSummary("Summary of " + method + " " + path)
+
+		return nil
+	}))
+
+	assert.Equal(t, []string{"get /orders", "get /users", "post /users"}, visited)
+
+	op := s.Paths.MapOfPathItemValues["/users"].Post
+	assert.Equal(t, "Summary of post /users", *op.Summary)
+
+	stop := errors.New("stop")
+	visited = nil
+
+	assert.Equal(t, stop, s.WalkOperations(func(method, path string, _ *openapi31.Operation) error {
+		visited = append(visited, method+" "+path)
+
+		return stop
+	}))
+	assert.Equal(t, []string{"get /orders"}, visited)
+}
+
+func TestSpec_WalkSchemas(t *testing.T) {
+	type Pet struct {
+		Name    string   `json:"name" example:"Rex"`
+		Aliases []string `json:"aliases"`
+	}
+
+	type req struct {
+		Limit int `query:"limit" example:"10"`
+		Pet
+	}
+
+	type resp struct {
+		Total int   `header:"X-Total"`
+		Pets  []Pet `json:"pets"`
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	var pointers []string
+
+	require.NoError(t, r.Spec.WalkSchemas(func(pointer string, schema map[string]interface{}) error {
+		pointers = append(pointers, pointer)
+		delete(schema, "examples")
+		schema["x-visited"] = true
+
+		return nil
+	}))
+
+	assert.Equal(t, []string{
+		"#/components/schemas/Openapi31TestPet",
+		"#/components/schemas/Openapi31TestPet/properties/aliases",
+		"#/components/schemas/Openapi31TestPet/properties/aliases/items",
+		"#/components/schemas/Openapi31TestPet/properties/name",
+		"#/components/schemas/Openapi31TestReq",
+		"#/components/schemas/Openapi31TestReq/properties/aliases",
+		"#/components/schemas/Openapi31TestReq/properties/aliases/items",
+		"#/components/schemas/Openapi31TestReq/properties/name",
+		"#/components/schemas/Openapi31TestResp",
+		"#/components/schemas/Openapi31TestResp/properties/pets",
+		"#/components/schemas/Openapi31TestResp/properties/pets/items",
+		"#/paths/~1pets/post/parameters/0/schema",
+		"#/paths/~1pets/post/requestBody/content/application~1json/schema",
+		"#/paths/~1pets/post/responses/200/headers/X-Total/schema",
+		"#/paths/~1pets/post/responses/200/content/application~1json/schema",
+	}, pointers)
+
+	assertjson.EqMarshal(t, `{"type":"string","x-visited":true}`,
+		r.Spec.Components.Schemas["Openapi31TestPet"]["properties"].(map[string]interface{})["name"])
+
+	stop := errors.New("stop")
+	pointers = nil
+
+	assert.Equal(t, stop, r.Spec.WalkSchemas(func(pointer string, _ map[string]interface{}) error {
+		pointers = append(pointers, pointer)
+
+		return stop
+	}))
+	assert.Equal(t, []string{"#/components/schemas/Openapi31TestPet"}, pointers)
+}
+
+func TestSpec_ExplainSchema(t *testing.T) {
+	type Owner struct {
+		Name  string   `json:"name" minLength:"1" description:"Full name."`
+		Pets  []string `json:"pets"`
+		Owner *Owner   `json:"owner,omitempty"`
+	}
+
+	type Pet struct {
+		ID     int64             `json:"id" required:"true"`
+		Status string            `json:"status" enum:"available,sold"`
+		Owner  Owner             `json:"owner"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets")
+	require.NoError(t, err)
+
+	oc.AddRespStructure([]Pet{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, `Openapi31TestPet: object
+  id: required integer
+  labels: object|null
+    additional properties: string
+  owner: object (Openapi31TestOwner)
+    name: string, minLength: 1 - Full name.
+    owner: object (Openapi31TestOwner)
+      (recursive Openapi31TestOwner)
+    pets: array of string|null
+  status: string, enum: available, sold
+Referenced by:
+  #/paths/~1pets/get/responses/200/content/application~1json/schema/items
+`, r.Spec.ExplainSchema("Openapi31TestPet"))
+
+	assert.Equal(t, "schema not found: Foo", r.Spec.ExplainSchema("Foo"))
+}
@@ -0,0 +1,17 @@
+package openapi31
+
+import "github.com/swaggest/openapi-go"
+
+// LoadSpec reads JSON or YAML Spec from a local file or http(s) URL.
+//
+// External references are bundled into components, so that loaded Spec
+// can be extended with Reflector.
+func LoadSpec(location string) (*Spec, error) {
+	s := &Spec{}
+
+	if err := (openapi.Loader{}).Load(location, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,55 @@
+package openapi31_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestLoadSpec(t *testing.T) {
+	s, err := openapi31.LoadSpec("testdata/bundle/openapi.yaml")
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"Bundled","version":"1.0.0"},
+	  "paths":{
+		"/users/{id}":{
+		  "get":{
+			"parameters":[{"$ref":"#/components/parameters/UserID"}],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"}}}
+			  },
+			  "404":{"$ref":"#/components/responses/NotFound"}
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Error":{"properties":{"message":{"type":"string"}},"type":"object"},
+		  "User":{
+			"properties":{
+			  "error":{"$ref":"#/components/schemas/Error"},
+			  "friends":{"items":{"$ref":"#/components/schemas/User"},"type":"array"},
+			  "name":{"type":"string"}
+			},
+			"type":"object"
+		  }
+		},
+		"responses":{
+		  "NotFound":{
+			"description":"Not Found",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error"}}}
+		  }
+		},
+		"parameters":{"UserID":{"name":"id","in":"path","required":true,"schema":{"type":"integer"}}}
+	  }
+	}`, s)
+
+	_, err = openapi31.LoadSpec("testdata/bundle/missing.yaml")
+	require.Error(t, err)
+}
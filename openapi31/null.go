@@ -0,0 +1,140 @@
+package openapi31
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// NullStrategy defines how nullable values (e.g. pointers) are rendered in schemas.
+type NullStrategy int
+
+// NullStrategy values enumeration.
+const (
+	// NullTypeArray adds "null" to schema type, e.g. {"type":["string","null"]}, default.
+	// References of nullable pointer properties (e.g. to structures) are kept as is.
+	NullTypeArray NullStrategy = iota
+
+	// NullAnyOf moves schema into "anyOf" together with null type,
+	// e.g. {"anyOf":[{"type":"string"},{"type":"null"}]}. References of nullable pointer properties
+	// are enveloped too, e.g. {"anyOf":[{"$ref":"#/components/schemas/User"},{"type":"null"}]}.
+	NullAnyOf
+
+	// NullOmit removes "null" from schema type, e.g. {"type":"string"}.
+	NullOmit
+)
+
+// String returns name of strategy.
+func (ns NullStrategy) String() string {
+	switch ns {
+	case NullTypeArray:
+		return "typeArray"
+	case NullAnyOf:
+		return "anyOf"
+	case NullOmit:
+		return "omit"
+	default:
+		return "unknown"
+	}
+}
+
+// nullAnnotations are kept in enveloping schema by NullAnyOf strategy.
+var nullAnnotations = map[string]bool{
+	"title":       true,
+	"description": true,
+	"default":     true,
+	"examples":    true,
+	"deprecated":  true,
+	"readOnly":    true,
+	"writeOnly":   true,
+}
+
+func (ns NullStrategy) apply(sm map[string]interface{}) {
+	if ns == NullTypeArray {
+		return
+	}
+
+	internal.WalkSchemaMap(sm, func(sm map[string]interface{}) {
+		types, ok := sm["type"].([]interface{})
+		if !ok {
+			return
+		}
+
+		var (
+			nonNull []interface{}
+			hasNull bool
+		)
+
+		for _, t := range types {
+			if t == string(jsonschema.Null) {
+				hasNull = true
+			} else {
+				nonNull = append(nonNull, t)
+			}
+		}
+
+		if !hasNull || len(nonNull) == 0 {
+			return
+		}
+
+		if len(nonNull) == 1 {
+			sm["type"] = nonNull[0]
+		} else {
+			sm["type"] = nonNull
+		}
+
+		if ns != NullAnyOf {
+			return
+		}
+
+		nonNullSchema := make(map[string]interface{}, len(sm))
+
+		for k, v := range sm {
+			if nullAnnotations[k] || strings.HasPrefix(k, "x-") {
+				continue
+			}
+
+			nonNullSchema[k] = v
+
+			delete(sm, k)
+		}
+
+		sm["anyOf"] = []interface{}{
+			nonNullSchema,
+			map[string]interface{}{"type": string(jsonschema.Null)},
+		}
+	})
+}
+
+// envelopNullableRefs returns reflection hook that makes references of nullable pointer properties
+// nullable with NullAnyOf strategy, e.g. {"anyOf":[{"$ref":"#/components/schemas/User"},{"type":"null"}]}.
+func envelopNullableRefs(strategy func() NullStrategy) func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		s := params.PropertySchema
+		if !params.Processed || s.Ref == nil || strategy() != NullAnyOf || !nullablePointer(params.Field) {
+			return nil
+		}
+
+		ref := jsonschema.Schema{Ref: s.Ref}
+		s.Ref = nil
+		s.AnyOf = append(s.AnyOf, ref.ToSchemaOrBool(), jsonschema.Null.ToSchemaOrBool())
+
+		return nil
+	})
+}
+
+// nullablePointer checks if field is a pointer that accepts null, `omitempty` disables nullability
+// unless it is enabled with `nullable:"true"`.
+func nullablePointer(field reflect.StructField) bool {
+	if field.Type.Kind() != reflect.Ptr {
+		return false
+	}
+
+	if nullable, ok := field.Tag.Lookup("nullable"); ok {
+		return nullable == "true"
+	}
+
+	return !strings.Contains(field.Tag.Get("json"), ",omitempty")
+}
@@ -1,11 +1,17 @@
 package openapi31_test
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +19,7 @@ import (
 	"github.com/swaggest/jsonschema-go"
 	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/report"
 )
 
 type WeirdResp interface {
@@ -1251,3 +1258,2665 @@ func TestReflector_AddOperation_rawSchema(t *testing.T) {
 	  }
 	}`, r.SpecSchema())
 }
+
+func TestReflector_NullStrategy(t *testing.T) {
+	type owner struct {
+		ID int `json:"id"`
+	}
+
+	type resp struct {
+		Name  *string   `json:"name" description:"Optional name."`
+		Tags  []string  `json:"tags"`
+		Count *int      `query:"count"`
+		Items []*string `json:"items,omitempty"`
+		Owner *owner    `json:"owner"`
+		Admin *owner    `json:"admin,omitempty"`
+	}
+
+	for strategy, expected := range map[openapi31.NullStrategy]string{
+		openapi31.NullTypeArray: `{
+		  "properties":{
+			"admin":{"$ref":"#/components/schemas/Openapi31TestOwner"},
+			"items":{"items":{"type":["null","string"]},"type":"array"},
+			"name":{"description":"Optional name.","type":["null","string"]},
+			"owner":{"$ref":"#/components/schemas/Openapi31TestOwner"},
+			"tags":{"items":{"type":"string"},"type":["array","null"]}
+		  },
+		  "type":"object"
+		}`,
+		openapi31.NullAnyOf: `{
+		  "properties":{
+			"admin":{"$ref":"#/components/schemas/Openapi31TestOwner"},
+			"items":{"items":{"anyOf":[{"type":"string"},{"type":"null"}]},"type":"array"},
+			"name":{"anyOf":[{"type":"string"},{"type":"null"}],"description":"Optional name."},
+			"owner":{"anyOf":[{"$ref":"#/components/schemas/Openapi31TestOwner"},{"type":"null"}]},
+			"tags":{"anyOf":[{"items":{"type":"string"},"type":"array"},{"type":"null"}]}
+		  },
+		  "type":"object"
+		}`,
+		openapi31.NullOmit: `{
+		  "properties":{
+			"admin":{"$ref":"#/components/schemas/Openapi31TestOwner"},
+			"items":{"items":{"type":"string"},"type":"array"},
+			"name":{"description":"Optional name.","type":"string"},
+			"owner":{"$ref":"#/components/schemas/Openapi31TestOwner"},
+			"tags":{"items":{"type":"string"},"type":"array"}
+		  },
+		  "type":"object"
+		}`,
+	} {
+		r := openapi31.Reflector{NullStrategy: strategy}
+
+		oc, err := r.NewOperationContext(http.MethodPost, "/")
+		require.NoError(t, err)
+
+		oc.AddReqStructure(resp{})
+		require.NoError(t, r.AddOperation(oc))
+
+		assertjson.EqMarshal(t, expected, r.Spec.Components.Schemas["Openapi31TestResp"])
+	}
+}
+
+func TestPointerOptional(t *testing.T) {
+	type req struct {
+		ID       int     `path:"id"`
+		Limit    int     `query:"limit"`
+		Offset   *int    `query:"offset"`
+		Name     string  `json:"name"`
+		Nick     *string `json:"nick"`
+		Comment  string  `json:"comment,omitempty"`
+		Explicit *string `json:"explicit" required:"true"`
+		Opt      string  `json:"opt" required:"false"`
+	}
+
+	type resp struct {
+		XRate  int     `header:"X-Rate"`
+		XTrace *string `header:"X-Trace"`
+		Total  int     `json:"total"`
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.PointerOptional)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/items/{id}":{
+		  "post":{
+			"parameters":[
+			  {"name":"limit","in":"query","required":true,"schema":{"type":"integer"}},
+			  {"name":"offset","in":"query","schema":{"type":["null","integer"]}},
+			  {"name":"id","in":"path","required":true,"schema":{"type":"integer"}}
+			],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}
+			  }
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"headers":{
+				  "X-Rate":{"style":"simple","required":true,"schema":{"type":"integer"}},
+				  "X-Trace":{"style":"simple","schema":{"type":["null","string"]}}
+				},
+				"content":{
+				  "application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestResp"}}
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestReq":{
+			"required":["name","explicit"],
+			"properties":{
+			  "comment":{"type":"string"},"explicit":{"type":["null","string"]},
+			  "name":{"type":"string"},"nick":{"type":["null","string"]},"opt":{"type":"string"}
+			},
+			"type":"object"
+		  },
+		  "Openapi31TestResp":{
+			"required":["total"],"properties":{"total":{"type":"integer"}},"type":"object"
+		  }
+		}
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestRequiredByDefault(t *testing.T) {
+	type item struct {
+		Title string  `json:"title"`
+		Note  *string `json:"note,omitempty"`
+	}
+
+	type req struct {
+		ID      int     `path:"id"`
+		Limit   int     `query:"limit"`
+		Name    string  `json:"name"`
+		Nick    *string `json:"nick"`
+		Comment string  `json:"comment,omitempty"`
+		Opt     string  `json:"opt" required:"false"`
+		Items   []item  `json:"items"`
+	}
+
+	type resp struct {
+		XRate int `header:"X-Rate"`
+		Total int `json:"total"`
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.RequiredByDefault)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/items/{id}":{
+		  "post":{
+			"parameters":[
+			  {"name":"limit","in":"query","schema":{"type":"integer"}},
+			  {"name":"id","in":"path","required":true,"schema":{"type":"integer"}}
+			],
+			"requestBody":{
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}}
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"headers":{"X-Rate":{"style":"simple","schema":{"type":"integer"}}},
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestResp"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestItem":{
+			"properties":{"note":{"type":["null","string"]},"title":{"type":"string"}},
+			"required":["title"],"type":"object"
+		  },
+		  "Openapi31TestReq":{
+			"properties":{
+			  "comment":{"type":"string"},
+			  "items":{"items":{"$ref":"#/components/schemas/Openapi31TestItem"},"type":["array","null"]},
+			  "name":{"type":"string"},"nick":{"type":["null","string"]},"opt":{"type":"string"}
+			},
+			"required":["name","nick","items"],"type":"object"
+		  },
+		  "Openapi31TestResp":{"properties":{"total":{"type":"integer"}},"required":["total"],"type":"object"}
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestGoTypes(t *testing.T) {
+	type req struct {
+		ID    int       `path:"id"`
+		Since time.Time `json:"since"`
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.GoTypes)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/checks/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(report.Finding{})
+	require.NoError(t, r.AddOperation(oc))
+
+	// Types of test package are not exposed.
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/checks/{id}":{
+		  "post":{
+			"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"integer"}}],
+			"requestBody":{
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}}
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/ReportFinding"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestReq":{
+			"properties":{"since":{"type":"string","format":"date-time"}},"type":"object"
+		  },
+		  "ReportFinding":{
+			"properties":{
+			  "message":{"type":"string"},"pointer":{"type":"string"},"rule":{"type":"string"},
+			  "severity":{"type":"string","x-go-type":"github.com/swaggest/openapi-go/report.Severity"}
+			},
+			"type":"object","x-go-type":"github.com/swaggest/openapi-go/report.Finding"
+		  }
+		}
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestSplitReadWriteOnly(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type User struct {
+		ID       int     `json:"id" readOnly:"true" required:"true"`
+		Password string  `json:"password" writeOnly:"true"`
+		Name     string  `json:"name" required:"true"`
+		Address  Address `json:"address"`
+	}
+
+	type Team struct {
+		Members []User `json:"members"`
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.SplitReadWriteOnly)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/teams")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Team{})
+	oc.AddRespStructure(Team{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi31TestAddress":{"properties":{"city":{"type":"string"}},"type":"object"},
+	  "Openapi31TestTeamRequest":{
+		"properties":{
+		  "members":{"items":{"$ref":"#/components/schemas/Openapi31TestUserRequest"},"type":["array","null"]}
+		},
+		"type":"object"
+	  },
+	  "Openapi31TestTeamResponse":{
+		"properties":{
+		  "members":{"items":{"$ref":"#/components/schemas/Openapi31TestUserResponse"},"type":["array","null"]}
+		},
+		"type":"object"
+	  },
+	  "Openapi31TestUserRequest":{
+		"required":["name"],
+		"properties":{
+		  "address":{"$ref":"#/components/schemas/Openapi31TestAddress"},"name":{"type":"string"},
+		  "password":{"type":"string","writeOnly":true}
+		},
+		"type":"object"
+	  },
+	  "Openapi31TestUserResponse":{
+		"required":["id","name"],
+		"properties":{
+		  "address":{"$ref":"#/components/schemas/Openapi31TestAddress"},
+		  "id":{"readOnly":true,"type":"integer"},"name":{"type":"string"}
+		},
+		"type":"object"
+	  }
+	}`, r.Spec.Components.Schemas)
+}
+
+func TestReflector_AddTypeMapping(t *testing.T) {
+	type UUID [16]byte
+
+	type Decimal struct {
+		value string
+	}
+
+	type req struct {
+		ID     UUID      `path:"id"`
+		Trace  *UUID     `header:"X-Trace"`
+		Amount Decimal   `json:"amount"`
+		Items  []UUID    `json:"items"`
+		Prices []Decimal `json:"prices"`
+	}
+
+	r := openapi31.NewReflector()
+	r.AddTypeMapping(UUID{}, "",
+		openapi.WithFormat("uuid"),
+		openapi.WithExample("248df4b7-aa70-47b8-a036-33ac447e668d"),
+	)
+	r.AddTypeMapping(Decimal{}, jsonschema.Schema{},
+		openapi.WithDescription("Decimal number."),
+		openapi.WithFormat("decimal"),
+	)
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/orders/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/orders/{id}":{
+		  "post":{
+			"parameters":[
+			  {
+				"name":"id","in":"path","required":true,
+				"schema":{"examples":["248df4b7-aa70-47b8-a036-33ac447e668d"],"format":"uuid","type":"string"}
+			  },
+			  {
+				"name":"X-Trace","in":"header",
+				"schema":{"examples":["248df4b7-aa70-47b8-a036-33ac447e668d"],"format":"uuid","type":["string","null"]}
+			  }
+			],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}
+			  }
+			},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestDecimal":{"description":"Decimal number.","format":"decimal"},
+		  "Openapi31TestReq":{
+			"properties":{
+			  "amount":{"$ref":"#/components/schemas/Openapi31TestDecimal"},
+			  "items":{
+				"items":{"examples":["248df4b7-aa70-47b8-a036-33ac447e668d"],"format":"uuid","type":"string"},
+				"type":["array","null"]
+			  },
+			  "prices":{"items":{"$ref":"#/components/schemas/Openapi31TestDecimal"},"type":["array","null"]}
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestReflector_Stats(t *testing.T) {
+	type Item struct {
+		Contact openapi31.Contact `json:"contact"`
+	}
+
+	type resp struct {
+		Items []Item            `json:"items"`
+		Info  openapi31.License `json:"info"`
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, map[string]int{
+		"github.com/swaggest/openapi-go/openapi31":      2,
+		"github.com/swaggest/openapi-go/openapi31_test": 2,
+	}, r.Stats().ComponentsByPackage)
+}
+
+func TestSpec_Stats(t *testing.T) {
+	type req struct {
+		ID string `path:"id" example:"abc"`
+	}
+
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.SetSummary("Get item.")
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(item{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/items")
+	require.NoError(t, err)
+	require.NoError(t, r.AddOperation(oc))
+
+	r.Spec.ComponentsEns().WithSchemasItem("Unused", map[string]interface{}{"type": "string"})
+
+	assertjson.EqMarshal(t, `{
+	  "operations":2,"operationsByMethod":{"get":1,"post":1},"schemas":2,
+	  "describedOperations":50,"exampledOperations":50,
+	  "orphanComponents":["#/components/schemas/Unused"]
+	}`, r.Spec.Stats())
+}
+
+type pgText struct {
+	String string
+	Valid  bool
+}
+
+type nullableAmount struct{}
+
+func (nullableAmount) NullWrapped() interface{} {
+	return float64(0)
+}
+
+func TestReflector_AddNullWrapper(t *testing.T) {
+	type req struct {
+		Since   sql.NullTime   `query:"since"`
+		Limit   sql.NullInt64  `query:"limit"`
+		Name    sql.NullString `json:"name"`
+		Comment pgText         `json:"comment"`
+		Amount  nullableAmount `json:"amount"`
+	}
+
+	r := openapi31.Reflector{}
+	r.AddNullWrapper(pgText{}, "String")
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/":{
+		  "post":{
+			"parameters":[
+			  {"name":"since","in":"query","schema":{"format":"date-time","type":["null","string"]}},
+			  {"name":"limit","in":"query","schema":{"type":["null","integer"]}}
+			],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}
+			  }
+			},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestReq":{
+			"properties":{
+			  "amount":{"type":["null","number"]},"comment":{"type":["null","string"]},
+			  "name":{"type":["null","string"]}
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+}
+
+type (
+	UserEmail   string
+	ArticleSlug string
+	UserID      string
+	PlainEmail  string
+)
+
+func (PlainEmail) PrepareJSONSchema(s *jsonschema.Schema) error {
+	s.WithFormat("idn-email")
+
+	return nil
+}
+
+func TestStringFormats(t *testing.T) {
+	type req struct {
+		Email  UserEmail   `query:"email"`
+		Slug   ArticleSlug `json:"slug"`
+		ID     UserID      `json:"id"`
+		Plain  PlainEmail  `json:"plain"`
+		Simple string      `json:"simple"`
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.StringFormats(
+		openapi.StringFormatRule{Name: regexp.MustCompile(`Email$`), Format: "email"},
+		openapi.StringFormatRule{Name: regexp.MustCompile(`Slug$`), Pattern: "^[a-z0-9]+(?:-[a-z0-9]+)*$"},
+		openapi.StringFormatRule{Name: regexp.MustCompile(`ID$`), Format: "ulid"},
+	))
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/":{
+		  "post":{
+			"parameters":[
+			  {"name":"email","in":"query","schema":{"$ref":"#/components/schemas/Openapi31TestUserEmail"}}
+			],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}
+			  }
+			},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestArticleSlug":{"pattern":"^[a-z0-9]+(?:-[a-z0-9]+)*$","type":"string"},
+		  "Openapi31TestPlainEmail":{"format":"idn-email","type":"string"},
+		  "Openapi31TestReq":{
+			"properties":{
+			  "id":{"$ref":"#/components/schemas/Openapi31TestUserID"},
+			  "plain":{"$ref":"#/components/schemas/Openapi31TestPlainEmail"},
+			  "simple":{"type":"string"},"slug":{"$ref":"#/components/schemas/Openapi31TestArticleSlug"}
+			},
+			"type":"object"
+		  },
+		  "Openapi31TestUserEmail":{"format":"email","type":"string"},
+		  "Openapi31TestUserID":{"format":"ulid","type":"string"}
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestFreeForm(t *testing.T) {
+	type Attributes map[string]interface{}
+
+	type req struct {
+		Raw   json.RawMessage        `json:"raw"`
+		Map   map[string]interface{} `json:"map,omitempty"`
+		Attrs *Attributes            `json:"attrs,omitempty"`
+	}
+
+	for style, expected := range map[openapi.FreeFormStyle]string{
+		openapi.FreeFormAny: `{
+		  "properties":{"attrs":{"$ref":"#/components/schemas/Openapi31TestAttributes"},"map":{},"raw":{}},
+		  "type":"object"
+		}`,
+		openapi.FreeFormObject: `{
+		  "properties":{
+			"attrs":{"$ref":"#/components/schemas/Openapi31TestAttributes"},
+			"map":{"additionalProperties":true,"type":"object"},
+			"raw":{"additionalProperties":true,"type":["object","null"]}
+		  },
+		  "type":"object"
+		}`,
+	} {
+		r := openapi31.NewReflector()
+		r.DefaultOptions = append(r.DefaultOptions, openapi.FreeForm(style))
+
+		oc, err := r.NewOperationContext(http.MethodPost, "/")
+		require.NoError(t, err)
+
+		oc.AddReqStructure(req{})
+		require.NoError(t, r.AddOperation(oc))
+
+		assertjson.EqMarshal(t, expected, r.Spec.Components.Schemas["Openapi31TestReq"])
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.FreeForm(openapi.FreeFormForbidden))
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	assert.EqualError(t, r.AddOperation(oc),
+		"setup request post /: free-form value at #.raw requires registered type mapping")
+
+	r = openapi31.NewReflector()
+	r.DefaultOptions = append(r.DefaultOptions, openapi.FreeForm(openapi.FreeFormForbidden))
+	r.AddTypeMapping(json.RawMessage{}, "")
+	r.AddTypeMapping(map[string]interface{}{}, struct{}{})
+	r.AddTypeMapping(Attributes{}, struct{}{})
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	require.NoError(t, r.AddOperation(oc))
+}
+
+type treeNode struct {
+	Name     string      `json:"name" query:"name"`
+	Children []treeNode  `json:"children,omitempty" query:"children"`
+	Parent   *treeNode   `json:"parent,omitempty" query:"parent"`
+	Links    listElement `json:"links" query:"links"`
+}
+
+type listElement struct {
+	Value int          `json:"value" query:"value"`
+	Next  *listElement `json:"next,omitempty" query:"next"`
+}
+
+func TestReflector_recursiveTypes(t *testing.T) {
+	type req struct {
+		Filter treeNode `query:"filter"`
+		Body   treeNode `json:"body"`
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(treeNode{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/":{
+		  "post":{
+			"parameters":[
+			  {
+				"name":"filter","in":"query","style":"deepObject","explode":true,
+				"schema":{"$ref":"#/components/schemas/Openapi31TestTreeNode"}
+			  }
+			],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}
+			  }
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestTreeNode"}}
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestListElement":{
+			"properties":{
+			  "next":{"$ref":"#/components/schemas/Openapi31TestListElement"},"value":{"type":"integer"}
+			},
+			"type":"object"
+		  },
+		  "Openapi31TestReq":{
+			"properties":{"body":{"$ref":"#/components/schemas/Openapi31TestTreeNode"}},"type":"object"
+		  },
+		  "Openapi31TestTreeNode":{
+			"properties":{
+			  "children":{"items":{"$ref":"#/components/schemas/Openapi31TestTreeNode"},"type":"array"},
+			  "links":{"$ref":"#/components/schemas/Openapi31TestListElement"},"name":{"type":"string"},
+			  "parent":{"$ref":"#/components/schemas/Openapi31TestTreeNode"}
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+
+	r = openapi31.NewReflector()
+	r.MaxRecursionDepth = 2
+
+	s, err := r.Reflect(listElement{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"next":{
+		  "properties":{"next":{},"value":{"type":"integer"}},"type":"object"
+		},
+		"value":{"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_Reset(t *testing.T) {
+	type money struct {
+		Amount int64
+	}
+
+	type currency struct {
+		Code int
+	}
+
+	type address struct {
+		City string `json:"city"`
+	}
+
+	type order struct {
+		Total    money    `json:"total"`
+		Currency currency `json:"currency"`
+		Address  address  `json:"address"`
+	}
+
+	r := openapi31.NewReflector()
+	r.Spec.Info.Title = "Orders"
+	r.AddTypeMapping(money{}, "", openapi.WithFormat("decimal"))
+	r.DefaultOptions = append(r.DefaultOptions, openapi.PointerOptional)
+
+	// Mappings added directly to jsonschema.Reflector are retained too.
+	r.JSONSchemaReflector().AddTypeMapping(currency{}, "")
+	r.Reflector.InlineDefinition(address{})
+
+	build := func() {
+		oc, err := r.NewOperationContext(http.MethodGet, "/order")
+		require.NoError(t, err)
+
+		oc.AddRespStructure(order{})
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	build()
+
+	expected, err := json.Marshal(r.Spec)
+	require.NoError(t, err)
+
+	r.Reset()
+
+	assertjson.EqMarshal(t, `{"openapi":"3.1.0","info":{"title":"","version":""}}`, r.Spec)
+	assert.Empty(t, r.Stats().ComponentsByPackage)
+
+	r.Spec.Info.Title = "Orders"
+
+	build()
+
+	assertjson.EqMarshal(t, string(expected), r.Spec)
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+	    "address":{"properties":{"city":{"type":"string"}},"required":["city"],"type":"object"},
+	    "currency":{"type":"string"},"total":{"format":"decimal","type":"string"}
+	  },
+	  "required":["total","currency","address"],"type":"object"
+	}`, r.Spec.Components.Schemas["Openapi31TestOrder"])
+}
+
+func benchmarkReflector(b *testing.B, r *openapi31.Reflector) {
+	b.Helper()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/foo/{in_path}")
+	require.NoError(b, err)
+
+	oc.AddReqStructure(Req{})
+	oc.AddRespStructure(Resp{})
+
+	require.NoError(b, r.AddOperation(oc))
+}
+
+func BenchmarkNewReflector(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r := openapi31.NewReflector()
+		r.AddTypeMapping(UUID{}, "", openapi.WithFormat("uuid"))
+		r.DefaultOptions = append(r.DefaultOptions, openapi.PointerOptional)
+
+		benchmarkReflector(b, r)
+	}
+}
+
+func BenchmarkReflector_Reset(b *testing.B) {
+	r := openapi31.NewReflector()
+	r.AddTypeMapping(UUID{}, "", openapi.WithFormat("uuid"))
+	r.DefaultOptions = append(r.DefaultOptions, openapi.PointerOptional)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.Reset()
+
+		benchmarkReflector(b, r)
+	}
+}
+
+func TestReflector_mapKeyNames(t *testing.T) {
+	type userID string
+
+	type req struct {
+		Scores map[UUID]int      `json:"scores"`
+		Names  map[userID]string `json:"names"`
+		Tags   map[string]string `json:"tags"`
+	}
+
+	r := openapi31.NewReflector()
+	r.AddTypeMapping(UUID{}, "", openapi.WithFormat("uuid"))
+	r.AddTypeMapping(userID(""), jsonschema.Schema{}, func(s *jsonschema.Schema) {
+		s.WithType(jsonschema.String.Type()).WithPattern("^u[0-9]+$")
+	})
+
+	s, err := r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"names":{
+		  "additionalProperties":{"type":"string"},"propertyNames":{"pattern":"^u[0-9]+$","type":"string"},
+		  "type":["object","null"]
+		},
+		"scores":{
+		  "additionalProperties":{"type":"integer"},"propertyNames":{"format":"uuid","type":"string"},
+		  "type":["object","null"]
+		},
+		"tags":{"additionalProperties":{"type":"string"},"type":["object","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type point struct {
+	Lat, Lon float64
+}
+
+func (point) TupleItems() []interface{} {
+	return []interface{}{0.0, 0.0}
+}
+
+type csvRow struct {
+	Name  string
+	Count int
+}
+
+func (*csvRow) TupleItems() []interface{} {
+	return []interface{}{"", 0}
+}
+
+func TestReflector_tuples(t *testing.T) {
+	type req struct {
+		Location point     `json:"location"`
+		Rows     []*csvRow `json:"rows"`
+		Color    [3]uint8  `json:"color"`
+	}
+
+	r := openapi31.NewReflector()
+
+	s, err := r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	// Tuples are disabled by default.
+	assertjson.EqMarshal(t, `{"items":{"minimum":0,"type":"integer"},"type":["array","null"]}`, s.Properties["color"])
+
+	r.Tuples = true
+
+	s, err = r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"color":{"items":{"minimum":0,"type":"integer"},"maxItems":3,"minItems":3,"type":"array"},
+		"location":{"maxItems":2,"minItems":2,"prefixItems":[{"type":"number"},{"type":"number"}],"type":"array"},
+		"rows":{
+		  "items":{
+			"maxItems":2,"minItems":2,"prefixItems":[{"type":"string"},{"type":"integer"}],
+			"type":["null","array"]
+		  },
+		  "type":["array","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type payment struct {
+	Method  string `json:"method" enum:"card,transfer"`
+	Card    string `json:"card,omitempty"`
+	IBAN    string `json:"iban,omitempty"`
+	Country string `json:"country,omitempty" dependentRequired:"zip"`
+	Zip     string `json:"zip,omitempty"`
+}
+
+func (payment) ConditionalRequired() []openapi.RequiredIf {
+	return []openapi.RequiredIf{
+		{If: map[string]interface{}{"method": "card"}, Then: []string{"card"}, Else: []string{"iban"}},
+	}
+}
+
+type shipment struct {
+	Express bool   `json:"express"`
+	Phone   string `json:"phone,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+func (*shipment) ConditionalRequired() []openapi.RequiredIf {
+	return []openapi.RequiredIf{
+		{If: map[string]interface{}{"express": true}, Then: []string{"phone"}},
+		{If: map[string]interface{}{"express": false}, Then: []string{"email"}},
+	}
+}
+
+func TestReflector_conditionalRequired(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	s, err := r.Reflect(payment{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"card":{"type":"string"},"country":{"type":"string"},"iban":{"type":"string"},
+		"method":{"enum":["card","transfer"],"type":"string"},"zip":{"type":"string"}
+	  },
+	  "type":"object","dependentRequired":{"country":["zip"]},
+	  "if":{"required":["method"],"properties":{"method":{"const":"card"}}},
+	  "then":{"required":["card"]},"else":{"required":["iban"]}
+	}`, s)
+
+	s, err = r.Reflect(shipment{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"email":{"type":"string"},"express":{"type":"boolean"},"phone":{"type":"string"}},
+	  "type":"object",
+	  "allOf":[
+		{"if":{"required":["express"],"properties":{"express":{"const":true}}},"then":{"required":["phone"]}},
+		{"if":{"required":["express"],"properties":{"express":{"const":false}}},"then":{"required":["email"]}}
+	  ]
+	}`, s)
+}
+
+func TestReflector_ComponentsStore(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	type order struct {
+		Owner user `json:"owner"`
+	}
+
+	store := &openapi.ComponentsStore{}
+
+	users := openapi31.NewReflector()
+	users.ComponentsStore = store
+
+	orders := openapi31.NewReflector()
+	orders.ComponentsStore = store
+
+	oc, err := users.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+	oc.AddRespStructure([]user{})
+	require.NoError(t, users.AddOperation(oc))
+
+	oc, err = orders.NewOperationContext(http.MethodGet, "/orders")
+	require.NoError(t, err)
+	oc.AddRespStructure([]order{})
+	require.NoError(t, orders.AddOperation(oc))
+
+	assert.Equal(t, []string{"Openapi31TestOrder", "Openapi31TestUser"}, sortedKeys(store.Schemas()))
+	assert.Empty(t, store.Conflicts())
+	assert.Equal(t, users.Spec.Components.Schemas["Openapi31TestUser"], orders.Spec.Components.Schemas["Openapi31TestUser"])
+
+	merged := openapi31.NewReflector()
+	merged.ComponentsStore = store
+	merged.AddStoredComponents()
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi31TestOrder":{"properties":{"owner":{"$ref":"#/components/schemas/Openapi31TestUser"}},"type":"object"},
+	  "Openapi31TestUser":{"properties":{"name":{"type":"string"}},"type":"object"}
+	}`, merged.Spec.Components.Schemas)
+
+	_, err = store.Add("Openapi31TestUser", jsonschema.String.ToSchemaOrBool())
+	assert.ErrorIs(t, err, openapi.ErrComponentConflict)
+	assert.Equal(t, []string{"Openapi31TestUser"}, store.Conflicts())
+}
+
+func TestReflector_ComponentsStore_conflict(t *testing.T) {
+	store := &openapi.ComponentsStore{}
+
+	users := openapi31.NewReflector()
+	users.ComponentsStore = store
+
+	oc, err := users.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	{
+		type user struct {
+			Name string `json:"name"`
+		}
+
+		oc.AddRespStructure([]user{})
+	}
+
+	require.NoError(t, users.AddOperation(oc))
+
+	orders := openapi31.NewReflector()
+	orders.ComponentsStore = store
+
+	oc, err = orders.NewOperationContext(http.MethodGet, "/orders")
+	require.NoError(t, err)
+
+	{
+		type user struct {
+			ID int `json:"id"`
+		}
+
+		oc.AddRespStructure([]user{})
+	}
+
+	err = orders.AddOperation(oc)
+	require.ErrorIs(t, err, openapi.ErrComponentConflict)
+	assert.EqualError(t, err, "shared components get /orders: component conflict: Openapi31TestUser has a different schema")
+
+	assert.Equal(t, []report.Finding{{
+		Rule:     "component-conflict",
+		Severity: report.SeverityError,
+		Message:  `schema component "Openapi31TestUser" conflicts with a different schema of the same name`,
+		Pointer:  "/components/schemas/Openapi31TestUser",
+	}}, orders.Finalize())
+}
+
+func sortedKeys(m map[string]jsonschema.SchemaOrBool) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func TestReflector_base64Bytes(t *testing.T) {
+	type req struct {
+		Avatar []byte `json:"avatar" contentEncoding:"base64" contentMediaType:"image/png"`
+		Opt    []byte `json:"opt,omitempty" contentEncoding:"base64"`
+		Raw    []byte `json:"raw"`
+	}
+
+	r := openapi31.NewReflector()
+
+	s, err := r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"avatar":{"type":["string","null"],"contentMediaType":"image/png","contentEncoding":"base64"},
+		"opt":{"type":"string","contentEncoding":"base64"},
+		"raw":{"items":{"minimum":0,"type":"integer"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type currencyCode string
+
+func (currencyCode) Description() string {
+	return "ISO 4217 currency code."
+}
+
+func TestMergeDescriptions(t *testing.T) {
+	type rate struct {
+		Name  string       `json:"name" description:"Display name."`
+		From  currencyCode `json:"from" description:"Source currency."`
+		To    currencyCode `json:"to"`
+		Since time.Time    `json:"since" description:"Start time."`
+	}
+
+	for _, tc := range []struct {
+		merge    openapi.DescriptionMerge
+		from     string
+		since    string
+		expected string
+	}{
+		{merge: openapi.DescriptionOverride, from: "Source currency.", since: "Start time."},
+		{merge: openapi.DescriptionKeep, from: "ISO 4217 currency code.", since: "Point in time."},
+		{merge: openapi.DescriptionAppend, from: "ISO 4217 currency code. Source currency.", since: "Point in time. Start time."},
+		{
+			merge:    openapi.DescriptionConflictError,
+			expected: `conflicting descriptions of from: "ISO 4217 currency code." in type, "Source currency." in field tag`,
+		},
+	} {
+		r := openapi31.NewReflector()
+		r.AddTypeMapping(time.Time{}, "", openapi.WithFormat("date-time"), openapi.WithDescription("Point in time."))
+		r.InlineDefinition(currencyCode(""))
+		r.DefaultOptions = append(r.DefaultOptions, openapi.MergeDescriptions(tc.merge, " "))
+
+		s, err := r.Reflect(rate{})
+		if tc.expected != "" {
+			assert.EqualError(t, err, tc.expected)
+
+			continue
+		}
+
+		require.NoError(t, err)
+
+		assertjson.EqMarshal(t, `{
+		  "properties":{
+			"from":{"description":"`+tc.from+`","type":"string"},
+			"name":{"description":"Display name.","type":"string"},
+			"since":{"description":"`+tc.since+`","format":"date-time","type":"string"},
+			"to":{"description":"ISO 4217 currency code.","type":"string"}
+		  },
+		  "type":"object"
+		}`, s)
+	}
+}
+
+func TestReflector_vendorExtensions(t *testing.T) {
+	type account struct {
+		_     struct{} `x:"go-name=Account"`
+		ID    string   `json:"id" x:"go-name=ID,faker=uuid_hyphenated"`
+		Roles []string `json:"roles" x:"x-enum-varnames=[\"Admin\",\"User\"],x-order=2"`
+	}
+
+	r := openapi31.NewReflector()
+
+	s, err := r.Reflect(account{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"id":{"type":"string","x-faker":"uuid_hyphenated","x-go-name":"ID"},
+		"roles":{
+		  "items":{"type":"string"},"type":["array","null"],
+		  "x-enum-varnames":["Admin","User"],"x-order":2
+		}
+	  },
+	  "type":"object","x-go-name":"Account"
+	}`, s)
+}
+
+func TestOperationContext_SetExtension(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	e, ok := oc.(openapi.OperationExtender)
+	require.True(t, ok)
+
+	e.SetExtension("x-internal", true)
+	e.SetExtension("codegen-request-body-name", "body")
+	assert.Equal(t, map[string]interface{}{"x-internal": true, "x-codegen-request-body-name": "body"}, e.Extensions())
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{"204":{"description":"No Content"}},
+	  "x-codegen-request-body-name":"body","x-internal":true
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].Get)
+}
+
+type orderStatus int
+
+func (orderStatus) Enum() []interface{} {
+	return []interface{}{0, 1, 2}
+}
+
+func (orderStatus) EnumDescriptions() map[interface{}]string {
+	return map[interface{}]string{
+		orderStatus(0): "Order is placed.",
+		orderStatus(1): "Order is paid.",
+		orderStatus(2): "Order is shipped.",
+	}
+}
+
+type priority string
+
+func (priority) Enum() []interface{} {
+	return []interface{}{"low", "high"}
+}
+
+func TestReflector_AddEnumDescriptions(t *testing.T) {
+	type req struct {
+		Status   orderStatus `json:"status"`
+		Priority priority    `json:"priority"`
+	}
+
+	r := openapi31.NewReflector()
+	r.AddEnumDescriptions(priority(""), map[interface{}]string{"high": "Processed first."})
+
+	s, err := r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"priority":{"enum":["low","high"],"type":"string","x-enum-descriptions":["","Processed first."]},
+		"status":{
+		  "enum":[0,1,2],"type":"integer",
+		  "x-enum-descriptions":["Order is placed.","Order is paid.","Order is shipped."]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	r = openapi31.NewReflector()
+	r.EnumOneOf = true
+
+	s, err = r.Reflect(req{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"priority":{"enum":["low","high"],"type":"string"},
+		"status":{
+		  "oneOf":[
+			{"const":0,"description":"Order is placed."},{"const":1,"description":"Order is paid."},
+			{"const":2,"description":"Order is shipped."}
+		  ],
+		  "type":"integer"
+		}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_Finalize(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Spec.Tags = []openapi31.Tag{{Name: "Users"}, {Name: "Legacy"}}
+	r.Spec.SetHTTPBasicSecurity("basic", "")
+	r.Spec.SetHTTPBearerTokenSecurity("bearer", "JWT", "")
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetTags("Users")
+	oc.AddSecurity("bearer")
+	oc.AddSecurity("apiKey")
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, []report.Finding{
+		{
+			Rule: "undeclared-security-scheme", Severity: report.SeverityError,
+			Message: `security scheme "apiKey" is not declared in components`, Pointer: "/paths/~1users/get/security/1",
+		},
+		{
+			Rule: "orphan-tag", Severity: report.SeverityWarning,
+			Message: `tag "Legacy" is not used by any operation`, Pointer: "/tags/1",
+		},
+		{
+			Rule: "unused-security-scheme", Severity: report.SeverityWarning,
+			Message: `security scheme "basic" is not required by any operation`, Pointer: "/components/securitySchemes/basic",
+		},
+	}, r.Finalize())
+}
+
+func TestReflector_KeepContentTypeParams(t *testing.T) {
+	type resp struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi31.NewReflector()
+	r.KeepContentTypeParams = true
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/user")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(resp{}, openapi.WithContentType("application/json; charset=utf-8"),
+		openapi.WithContentTypeVariants("application/json; profile=user"))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "content":{
+			"application/json; charset=utf-8":{"schema":{"$ref":"#/components/schemas/Openapi31TestResp"}},
+			"application/json; profile=user":{"schema":{"$ref":"#/components/schemas/Openapi31TestResp"}}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/user"].Get)
+}
+
+func TestReflector_AddOperation_jsonSuffix(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		ID   int    `query:"id"`
+		Name string `json:"name"`
+	}
+
+	type Resp struct {
+		Name string `json:"name"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/things")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{}, openapi.WithContentType("application/vnd.company.v2+json"))
+	oc.AddRespStructure(Resp{}, openapi.WithContentType("application/problem+json"))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/things":{
+		  "post":{
+			"parameters":[
+			  {"name":"id","in":"query","schema":{"type":"integer"}}
+			],
+			"requestBody":{
+			  "content":{
+				"application/vnd.company.v2+json":{
+				  "schema":{"$ref":"#/components/schemas/Openapi31TestReq"}
+				}
+			  }
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/problem+json":{
+					"schema":{"$ref":"#/components/schemas/Openapi31TestResp"}
+				  }
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestReq":{"properties":{"name":{"type":"string"}},"type":"object"},
+		  "Openapi31TestResp":{"properties":{"name":{"type":"string"}},"type":"object"}
+		}
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestReflector_AddOperation_pathParameterStyle(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		ID     int   `path:"id" style:"matrix"`
+		Coords []int `path:"coords" style:"label" explode:"true"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id}/{coords}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {
+		"name":"id","in":"path","required":true,"schema":{"type":"integer"},
+		"style":"matrix","explode":false
+	  },
+	  {
+		"name":"coords","in":"path","required":true,
+		"schema":{"items":{"type":"integer"},"type":["array","null"]},
+		"style":"label","explode":true
+	  }
+	]`, r.Spec.Paths.MapOfPathItemValues["/items/{id}/{coords}"].Get.Parameters)
+
+	type QueryReq struct {
+		ID int `query:"id" style:"matrix"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(QueryReq{})
+	assert.EqualError(t, r.AddOperation(oc),
+		"setup request get /items: style matrix is only applicable to path parameters, id in query")
+}
+
+func TestAddVersionedReqStructures(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type ThingV1 struct {
+		Name string `json:"name"`
+	}
+
+	type ThingV2 struct {
+		Title string `json:"title"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/thing")
+	require.NoError(t, err)
+
+	openapi.AddVersionedReqStructures(oc, []openapi.ContentVersion{
+		{ContentType: "application/vnd.company.v1+json", Structure: ThingV1{}, Deprecated: true},
+		{ContentType: "application/vnd.company.v2+json", Structure: ThingV2{}},
+	})
+	openapi.AddVersionedRespStructures(oc, []openapi.ContentVersion{
+		{ContentType: "application/vnd.company.v1+json", Structure: ThingV1{}, Deprecated: true},
+		{ContentType: "application/vnd.company.v2+json", Structure: ThingV2{}},
+	}, openapi.WithHTTPStatus(http.StatusCreated))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "requestBody":{
+		"content":{
+		  "application/vnd.company.v1+json":{
+			"schema":{"$ref":"#/components/schemas/Openapi31TestThingV1"},
+			"x-deprecated":true
+		  },
+		  "application/vnd.company.v2+json":{
+			"schema":{"$ref":"#/components/schemas/Openapi31TestThingV2"}
+		  }
+		}
+	  },
+	  "responses":{
+		"201":{
+		  "description":"Created",
+		  "content":{
+			"application/vnd.company.v1+json":{
+			  "schema":{"$ref":"#/components/schemas/Openapi31TestThingV1"},
+			  "x-deprecated":true
+			},
+			"application/vnd.company.v2+json":{
+			  "schema":{"$ref":"#/components/schemas/Openapi31TestThingV2"}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/thing"].Put)
+}
+
+func TestReflector_AddOperation_allowReserved(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		Callback string `query:"callback" allowReserved:"true"`
+		Verbose  bool   `query:"verbose" allowEmptyValue:"true"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/hooks")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"callback","in":"query","schema":{"type":"string"},"allowReserved":true},
+	  {"name":"verbose","in":"query","schema":{"type":"boolean"},"allowEmptyValue":true}
+	]`, r.Spec.Paths.MapOfPathItemValues["/hooks"].Get.Parameters)
+
+	type HeaderReq struct {
+		Callback string `header:"X-Callback" allowReserved:"true"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/other-hooks")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(HeaderReq{})
+	assert.EqualError(t, r.AddOperation(oc), "setup request get /other-hooks: "+
+		"allowReserved and allowEmptyValue are only applicable to query parameters, X-Callback in header")
+}
+
+func TestReflector_AddOperation_paramExamples(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		Limit  int    `query:"limit" paramExample:"20"`
+		Sort   string `query:"sort"`
+		Locale string `header:"Accept-Language"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{},
+		openapi.WithParamExample(openapi.InQuery, "sort", "byName", "name"),
+		openapi.WithParamExample(openapi.InQuery, "sort", "newestFirst", "-created_at"),
+		openapi.WithParamExample(openapi.InHeader, "Accept-Language", "", "en-US"),
+	)
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"limit","in":"query","schema":{"type":"integer"},"example":20},
+	  {
+		"name":"sort","in":"query","schema":{"type":"string"},
+		"examples":{"byName":{"value":"name"},"newestFirst":{"value":"-created_at"}}
+	  },
+	  {"name":"Accept-Language","in":"header","schema":{"type":"string"},"example":"en-US"}
+	]`, r.Spec.Paths.MapOfPathItemValues["/items"].Get.Parameters)
+}
+
+func TestOperationContext_SetForbidUnknownParams(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		Filter string   `query:"filter"`
+		Trace  string   `header:"X-Trace"`
+		_      struct{} `query:"_" header:"_" additionalProperties:"false"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	f, ok := oc.(openapi.UnknownParamsForbidder)
+	require.True(t, ok)
+
+	oc.AddReqStructure(Req{})
+	f.SetForbidUnknownParams(openapi.InQuery, false)
+	f.SetForbidUnknownParams(openapi.InCookie, true)
+
+	forbid, isSet := f.ForbidUnknownParams(openapi.InQuery)
+	assert.False(t, forbid)
+	assert.True(t, isSet)
+
+	_, isSet = f.ForbidUnknownParams(openapi.InHeader)
+	assert.False(t, isSet)
+
+	require.NoError(t, r.AddOperation(oc))
+
+	op := r.Spec.Paths.MapOfPathItemValues["/items"].Get
+	assert.False(t, op.UnknownParamIsForbidden(openapi31.ParameterInQuery))
+	assert.True(t, op.UnknownParamIsForbidden(openapi31.ParameterInHeader))
+	assert.True(t, op.UnknownParamIsForbidden(openapi31.ParameterInCookie))
+	assert.False(t, op.UnknownParamIsForbidden(openapi31.ParameterInPath))
+}
+
+func TestReflector_AddOperation_parameterStyle(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Filter struct {
+		Status string `query:"status"`
+	}
+
+	type Req struct {
+		Filter Filter   `query:"filter" style:"form"`
+		IDs    []int    `query:"ids" style:"pipeDelimited" explode:"true"`
+		Tags   []string `query:"tags" collectionFormat:"csv" style:"spaceDelimited"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {
+		"name":"filter","in":"query","schema":{"$ref":"#/components/schemas/Openapi31TestFilter"},
+		"style":"form","explode":true
+	  },
+	  {
+		"name":"ids","in":"query","schema":{"items":{"type":"integer"},"type":["array","null"]},
+		"style":"pipeDelimited","explode":true
+	  },
+	  {
+		"name":"tags","in":"query","schema":{"items":{"type":"string"},"type":["array","null"]},
+		"style":"spaceDelimited","explode":false
+	  }
+	]`, r.Spec.Paths.MapOfPathItemValues["/items"].Get.Parameters)
+
+	type BadReq struct {
+		Trace string `header:"X-Trace" style:"form"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/other-items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(BadReq{})
+	assert.EqualError(t, r.AddOperation(oc), "setup request get /other-items: "+
+		"style form is only applicable to query and cookie parameters, X-Trace in header")
+}
+
+func TestReflector_AddOperation_pathPatterns(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		ID   int    `path:"id"`
+		Code string `path:"code" pattern:"^[A-Z]+$"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id:[0-9]+}/codes/{code:[a-z]+}")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/users/{id}/codes/{code}", oc.PathPattern())
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"id","in":"path","required":true,"schema":{"pattern":"^[0-9]+$","type":"integer"}},
+	  {"name":"code","in":"path","required":true,"schema":{"pattern":"^[A-Z]+$","type":"string"}}
+	]`, r.Spec.Paths.MapOfPathItemValues["/users/{id}/codes/{code}"].Get.Parameters)
+}
+
+func TestReflector_AddGeneratorConfig(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.NullStrategy = openapi31.NullAnyOf
+	r.DefaultTags = openapi.TagsFromPackage
+	r.AddTypeMapping(time.Duration(0), "", openapi.WithFormat("duration"))
+
+	r.AddGeneratorConfig()
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "x-generator-config":{
+		"defaults":[
+		  "schemaReplacements","fieldFilters","views","tagNamespace","wrappers","implementations","limitRecursion",
+		  "enumDescriptions","base64Bytes","vendorExtensions","mapKeyNames","tuples","conditions",
+		  "nullableRefs"
+		],
+		"nullStrategy":"anyOf","maxRecursionDepth":1,"keepContentTypeParams":false,
+		"enumOneOf":false,"defaultTags":true,"sharedComponents":false,
+		"typeMappings":{"time.Duration":"jsonschema.Schema"},
+		"inlinedTypes":["time.Duration"]
+	  }
+	}`, r.SpecSchema())
+}
+
+func TestReflector_AddOperation_wildcard(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		Path string `path:"path"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/files/{path...}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `[
+	  {"name":"path","in":"path","required":true,"schema":{"type":"string"},"x-wildcard":true}
+	]`, r.Spec.Paths.MapOfPathItemValues["/files/{path}"].Get.Parameters)
+}
+
+func TestReflector_AddOperation_duplicateID(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/people")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	assert.EqualError(t, r.AddOperation(oc),
+		`duplicate operation ID "listUsers" of get /people, already used by get /users`)
+
+	// Operations added to spec directly are checked on finalization.
+	op := openapi31.Operation{}
+	op.WithID("listUsers")
+	require.NoError(t, r.Spec.AddOperation(http.MethodGet, "/people", op))
+	assert.Equal(t, []report.Finding{{
+		Rule:     "duplicate-operation-id",
+		Severity: report.SeverityError,
+		Message:  `operation ID "listUsers" is already used by /paths/~1people/get`,
+		Pointer:  "/paths/~1users/get/operationId",
+	}}, r.Finalize())
+}
+
+func TestReflector_ReplaceOperations(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		Limit int `query:"limit"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	oc.AddReqStructure(Req{})
+	require.NoError(t, r.AddOperation(oc))
+
+	_, err = r.NewOperationContext(http.MethodGet, "/users")
+	assert.EqualError(t, err, "operation already exists: get /users")
+
+	r.ReplaceOperations = true
+
+	type ReqV2 struct {
+		Cursor string `query:"cursor"`
+	}
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetID("listUsers")
+	oc.AddReqStructure(ReqV2{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "operationId":"listUsers",
+	  "parameters":[{"name":"cursor","in":"query","schema":{"type":"string"}}],
+	  "responses":{"204":{"description":"No Content"}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].Get)
+}
+
+func TestReflector_AddOperation_queryParamsDependencies(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type Req struct {
+		Cursor  string `query:"cursor"`
+		Page    int    `query:"page"`
+		Sort    string `query:"sort"`
+		SortDir string `query:"sort_dir"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{},
+		openapi.WithExclusiveQueryParams("cursor", "page"),
+		openapi.WithDependentQueryParams("sort_dir", "sort"),
+	)
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "type":"object","dependentRequired":{"sort_dir":["sort"]},
+	  "oneOf":[
+		{"required":["cursor"]},{"required":["page"]},
+		{"not":{"anyOf":[{"required":["cursor"]},{"required":["page"]}]}}
+	  ]
+	}`, r.Spec.Paths.MapOfPathItemValues["/items"].Get.MapOfAnything["x-parameters-schema"])
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/other-items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Req{},
+		openapi.WithOneOfQueryParams("cursor", "page"),
+		openapi.WithOneOfQueryParams("sort", "order"),
+	)
+	assert.EqualError(t, r.AddOperation(oc), "setup request get /other-items: "+
+		"undefined query parameter in dependencies: order")
+}
+
+func TestReflector_Mount(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	v1 := r.Mount("/v1/")
+	admin := v1.Mount("/admin", "Admin")
+
+	for _, m := range []openapi.MountedReflector{v1, admin} {
+		for _, path := range []string{"/", "/users/{id}"} {
+			oc, err := m.NewOperationContext(http.MethodGet, path)
+			require.NoError(t, err)
+
+			if path != "/" {
+				oc.AddReqStructure(struct {
+					ID string `path:"id"`
+				}{})
+			}
+
+			require.NoError(t, m.AddOperation(oc))
+		}
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "/v1":{"get":{"responses":{"204":{"description":"No Content"}}}},
+	  "/v1/admin":{"get":{"tags":["Admin"],"responses":{"204":{"description":"No Content"}}}},
+	  "/v1/admin/users/{id}":{
+		"get":{
+		  "tags":["Admin"],"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+		  "responses":{"204":{"description":"No Content"}}
+		}
+	  },
+	  "/v1/users/{id}":{
+		"get":{
+		  "parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+		  "responses":{"204":{"description":"No Content"}}
+		}
+	  }
+	}`, r.Spec.Paths)
+}
+
+func TestReflector_Group(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type errResp struct {
+		Message string `json:"message"`
+	}
+
+	pets := r.Group(
+		openapi.WithTags("Pets"),
+		openapi.WithSecurity("bearerAuth"),
+		openapi.WithRespStructure(errResp{}, http.StatusInternalServerError),
+	)
+	admin := pets.Group(openapi.WithTags("Admin"), openapi.WithTrait(openapi.Trait{
+		Extensions: map[string]interface{}{"internal": true},
+	}))
+
+	for method, g := range map[string]openapi.Group{http.MethodGet: pets, http.MethodDelete: admin} {
+		oc, err := g.NewOperationContext(method, "/pets")
+		require.NoError(t, err)
+		require.NoError(t, g.AddOperation(oc))
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "get":{
+		"tags":["Pets"],
+		"responses":{
+		  "500":{
+			"description":"Internal Server Error",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestErrResp"}}}
+		  }
+		},
+		"security":[{"bearerAuth":[]}]
+	  },
+	  "delete":{
+		"tags":["Pets","Admin"],
+		"responses":{
+		  "500":{
+			"description":"Internal Server Error",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestErrResp"}}}
+		  }
+		},
+		"security":[{"bearerAuth":[]}],"x-internal":true
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/pets"])
+}
+
+type auditable interface {
+	Audit() string
+}
+
+type linkable interface {
+	Links() []string
+}
+
+type auditInfo struct {
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type links struct {
+	Self string `json:"self"`
+}
+
+func TestReflector_AddImplementation(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.AddImplementation(new(auditable), auditInfo{})
+	r.AddImplementation(new(linkable), links{})
+
+	type Pet struct {
+		auditable
+		linkable
+
+		Name string `json:"name"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+	}{})
+	oc.AddRespStructure(Pet{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "schemas":{
+		"Openapi31TestAuditInfo":{
+		  "properties":{"createdAt":{"format":"date-time","type":"string"},"createdBy":{"type":"string"}},
+		  "type":"object"
+		},
+		"Openapi31TestLinks":{"properties":{"self":{"type":"string"}},"type":"object"},
+		"Openapi31TestPet":{
+		  "allOf":[
+			{"$ref":"#/components/schemas/Openapi31TestAuditInfo"},
+			{"$ref":"#/components/schemas/Openapi31TestLinks"}
+		  ],
+		  "properties":{"name":{"type":"string"}},"type":"object"
+		}
+	  }
+	}`, r.Spec.Components)
+
+	assert.Panics(t, func() {
+		r.AddImplementation(auditInfo{}, auditInfo{})
+	})
+}
+
+func TestReflector_AddPathParameters(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	require.NoError(t, r.AddPathParameters("/accounts/{accountID:[0-9]+}/users", struct {
+		AccountID int    `path:"accountID"`
+		Tenant    string `header:"X-Tenant"`
+	}{}))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		oc, err := r.NewOperationContext(method, "/accounts/{accountID:[0-9]+}/users")
+		require.NoError(t, err)
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{"name":"accountID","in":"path","required":true,"schema":{"pattern":"^[0-9]+$","type":"integer"}},
+		{"name":"X-Tenant","in":"header","schema":{"type":"string"}}
+	  ],
+	  "get":{"responses":{"204":{"description":"No Content"}}},
+	  "post":{"responses":{"204":{"description":"No Content"}}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/accounts/{accountID}/users"])
+
+	assert.EqualError(t, r.AddPathParameters("/accounts", struct {
+		AccountID int `path:"accountID"`
+	}{}), "missing path parameter placeholder in url: accountID")
+}
+
+func TestReflector_AddOperation_hoistParameters(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.HoistParameters = true
+
+	type itemReq struct {
+		ID      string `path:"id"`
+		Verbose bool   `query:"verbose"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		itemReq
+		Fields []string `query:"fields"`
+	}{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPut, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(itemReq{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{"name":"verbose","in":"query","schema":{"type":"boolean"}},
+		{"name":"id","in":"path","required":true,"schema":{"type":"string"}}
+	  ],
+	  "get":{
+		"parameters":[{"name":"fields","in":"query","schema":{"items":{"type":"string"},"type":["array","null"]}}],
+		"responses":{"204":{"description":"No Content"}}
+	  },
+	  "put":{"responses":{"204":{"description":"No Content"}}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/items/{id}"])
+
+	oc, err = r.NewOperationContext(http.MethodDelete, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+	}{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+	  "get":{
+		"parameters":[
+		  {"name":"fields","in":"query","schema":{"items":{"type":"string"},"type":["array","null"]}},
+		  {"name":"verbose","in":"query","schema":{"type":"boolean"}}
+		],
+		"responses":{"204":{"description":"No Content"}}
+	  },
+	  "put":{
+		"parameters":[{"name":"verbose","in":"query","schema":{"type":"boolean"}}],
+		"responses":{"204":{"description":"No Content"}}
+	  },
+	  "delete":{"responses":{"204":{"description":"No Content"}}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/items/{id}"])
+}
+
+func TestReflector_AddDefaultResponse(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type errResp struct {
+		Message string `json:"message"`
+	}
+
+	type notFound struct {
+		Resource string `json:"resource"`
+	}
+
+	r.AddDefaultResponse(http.StatusUnauthorized, errResp{})
+	r.AddDefaultResponse(http.StatusNotFound, errResp{})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets")
+	require.NoError(t, err)
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodDelete, "/pets")
+	require.NoError(t, err)
+	oc.AddRespStructure(notFound{}, openapi.WithHTTPStatus(http.StatusNotFound))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "get":{
+		"responses":{
+		  "204":{"description":"No Content"},
+		  "401":{
+			"description":"Unauthorized",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestErrResp"}}}
+		  },
+		  "404":{
+			"description":"Not Found",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestErrResp"}}}
+		  }
+		}
+	  },
+	  "delete":{
+		"responses":{
+		  "401":{
+			"description":"Unauthorized",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestErrResp"}}}
+		  },
+		  "404":{
+			"description":"Not Found",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestNotFound"}}}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/pets"])
+}
+
+func TestReflector_AddOperation_anchors(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Anchors = true
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID int `path:"id"`
+	}{})
+	oc.AddRespStructure(user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/users")
+	require.NoError(t, err)
+	oc.SetID("createUser")
+	openapi.Trait{Extensions: map[string]interface{}{openapi.XAnchor: "custom"}}.Apply(oc)
+	oc.AddReqStructure(user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, "operation/getUsersId", r.Spec.Paths.MapOfPathItemValues["/users/{id}"].Get.MapOfAnything[openapi.XAnchor])
+	assert.Equal(t, "custom", r.Spec.Paths.MapOfPathItemValues["/users"].Post.MapOfAnything[openapi.XAnchor])
+	assert.Equal(t, "schema/Openapi31TestUser", r.Spec.Components.Schemas["Openapi31TestUser"][openapi.XAnchor])
+}
+
+func TestReflector_WithOperationInterceptor(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	r.WithOperationInterceptor(func(_ openapi.OperationContext, op *openapi31.Operation) error {
+		if op.Summary == nil {
+			return errors.New("missing summary")
+		}
+
+		return nil
+	})
+	r.WithOperationInterceptor(func(_ openapi.OperationContext, op *openapi31.Operation) error {
+		op.WithSecurity(map[string][]string{"bearerAuth": {}})
+
+		return nil
+	})
+
+	var added []string
+
+	r.WithAddedOperationInterceptor(func(oc openapi.OperationContext, _ *openapi31.Operation) error {
+		added = append(added, oc.Method()+" "+oc.PathPattern())
+
+		return nil
+	})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+	require.EqualError(t, r.AddOperation(oc), "intercept operation get /users: missing summary")
+	assert.Nil(t, r.Spec.Paths.MapOfPathItemValues["/users"].Get)
+
+	oc.SetSummary("List users")
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Equal(t, []string{"get /users"}, added)
+	assertjson.EqMarshal(t, `{
+	  "summary":"List users","responses":{"204":{"description":"No Content"}},
+	  "security":[{"bearerAuth":[]}]
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].Get)
+}
+
+func TestReflector_AddOperation_bodySize(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/upload")
+	require.NoError(t, err)
+	oc.AddReqStructure(nil, openapi.WithContentType("text/csv"), openapi.WithMaxBodyBytes(1<<20))
+	oc.AddRespStructure(struct {
+		Rows int `json:"rows"`
+	}{}, openapi.WithTypicalBodyBytes(64), openapi.WithMaxBodyBytes(1024))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "requestBody":{
+		"content":{"text/csv":{"schema":{"type":"string","maxLength":1048576}}},
+		"x-max-body-bytes":1048576
+	  },
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "content":{
+			"application/json":{"schema":{"type":"object","properties":{"rows":{"type":"integer"}}}}
+		  },
+		  "x-max-body-bytes":1024,"x-typical-body-bytes":64
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/upload"].Post)
+}
+
+func TestReflector_AddPreflightOperations(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type req struct {
+		ID int `path:"id"`
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		oc, err := r.NewOperationContext(method, "/users/{id}")
+		require.NoError(t, err)
+		oc.AddReqStructure(req{})
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	oc, err := r.NewOperationContext(http.MethodOptions, "/custom")
+	require.NoError(t, err)
+	require.NoError(t, r.AddOperation(oc))
+
+	require.NoError(t, r.AddPreflightOperations())
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{"name":"id","in":"path","required":true,"schema":{"type":"integer"}},
+		{
+		  "name":"Origin","in":"header","description":"Origin of cross-origin request.",
+		  "schema":{"description":"Origin of cross-origin request.","type":"string"}
+		},
+		{
+		  "name":"Access-Control-Request-Method","in":"header","description":"Method of cross-origin request.",
+		  "schema":{
+			"description":"Method of cross-origin request.","enum":["GET","DELETE","OPTIONS"],"type":"string"
+		  }
+		},
+		{
+		  "name":"Access-Control-Request-Headers","in":"header",
+		  "description":"Comma-separated headers of cross-origin request.",
+		  "schema":{"description":"Comma-separated headers of cross-origin request.","type":"string"}
+		}
+	  ],
+	  "responses":{
+		"204":{
+		  "description":"No Content",
+		  "headers":{
+			"Access-Control-Allow-Headers":{
+			  "style":"simple","description":"Headers allowed for cross-origin requests.",
+			  "schema":{"description":"Headers allowed for cross-origin requests.","type":"string"}
+			},
+			"Access-Control-Allow-Methods":{
+			  "style":"simple","description":"Methods allowed for cross-origin requests.",
+			  "schema":{
+				"description":"Methods allowed for cross-origin requests.",
+				"examples":["GET, DELETE, OPTIONS"],"type":"string"
+			  }
+			},
+			"Access-Control-Allow-Origin":{
+			  "style":"simple","description":"Origin that is allowed to access resource.",
+			  "schema":{"description":"Origin that is allowed to access resource.","type":"string"}
+			},
+			"Access-Control-Max-Age":{
+			  "style":"simple","description":"Seconds for which preflight response can be cached.",
+			  "schema":{"description":"Seconds for which preflight response can be cached.","type":"integer"}
+			},
+			"Allow":{
+			  "style":"simple","description":"Methods supported by resource.","required":true,
+			  "schema":{
+				"description":"Methods supported by resource.","examples":["GET, DELETE, OPTIONS"],"type":"string"
+			  }
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/users/{id}"].Options)
+	assertjson.EqMarshal(t, `{"responses":{"204":{"description":"No Content"}}}`,
+		r.Spec.Paths.MapOfPathItemValues["/custom"].Options)
+}
+
+func TestReflector_AddOperation_msgpack(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	type shape struct {
+		Name   string  `msgpack:"n" json:"name"`
+		Points []point `msgpack:"p" json:"points"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/shapes")
+	require.NoError(t, err)
+	oc.AddReqStructure(shape{}, openapi.WithContentType("application/x-msgpack"))
+	oc.AddRespStructure(shape{}, openapi.WithContentType("application/x-msgpack"))
+	oc.AddRespStructure(shape{}, openapi.WithHTTPStatus(http.StatusAccepted))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/shapes":{
+		  "post":{
+			"requestBody":{
+			  "content":{
+				"application/x-msgpack":{"schema":{"$ref":"#/components/schemas/MsgpackOpenapi31TestShape"}}
+			  }
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/x-msgpack":{"schema":{"$ref":"#/components/schemas/MsgpackOpenapi31TestShape"}}
+				}
+			  },
+			  "202":{
+				"description":"Accepted",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestShape"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "MsgpackOpenapi31TestShape":{
+			"properties":{
+			  "n":{"type":"string"},
+			  "p":{"items":{"$ref":"#/components/schemas/Openapi31TestPoint"},"type":["array","null"]}
+			},
+			"type":"object"
+		  },
+		  "Openapi31TestPoint":{"properties":{"x":{"type":"integer"},"y":{"type":"integer"}},"type":"object"},
+		  "Openapi31TestShape":{
+			"properties":{
+			  "name":{"type":"string"},
+			  "points":{"items":{"$ref":"#/components/schemas/Openapi31TestPoint"},"type":["array","null"]}
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestReflector_AddOperation_hal(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type item struct {
+		Links openapi.HALLinks `json:"_links"`
+		SKU   string           `json:"sku"`
+	}
+
+	type order struct {
+		Links    openapi.HALLinks `json:"_links"`
+		Embedded struct {
+			Items []item `json:"items"`
+		} `json:"_embedded"`
+		Total float64 `json:"total"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/orders/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+	}{})
+	oc.AddRespStructure(order{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/orders/{id}":{
+		  "get":{
+			"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/hal+json":{"schema":{"$ref":"#/components/schemas/Openapi31TestOrder"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestItem":{
+			"properties":{"_links":{"$ref":"#/components/schemas/OpenapiGoHALLinks"},"sku":{"type":"string"}},
+			"type":"object"
+		  },
+		  "Openapi31TestOrder":{
+			"properties":{
+			  "_embedded":{
+				"properties":{
+				  "items":{"items":{"$ref":"#/components/schemas/Openapi31TestItem"},"type":["array","null"]}
+				},
+				"type":"object"
+			  },
+			  "_links":{"$ref":"#/components/schemas/OpenapiGoHALLinks"},
+			  "total":{"type":"number"}
+			},
+			"type":"object"
+		  },
+		  "OpenapiGoHALLink":{
+			"properties":{
+			  "deprecation":{"description":"URL with deprecation notice of link.","format":"uri","type":"string"},
+			  "href":{"description":"URI or URI Template of target resource.","type":"string"},
+			  "hreflang":{"type":"string"},
+			  "name":{"description":"Secondary key to select link of the same relation.","type":"string"},
+			  "profile":{"format":"uri","type":"string"},
+			  "templated":{"description":"Indicates that href is a URI Template.","type":"boolean"},
+			  "title":{"type":"string"},
+			  "type":{"description":"Media type of target resource.","type":"string"}
+			},
+			"required":["href"],"type":"object"
+		  },
+		  "OpenapiGoHALLinks":{
+			"additionalProperties":{
+			  "oneOf":[
+				{"$ref":"#/components/schemas/OpenapiGoHALLink"},
+				{"items":{"$ref":"#/components/schemas/OpenapiGoHALLink"},"type":"array"}
+			  ]
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestSunset(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/v1/users")
+	require.NoError(t, err)
+	openapi.Sunset(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).Apply(oc)
+	oc.AddRespStructure([]string{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNotFound))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "headers":{
+			"Deprecation":{
+			  "style":"simple","description":"Indicates that operation is deprecated.",
+			  "schema":{"description":"Indicates that operation is deprecated.","type":"string"}
+			},
+			"Sunset":{
+			  "style":"simple","description":"Date after which operation is no longer available.",
+			  "schema":{
+				"description":"Date after which operation is no longer available.",
+				"examples":["Sun, 01 Jun 2025 00:00:00 GMT"],"type":"string"
+			  }
+			}
+		  },
+		  "content":{"application/json":{"schema":{"items":{"type":"string"},"type":"array"}}}
+		},
+		"404":{
+		  "description":"Not Found",
+		  "headers":{
+			"Deprecation":{
+			  "style":"simple","description":"Indicates that operation is deprecated.",
+			  "schema":{"description":"Indicates that operation is deprecated.","type":"string"}
+			},
+			"Sunset":{
+			  "style":"simple","description":"Date after which operation is no longer available.",
+			  "schema":{
+				"description":"Date after which operation is no longer available.",
+				"examples":["Sun, 01 Jun 2025 00:00:00 GMT"],"type":"string"
+			  }
+			}
+		  }
+		}
+	  },
+	  "deprecated":true,"x-sunset":"2025-06-01T00:00:00Z"
+	}`, r.Spec.Paths.MapOfPathItemValues["/v1/users"].Get)
+}
+
+type money struct {
+	units int64
+	nanos int32
+}
+
+func TestReflector_ReplaceSchema(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.ReplaceSchema(money{}, func(s *jsonschema.Schema) {
+		s.WithType(jsonschema.String.Type()).WithFormat("decimal").WithExamples("12.30")
+	})
+
+	type order struct {
+		Total    money  `json:"total"`
+		Discount *money `json:"discount,omitempty"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/orders")
+	require.NoError(t, err)
+	oc.AddReqStructure(order{})
+	oc.AddRespStructure(order{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi31TestMoney":{"examples":["12.30"],"format":"decimal","type":"string"},
+	  "Openapi31TestOrder":{
+		"properties":{
+		  "discount":{"$ref":"#/components/schemas/Openapi31TestMoney"},
+		  "total":{"$ref":"#/components/schemas/Openapi31TestMoney"}
+		},
+		"type":"object"
+	  }
+	}`, r.Spec.Components.Schemas)
+}
+
+func TestReflector_WithOperationCustomizer(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.WithOperationCustomizer(func(op *openapi31.Operation) {
+		if op.Summary == nil && op.ID != nil {
+			op.WithSummary(*op.ID)
+		}
+	})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items")
+	require.NoError(t, err)
+	oc.SetID("listItems")
+	oc.AddRespStructure([]string{})
+	require.NoError(t, r.AddOperation(oc))
+
+	require.NoError(t, r.Spec.SetupOperation(http.MethodGet, "/items", func(op *openapi31.Operation) error {
+		require.NotNil(t, op.Summary)
+		assert.Equal(t, "listItems", *op.Summary)
+
+		return nil
+	}))
+}
+
+func TestView(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type user struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email" required:"true" view:"admin,self"`
+		Notes string `json:"notes" view:"admin"`
+	}
+
+	type team struct {
+		Title   string `json:"title"`
+		Members []user `json:"members"`
+	}
+
+	for path, view := range map[string]string{"/teams": "", "/admin/teams": "admin", "/public/teams": "public"} {
+		oc, err := r.NewOperationContext(http.MethodGet, path)
+		require.NoError(t, err)
+		openapi.View(view).Apply(oc)
+		oc.AddRespStructure(team{})
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	assertjson.EqMarshal(t, `{
+	  "Openapi31TestTeam":{
+		"properties":{
+		  "members":{"items":{"$ref":"#/components/schemas/Openapi31TestUser"},"type":["array","null"]},
+		  "title":{"type":"string"}
+		},
+		"type":"object"
+	  },
+	  "Openapi31TestTeamAdminView":{
+		"properties":{
+		  "members":{"items":{"$ref":"#/components/schemas/Openapi31TestUserAdminView"},"type":["array","null"]},
+		  "title":{"type":"string"}
+		},
+		"type":"object"
+	  },
+	  "Openapi31TestTeamPublicView":{
+		"properties":{
+		  "members":{"items":{"$ref":"#/components/schemas/Openapi31TestUserPublicView"},"type":["array","null"]},
+		  "title":{"type":"string"}
+		},
+		"type":"object"
+	  },
+	  "Openapi31TestUser":{
+		"properties":{
+		  "email":{"type":"string"},"id":{"type":"integer"},"name":{"type":"string"},"notes":{"type":"string"}
+		},
+		"required":["email"],"type":"object"
+	  },
+	  "Openapi31TestUserAdminView":{
+		"properties":{
+		  "email":{"type":"string"},"id":{"type":"integer"},"name":{"type":"string"},"notes":{"type":"string"}
+		},
+		"required":["email"],"type":"object"
+	  },
+	  "Openapi31TestUserPublicView":{
+		"properties":{"id":{"type":"integer"},"name":{"type":"string"}},
+		"type":"object"
+	  }
+	}`, r.Spec.Components.Schemas)
+}
+
+func TestReflector_TagNamespace(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.TagNamespace = "oas"
+
+	type record struct {
+		ID      int    `json:"_id" oas:"id"`
+		Name    string `json:"name"`
+		Version int    `json:"_v" oas:"-"`
+		Title   string `oas:"title"`
+	}
+
+	type req struct {
+		ID    int    `path:"id"`
+		Trace string `header:"X-Trace" oas-header:"X-Trace-ID"`
+		Limit int    `query:"l" oas-query:"limit"`
+		record
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/records/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(req{})
+	oc.AddRespStructure(record{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/records/{id}":{
+		  "put":{
+			"parameters":[
+			  {"name":"limit","in":"query","schema":{"type":"integer"}},
+			  {"name":"id","in":"path","required":true,"schema":{"type":"integer"}},
+			  {"name":"X-Trace-ID","in":"header","schema":{"type":"string"}}
+			],
+			"requestBody":{
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestReq"}}}
+			},
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestRecord"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Openapi31TestRecord":{
+			"properties":{"id":{"type":"integer"},"name":{"type":"string"},"title":{"type":"string"}},
+			"type":"object"
+		  },
+		  "Openapi31TestReq":{
+			"properties":{"id":{"type":"integer"},"name":{"type":"string"},"title":{"type":"string"}},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestReflector_StrictTags(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.StrictTags = true
+
+	type address struct {
+		City string `json:"city"`
+		Zip  string
+	}
+
+	type Audit struct {
+		CreatedBy string
+	}
+
+	type req struct {
+		Audit
+		ID      int       `path:"id"`
+		Name    string    `json:"name"`
+		Address address   `json:"address"`
+		Since   time.Time `json:"since"`
+		Comment string
+		_       struct{} `additionalProperties:"false"`
+		local   string
+	}
+
+	type resp struct {
+		Name  string `json:"name"`
+		Trace string `header:"X-Trace"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/items/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(req{local: "skipped"})
+	oc.AddRespStructure(resp{})
+	assert.EqualError(t, r.AddOperation(oc),
+		"check field tags put /items/{id}: untagged fields: Audit.CreatedBy, address.Zip, req.Comment")
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/items/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID int `path:"id"`
+	}{})
+	oc.AddRespStructure(resp{})
+	require.NoError(t, r.AddOperation(oc))
+}
@@ -0,0 +1,172 @@
+package openapi31
+
+import (
+	"strconv"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// SchemaVisitor is called by Spec.WalkSchemas for every schema with its JSON pointer,
+// e.g. "#/paths/~1pets/get/responses/200/content/application~1json/schema".
+//
+// Schema can be changed in place, e.g. to add vendor extension or to remove examples.
+type SchemaVisitor func(pointer string, schema map[string]interface{}) error
+
+// WalkSchemas calls visit for every schema of components, parameters, headers, request bodies and responses.
+//
+// Subschemas (e.g. properties or items) are visited after their parent.
+// Walk is sorted by names and stops on the first error of visit.
+func (s *Spec) WalkSchemas(visit SchemaVisitor) error {
+	w := schemaWalker{visit: visit}
+
+	if c := s.Components; c != nil {
+		if err := w.components(c); err != nil {
+			return err
+		}
+	}
+
+	if s.Paths == nil {
+		return nil
+	}
+
+	for _, path := range internal.SortedKeys(s.Paths.MapOfPathItemValues) {
+		pi := s.Paths.MapOfPathItemValues[path]
+
+		if err := w.parameters("#/paths/"+openapi.PointerToken(path)+"/parameters", pi.Parameters); err != nil {
+			return err
+		}
+	}
+
+	return s.WalkOperations(func(method, path string, op *Operation) error {
+		return w.operation("#/paths/"+openapi.PointerToken(path)+"/"+method, op)
+	})
+}
+
+type schemaWalker struct {
+	visit SchemaVisitor
+}
+
+func (w schemaWalker) components(c *Components) error {
+	for _, name := range internal.SortedKeys(c.Schemas) {
+		if err := internal.VisitSchemaMap("#/components/schemas/"+openapi.PointerToken(name), c.Schemas[name], w.visit); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range internal.SortedKeys(c.Parameters) {
+		if err := w.parameter("#/components/parameters/"+openapi.PointerToken(name), c.Parameters[name].Parameter); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range internal.SortedKeys(c.Headers) {
+		if err := w.header("#/components/headers/"+openapi.PointerToken(name), c.Headers[name].Header); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range internal.SortedKeys(c.RequestBodies) {
+		if rb := c.RequestBodies[name].RequestBody; rb != nil {
+			if err := w.content("#/components/requestBodies/"+openapi.PointerToken(name)+"/content", rb.Content); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range internal.SortedKeys(c.Responses) {
+		if err := w.response("#/components/responses/"+openapi.PointerToken(name), c.Responses[name].Response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w schemaWalker) operation(pointer string, op *Operation) error {
+	if err := w.parameters(pointer+"/parameters", op.Parameters); err != nil {
+		return err
+	}
+
+	if op.RequestBody != nil && op.RequestBody.RequestBody != nil {
+		if err := w.content(pointer+"/requestBody/content", op.RequestBody.RequestBody.Content); err != nil {
+			return err
+		}
+	}
+
+	if op.Responses == nil {
+		return nil
+	}
+
+	if op.Responses.Default != nil {
+		if err := w.response(pointer+"/responses/default", op.Responses.Default.Response); err != nil {
+			return err
+		}
+	}
+
+	for _, status := range internal.SortedKeys(op.Responses.MapOfResponseOrReferenceValues) {
+		if err := w.response(pointer+"/responses/"+status, op.Responses.MapOfResponseOrReferenceValues[status].Response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w schemaWalker) parameters(pointer string, params []ParameterOrReference) error {
+	for i, p := range params {
+		if err := w.parameter(pointer+"/"+strconv.Itoa(i), p.Parameter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w schemaWalker) parameter(pointer string, p *Parameter) error {
+	if p == nil {
+		return nil
+	}
+
+	if err := internal.VisitSchemaMap(pointer+"/schema", p.Schema, w.visit); err != nil {
+		return err
+	}
+
+	return w.content(pointer+"/content", p.Content)
+}
+
+func (w schemaWalker) header(pointer string, h *Header) error {
+	if h == nil {
+		return nil
+	}
+
+	if err := internal.VisitSchemaMap(pointer+"/schema", h.Schema, w.visit); err != nil {
+		return err
+	}
+
+	return w.content(pointer+"/content", h.Content)
+}
+
+func (w schemaWalker) response(pointer string, r *Response) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, name := range internal.SortedKeys(r.Headers) {
+		if err := w.header(pointer+"/headers/"+openapi.PointerToken(name), r.Headers[name].Header); err != nil {
+			return err
+		}
+	}
+
+	return w.content(pointer+"/content", r.Content)
+}
+
+func (w schemaWalker) content(pointer string, content map[string]MediaType) error {
+	for _, ct := range internal.SortedKeys(content) {
+		if err := internal.VisitSchemaMap(pointer+"/"+openapi.PointerToken(ct)+"/schema", content[ct].Schema, w.visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,519 @@
+package openapidiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/report"
+)
+
+// Kind identifies a type of breaking change.
+type Kind string
+
+// Kind values enumeration.
+const (
+	RemovedOperation     = Kind("removed-operation")
+	RemovedResponse      = Kind("removed-response")
+	RemovedField         = Kind("removed-field")
+	TypeChange           = Kind("type-change")
+	NewRequiredParameter = Kind("new-required-parameter")
+	NewRequiredField     = Kind("new-required-field")
+	RemovedEnumValue     = Kind("removed-enum-value")
+	NewNullable          = Kind("new-nullable")
+	RemovedVariant       = Kind("removed-variant")
+	NewVariant           = Kind("new-variant")
+	ClosedObject         = Kind("closed-object")
+)
+
+// Violation describes a breaking change.
+type Violation struct {
+	Kind    Kind   `json:"kind"`
+	Message string `json:"message"`
+
+	// Pointer is a JSON pointer to the affected value, removals point to the old spec,
+	// other changes point to the new spec.
+	Pointer string `json:"pointer"`
+}
+
+// Key identifies violation in Allowlist, e.g. "removed-field /components/schemas/User/properties/name".
+func (v Violation) Key() string {
+	return string(v.Kind) + " " + v.Pointer
+}
+
+// Finding returns violation as an error finding for CI reports.
+func (v Violation) Finding() report.Finding {
+	return report.Finding{
+		Rule:     "breaking: " + string(v.Kind),
+		Severity: report.SeverityError,
+		Message:  v.Message,
+		Pointer:  v.Pointer,
+	}
+}
+
+// Violations is an error of incompatible spec.
+type Violations []Violation
+
+// Error implements error.
+func (vs Violations) Error() string {
+	msgs := make([]string, 0, len(vs))
+	for _, v := range vs {
+		msgs = append(msgs, v.Key()+": "+v.Message)
+	}
+
+	return "breaking changes: " + strings.Join(msgs, ", ")
+}
+
+// Allowlist contains keys of accepted violations, see Violation.Key.
+type Allowlist []string
+
+// AssertCompatible returns Violations error if new spec breaks clients of old spec,
+// violations listed in allowlist are ignored.
+//
+// Specs can be openapi3.Spec, openapi31.Spec or any other values that are marshaled to OpenAPI documents.
+//
+//	err := openapidiff.AssertCompatible(released, r.Spec, nil)
+//
+//	var vs openapidiff.Violations
+//	if errors.As(err, &vs) { ... }
+func AssertCompatible(oldSpec, newSpec interface{}, allowlist Allowlist) error {
+	vs, err := Diff(oldSpec, newSpec)
+	if err != nil {
+		return err
+	}
+
+	allowed := make(map[string]bool, len(allowlist))
+	for _, key := range allowlist {
+		allowed[key] = true
+	}
+
+	var res Violations
+
+	for _, v := range vs {
+		if !allowed[v.Key()] {
+			res = append(res, v)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// Diff returns breaking changes of new spec against old spec.
+func Diff(oldSpec, newSpec interface{}) ([]Violation, error) {
+	oldDoc, err := decode(oldSpec)
+	if err != nil {
+		return nil, fmt.Errorf("old spec: %w", err)
+	}
+
+	newDoc, err := decode(newSpec)
+	if err != nil {
+		return nil, fmt.Errorf("new spec: %w", err)
+	}
+
+	d := differ{
+		old:     oldDoc,
+		new:     newDoc,
+		seen:    map[string]bool{},
+		visited: map[string]bool{},
+	}
+
+	d.paths()
+
+	return d.violations, nil
+}
+
+func decode(spec interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+var methods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+}
+
+type differ struct {
+	old, new   map[string]interface{}
+	violations []Violation
+
+	seen    map[string]bool
+	visited map[string]bool
+}
+
+func (d *differ) add(kind Kind, pointer, message string) {
+	v := Violation{Kind: kind, Message: message, Pointer: pointer}
+
+	if d.seen[v.Key()] {
+		return
+	}
+
+	d.seen[v.Key()] = true
+	d.violations = append(d.violations, v)
+}
+
+func (d *differ) paths() {
+	oldPaths, _ := d.old["paths"].(map[string]interface{}) //nolint:errcheck // Missing paths are empty.
+	newPaths, _ := d.new["paths"].(map[string]interface{}) //nolint:errcheck // Missing paths are empty.
+
+	for _, path := range internal.SortedKeys(oldPaths) {
+		oldItem, _ := oldPaths[path].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+		newItem, _ := newPaths[path].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+		for _, method := range internal.SortedKeys(oldItem) {
+			if !methods[method] {
+				continue
+			}
+
+			pointer := "/paths/" + openapi.PointerToken(path) + "/" + method
+
+			newOp, ok := newItem[method].(map[string]interface{})
+			if !ok {
+				d.add(RemovedOperation, pointer, "Operation "+strings.ToUpper(method)+" "+path+" was removed.")
+
+				continue
+			}
+
+			oldOp, _ := oldItem[method].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+			d.parameters(pointer, oldItem, oldOp, newItem, newOp)
+			d.requestBody(pointer, oldOp, newOp)
+			d.responses(pointer, oldOp, newOp)
+		}
+	}
+}
+
+type parameter struct {
+	pointer string
+	value   map[string]interface{}
+}
+
+// operationParameters returns parameters of path item and operation by location and name.
+func operationParameters(doc map[string]interface{}, pointer string, item, op map[string]interface{}) map[string]parameter {
+	res := map[string]parameter{}
+
+	for i, p := range []map[string]interface{}{item, op} {
+		params, _ := p["parameters"].([]interface{}) //nolint:errcheck // Missing parameters are empty.
+
+		base := pointer
+		if i == 0 {
+			base = pointer[:strings.LastIndex(pointer, "/")]
+		}
+
+		for j, param := range params {
+			pp, v := resolve(doc, fmt.Sprintf("%s/parameters/%d", base, j), param)
+			if v == nil {
+				continue
+			}
+
+			res[fmt.Sprint(v["in"])+" "+fmt.Sprint(v["name"])] = parameter{pointer: pp, value: v}
+		}
+	}
+
+	return res
+}
+
+func (d *differ) parameters(pointer string, oldItem, oldOp, newItem, newOp map[string]interface{}) {
+	oldParams := operationParameters(d.old, pointer, oldItem, oldOp)
+	newParams := operationParameters(d.new, pointer, newItem, newOp)
+
+	keys := make([]string, 0, len(newParams))
+	for key := range newParams {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		np := newParams[key]
+		op, existed := oldParams[key]
+
+		if np.value["required"] == true && (!existed || op.value["required"] != true) {
+			d.add(NewRequiredParameter, np.pointer, "Parameter "+key+" is required.")
+		}
+
+		if existed {
+			d.schema(op.pointer+"/schema", op.value["schema"], np.pointer+"/schema", np.value["schema"], true)
+		}
+	}
+}
+
+func (d *differ) requestBody(pointer string, oldOp, newOp map[string]interface{}) {
+	oldPointer, oldBody := resolve(d.old, pointer+"/requestBody", oldOp["requestBody"])
+	newPointer, newBody := resolve(d.new, pointer+"/requestBody", newOp["requestBody"])
+
+	if newBody == nil {
+		return
+	}
+
+	if newBody["required"] == true && (oldBody == nil || oldBody["required"] != true) {
+		d.add(NewRequiredField, newPointer, "Request body is required.")
+	}
+
+	if oldBody == nil {
+		return
+	}
+
+	d.content(oldPointer, oldBody, newPointer, newBody, true)
+}
+
+func (d *differ) responses(pointer string, oldOp, newOp map[string]interface{}) {
+	oldResps, _ := oldOp["responses"].(map[string]interface{}) //nolint:errcheck // Missing responses are empty.
+	newResps, _ := newOp["responses"].(map[string]interface{}) //nolint:errcheck // Missing responses are empty.
+
+	for _, status := range internal.SortedKeys(oldResps) {
+		rp := pointer + "/responses/" + openapi.PointerToken(status)
+
+		oldPointer, oldResp := resolve(d.old, rp, oldResps[status])
+		newPointer, newResp := resolve(d.new, rp, newResps[status])
+
+		if newResp == nil {
+			d.add(RemovedResponse, rp, "Response "+status+" was removed.")
+
+			continue
+		}
+
+		d.content(oldPointer, oldResp, newPointer, newResp, false)
+	}
+}
+
+// content compares schemas of media types that are available in both specs.
+func (d *differ) content(oldPointer string, oldUnit map[string]interface{}, newPointer string, newUnit map[string]interface{}, request bool) {
+	oldContent, _ := oldUnit["content"].(map[string]interface{}) //nolint:errcheck // Missing content is empty.
+	newContent, _ := newUnit["content"].(map[string]interface{}) //nolint:errcheck // Missing content is empty.
+
+	for _, mt := range internal.SortedKeys(oldContent) {
+		oldMedia, _ := oldContent[mt].(map[string]interface{}) //nolint:errcheck // Type is checked by access.
+
+		newMedia, ok := newContent[mt].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		suffix := "/content/" + openapi.PointerToken(mt) + "/schema"
+
+		d.schema(oldPointer+suffix, oldMedia["schema"], newPointer+suffix, newMedia["schema"], request)
+	}
+}
+
+// schema compares types, properties, enums and subschemas of schemas.
+//
+// Request schemas are checked for new required properties, removed enum values, removed oneOf/anyOf variants
+// and forbidden additional properties. Response schemas are checked for removed properties, new oneOf/anyOf
+// variants and newly allowed null.
+func (d *differ) schema(oldPointer string, oldValue interface{}, newPointer string, newValue interface{}, request bool) {
+	oldPointer, oldSchema := resolve(d.old, oldPointer, oldValue)
+	newPointer, newSchema := resolve(d.new, newPointer, newValue)
+
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+
+	visit := fmt.Sprintf("%s %s %t", oldPointer, newPointer, request)
+	if d.visited[visit] {
+		return
+	}
+
+	d.visited[visit] = true
+
+	oldTypes, newTypes := schemaTypes(oldSchema), schemaTypes(newSchema)
+	if oldTypes != "" && newTypes != "" && oldTypes != newTypes {
+		d.add(TypeChange, newPointer, "Type changed from "+oldTypes+" to "+newTypes+".")
+	}
+
+	oldProps, _ := oldSchema["properties"].(map[string]interface{}) //nolint:errcheck // Missing properties are empty.
+	newProps, _ := newSchema["properties"].(map[string]interface{}) //nolint:errcheck // Missing properties are empty.
+
+	for _, name := range internal.SortedKeys(oldProps) {
+		pp := "/properties/" + openapi.PointerToken(name)
+
+		if _, ok := newProps[name]; !ok {
+			if !request {
+				d.add(RemovedField, oldPointer+pp, "Property "+name+" was removed.")
+			}
+
+			continue
+		}
+
+		d.schema(oldPointer+pp, oldProps[name], newPointer+pp, newProps[name], request)
+	}
+
+	if request {
+		oldRequired := map[string]bool{}
+
+		oldReq, _ := oldSchema["required"].([]interface{}) //nolint:errcheck // Missing required is empty.
+		for _, name := range oldReq {
+			oldRequired[fmt.Sprint(name)] = true
+		}
+
+		newReq, _ := newSchema["required"].([]interface{}) //nolint:errcheck // Missing required is empty.
+		for _, name := range newReq {
+			if n := fmt.Sprint(name); !oldRequired[n] {
+				d.add(NewRequiredField, newPointer+"/properties/"+openapi.PointerToken(n), "Property "+n+" is required.")
+			}
+		}
+	}
+
+	if request {
+		d.enum(oldSchema, newPointer, newSchema)
+		d.additionalProperties(oldSchema, newPointer, newSchema)
+	} else if oldTypes != "" && !allowsNull(oldSchema) && allowsNull(newSchema) {
+		d.add(NewNullable, newPointer, "Null is allowed.")
+	}
+
+	if oldItems, ok := oldSchema["items"]; ok {
+		d.schema(oldPointer+"/items", oldItems, newPointer+"/items", newSchema["items"], request)
+	}
+
+	if oldAP, ok := oldSchema["additionalProperties"].(map[string]interface{}); ok {
+		d.schema(oldPointer+"/additionalProperties", oldAP, newPointer+"/additionalProperties",
+			newSchema["additionalProperties"], request)
+	}
+
+	d.variants(oldPointer, oldSchema, newPointer, newSchema, request)
+}
+
+// variants compares subschemas of allOf, oneOf and anyOf by position.
+func (d *differ) variants(oldPointer string, oldSchema map[string]interface{}, newPointer string, newSchema map[string]interface{}, request bool) {
+	for _, kw := range []string{"allOf", "oneOf", "anyOf"} {
+		oldList, _ := oldSchema[kw].([]interface{}) //nolint:errcheck // Missing list is empty.
+		newList, _ := newSchema[kw].([]interface{}) //nolint:errcheck // Missing list is empty.
+
+		if kw != "allOf" && len(oldList) > 0 {
+			switch {
+			case request && len(newList) < len(oldList):
+				d.add(RemovedVariant, newPointer+"/"+kw,
+					fmt.Sprintf("Variants of %s reduced from %d to %d.", kw, len(oldList), len(newList)))
+			case !request && len(newList) > len(oldList):
+				d.add(NewVariant, newPointer+"/"+kw,
+					fmt.Sprintf("Variants of %s increased from %d to %d.", kw, len(oldList), len(newList)))
+			}
+		}
+
+		for i := range oldList {
+			if i >= len(newList) {
+				break
+			}
+
+			ip := fmt.Sprintf("/%s/%d", kw, i)
+
+			d.schema(oldPointer+ip, oldList[i], newPointer+ip, newList[i], request)
+		}
+	}
+}
+
+// enum reports values of request enum that are not accepted anymore.
+func (d *differ) enum(oldSchema map[string]interface{}, newPointer string, newSchema map[string]interface{}) {
+	oldEnum, ok := oldSchema["enum"].([]interface{})
+	if !ok {
+		return
+	}
+
+	newEnum, ok := newSchema["enum"].([]interface{})
+	if !ok {
+		return
+	}
+
+	accepted := make(map[string]bool, len(newEnum))
+	for _, v := range newEnum {
+		accepted[jsonValue(v)] = true
+	}
+
+	for _, v := range oldEnum {
+		if jv := jsonValue(v); !accepted[jv] {
+			d.add(RemovedEnumValue, newPointer+"/enum", "Enum value "+jv+" was removed.")
+		}
+	}
+}
+
+// additionalProperties reports request objects that do not accept additional properties anymore.
+func (d *differ) additionalProperties(oldSchema map[string]interface{}, newPointer string, newSchema map[string]interface{}) {
+	if newSchema["additionalProperties"] != false || oldSchema["additionalProperties"] == false {
+		return
+	}
+
+	d.add(ClosedObject, newPointer+"/additionalProperties", "Additional properties are not allowed.")
+}
+
+// allowsNull checks if schema accepts null with type or nullable keyword.
+func allowsNull(schema map[string]interface{}) bool {
+	if schema["nullable"] == true {
+		return true
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		return t == "null"
+	case []interface{}:
+		for _, item := range t {
+			if item == "null" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func jsonValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+// schemaTypes returns sorted non-null types of schema, or empty string if type is not constrained.
+func schemaTypes(schema map[string]interface{}) string {
+	var types []string
+
+	switch t := schema["type"].(type) {
+	case string:
+		types = append(types, t)
+	case []interface{}:
+		for _, item := range t {
+			if s := fmt.Sprint(item); s != "null" {
+				types = append(types, s)
+			}
+		}
+	}
+
+	sort.Strings(types)
+
+	return strings.Join(types, "|")
+}
+
+// resolve follows local references of value and returns pointer and value of target.
+func resolve(doc map[string]interface{}, pointer string, v interface{}) (string, map[string]interface{}) {
+	for i := 0; i < 32; i++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return pointer, nil
+		}
+
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return pointer, m
+		}
+
+		pointer = ref[1:]
+		v, _ = openapi.ResolvePointer(doc, pointer) //nolint:errcheck // Missing target is nil.
+	}
+
+	return pointer, nil
+}
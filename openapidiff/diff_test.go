@@ -0,0 +1,268 @@
+package openapidiff_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapidiff"
+	"github.com/swaggest/openapi-go/report"
+)
+
+func TestAssertCompatible(t *testing.T) {
+	type userV1 struct {
+		Name  string `json:"name"`
+		Age   int    `json:"age"`
+		Email string `json:"email"`
+	}
+
+	type userV2 struct {
+		Name string `json:"name"`
+		Age  string `json:"age"`
+	}
+
+	type listV1 struct {
+		Limit int `query:"limit"`
+	}
+
+	type listV2 struct {
+		Limit  int    `query:"limit"`
+		Tenant string `header:"X-Tenant" required:"true"`
+	}
+
+	build := func(list, user interface{}, withDelete bool) openapi3.Spec {
+		r := openapi3.NewReflector()
+
+		oc, err := r.NewOperationContext(http.MethodGet, "/users")
+		require.NoError(t, err)
+
+		oc.AddReqStructure(list)
+		oc.AddRespStructure(user)
+		require.NoError(t, r.AddOperation(oc))
+
+		if withDelete {
+			oc, err = r.NewOperationContext(http.MethodDelete, "/users")
+			require.NoError(t, err)
+			require.NoError(t, r.AddOperation(oc))
+		}
+
+		return *r.Spec
+	}
+
+	v1 := build(listV1{}, userV1{}, true)
+	v2 := build(listV2{}, userV2{}, false)
+
+	require.NoError(t, openapidiff.AssertCompatible(v1, v1, nil))
+
+	err := openapidiff.AssertCompatible(v1, v2, nil)
+
+	var vs openapidiff.Violations
+
+	require.True(t, errors.As(err, &vs))
+	assert.Equal(t, openapidiff.Violations{
+		{
+			Kind:    openapidiff.RemovedOperation,
+			Message: "Operation DELETE /users was removed.",
+			Pointer: "/paths/~1users/delete",
+		},
+		{
+			Kind:    openapidiff.NewRequiredParameter,
+			Message: "Parameter header X-Tenant is required.",
+			Pointer: "/paths/~1users/get/parameters/1",
+		},
+		{
+			Kind:    openapidiff.TypeChange,
+			Message: "Type changed from integer to string.",
+			Pointer: "/components/schemas/OpenapidiffTestUserV2/properties/age",
+		},
+		{
+			Kind:    openapidiff.RemovedField,
+			Message: "Property email was removed.",
+			Pointer: "/components/schemas/OpenapidiffTestUserV1/properties/email",
+		},
+	}, vs)
+
+	err = openapidiff.AssertCompatible(v1, v2, openapidiff.Allowlist{
+		vs[0].Key(), vs[1].Key(), vs[2].Key(),
+	})
+	require.True(t, errors.As(err, &vs))
+	require.Len(t, vs, 1)
+	assert.Equal(t, report.Finding{
+		Rule:     "breaking: removed-field",
+		Severity: report.SeverityError,
+		Message:  "Property email was removed.",
+		Pointer:  "/components/schemas/OpenapidiffTestUserV1/properties/email",
+	}, vs[0].Finding())
+	assert.Equal(t, "breaking changes: removed-field /components/schemas/OpenapidiffTestUserV1/properties/email: "+
+		"Property email was removed.", err.Error())
+}
+
+func TestDiff_schema(t *testing.T) {
+	doc := func(param, body, resp string) json.RawMessage {
+		return json.RawMessage(`{
+		  "openapi":"3.1.0",
+		  "paths":{
+			"/items":{
+			  "post":{
+				"parameters":[{"name":"kind","in":"query","schema":` + param + `}],
+				"requestBody":` + body + `,
+				"responses":{"200":{"description":"OK","content":{"application/json":{"schema":` + resp + `}}}}
+			  }
+			}
+		  }
+		}`)
+	}
+
+	const (
+		param = `{"type":"string"}`
+		body  = `{"content":{"application/json":{"schema":{"type":"object"}}}}`
+		resp  = `{"type":"object"}`
+	)
+
+	reqBody := func(schema string) string {
+		return `{"content":{"application/json":{"schema":` + schema + `}}}`
+	}
+
+	for _, tc := range []struct {
+		name     string
+		old, new json.RawMessage
+		expected []openapidiff.Violation
+	}{
+		{
+			name: "allOf",
+			old:  doc(param, body, `{"allOf":[{"type":"object"},{"properties":{"id":{"type":"integer"}}}]}`),
+			new:  doc(param, body, `{"allOf":[{"type":"object"},{"properties":{"id":{"type":"string"}}}]}`),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.TypeChange,
+				Message: "Type changed from integer to string.",
+				Pointer: "/paths/~1items/post/responses/200/content/application~1json/schema/allOf/1/properties/id",
+			}},
+		},
+		{
+			name: "oneOf request variant removed",
+			old:  doc(param, reqBody(`{"oneOf":[{"type":"string"},{"type":"integer"}]}`), resp),
+			new:  doc(param, reqBody(`{"oneOf":[{"type":"string"}]}`), resp),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.RemovedVariant,
+				Message: "Variants of oneOf reduced from 2 to 1.",
+				Pointer: "/paths/~1items/post/requestBody/content/application~1json/schema/oneOf",
+			}},
+		},
+		{
+			name: "anyOf response variant added",
+			old:  doc(param, body, `{"anyOf":[{"type":"string"}]}`),
+			new:  doc(param, body, `{"anyOf":[{"type":"string"},{"type":"integer"}]}`),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.NewVariant,
+				Message: "Variants of anyOf increased from 1 to 2.",
+				Pointer: "/paths/~1items/post/responses/200/content/application~1json/schema/anyOf",
+			}},
+		},
+		{
+			name: "anyOf request variant added",
+			old:  doc(param, reqBody(`{"anyOf":[{"type":"string"}]}`), resp),
+			new:  doc(param, reqBody(`{"anyOf":[{"type":"string"},{"type":"integer"}]}`), resp),
+		},
+		{
+			name: "additionalProperties schema",
+			old:  doc(param, body, `{"type":"object","additionalProperties":{"type":"integer"}}`),
+			new:  doc(param, body, `{"type":"object","additionalProperties":{"type":"string"}}`),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.TypeChange,
+				Message: "Type changed from integer to string.",
+				Pointer: "/paths/~1items/post/responses/200/content/application~1json/schema/additionalProperties",
+			}},
+		},
+		{
+			name: "additionalProperties forbidden in request",
+			old:  doc(param, reqBody(`{"type":"object","properties":{"id":{"type":"integer"}}}`), resp),
+			new: doc(param,
+				reqBody(`{"type":"object","properties":{"id":{"type":"integer"}},"additionalProperties":false}`), resp),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.ClosedObject,
+				Message: "Additional properties are not allowed.",
+				Pointer: "/paths/~1items/post/requestBody/content/application~1json/schema/additionalProperties",
+			}},
+		},
+		{
+			name: "enum value removed from request body",
+			old:  doc(param, reqBody(`{"type":"string","enum":["draft","active","archived"]}`), resp),
+			new:  doc(param, reqBody(`{"type":"string","enum":["active","draft"]}`), resp),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.RemovedEnumValue,
+				Message: `Enum value "archived" was removed.`,
+				Pointer: "/paths/~1items/post/requestBody/content/application~1json/schema/enum",
+			}},
+		},
+		{
+			name: "enum value removed from parameter",
+			old:  doc(`{"type":"integer","enum":[1,2]}`, body, resp),
+			new:  doc(`{"type":"integer","enum":[1]}`, body, resp),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.RemovedEnumValue,
+				Message: "Enum value 2 was removed.",
+				Pointer: "/paths/~1items/post/parameters/0/schema/enum",
+			}},
+		},
+		{
+			name: "enum value added to request",
+			old:  doc(`{"type":"integer","enum":[1]}`, body, resp),
+			new:  doc(`{"type":"integer","enum":[1,2]}`, body, resp),
+		},
+		{
+			name: "enum value removed from response",
+			old:  doc(param, body, `{"type":"string","enum":["a","b"]}`),
+			new:  doc(param, body, `{"type":"string","enum":["a"]}`),
+		},
+		{
+			name: "null allowed in response",
+			old:  doc(param, body, `{"type":"object","properties":{"tags":{"type":"array"}}}`),
+			new:  doc(param, body, `{"type":"object","properties":{"tags":{"type":["array","null"]}}}`),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.NewNullable,
+				Message: "Null is allowed.",
+				Pointer: "/paths/~1items/post/responses/200/content/application~1json/schema/properties/tags",
+			}},
+		},
+		{
+			name: "nullable response",
+			old:  doc(param, body, `{"type":"object"}`),
+			new:  doc(param, body, `{"type":"object","nullable":true}`),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.NewNullable,
+				Message: "Null is allowed.",
+				Pointer: "/paths/~1items/post/responses/200/content/application~1json/schema",
+			}},
+		},
+		{
+			name: "null allowed in request",
+			old:  doc(param, reqBody(`{"type":"string"}`), resp),
+			new:  doc(param, reqBody(`{"type":["string","null"]}`), resp),
+		},
+		{
+			name: "request body required",
+			old:  doc(param, body, resp),
+			new:  doc(param, `{"required":true,"content":{"application/json":{"schema":{"type":"object"}}}}`, resp),
+			expected: []openapidiff.Violation{{
+				Kind:    openapidiff.NewRequiredField,
+				Message: "Request body is required.",
+				Pointer: "/paths/~1items/post/requestBody",
+			}},
+		},
+		{
+			name: "request body optional",
+			old:  doc(param, `{"required":true,"content":{"application/json":{"schema":{"type":"object"}}}}`, resp),
+			new:  doc(param, body, resp),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			vs, err := openapidiff.Diff(tc.old, tc.new)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, vs)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package openapidiff detects breaking changes between versions of a spec to gate incompatible releases in CI.
+package openapidiff
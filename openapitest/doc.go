@@ -0,0 +1,2 @@
+// Package openapitest provides test helpers for reflected specs.
+package openapitest
@@ -0,0 +1,96 @@
+package openapitest
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+)
+
+// UpdateEnv is a name of environment variable that enables writing of golden files, e.g. OPENAPITEST_UPDATE=1.
+const UpdateEnv = "OPENAPITEST_UPDATE"
+
+const updateFlag = "update"
+
+// updating checks UpdateEnv and "update" flag, the flag is not defined by this package,
+// but test package may define it, e.g. flag.Bool("update", false, "update golden files").
+func updating() bool {
+	if u, err := strconv.ParseBool(os.Getenv(UpdateEnv)); err == nil && u {
+		return true
+	}
+
+	f := flag.Lookup(updateFlag)
+	if f == nil {
+		return false
+	}
+
+	u, err := strconv.ParseBool(f.Value.String())
+
+	return err == nil && u
+}
+
+// TestingT is a subset of testing.TB.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertSpec compares spec of reflector with golden file, mismatch is reported with structural diff.
+//
+// Spec is marshaled in canonical form with sorted map keys, so the comparison is stable.
+// Run tests with OPENAPITEST_UPDATE=1 environment variable (or -update flag if test package defines it)
+// to write golden file.
+//
+//	func TestSpec(t *testing.T) {
+//		openapitest.AssertSpec(t, newReflector(), "testdata/openapi.json")
+//	}
+func AssertSpec(t TestingT, r openapi.Reflector, goldenFile string) bool {
+	t.Helper()
+
+	actual, err := assertjson.MarshalIndentCompact(r.SpecSchema(), "", " ", 120)
+	if err != nil {
+		t.Errorf("marshal spec: %s", err)
+
+		return false
+	}
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0o700); err != nil {
+			t.Errorf("create golden file directory: %s", err)
+
+			return false
+		}
+
+		if err := os.WriteFile(goldenFile, append(actual, '\n'), 0o600); err != nil {
+			t.Errorf("write golden file: %s", err)
+
+			return false
+		}
+
+		return true
+	}
+
+	expected, err := os.ReadFile(goldenFile) //nolint:gosec // Golden file is provided by test.
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("golden file %s not found, run tests with %s=1 to create it", goldenFile, UpdateEnv)
+		} else {
+			t.Errorf("read golden file: %s", err)
+		}
+
+		return false
+	}
+
+	if err := assertjson.FailNotEqual(expected, actual); err != nil {
+		t.Errorf("spec does not match %s, run tests with %s=1 to accept changes:\n%s", goldenFile, UpdateEnv, err)
+
+		return false
+	}
+
+	return true
+}
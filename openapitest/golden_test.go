@@ -0,0 +1,85 @@
+package openapitest_test
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/openapitest"
+)
+
+// Test package can define update flag, openapitest does not register it.
+var update = flag.Bool("update", false, "update golden files")
+
+type recorder struct {
+	errors []string
+}
+
+func (*recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func newReflector(t *testing.T, title string) *openapi31.Reflector {
+	t.Helper()
+
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle(title)
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.AddRespStructure([]user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	return r
+}
+
+func TestAssertSpec(t *testing.T) {
+	assert.True(t, openapitest.AssertSpec(t, newReflector(t, "Users"), "testdata/openapi.json"))
+
+	rec := &recorder{}
+	assert.False(t, openapitest.AssertSpec(rec, newReflector(t, "Accounts"), "testdata/openapi.json"))
+	require.Len(t, rec.errors, 1)
+	assert.True(t, strings.HasPrefix(rec.errors[0], "spec does not match testdata/openapi.json, "+
+		"run tests with OPENAPITEST_UPDATE=1 to accept changes:\n"), rec.errors[0])
+	assert.Contains(t, rec.errors[0], `"title": "Users"`)
+
+	goldenFile := filepath.Join(t.TempDir(), "api", "openapi.json")
+
+	rec = &recorder{}
+	assert.False(t, openapitest.AssertSpec(rec, newReflector(t, "Users"), goldenFile))
+	assert.Equal(t, []string{"golden file " + goldenFile + " not found, run tests with OPENAPITEST_UPDATE=1 to create it"}, rec.errors)
+
+	t.Setenv(openapitest.UpdateEnv, "1")
+	assert.True(t, openapitest.AssertSpec(t, newReflector(t, "Users"), goldenFile))
+
+	expected, err := os.ReadFile("testdata/openapi.json")
+	require.NoError(t, err)
+
+	actual, err := os.ReadFile(goldenFile) //nolint:gosec // Test file.
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(actual))
+
+	t.Setenv(openapitest.UpdateEnv, "")
+	require.NoError(t, os.Remove(goldenFile))
+
+	*update = true
+	defer func() { *update = false }()
+
+	assert.True(t, openapitest.AssertSpec(t, newReflector(t, "Users"), goldenFile))
+	assert.FileExists(t, goldenFile)
+}
@@ -0,0 +1,72 @@
+package openapitest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/docsui"
+	"github.com/swaggest/openapi-go/mock"
+)
+
+// Paths served by Handler in addition to operations of spec.
+const (
+	DocsPath     = "/docs"
+	SpecJSONPath = "/openapi.json"
+	SpecYAMLPath = "/openapi.yaml"
+)
+
+// Handler serves spec, docs UI and mock responses of reflector, e.g. for end-to-end tests and local demos.
+//
+// Spec is served at SpecJSONPath and SpecYAMLPath, Swagger UI is served at DocsPath (see docsui.New), other paths serve
+// mock responses of operations (see mock.Handler), requests are validated against operations.
+// Operations are captured at creation, later changes of spec are only visible in spec and docs.
+//
+//	h, err := openapitest.Handler(reflector)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	log.Fatal(http.ListenAndServe("localhost:8080", h))
+func Handler(r openapi.Reflector) (http.Handler, error) {
+	doc, ok := r.SpecSchema().(openapi.SpecDocument)
+	if !ok {
+		return nil, fmt.Errorf("spec %T can not be marshaled to JSON and YAML", r.SpecSchema())
+	}
+
+	m, err := mock.NewHandler(doc)
+	if err != nil {
+		return nil, fmt.Errorf("mock handler: %w", err)
+	}
+
+	m.ValidateRequests = true
+
+	spec := openapi.NewSpecHandler(doc)
+	docs := docsui.New(doc, docsui.Config{Path: DocsPath})
+
+	mux := http.NewServeMux()
+	mux.Handle(SpecJSONPath, spec)
+	mux.Handle(SpecYAMLPath, spec)
+	mux.Handle(DocsPath, docs)
+	mux.Handle(DocsPath+"/", docs)
+	mux.Handle("/", m)
+
+	return mux, nil
+}
+
+// Server starts ephemeral HTTP server of Handler, it has to be closed after use.
+//
+//	srv, err := openapitest.Server(reflector)
+//	require.NoError(t, err)
+//	defer srv.Close()
+//
+//	resp, err := http.Get(srv.URL + "/users/1")
+func Server(r openapi.Reflector) (*httptest.Server, error) {
+	h, err := Handler(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return httptest.NewServer(h), nil
+}
@@ -0,0 +1,97 @@
+package openapitest_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/openapitest"
+)
+
+type userReq struct {
+	ID      int  `path:"id" minimum:"1"`
+	Details bool `query:"details"`
+}
+
+type newUser struct {
+	Name string `json:"name" required:"true" minLength:"2"`
+}
+
+func TestServer(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Users")
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(userReq{})
+	oc.AddRespStructure(user{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/users")
+	require.NoError(t, err)
+	oc.AddReqStructure(newUser{})
+	oc.AddRespStructure(user{}, openapi.WithHTTPStatus(http.StatusCreated))
+	require.NoError(t, r.AddOperation(oc))
+
+	srv, err := openapitest.Server(r)
+	require.NoError(t, err)
+
+	defer srv.Close()
+
+	do := func(method, path, contentType, body string) (int, string) {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, resp.Body.Close())
+		}()
+
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(b)
+	}
+
+	status, body := do(http.MethodGet, "/openapi.json", "", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"title":"Users"`)
+
+	// Docs page depends on embedded UI assets, spec of docs UI is served regardless of them.
+	status, body = do(http.MethodGet, "/docs/openapi.json", "", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"title":"Users"`)
+
+	status, body = do(http.MethodGet, "/users/1?details=true", "", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"name":"string"}`, body)
+
+	status, body = do(http.MethodGet, "/users/0?details=maybe", "", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, `request does not match spec:
+  query details: expected type boolean, got string "maybe"
+  path id: value 0 is less than minimum 1
+`, body)
+
+	status, body = do(http.MethodPost, "/users", "application/json", `{"name":"J"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "request does not match spec:\n  body/name: expected at least 2 characters, got 1\n", body)
+
+	status, body = do(http.MethodPost, "/users", "text/plain", `Jane`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, status)
+	assert.Equal(t, "request does not match spec:\n  content type \"text/plain\" is not declared, expected one of application/json\n", body)
+
+	status, _ = do(http.MethodPost, "/users", "application/json", `{"name":"Jane"}`)
+	assert.Equal(t, http.StatusCreated, status)
+}
@@ -3,7 +3,6 @@ package openapi
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/swaggest/jsonschema-go"
@@ -31,14 +30,34 @@ type ContentUnit struct {
 	ContentType string
 	Format      string
 
+	// ContentTypeVariants are additional media types of response that share schema,
+	// e.g. "application/json; charset=utf-8".
+	ContentTypeVariants []string
+
 	// HTTPStatus can have values 100-599 for single status, or 1-5 for status families (e.g. 2XX)
 	HTTPStatus int
 
 	// IsDefault indicates default response.
 	IsDefault bool
 
-	Description  string
-	fieldMapping map[In]map[string]string
+	// IsDeprecated marks media type as deprecated with "x-deprecated" vendor extension,
+	// e.g. an old version of vendor media type.
+	IsDeprecated bool
+
+	// MaxBodyBytes documents maximum size of body with "x-max-body-bytes" vendor extension,
+	// schema of string body receives maxLength too.
+	MaxBodyBytes int64
+
+	// TypicalBodyBytes documents typical size of body with "x-typical-body-bytes" vendor extension.
+	TypicalBodyBytes int64
+
+	// CacheControl documents Cache-Control, Age and Expires headers of response.
+	CacheControl *CacheControl
+
+	Description      string
+	fieldMapping     map[In]map[string]string
+	paramExamples    map[In]map[string]map[string]interface{}
+	queryParamsRules *queryParamsRules
 }
 
 // ContentUnitPreparer defines self-contained ContentUnit.
@@ -53,6 +72,20 @@ func WithContentType(contentType string) func(cu *ContentUnit) {
 	}
 }
 
+// WithContentTypeVariants is a ContentUnit option.
+func WithContentTypeVariants(contentTypes ...string) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		cu.ContentTypeVariants = append(cu.ContentTypeVariants, contentTypes...)
+	}
+}
+
+// WithDeprecatedContentType is a ContentUnit option.
+func WithDeprecatedContentType() func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		cu.IsDeprecated = true
+	}
+}
+
 // WithHTTPStatus is a ContentUnit option.
 func WithHTTPStatus(httpStatus int) func(cu *ContentUnit) {
 	return func(cu *ContentUnit) {
@@ -60,6 +93,21 @@ func WithHTTPStatus(httpStatus int) func(cu *ContentUnit) {
 	}
 }
 
+// WithMaxBodyBytes is a ContentUnit option, it documents maximum size of body,
+// e.g. for a gateway to configure request limits from spec.
+func WithMaxBodyBytes(maxBytes int64) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		cu.MaxBodyBytes = maxBytes
+	}
+}
+
+// WithTypicalBodyBytes is a ContentUnit option, it documents typical size of body.
+func WithTypicalBodyBytes(typicalBytes int64) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		cu.TypicalBodyBytes = typicalBytes
+	}
+}
+
 // SetFieldMapping sets custom field mapping.
 func (c *ContentUnit) SetFieldMapping(in In, fieldToParamName map[string]string) {
 	if len(fieldToParamName) == 0 {
@@ -78,6 +126,41 @@ func (c ContentUnit) FieldMapping(in In) map[string]string {
 	return c.fieldMapping[in]
 }
 
+// WithParamExample is a ContentUnit option, it adds named example of parameter value.
+//
+// Empty exampleName sets a single example of parameter.
+func WithParamExample(in In, paramName, exampleName string, value interface{}) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		cu.AddParamExample(in, paramName, exampleName, value)
+	}
+}
+
+// AddParamExample adds named example of parameter value, empty exampleName sets a single example.
+func (c *ContentUnit) AddParamExample(in In, paramName, exampleName string, value interface{}) {
+	if c.paramExamples == nil {
+		c.paramExamples = make(map[In]map[string]map[string]interface{})
+	}
+
+	params := c.paramExamples[in]
+	if params == nil {
+		params = make(map[string]map[string]interface{})
+		c.paramExamples[in] = params
+	}
+
+	examples := params[paramName]
+	if examples == nil {
+		examples = make(map[string]interface{})
+		params[paramName] = examples
+	}
+
+	examples[exampleName] = value
+}
+
+// ParamExamples returns examples of parameter value by example names, single example has empty name.
+func (c ContentUnit) ParamExamples(in In, paramName string) map[string]interface{} {
+	return c.paramExamples[in][paramName]
+}
+
 // OperationContext defines operation and processing state.
 type OperationContext interface {
 	OperationInfo
@@ -116,6 +199,30 @@ type OperationInfoReader interface {
 	ID() string
 }
 
+// OperationExtender is implemented by operation contexts that support vendor extensions,
+// operation contexts of openapi3 and openapi31 reflectors implement it.
+type OperationExtender interface {
+	// SetExtension sets vendor extension value, "x-" prefix is added to key if it is missing.
+	SetExtension(key string, value interface{})
+
+	// Extensions returns vendor extensions of operation.
+	Extensions() map[string]interface{}
+}
+
+// UnknownParamsForbidder is implemented by operation contexts that support explicit control of unknown parameters,
+// operation contexts of openapi3 and openapi31 reflectors implement it.
+type UnknownParamsForbidder interface {
+	// SetForbidUnknownParams explicitly controls "x-forbid-unknown-<in>" extension of operation.
+	//
+	// By default, the extension is inferred from `additionalProperties: false` of request structure.
+	// Runtime request validators use the extension to reject requests with unknown parameters,
+	// so an explicit false keeps them accepted even if structure disables additional properties.
+	SetForbidUnknownParams(in In, forbid bool)
+
+	// ForbidUnknownParams returns explicit setting of unknown parameters, isSet is false if it is inferred.
+	ForbidUnknownParams(in In) (forbid, isSet bool)
+}
+
 // OperationState extends OperationContext with processing state information.
 type OperationState interface {
 	IsProcessingResponse() bool
@@ -146,12 +253,78 @@ func OperationCtx(rc *jsonschema.ReflectContext) (OperationContext, bool) {
 	return nil, false
 }
 
-var regexFindPathParameter = regexp.MustCompile(`{([^}:]+)(:[^}]+)?(?:})`)
+// pathParameter is a templated element of URL path pattern, e.g. "{id}" or "{id:[0-9]+}".
+type pathParameter struct {
+	start, end int // Bounds of element in path pattern, including braces.
+	name       string
+	pattern    string // Router regexp, e.g. "[0-9]+".
+	wildcard   bool   // Catch-all element, e.g. "{path...}" or trailing "*".
+}
+
+// WildcardParameter is a name of path parameter of trailing "*" catch-all element, e.g. "/static/*".
+const WildcardParameter = "wildcard"
+
+// findPathParameters scans path pattern for parameters, router regexps may contain nested braces.
+func findPathParameters(pathPattern string) []pathParameter {
+	var params []pathParameter
+
+	for i := 0; i < len(pathPattern); i++ {
+		if pathPattern[i] != '{' {
+			continue
+		}
+
+		depth, colon := 0, -1
+
+		for j := i; j < len(pathPattern); j++ {
+			switch pathPattern[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case ':':
+				if colon == -1 && depth == 1 {
+					colon = j
+				}
+			}
+
+			if depth != 0 {
+				continue
+			}
+
+			p := pathParameter{start: i, end: j + 1, name: pathPattern[i+1 : j]}
+			if colon != -1 {
+				p.name = pathPattern[i+1 : colon]
+				p.pattern = pathPattern[colon+1 : j]
+			}
+
+			if strings.HasSuffix(p.name, "...") {
+				p.name = strings.TrimSuffix(p.name, "...")
+				p.wildcard = true
+			}
+
+			if p.name != "" {
+				params = append(params, p)
+			}
+
+			i = j
+
+			break
+		}
+	}
+
+	if strings.HasSuffix(pathPattern, "/*") {
+		params = append(params, pathParameter{
+			start: len(pathPattern) - 1, end: len(pathPattern),
+			name: WildcardParameter, wildcard: true,
+		})
+	}
+
+	return params
+}
 
 // SanitizeMethodPath validates method and parses path element names.
 func SanitizeMethodPath(method, pathPattern string) (cleanMethod string, cleanPath string, pathParams []string, err error) {
 	method = strings.ToLower(method)
-	pathParametersSubmatches := regexFindPathParameter.FindAllStringSubmatch(pathPattern, -1)
 
 	switch method {
 	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
@@ -160,15 +333,58 @@ func SanitizeMethodPath(method, pathPattern string) (cleanMethod string, cleanPa
 		return "", "", nil, fmt.Errorf("unexpected http method: %s", method)
 	}
 
-	if len(pathParametersSubmatches) > 0 {
-		for _, submatch := range pathParametersSubmatches {
-			pathParams = append(pathParams, submatch[1])
+	params := findPathParameters(pathPattern)
 
-			if submatch[2] != "" { // Remove gorilla.Mux-style regexp in path.
-				pathPattern = strings.Replace(pathPattern, submatch[0], "{"+submatch[1]+"}", 1)
-			}
+	for _, p := range params {
+		pathParams = append(pathParams, p.name)
+	}
+
+	// Remove gorilla.Mux-style and chi-style regexps and wildcards in path, backwards to keep bounds valid.
+	for i := len(params) - 1; i >= 0; i-- {
+		if p := params[i]; p.pattern != "" || p.wildcard {
+			pathPattern = pathPattern[:p.start] + "{" + p.name + "}" + pathPattern[p.end:]
 		}
 	}
 
 	return method, pathPattern, pathParams, nil
 }
+
+// PathParameterPatterns returns regexps of path parameters declared with router syntax, e.g. "/users/{id:[0-9]+}".
+//
+// Regexps are anchored to match whole path element, as routers do.
+func PathParameterPatterns(pathPattern string) map[string]string {
+	var patterns map[string]string
+
+	for _, p := range findPathParameters(pathPattern) {
+		if p.pattern == "" {
+			continue
+		}
+
+		if patterns == nil {
+			patterns = make(map[string]string)
+		}
+
+		pattern := strings.TrimSuffix(strings.TrimPrefix(p.pattern, "^"), "$")
+		if strings.Contains(pattern, "|") {
+			pattern = "(?:" + pattern + ")"
+		}
+
+		patterns[p.name] = "^" + pattern + "$"
+	}
+
+	return patterns
+}
+
+// WildcardPathParameters returns names of catch-all path parameters, e.g. "path" of "/files/{path...}",
+// or WildcardParameter of "/static/*".
+func WildcardPathParameters(pathPattern string) []string {
+	var names []string
+
+	for _, p := range findPathParameters(pathPattern) {
+		if p.wildcard {
+			names = append(names, p.name)
+		}
+	}
+
+	return names
+}
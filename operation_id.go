@@ -0,0 +1,43 @@
+package openapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// OperationIDResolver derives ID of an operation that has none.
+type OperationIDResolver func(oc OperationContext) string
+
+// OperationIDFromMethodPath is an OperationIDResolver that joins lower case method with camel case path elements,
+// e.g. "getUsersId" for "GET /users/{id}".
+func OperationIDFromMethodPath(oc OperationContext) string {
+	return methodPathOperationID(oc.Method(), oc.PathPattern())
+}
+
+func methodPathOperationID(method, pathPattern string) string {
+	return camelOperationID(method, strings.FieldsFunc(pathPattern, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+}
+
+// OperationIDFromPath creates an OperationIDResolver that joins lower case method with camel case resource names,
+// e.g. "getUsersOrders" for "GET /api/v2/users/{id}/orders" with openapi.RESTPathParser("/api").
+func OperationIDFromPath(parse PathParser) OperationIDResolver {
+	return func(oc OperationContext) string {
+		return camelOperationID(oc.Method(), parse(oc.PathPattern()))
+	}
+}
+
+func camelOperationID(method string, elements []string) string {
+	id := strings.ToLower(method)
+
+	for _, e := range elements {
+		for _, word := range strings.FieldsFunc(e, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return id
+}
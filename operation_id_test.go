@@ -0,0 +1,42 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestOperationIDFromMethodPath(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.DefaultOperationID = openapi.OperationIDFromMethodPath
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users/{id}/order-items")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		ID int `path:"id"`
+	}{})
+	require.NoError(t, r.AddOperation(oc))
+	assert.Equal(t, "getUsersIdOrderItems", oc.ID())
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/users")
+	require.NoError(t, err)
+
+	oc.SetID("createUser")
+	require.NoError(t, r.AddOperation(oc))
+	assert.Equal(t, "createUser", oc.ID())
+}
+
+func TestOperationIDFromPath(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.DefaultOperationID = openapi.OperationIDFromPath(openapi.RESTPathParser("/api"))
+
+	oc, err := r.NewOperationContext(http.MethodDelete, "/api/v2/users/{id}/orders")
+	require.NoError(t, err)
+
+	assert.Equal(t, "deleteUsersOrders", r.DefaultOperationID(oc))
+}
@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
 	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
 )
 
 func TestContentUnit_options(t *testing.T) {
@@ -16,3 +19,320 @@ func TestContentUnit_options(t *testing.T) {
 	assert.Equal(t, "text/csv", cu.ContentType)
 	assert.Equal(t, http.StatusConflict, cu.HTTPStatus)
 }
+
+func TestSanitizeMethodPath(t *testing.T) {
+	method, path, params, err := openapi.SanitizeMethodPath("GET", "/users/{id:[0-9]{3}}/files/{name}.{ext:json|csv}")
+	require.NoError(t, err)
+
+	assert.Equal(t, "get", method)
+	assert.Equal(t, "/users/{id}/files/{name}.{ext}", path)
+	assert.Equal(t, []string{"id", "name", "ext"}, params)
+
+	_, _, _, err = openapi.SanitizeMethodPath("FETCH", "/")
+	assert.EqualError(t, err, "unexpected http method: fetch")
+}
+
+func TestPathParameterPatterns(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"id":  "^[0-9]{3}$",
+		"ext": "^(?:json|csv)$",
+	}, openapi.PathParameterPatterns("/users/{id:^[0-9]{3}$}/files/{name}.{ext:json|csv}"))
+
+	assert.Nil(t, openapi.PathParameterPatterns("/users/{id}"))
+}
+
+func TestWildcardPathParameters(t *testing.T) {
+	_, path, params, err := openapi.SanitizeMethodPath(http.MethodGet, "/files/{path...}")
+	require.NoError(t, err)
+	assert.Equal(t, "/files/{path}", path)
+	assert.Equal(t, []string{"path"}, params)
+	assert.Equal(t, []string{"path"}, openapi.WildcardPathParameters("/files/{path...}"))
+
+	_, path, params, err = openapi.SanitizeMethodPath(http.MethodGet, "/static/{version}/*")
+	require.NoError(t, err)
+	assert.Equal(t, "/static/{version}/{wildcard}", path)
+	assert.Equal(t, []string{"version", openapi.WildcardParameter}, params)
+	assert.Equal(t, []string{openapi.WildcardParameter}, openapi.WildcardPathParameters("/static/{version}/*"))
+
+	assert.Nil(t, openapi.WildcardPathParameters("/static/{version}"))
+}
+
+func TestRedirectResponse(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/docs")
+	require.NoError(t, err)
+	openapi.RedirectResponse(http.StatusFound, "URL of latest documentation.").Apply(oc)
+
+	rr, ok := oc.(openapi.RedirectResponder)
+	require.True(t, ok)
+	rr.AddRedirectResponse(http.StatusSeeOther, "")
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"302":{
+		  "description":"Found",
+		  "headers":{
+			"Location":{
+			  "style":"simple","description":"URL of latest documentation.","required":true,
+			  "schema":{"description":"URL of latest documentation.","format":"uri-reference","type":"string"}
+			}
+		  }
+		},
+		"303":{
+		  "description":"See Other",
+		  "headers":{
+			"Location":{"style":"simple","required":true,"schema":{"format":"uri-reference","type":"string"}}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/docs"].Get)
+}
+
+func TestProblemResponse(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type validationError struct {
+		Field string `json:"field"`
+	}
+
+	type invalidParams struct {
+		Type          string            `json:"type" enum:"https://example.com/probs/invalid-params"`
+		InvalidParams []validationError `json:"invalidParams"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/users")
+	require.NoError(t, err)
+	openapi.ProblemResponse(http.StatusInternalServerError).Apply(oc)
+
+	pr, ok := oc.(openapi.ProblemResponder)
+	require.True(t, ok)
+	pr.AddProblemResponse(http.StatusBadRequest, invalidParams{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/users":{
+		  "post":{
+			"responses":{
+			  "400":{
+				"description":"Bad Request",
+				"content":{
+				  "application/problem+json":{
+					"schema":{
+					  "properties":{
+						"detail":{"description":"Explanation of problem occurrence.","type":"string"},
+						"instance":{
+						  "description":"URI reference that identifies problem occurrence.",
+						  "format":"uri-reference","type":"string"
+						},
+						"invalidParams":{
+						  "items":{"$ref":"#/components/schemas/OpenapiGoTestValidationError"},
+						  "type":["array","null"]
+						},
+						"status":{
+						  "description":"HTTP status code of problem occurrence.",
+						  "maximum":599,"minimum":100,"type":"integer"
+						},
+						"title":{"description":"Short summary of problem type.","type":"string"},
+						"type":{"enum":["https://example.com/probs/invalid-params"],"type":"string"}
+					  },
+					  "type":"object"
+					}
+				  }
+				}
+			  },
+			  "500":{
+				"description":"Internal Server Error",
+				"content":{
+				  "application/problem+json":{"schema":{"$ref":"#/components/schemas/OpenapiGoProblemDetails"}}
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "OpenapiGoProblemDetails":{
+			"properties":{
+			  "detail":{"description":"Explanation of problem occurrence.","type":"string"},
+			  "instance":{
+				"description":"URI reference that identifies problem occurrence.",
+				"format":"uri-reference","type":"string"
+			  },
+			  "status":{
+				"description":"HTTP status code of problem occurrence.",
+				"maximum":599,"minimum":100,"type":"integer"
+			  },
+			  "title":{"description":"Short summary of problem type.","type":"string"},
+			  "type":{
+				"default":"about:blank","description":"URI reference that identifies problem type.",
+				"format":"uri-reference","type":"string"
+			  }
+			},
+			"type":"object"
+		  },
+		  "OpenapiGoTestValidationError":{"properties":{"field":{"type":"string"}},"type":"object"}
+		}
+	  }
+	}`, r.Spec)
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/search")
+	require.NoError(t, err)
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	openapi.RateLimitHeaders("").Apply(oc)
+
+	rl, ok := oc.(openapi.RateLimitHeadersAdder)
+	require.True(t, ok)
+	rl.AddRateLimitHeaders(openapi.RateLimitDraft, http.StatusTooManyRequests)
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"204":{
+		  "description":"No Content",
+		  "headers":{
+			"X-RateLimit-Limit":{
+			  "style":"simple","description":"Maximum number of requests in time window.",
+			  "schema":{"description":"Maximum number of requests in time window.","minimum":0,"type":"integer"}
+			},
+			"X-RateLimit-Remaining":{
+			  "style":"simple","description":"Number of requests left in current time window.",
+			  "schema":{"description":"Number of requests left in current time window.","minimum":0,"type":"integer"}
+			},
+			"X-RateLimit-Reset":{
+			  "style":"simple","description":"Unix time in seconds when quota is restored.",
+			  "schema":{"description":"Unix time in seconds when quota is restored.","minimum":0,"type":"integer"}
+			}
+		  }
+		},
+		"429":{
+		  "description":"Too Many Requests",
+		  "headers":{
+			"RateLimit-Limit":{
+			  "style":"simple","description":"Maximum number of requests in time window.",
+			  "schema":{"description":"Maximum number of requests in time window.","minimum":0,"type":"integer"}
+			},
+			"RateLimit-Remaining":{
+			  "style":"simple","description":"Number of requests left in current time window.",
+			  "schema":{"description":"Number of requests left in current time window.","minimum":0,"type":"integer"}
+			},
+			"RateLimit-Reset":{
+			  "style":"simple","description":"Number of seconds until quota is restored.",
+			  "schema":{"description":"Number of seconds until quota is restored.","minimum":0,"type":"integer"}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/search"].Get)
+}
+
+func TestConditionalRequests(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	type doc struct {
+		Title string `json:"title"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/docs/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+	}{})
+	oc.AddRespStructure(doc{})
+	openapi.ConditionalRequests().Apply(oc)
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPut, "/docs/{id}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID string `path:"id"`
+		doc
+	}{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	openapi.ConditionalRequests().Apply(oc)
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+		"get":{
+		  "parameters":[
+			{"name":"id","in":"path","required":true,"schema":{"type":"string"}},
+			{
+			  "name":"If-None-Match","in":"header",
+			  "description":"Entity tags of representations that client has, or * to match any.",
+			  "schema":{
+				"description":"Entity tags of representations that client has, or * to match any.",
+				"type":"string"
+			  }
+			}
+		  ],
+		  "responses":{
+			"200":{
+			  "description":"OK",
+			  "headers":{
+				"ETag":{
+				  "style":"simple",
+				  "description":"Entity tag of current representation of resource.",
+				  "schema":{"description":"Entity tag of current representation of resource.","type":"string"}
+				}
+			  },
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiGoTestDoc"}}}
+			},
+			"304":{
+			  "description":"Not Modified",
+			  "headers":{
+				"ETag":{
+				  "style":"simple",
+				  "description":"Entity tag of current representation of resource.",
+				  "schema":{"description":"Entity tag of current representation of resource.","type":"string"}
+				}
+			  }
+			}
+		  }
+		},
+		"put":{
+		  "parameters":[
+			{"name":"id","in":"path","required":true,"schema":{"type":"string"}},
+			{
+			  "name":"If-Match","in":"header",
+			  "description":"Entity tags that current representation must match, or * to match any.",
+			  "schema":{
+				"description":"Entity tags that current representation must match, or * to match any.",
+				"type":"string"
+			  }
+			},
+			{
+			  "name":"If-None-Match","in":"header",
+			  "description":"Entity tags of representations that client has, or * to match any.",
+			  "schema":{
+				"description":"Entity tags of representations that client has, or * to match any.",
+				"type":"string"
+			  }
+			}
+		  ],
+		  "requestBody":{
+			"content":{"application/json":{"schema":{"properties":{"title":{"type":"string"}},"type":"object"}}}
+		  },
+		  "responses":{
+			"204":{
+			  "description":"No Content",
+			  "headers":{
+				"ETag":{
+				  "style":"simple",
+				  "description":"Entity tag of current representation of resource.",
+				  "schema":{"description":"Entity tag of current representation of resource.","type":"string"}
+				}
+			  }
+			},
+			"412":{"description":"Precondition Failed"}
+		  }
+		}
+	  }`, r.Spec.Paths.MapOfPathItemValues["/docs/{id}"])
+}
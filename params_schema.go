@@ -0,0 +1,125 @@
+package openapi
+
+import "sort"
+
+// queryParamsRules declares dependencies between query parameters.
+type queryParamsRules struct {
+	dependentRequired map[string][]string
+	oneOf             [][]string
+	exclusive         [][]string
+}
+
+// WithDependentQueryParams is a ContentUnit option, it declares that query parameter
+// can only be used together with required query parameters, e.g. "sort_dir" requires "sort".
+func WithDependentQueryParams(param string, required ...string) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		r := cu.ensureQueryParamsRules()
+
+		if r.dependentRequired == nil {
+			r.dependentRequired = make(map[string][]string)
+		}
+
+		r.dependentRequired[param] = append(r.dependentRequired[param], required...)
+	}
+}
+
+// WithOneOfQueryParams is a ContentUnit option, it declares that exactly one of query parameters
+// must be used, e.g. either "cursor" or "page".
+func WithOneOfQueryParams(params ...string) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		r := cu.ensureQueryParamsRules()
+		r.oneOf = append(r.oneOf, params)
+	}
+}
+
+// WithExclusiveQueryParams is a ContentUnit option, it declares that at most one of query parameters
+// can be used, all of them can be omitted.
+func WithExclusiveQueryParams(params ...string) func(cu *ContentUnit) {
+	return func(cu *ContentUnit) {
+		r := cu.ensureQueryParamsRules()
+		r.exclusive = append(r.exclusive, params)
+	}
+}
+
+func (c *ContentUnit) ensureQueryParamsRules() *queryParamsRules {
+	if c.queryParamsRules == nil {
+		c.queryParamsRules = &queryParamsRules{}
+	}
+
+	return c.queryParamsRules
+}
+
+// QueryParamsSchema returns JSON Schema of query parameters object with declared dependencies
+// and sorted names of parameters that are referenced by dependencies, schema is nil if there are no dependencies.
+//
+// Such schema is exposed as "x-parameters-schema" operation extension, because dependencies
+// between parameters can not be expressed with schemas of individual parameters.
+func (c ContentUnit) QueryParamsSchema() (schema map[string]interface{}, params []string) {
+	r := c.queryParamsRules
+	if r == nil {
+		return nil, nil
+	}
+
+	names := map[string]bool{}
+	schema = map[string]interface{}{"type": "object"}
+
+	if len(r.dependentRequired) > 0 {
+		dr := make(map[string]interface{}, len(r.dependentRequired))
+
+		for param, required := range r.dependentRequired {
+			names[param] = true
+
+			for _, name := range required {
+				names[name] = true
+			}
+
+			dr[param] = required
+		}
+
+		schema["dependentRequired"] = dr
+	}
+
+	var groups [][]interface{}
+
+	for _, group := range r.oneOf {
+		groups = append(groups, requiredEach(group, names))
+	}
+
+	for _, group := range r.exclusive {
+		none := map[string]interface{}{"not": map[string]interface{}{"anyOf": requiredEach(group, names)}}
+		groups = append(groups, append(requiredEach(group, names), none))
+	}
+
+	switch len(groups) {
+	case 0:
+	case 1:
+		schema["oneOf"] = groups[0]
+	default:
+		allOf := make([]interface{}, 0, len(groups))
+		for _, g := range groups {
+			allOf = append(allOf, map[string]interface{}{"oneOf": g})
+		}
+
+		schema["allOf"] = allOf
+	}
+
+	for name := range names {
+		params = append(params, name)
+	}
+
+	sort.Strings(params)
+
+	return schema, params
+}
+
+func requiredEach(params []string, names map[string]bool) []interface{} {
+	res := make([]interface{}, 0, len(params))
+
+	for _, name := range params {
+		names[name] = true
+
+		res = append(res, map[string]interface{}{"required": []string{name}})
+	}
+
+	return res
+}
@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PathParser extracts resource names from templated URL path.
+type PathParser func(pathPattern string) []string
+
+var versionSegment = regexp.MustCompile(`^[vV][0-9]+([a-z]+[0-9]*)?$`)
+
+// RESTPathParser creates a PathParser for REST-style paths.
+//
+// Path parameters, version segments (e.g. "v2", "v1beta1") and the longest matching prefix of skipPrefixes
+// are skipped, "/api/v2/users/{id}/orders" gives "users", "orders" with "/api" in skipPrefixes.
+func RESTPathParser(skipPrefixes ...string) PathParser {
+	return func(pathPattern string) []string {
+		return resourceNames(trimLongestPrefix(pathPattern, skipPrefixes), "/")
+	}
+}
+
+// RPCPathParser creates a PathParser for RPC-style paths, where method names are separated by dots or slashes.
+//
+// It skips the same elements as RESTPathParser, "/rpc/users.list" gives "users", "list" with "/rpc" in skipPrefixes,
+// "/twirp/acme.v1.Users/List" gives "acme", "Users", "List" with "/twirp".
+func RPCPathParser(skipPrefixes ...string) PathParser {
+	return func(pathPattern string) []string {
+		return resourceNames(trimLongestPrefix(pathPattern, skipPrefixes), "/.")
+	}
+}
+
+// TagsFromPath creates a TagResolver that uses the first resource name of operation path.
+//
+//	r.DefaultTags = openapi.TagsFromPath(openapi.RESTPathParser("/api"))
+func TagsFromPath(parse PathParser) TagResolver {
+	return func(oc OperationContext) []string {
+		if names := parse(oc.PathPattern()); len(names) > 0 {
+			return names[:1]
+		}
+
+		return nil
+	}
+}
+
+func trimLongestPrefix(pathPattern string, prefixes []string) string {
+	matched := ""
+
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+
+		if len(prefix) <= len(matched) {
+			continue
+		}
+
+		// Prefix has to match whole path segments.
+		if pathPattern == prefix || strings.HasPrefix(pathPattern, prefix+"/") {
+			matched = prefix
+		}
+	}
+
+	return pathPattern[len(matched):]
+}
+
+func resourceNames(pathPattern string, separators string) []string {
+	var names []string
+
+	for _, s := range strings.FieldsFunc(pathPattern, func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	}) {
+		if strings.HasPrefix(s, "{") || versionSegment.MatchString(s) {
+			continue
+		}
+
+		names = append(names, s)
+	}
+
+	return names
+}
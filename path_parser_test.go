@@ -0,0 +1,43 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestRESTPathParser(t *testing.T) {
+	parse := openapi.RESTPathParser("/api", "/internal/api")
+
+	assert.Equal(t, []string{"users", "orders"}, parse("/api/v2/users/{id}/orders"))
+	assert.Equal(t, []string{"users"}, parse("/internal/api/v1beta1/users/{id}"))
+	assert.Equal(t, []string{"apis", "users"}, parse("/apis/users"))
+	assert.Nil(t, parse("/api/v1"))
+}
+
+func TestRPCPathParser(t *testing.T) {
+	parse := openapi.RPCPathParser("/rpc", "/twirp")
+
+	assert.Equal(t, []string{"users", "list"}, parse("/rpc/users.list"))
+	assert.Equal(t, []string{"acme", "Users", "List"}, parse("/twirp/acme.v1.Users/List"))
+}
+
+func TestTagsFromPath(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.DefaultTags = openapi.TagsFromPath(openapi.RESTPathParser("/api"))
+
+	for path, tags := range map[string][]string{
+		"/api/v2/users/list": {"users"},
+		"/api/v1":            nil,
+	} {
+		oc, err := r.NewOperationContext(http.MethodGet, path)
+		require.NoError(t, err)
+
+		require.NoError(t, r.AddOperation(oc))
+		assert.Equal(t, tags, oc.Tags(), path)
+	}
+}
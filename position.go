@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position describes location of a value in a marshaled document.
+//
+// Line and Column are 1-based, Column counts runes.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// PositionIndex maps JSON pointers (RFC 6901) to positions in a marshaled document.
+//
+// Object members are indexed by position of their keys, array items by position of their values,
+// root document has an empty pointer.
+type PositionIndex map[string]Position
+
+// Lookup returns position of a JSON pointer or of its closest indexed parent.
+//
+// Pointer can be in URI fragment form, e.g. "#/paths/~1users".
+func (pi PositionIndex) Lookup(pointer string) (Position, bool) {
+	pointer = strings.TrimPrefix(pointer, "#")
+
+	for {
+		if p, ok := pi[pointer]; ok {
+			return p, true
+		}
+
+		if pointer == "" {
+			return Position{}, false
+		}
+
+		if i := strings.LastIndex(pointer, "/"); i > 0 {
+			pointer = pointer[:i]
+		} else {
+			pointer = ""
+		}
+	}
+}
+
+// PointerToken escapes JSON pointer reference token.
+func PointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// UnescapePointerToken unescapes JSON pointer reference token.
+func UnescapePointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+// ResolvePointer finds a value by JSON pointer in a decoded JSON document.
+//
+// Pointer can be in URI fragment form, e.g. "#/paths/~1users".
+func ResolvePointer(doc interface{}, pointer string) (interface{}, error) {
+	pointer = strings.TrimPrefix(pointer, "#")
+
+	if pointer == "" {
+		return doc, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	v := doc
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = UnescapePointerToken(token)
+
+		switch x := v.(type) {
+		case map[string]interface{}:
+			item, ok := x[token]
+			if !ok {
+				return nil, fmt.Errorf("missing %q", token)
+			}
+
+			v = item
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, fmt.Errorf("invalid index %q", token)
+			}
+
+			v = x[i]
+		default:
+			return nil, fmt.Errorf("can not resolve %q in %T", token, v)
+		}
+	}
+
+	return v, nil
+}
+
+// IndexJSON builds PositionIndex of JSON document.
+func IndexJSON(data []byte) (PositionIndex, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("invalid JSON document")
+	}
+
+	ji := jsonIndexer{
+		data:  data,
+		line:  1,
+		col:   1,
+		index: PositionIndex{},
+	}
+
+	ji.skipSpace()
+	ji.value("", ji.position())
+
+	return ji.index, nil
+}
+
+type jsonIndexer struct {
+	data  []byte
+	pos   int
+	line  int
+	col   int
+	index PositionIndex
+}
+
+func (ji *jsonIndexer) position() Position {
+	return Position{Line: ji.line, Column: ji.col}
+}
+
+func (ji *jsonIndexer) advance() {
+	c := ji.data[ji.pos]
+	ji.pos++
+
+	switch {
+	case c == '\n':
+		ji.line++
+		ji.col = 1
+	case utf8.RuneStart(c):
+		ji.col++
+	}
+}
+
+func (ji *jsonIndexer) skipSpace() {
+	for ji.pos < len(ji.data) {
+		switch ji.data[ji.pos] {
+		case ' ', '\t', '\r', '\n':
+			ji.advance()
+		default:
+			return
+		}
+	}
+}
+
+// value indexes a value at current position, input is expected to be valid JSON.
+func (ji *jsonIndexer) value(pointer string, pos Position) {
+	ji.index[pointer] = pos
+
+	switch ji.data[ji.pos] {
+	case '{':
+		ji.advance()
+		ji.skipSpace()
+
+		for ji.data[ji.pos] != '}' {
+			keyPos := ji.position()
+			key := ji.str()
+
+			ji.skipSpace()
+			ji.advance() // Colon.
+			ji.skipSpace()
+			ji.value(pointer+"/"+PointerToken(key), keyPos)
+			ji.skipSpace()
+
+			if ji.data[ji.pos] == ',' {
+				ji.advance()
+				ji.skipSpace()
+			}
+		}
+
+		ji.advance()
+	case '[':
+		ji.advance()
+		ji.skipSpace()
+
+		for i := 0; ji.data[ji.pos] != ']'; i++ {
+			ji.value(pointer+"/"+strconv.Itoa(i), ji.position())
+			ji.skipSpace()
+
+			if ji.data[ji.pos] == ',' {
+				ji.advance()
+				ji.skipSpace()
+			}
+		}
+
+		ji.advance()
+	case '"':
+		ji.str()
+	default:
+		for ji.pos < len(ji.data) && !strings.ContainsRune(" \t\r\n,]}", rune(ji.data[ji.pos])) {
+			ji.advance()
+		}
+	}
+}
+
+func (ji *jsonIndexer) str() string {
+	start := ji.pos
+
+	ji.advance() // Opening quote.
+
+	for ji.data[ji.pos] != '"' {
+		if ji.data[ji.pos] == '\\' {
+			ji.advance()
+		}
+
+		ji.advance()
+	}
+
+	ji.advance() // Closing quote.
+
+	var s string
+
+	_ = json.Unmarshal(ji.data[start:ji.pos], &s) //nolint:errcheck // Document is already validated.
+
+	return s
+}
+
+// IndexYAML builds PositionIndex of YAML document.
+func IndexYAML(data []byte) (PositionIndex, error) {
+	var n yaml.Node
+
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+
+	index := PositionIndex{}
+
+	if len(n.Content) > 0 {
+		root := n.Content[0]
+		indexYAMLNode(root, "", Position{Line: root.Line, Column: root.Column}, index)
+	}
+
+	return index, nil
+}
+
+func indexYAMLNode(n *yaml.Node, pointer string, pos Position, index PositionIndex) {
+	index[pointer] = pos
+
+	switch n.Kind { //nolint:exhaustive // Scalars and aliases have no children.
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			indexYAMLNode(n.Content[i+1], pointer+"/"+PointerToken(k.Value), Position{Line: k.Line, Column: k.Column}, index)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			indexYAMLNode(c, pointer+"/"+strconv.Itoa(i), Position{Line: c.Line, Column: c.Column}, index)
+		}
+	}
+}
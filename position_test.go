@@ -0,0 +1,71 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+)
+
+func TestIndexJSON(t *testing.T) {
+	index, err := openapi.IndexJSON([]byte(`{
+  "openapi": "3.1.0",
+  "paths": {
+    "/users/{id}": {"get": {"tags": ["a", "b"]}}
+  }
+}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, openapi.Position{Line: 1, Column: 1}, index[""])
+	assert.Equal(t, openapi.Position{Line: 2, Column: 3}, index["/openapi"])
+	assert.Equal(t, openapi.Position{Line: 4, Column: 5}, index["/paths/~1users~1{id}"])
+	assert.Equal(t, openapi.Position{Line: 4, Column: 43}, index["/paths/~1users~1{id}/get/tags/1"])
+
+	pos, found := index.Lookup("#/paths/~1users~1{id}/get/responses")
+	assert.True(t, found)
+	assert.Equal(t, openapi.Position{Line: 4, Column: 21}, pos)
+
+	_, err = openapi.IndexJSON([]byte(`{"foo":`))
+	assert.Error(t, err)
+}
+
+func TestIndexYAML(t *testing.T) {
+	index, err := openapi.IndexYAML([]byte(`openapi: 3.1.0
+paths:
+  /users/{id}:
+    get:
+      tags:
+        - a
+        - b
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, openapi.Position{Line: 1, Column: 1}, index["/openapi"])
+	assert.Equal(t, openapi.Position{Line: 3, Column: 3}, index["/paths/~1users~1{id}"])
+	assert.Equal(t, openapi.Position{Line: 7, Column: 11}, index["/paths/~1users~1{id}/get/tags/1"])
+}
+
+func TestResolvePointer(t *testing.T) {
+	doc := map[string]interface{}{
+		"":    "empty",
+		"a/b": []interface{}{"x", map[string]interface{}{"m~n": 1}},
+	}
+
+	for pointer, expected := range map[string]interface{}{
+		"":              doc,
+		"#":             doc,
+		"/":             "empty",
+		"/a~1b/0":       "x",
+		"#/a~1b/1/m~0n": 1,
+	} {
+		v, err := openapi.ResolvePointer(doc, pointer)
+		require.NoError(t, err, pointer)
+		assert.Equal(t, expected, v, pointer)
+	}
+
+	for _, pointer := range []string{"a~1b", "/missing", "/a~1b/2", "/a~1b/x", "/a~1b/0/foo"} {
+		_, err := openapi.ResolvePointer(doc, pointer)
+		assert.Error(t, err, pointer)
+	}
+}
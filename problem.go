@@ -0,0 +1,108 @@
+package openapi
+
+import "reflect"
+
+// ProblemDetails is an error response body of RFC 7807, it is used with "application/problem+json" media type.
+//
+// Extension members can be added to schema with ProblemResponse.
+type ProblemDetails struct {
+	Type     string `json:"type,omitempty" format:"uri-reference" default:"about:blank" description:"URI reference that identifies problem type."`
+	Title    string `json:"title,omitempty" description:"Short summary of problem type."`
+	Status   int    `json:"status,omitempty" minimum:"100" maximum:"599" description:"HTTP status code of problem occurrence."`
+	Detail   string `json:"detail,omitempty" description:"Explanation of problem occurrence."`
+	Instance string `json:"instance,omitempty" format:"uri-reference" description:"URI reference that identifies problem occurrence."`
+}
+
+// ProblemJSONMediaType is a media type of ProblemDetails.
+const ProblemJSONMediaType = "application/problem+json"
+
+// ProblemResponder is implemented by operation contexts that add problem responses,
+// operation contexts of openapi3 and openapi31 reflectors implement it.
+type ProblemResponder interface {
+	// AddProblemResponse adds "application/problem+json" response, see ProblemResponse.
+	AddProblemResponse(httpStatus int, extensions ...interface{})
+}
+
+// ProblemResponse returns a Trait of "application/problem+json" response with ProblemDetails schema,
+// fields of extensions structures are added as extension members.
+//
+//	openapi.ProblemResponse(http.StatusBadRequest, invalidParams{}).Apply(oc)
+//
+// Operation contexts that implement ProblemResponder add it with oc.AddProblemResponse.
+func ProblemResponse(httpStatus int, extensions ...interface{}) Trait {
+	var problem interface{} = ProblemDetails{}
+
+	if len(extensions) > 0 {
+		fields := structFields(reflect.TypeOf(problem))
+		index := make(map[string]int, len(fields))
+
+		for i, f := range fields {
+			index[f.Name] = i
+		}
+
+		for _, ext := range extensions {
+			t := reflect.TypeOf(ext)
+			for t != nil && t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+
+			if t == nil || t.Kind() != reflect.Struct {
+				continue
+			}
+
+			// Extension can override standard member, e.g. to declare enum of problem types.
+			for _, f := range structFields(t) {
+				if i, ok := index[f.Name]; ok {
+					fields[i] = f
+
+					continue
+				}
+
+				index[f.Name] = len(fields)
+				fields = append(fields, f)
+			}
+		}
+
+		// Structure is created dynamically to have extension members next to standard members.
+		problem = reflect.New(reflect.StructOf(fields)).Elem().Interface()
+	}
+
+	return Trait{
+		Responses: []TraitResponse{
+			{Structure: problem, Options: []ContentOption{WithHTTPStatus(httpStatus), WithContentType(ProblemJSONMediaType)}},
+		},
+	}
+}
+
+// structFields returns exported fields of structure, fields of untagged embedded structures are flattened.
+func structFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			et := f.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+
+			if et.Kind() == reflect.Struct {
+				fields = append(fields, structFields(et)...)
+
+				continue
+			}
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		f.Index = nil
+		f.Offset = 0
+		f.Anonymous = false
+		fields = append(fields, f)
+	}
+
+	return fields
+}
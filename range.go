@@ -0,0 +1,42 @@
+package openapi
+
+import "net/http"
+
+type rangeRequestHeaders struct {
+	Range   string `header:"Range" example:"bytes=0-1023" description:"Requests only a part of content, e.g. to resume download."`
+	IfRange string `header:"If-Range" description:"Applies Range only if ETag or Last-Modified date matches current content."`
+}
+
+type partialContentHeaders struct {
+	ContentRange string `header:"Content-Range" example:"bytes 0-1023/146515" description:"Position of partial content in full content."`
+	AcceptRanges string `header:"Accept-Ranges" enum:"bytes" description:"Unit of ranges supported by server."`
+}
+
+type rangeNotSatisfiableHeaders struct {
+	ContentRange string `header:"Content-Range" example:"bytes */146515" description:"Size of full content."`
+}
+
+// RangeRequests returns a Trait of file-serving operation that supports range requests.
+//
+// It documents optional "Range" and "If-Range" request headers, "206 Partial Content" response
+// of contentType with "Content-Range" and "Accept-Ranges" headers and "416 Range Not Satisfiable" response.
+//
+//	openapi.RangeRequests("video/mp4").Apply(oc)
+func RangeRequests(contentType string) Trait {
+	return Trait{
+		Request: []interface{}{rangeRequestHeaders{}},
+		Responses: []TraitResponse{
+			{
+				Structure: partialContentHeaders{},
+				Options: []ContentOption{
+					WithHTTPStatus(http.StatusPartialContent), WithContentType(contentType),
+					func(cu *ContentUnit) { cu.Format = "binary" },
+				},
+			},
+			{
+				Structure: rangeNotSatisfiableHeaders{},
+				Options:   []ContentOption{WithHTTPStatus(http.StatusRequestedRangeNotSatisfiable)},
+			},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package openapi
+
+// RateLimitStyle is a prefix of rate limit header names.
+type RateLimitStyle string
+
+// RateLimitStyle values enumeration.
+const (
+	// RateLimitX is a style of widely used "X-RateLimit-Limit", "X-RateLimit-Remaining" and "X-RateLimit-Reset"
+	// headers, reset is a Unix time in seconds.
+	RateLimitX = RateLimitStyle("X-RateLimit-")
+
+	// RateLimitDraft is a style of "RateLimit-Limit", "RateLimit-Remaining" and "RateLimit-Reset" headers of IETF draft,
+	// reset is a number of seconds until quota is restored.
+	//
+	// See https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/.
+	RateLimitDraft = RateLimitStyle("RateLimit-")
+)
+
+// ResetDescription describes value of reset header.
+func (s RateLimitStyle) ResetDescription() string {
+	if s == RateLimitDraft {
+		return "Number of seconds until quota is restored."
+	}
+
+	return "Unix time in seconds when quota is restored."
+}
+
+// RateLimitHeadersAdder is implemented by operation contexts that add rate limit headers,
+// operation contexts of openapi3 and openapi31 reflectors implement it.
+type RateLimitHeadersAdder interface {
+	// AddRateLimitHeaders adds integer rate limit headers to responses, see RateLimitHeaders.
+	AddRateLimitHeaders(style RateLimitStyle, httpStatuses ...int)
+}
+
+// RateLimitHeaders returns a Trait that adds integer rate limit headers with names of style (default RateLimitX)
+// to responses with HTTP statuses, or to every response added before if statuses are omitted.
+//
+//	openapi.RateLimitHeaders(openapi.RateLimitDraft, http.StatusOK, http.StatusTooManyRequests).Apply(oc)
+//
+// Operation contexts that implement RateLimitHeadersAdder add them with oc.AddRateLimitHeaders.
+func RateLimitHeaders(style RateLimitStyle, httpStatuses ...int) Trait {
+	prefix := string(style)
+	if prefix == "" {
+		prefix = string(RateLimitX)
+	}
+
+	return responseHeaders(headersStructure(
+		headerField{
+			Tag:    `header:"` + prefix + `Limit" minimum:"0" description:"Maximum number of requests in time window."`,
+			Sample: 0,
+		},
+		headerField{
+			Tag:    `header:"` + prefix + `Remaining" minimum:"0" description:"Number of requests left in current time window."`,
+			Sample: 0,
+		},
+		headerField{
+			Tag:    `header:"` + prefix + `Reset" minimum:"0" description:"` + style.ResetDescription() + `"`,
+			Sample: 0,
+		},
+	), httpStatuses...)
+}
@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// SplitReadWriteOnly is a jsonschema.ReflectContext option to reflect distinct request and response schemas
+// of types that have fields tagged with `readOnly:"true"` or `writeOnly:"true"`.
+//
+// Read-only fields are omitted in requests and write-only fields are omitted in responses.
+// Definitions of such types (and of types that contain them) get "Request" or "Response" suffix,
+// other definitions are shared.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.SplitReadWriteOnly)
+func SplitReadWriteOnly(rc *jsonschema.ReflectContext) {
+	jsonschema.InterceptDefName(func(t reflect.Type, defaultDefName string) string {
+		oc, ok := OperationCtx(rc)
+		if !ok || !hasReadWriteOnly(t) {
+			return defaultDefName
+		}
+
+		if oc.IsProcessingResponse() {
+			return defaultDefName + "Response"
+		}
+
+		return defaultDefName + "Request"
+	})(rc)
+
+	jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		oc, ok := OperationCtx(rc)
+		if !ok {
+			return nil
+		}
+
+		readOnly, writeOnly := readWriteOnly(params.Field)
+
+		if params.Processed {
+			if writeOnly && !oc.IsProcessingResponse() {
+				params.PropertySchema.WithExtraPropertiesItem("writeOnly", true)
+			}
+
+			return nil
+		}
+
+		if (readOnly && !oc.IsProcessingResponse()) || (writeOnly && oc.IsProcessingResponse()) {
+			if params.ParentSchema != nil {
+				required := params.ParentSchema.Required[:0]
+
+				for _, name := range params.ParentSchema.Required {
+					if name != params.Name {
+						required = append(required, name)
+					}
+				}
+
+				params.ParentSchema.Required = required
+			}
+
+			return jsonschema.ErrSkipProperty
+		}
+
+		return nil
+	})(rc)
+}
+
+func readWriteOnly(field reflect.StructField) (readOnly, writeOnly bool) {
+	// Invalid tag values are treated as false.
+	_ = refl.ReadBoolTag(field.Tag, "readOnly", &readOnly)   //nolint:errcheck
+	_ = refl.ReadBoolTag(field.Tag, "writeOnly", &writeOnly) //nolint:errcheck
+
+	return readOnly, writeOnly
+}
+
+var readWriteOnlyTypes sync.Map // map[reflect.Type]bool
+
+// hasReadWriteOnly checks if type contains read-only or write-only fields, including nested types.
+func hasReadWriteOnly(t reflect.Type) bool {
+	if v, ok := readWriteOnlyTypes.Load(t); ok {
+		return v.(bool) //nolint:errcheck // Type is guaranteed.
+	}
+
+	res := checkReadWriteOnly(t, map[reflect.Type]bool{})
+	readWriteOnlyTypes.Store(t, res)
+
+	return res
+}
+
+func checkReadWriteOnly(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if readOnly, writeOnly := readWriteOnly(field); readOnly || writeOnly {
+			return true
+		}
+
+		if checkReadWriteOnly(field.Type, visited) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package openapi
+
+import "strconv"
+
+// RedirectResponder is implemented by operation contexts that add redirect responses,
+// operation contexts of openapi3 and openapi31 reflectors implement it.
+type RedirectResponder interface {
+	// AddRedirectResponse adds 3XX response with required "Location" header, see RedirectResponse.
+	AddRedirectResponse(httpStatus int, locationDescription string)
+}
+
+// RedirectResponse returns a Trait of 3XX response with required "Location" header.
+//
+//	openapi.RedirectResponse(http.StatusFound, "URL of latest documentation.").Apply(oc)
+//
+// Operation contexts that implement RedirectResponder add it with oc.AddRedirectResponse.
+func RedirectResponse(httpStatus int, locationDescription string) Trait {
+	tag := `header:"Location" required:"true" format:"uri-reference"`
+	if locationDescription != "" {
+		tag += ` description:` + strconv.Quote(locationDescription)
+	}
+
+	return Trait{
+		Responses: []TraitResponse{
+			{Structure: headersStructure(headerField{Tag: tag}), Options: []ContentOption{WithHTTPStatus(httpStatus)}},
+		},
+	}
+}
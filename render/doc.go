@@ -0,0 +1,2 @@
+// Package render creates single-page Markdown or HTML reference of a spec for publishing in repositories and wikis.
+package render
@@ -0,0 +1,25 @@
+package render
+
+import (
+	"embed"
+	"html/template"
+	"io"
+)
+
+//go:embed templates/reference.html
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.New("reference.html").
+	Funcs(template.FuncMap{"anchor": schemaAnchor}).
+	ParseFS(templates, "templates/reference.html"))
+
+// HTML writes single-page reference of spec, e.g. *openapi31.Spec, as a standalone HTML document
+// with operations grouped by tags and appendix of schema components.
+func HTML(w io.Writer, spec interface{}) error {
+	d, err := newDocument(spec)
+	if err != nil {
+		return err
+	}
+
+	return htmlTemplate.Execute(w, d)
+}
@@ -0,0 +1,176 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Markdown writes single-page reference of spec, e.g. *openapi31.Spec, with operations grouped by tags
+// and appendix of schema components.
+func Markdown(w io.Writer, spec interface{}) error {
+	d, err := newDocument(spec)
+	if err != nil {
+		return err
+	}
+
+	m := markdown{}
+
+	m.line("# " + d.Title)
+
+	if d.Version != "" {
+		m.para("Version: " + d.Version)
+	}
+
+	m.para(d.Description)
+
+	for _, g := range d.Groups {
+		m.line("## " + g.Tag)
+		m.para(g.Description)
+
+		for _, o := range g.Operations {
+			m.operation(o)
+		}
+	}
+
+	if len(d.Schemas) > 0 {
+		m.line("## Schemas")
+
+		for _, s := range d.Schemas {
+			m.schema(s)
+		}
+	}
+
+	_, err = io.WriteString(w, strings.TrimRight(m.b.String(), "\n")+"\n")
+
+	return err
+}
+
+type markdown struct {
+	b strings.Builder
+}
+
+func (m *markdown) line(s string) {
+	m.b.WriteString(s)
+	m.b.WriteString("\n\n")
+}
+
+func (m *markdown) para(s string) {
+	if s != "" {
+		m.line(s)
+	}
+}
+
+func (m *markdown) row(cells ...string) {
+	for i, c := range cells {
+		cells[i] = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(c)
+	}
+
+	m.b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+}
+
+func (m *markdown) table(header ...string) {
+	m.row(header...)
+
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+
+	m.b.WriteString("|" + strings.Join(sep, "|") + "|\n")
+}
+
+func (m *markdown) typeRef(t typeRef) string {
+	if t.Schema == "" {
+		return "`" + t.Prefix + "`"
+	}
+
+	link := fmt.Sprintf("[%s](#%s)", t.Schema, schemaAnchor(t.Schema))
+	if t.Prefix == "" {
+		return link
+	}
+
+	return "`" + t.Prefix + "`" + link
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func (m *markdown) operation(o operation) {
+	heading := "### `" + o.Method + " " + o.Path + "`"
+	if o.Deprecated {
+		heading += " (deprecated)"
+	}
+
+	m.line(heading)
+	m.para(o.Summary)
+	m.para(o.Description)
+
+	if o.ID != "" {
+		m.para("Operation ID: `" + o.ID + "`")
+	}
+
+	if len(o.Parameters) > 0 {
+		m.line("**Parameters**")
+		m.table("Name", "In", "Type", "Required", "Description")
+
+		for _, p := range o.Parameters {
+			m.row("`"+p.Name+"`", p.In, m.typeRef(p.Type), yesNo(p.Required), p.Description)
+		}
+
+		m.b.WriteString("\n")
+	}
+
+	if len(o.RequestBody) > 0 {
+		m.line("**Request body**")
+		m.table("Content type", "Type")
+
+		for _, c := range o.RequestBody {
+			m.row(c.MediaType, m.typeRef(c.Type))
+		}
+
+		m.b.WriteString("\n")
+	}
+
+	if len(o.Responses) > 0 {
+		m.line("**Responses**")
+		m.table("Status", "Description", "Content type", "Type")
+
+		for _, r := range o.Responses {
+			if len(r.Content) == 0 {
+				m.row(r.Status, r.Description, "", "")
+			}
+
+			for _, c := range r.Content {
+				m.row(r.Status, r.Description, c.MediaType, m.typeRef(c.Type))
+			}
+		}
+
+		m.b.WriteString("\n")
+	}
+}
+
+func (m *markdown) schema(s schema) {
+	m.line(`<a id="` + s.Anchor + `"></a>`)
+	m.line("### " + s.Name)
+	m.para(s.Description)
+
+	if len(s.Properties) == 0 {
+		m.para("Type: " + m.typeRef(s.Type))
+
+		return
+	}
+
+	m.table("Property", "Type", "Required", "Description")
+
+	for _, p := range s.Properties {
+		m.row("`"+p.Name+"`", m.typeRef(p.Type), yesNo(p.Required), p.Description)
+	}
+
+	m.b.WriteString("\n")
+}
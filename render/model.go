@@ -0,0 +1,332 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// otherOperations is a group title of operations without tags.
+const otherOperations = "Other operations"
+
+type document struct {
+	Title       string
+	Version     string
+	Description string
+	Groups      []group
+	Schemas     []schema
+}
+
+type group struct {
+	Tag         string
+	Description string
+	Operations  []operation
+}
+
+type operation struct {
+	Method      string
+	Path        string
+	ID          string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Parameters  []parameter
+	RequestBody []content
+	Responses   []response
+}
+
+type parameter struct {
+	Name        string
+	In          string
+	Type        typeRef
+	Required    bool
+	Description string
+}
+
+type response struct {
+	Status      string
+	Description string
+	Content     []content
+}
+
+type content struct {
+	MediaType string
+	Type      typeRef
+}
+
+type schema struct {
+	Name        string
+	Anchor      string
+	Type        typeRef
+	Description string
+	Properties  []property
+}
+
+type property struct {
+	Name        string
+	Type        typeRef
+	Required    bool
+	Description string
+}
+
+// typeRef describes type of schema, Schema is a name of referenced component that follows Prefix.
+type typeRef struct {
+	Prefix string
+	Schema string
+}
+
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// newDocument decodes spec into renderable document.
+func newDocument(spec interface{}) (document, error) {
+	var d document
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return d, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return d, err
+	}
+
+	info := object(doc["info"])
+	d.Title = str(info["title"])
+	d.Version = str(info["version"])
+	d.Description = str(info["description"])
+
+	groups := map[string]*group{}
+
+	var order []string
+
+	addGroup := func(tag, description string) *group {
+		if g, ok := groups[tag]; ok {
+			return g
+		}
+
+		groups[tag] = &group{Tag: tag, Description: description}
+		order = append(order, tag)
+
+		return groups[tag]
+	}
+
+	if tags, ok := doc["tags"].([]interface{}); ok {
+		for _, t := range tags {
+			t := object(t)
+			addGroup(str(t["name"]), str(t["description"]))
+		}
+	}
+
+	var other []operation
+
+	paths := object(doc["paths"])
+
+	for _, path := range internal.SortedKeys(paths) {
+		pathItem := object(paths[path])
+
+		for _, method := range operationMethods {
+			op, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			o := newOperation(doc, method, path, pathItem, op)
+
+			tags, _ := op["tags"].([]interface{}) //nolint:errcheck // Missing tags are empty.
+			if len(tags) == 0 {
+				other = append(other, o)
+
+				continue
+			}
+
+			for _, tag := range tags {
+				g := addGroup(str(tag), "")
+				g.Operations = append(g.Operations, o)
+			}
+		}
+	}
+
+	for _, tag := range order {
+		if g := groups[tag]; len(g.Operations) > 0 {
+			d.Groups = append(d.Groups, *g)
+		}
+	}
+
+	if len(other) > 0 {
+		d.Groups = append(d.Groups, group{Tag: otherOperations, Operations: other})
+	}
+
+	schemas := object(object(doc["components"])["schemas"])
+
+	for _, name := range internal.SortedKeys(schemas) {
+		s := object(schemas[name])
+
+		d.Schemas = append(d.Schemas, schema{
+			Name:        name,
+			Anchor:      schemaAnchor(name),
+			Type:        newTypeRef(s),
+			Description: str(s["description"]),
+			Properties:  properties(s),
+		})
+	}
+
+	return d, nil
+}
+
+func newOperation(doc map[string]interface{}, method, path string, pathItem, op map[string]interface{}) operation {
+	o := operation{
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		ID:          str(op["operationId"]),
+		Summary:     str(op["summary"]),
+		Description: str(op["description"]),
+		Deprecated:  op["deprecated"] == true,
+	}
+
+	for _, params := range []interface{}{pathItem["parameters"], op["parameters"]} {
+		params, _ := params.([]interface{}) //nolint:errcheck // Missing parameters are empty.
+
+		for _, p := range params {
+			p := internal.ResolveLocalRef(doc, p)
+
+			o.Parameters = append(o.Parameters, parameter{
+				Name:        str(p["name"]),
+				In:          str(p["in"]),
+				Type:        newTypeRef(object(p["schema"])),
+				Required:    p["required"] == true,
+				Description: str(p["description"]),
+			})
+		}
+	}
+
+	o.RequestBody = contents(internal.ResolveLocalRef(doc, op["requestBody"]))
+
+	responses := object(op["responses"])
+
+	for _, status := range internal.SortedKeys(responses) {
+		resp := internal.ResolveLocalRef(doc, responses[status])
+
+		o.Responses = append(o.Responses, response{
+			Status:      status,
+			Description: str(resp["description"]),
+			Content:     contents(resp),
+		})
+	}
+
+	return o
+}
+
+func contents(unit map[string]interface{}) []content {
+	c := object(unit["content"])
+	res := make([]content, 0, len(c))
+
+	for _, mt := range internal.SortedKeys(c) {
+		res = append(res, content{MediaType: mt, Type: newTypeRef(object(object(c[mt])["schema"]))})
+	}
+
+	return res
+}
+
+func properties(s map[string]interface{}) []property {
+	required := map[string]bool{}
+
+	if req, ok := s["required"].([]interface{}); ok {
+		for _, name := range req {
+			required[str(name)] = true
+		}
+	}
+
+	props := object(s["properties"])
+	res := make([]property, 0, len(props))
+
+	for _, name := range internal.SortedKeys(props) {
+		p := object(props[name])
+
+		res = append(res, property{
+			Name:        name,
+			Type:        newTypeRef(p),
+			Required:    required[name],
+			Description: str(p["description"]),
+		})
+	}
+
+	return res
+}
+
+const componentsSchemas = "#/components/schemas/"
+
+// newTypeRef describes schema type, e.g. "[]" + User, "string (date-time)", "string, one of: a, b".
+func newTypeRef(s map[string]interface{}) typeRef {
+	if ref, ok := s["$ref"].(string); ok && strings.HasPrefix(ref, componentsSchemas) {
+		return typeRef{Schema: openapi.UnescapePointerToken(ref[len(componentsSchemas):])}
+	}
+
+	var t string
+
+	switch v := s["type"].(type) {
+	case string:
+		t = v
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			types = append(types, str(item))
+		}
+
+		t = strings.Join(types, "|")
+	}
+
+	if items, ok := s["items"].(map[string]interface{}); ok {
+		tr := newTypeRef(items)
+		tr.Prefix = "[]" + tr.Prefix
+
+		return tr
+	}
+
+	for _, kw := range []string{"oneOf", "anyOf", "allOf"} {
+		if variants, ok := s[kw].([]interface{}); ok && len(variants) > 0 && t == "" {
+			t = kw
+		}
+	}
+
+	if t == "" {
+		t = "any"
+	}
+
+	if f := str(s["format"]); f != "" {
+		t += " (" + f + ")"
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			values = append(values, fmt.Sprint(v))
+		}
+
+		t += ", one of: " + strings.Join(values, ", ")
+	}
+
+	return typeRef{Prefix: t}
+}
+
+// schemaAnchor returns HTML id of schema component.
+func schemaAnchor(name string) string {
+	return "schema-" + strings.ToLower(strings.NewReplacer("/", "-", " ", "-").Replace(name))
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{}) //nolint:errcheck // Other values are empty.
+
+	return m
+}
+
+func str(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,79 @@
+package render_test
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/render"
+)
+
+type petReq struct {
+	ID int `path:"id" description:"Pet ID."`
+}
+
+type pet struct {
+	Name    string    `json:"name" required:"true" description:"Name of | pet."`
+	Kind    string    `json:"kind" enum:"cat,dog"`
+	Born    time.Time `json:"born"`
+	Friends []pet     `json:"friends"`
+}
+
+func newSpec(t *testing.T) *openapi31.Spec {
+	t.Helper()
+
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Pet store").WithVersion("1.0").WithDescription("Pets API.")
+	r.Spec.WithTags(openapi31.Tag{Name: "Pets", Description: ptr("Pet management.")})
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets/{id}")
+	require.NoError(t, err)
+
+	oc.SetTags("Pets")
+	oc.SetID("getPet")
+	oc.SetSummary("Get pet.")
+	oc.AddReqStructure(petReq{})
+	oc.AddRespStructure(pet{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNotFound))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+
+	oc.SetIsDeprecated(true)
+	oc.AddReqStructure(pet{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	require.NoError(t, r.AddOperation(oc))
+
+	return r.Spec
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestMarkdown(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, render.Markdown(buf, newSpec(t)))
+
+	expected, err := os.ReadFile("testdata/reference.md")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestHTML(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, render.HTML(buf, newSpec(t)))
+
+	expected, err := os.ReadFile("testdata/reference.html")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), buf.String())
+}
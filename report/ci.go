@@ -0,0 +1,106 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteGitHubActions writes findings as GitHub Actions workflow commands to create annotations.
+//
+// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions.
+func WriteGitHubActions(w io.Writer, doc Document, findings []Finding) error {
+	for _, f := range findings {
+		command := "notice"
+
+		switch f.Severity {
+		case SeverityError:
+			command = "error"
+		case SeverityWarning:
+			command = "warning"
+		case SeverityInfo:
+		}
+
+		pos := doc.Position(f.Pointer)
+		message := f.Message
+
+		if f.Pointer != "" {
+			message += " (" + f.Pointer + ")"
+		}
+
+		if _, err := fmt.Fprintf(w, "::%s file=%s,line=%d,col=%d,title=%s::%s\n",
+			command,
+			escapeGitHubProperty(doc.Path),
+			pos.Line,
+			pos.Column,
+			escapeGitHubProperty(f.Rule),
+			escapeGitHubData(message),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeGitHubData(s))
+}
+
+type gitLabIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    gitLabIssueLocation `json:"location"`
+}
+
+type gitLabIssueLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// WriteGitLabCodeQuality writes findings as GitLab Code Quality report.
+//
+// See https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool.
+func WriteGitLabCodeQuality(w io.Writer, doc Document, findings []Finding) error {
+	issues := make([]gitLabIssue, 0, len(findings))
+
+	for _, f := range findings {
+		issue := gitLabIssue{
+			Description: f.Message,
+			CheckName:   f.Rule,
+			Severity:    "info",
+		}
+
+		switch f.Severity {
+		case SeverityError:
+			issue.Severity = "major"
+		case SeverityWarning:
+			issue.Severity = "minor"
+		case SeverityInfo:
+		}
+
+		h := sha256.Sum256([]byte(doc.Path + "\n" + f.Rule + "\n" + f.Pointer + "\n" + f.Message))
+		issue.Fingerprint = hex.EncodeToString(h[:16])
+
+		issue.Location.Path = doc.Path
+		issue.Location.Lines.Begin = doc.Position(f.Pointer).Line
+
+		issues = append(issues, issue)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+
+	return enc.Encode(issues)
+}
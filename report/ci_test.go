@@ -0,0 +1,63 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/report"
+)
+
+var (
+	doc = report.Document{
+		Path: "api/openapi.json",
+		Index: openapi.PositionIndex{
+			"/paths/~1users/get": {Line: 12, Column: 7},
+		},
+	}
+
+	findings = []report.Finding{
+		{
+			Rule:     "operation-summary",
+			Severity: report.SeverityWarning,
+			Message:  "Operation should have summary.",
+			Pointer:  "/paths/~1users/get/summary",
+		},
+		{
+			Rule:     "breaking: removed-operation",
+			Severity: report.SeverityError,
+			Message:  "Operation was removed,\nclients will break.",
+		},
+	}
+)
+
+func TestWriteGitHubActions(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	require.NoError(t, report.WriteGitHubActions(buf, doc, findings))
+	assert.Equal(t, "::warning file=api/openapi.json,line=12,col=7,title=operation-summary::"+
+		"Operation should have summary. (/paths/~1users/get/summary)\n"+
+		"::error file=api/openapi.json,line=1,col=1,title=breaking%3A removed-operation::"+
+		"Operation was removed,%0Aclients will break.\n", buf.String())
+}
+
+func TestWriteGitLabCodeQuality(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	require.NoError(t, report.WriteGitLabCodeQuality(buf, doc, findings))
+	assertjson.Equal(t, []byte(`[
+	  {
+		"description":"Operation should have summary.","check_name":"operation-summary",
+		"fingerprint":"<ignore-diff>","severity":"minor",
+		"location":{"path":"api/openapi.json","lines":{"begin":12}}
+	  },
+	  {
+		"description":"Operation was removed,\nclients will break.","check_name":"breaking: removed-operation",
+		"fingerprint":"<ignore-diff>","severity":"major",
+		"location":{"path":"api/openapi.json","lines":{"begin":1}}
+	  }
+	]`), buf.Bytes())
+}
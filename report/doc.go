@@ -0,0 +1,2 @@
+// Package report provides findings of spec analysis and their formatters for CI integrations.
+package report
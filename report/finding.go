@@ -0,0 +1,42 @@
+package report
+
+import "github.com/swaggest/openapi-go"
+
+// Severity describes importance of a finding.
+type Severity string
+
+// Severity values enumeration.
+const (
+	SeverityError   = Severity("error")
+	SeverityWarning = Severity("warning")
+	SeverityInfo    = Severity("info")
+)
+
+// Finding describes an issue discovered by spec analysis (lint, diff, validation).
+type Finding struct {
+	// Rule is an identifier of a check that produced the finding.
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+
+	// Pointer is a JSON pointer to the affected value of the spec, e.g. "/paths/~1users/get".
+	Pointer string `json:"pointer,omitempty"`
+}
+
+// Document identifies analyzed spec file to position findings.
+type Document struct {
+	// Path is a file name relative to repository root.
+	Path string
+
+	// Index resolves JSON pointers to positions, optional.
+	Index openapi.PositionIndex
+}
+
+// Position returns position of a JSON pointer in the document, first line is used for unknown pointers.
+func (d Document) Position(pointer string) openapi.Position {
+	if pos, found := d.Index.Lookup(pointer); found {
+		return pos
+	}
+
+	return openapi.Position{Line: 1, Column: 1}
+}
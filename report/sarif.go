@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationURI string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID  string `json:"ruleId"`
+	Level   string `json:"level"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine   int `json:"startLine"`
+			StartColumn int `json:"startColumn"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteSARIF writes findings as SARIF 2.1.0 log for code scanning dashboards.
+//
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+func WriteSARIF(w io.Writer, doc Document, findings []Finding) error {
+	run := sarifRun{
+		Results: make([]sarifResult, 0, len(findings)),
+	}
+
+	run.Tool.Driver.Name = "openapi-go"
+	run.Tool.Driver.InformationURI = "https://github.com/swaggest/openapi-go"
+	run.Tool.Driver.Rules = []sarifRule{}
+
+	rules := map[string]bool{}
+
+	for _, f := range findings {
+		if !rules[f.Rule] {
+			rules[f.Rule] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.Rule})
+		}
+
+		res := sarifResult{
+			RuleID: f.Rule,
+			Level:  "note",
+		}
+
+		switch f.Severity {
+		case SeverityError:
+			res.Level = "error"
+		case SeverityWarning:
+			res.Level = "warning"
+		case SeverityInfo:
+		}
+
+		res.Message.Text = f.Message
+
+		pos := doc.Position(f.Pointer)
+		loc := sarifLocation{}
+		loc.PhysicalLocation.ArtifactLocation.URI = doc.Path
+		loc.PhysicalLocation.Region.StartLine = pos.Line
+		loc.PhysicalLocation.Region.StartColumn = pos.Column
+
+		if f.Pointer != "" {
+			loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.Pointer}}
+		}
+
+		res.Locations = []sarifLocation{loc}
+		run.Results = append(run.Results, res)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,56 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/report"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	require.NoError(t, report.WriteSARIF(buf, doc, findings))
+	assertjson.Equal(t, []byte(`{
+	  "$schema":"https://json.schemastore.org/sarif-2.1.0.json","version":"2.1.0",
+	  "runs":[
+		{
+		  "tool":{
+			"driver":{
+			  "name":"openapi-go","informationUri":"https://github.com/swaggest/openapi-go",
+			  "rules":[{"id":"operation-summary"},{"id":"breaking: removed-operation"}]
+			}
+		  },
+		  "results":[
+			{
+			  "ruleId":"operation-summary","level":"warning",
+			  "message":{"text":"Operation should have summary."},
+			  "locations":[
+				{
+				  "physicalLocation":{
+					"artifactLocation":{"uri":"api/openapi.json"},
+					"region":{"startLine":12,"startColumn":7}
+				  },
+				  "logicalLocations":[{"fullyQualifiedName":"/paths/~1users/get/summary"}]
+				}
+			  ]
+			},
+			{
+			  "ruleId":"breaking: removed-operation","level":"error",
+			  "message":{"text":"Operation was removed,\nclients will break."},
+			  "locations":[
+				{
+				  "physicalLocation":{
+					"artifactLocation":{"uri":"api/openapi.json"},
+					"region":{"startLine":1,"startColumn":1}
+				  }
+				}
+			  ]
+			}
+		  ]
+		}
+	  ]
+	}`), buf.Bytes())
+}
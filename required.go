@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// PointerOptional is a jsonschema.ReflectContext option to infer required properties from field types.
+//
+// Pointer fields are optional and value fields are required, unless field has explicit `required` tag
+// or `omitempty` in its property name tag.
+//
+// Add it to default options of reflector to enable the convention for all reflected schemas and parameters.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.PointerOptional)
+func PointerOptional(rc *jsonschema.ReflectContext) {
+	jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if params.Processed || params.ParentSchema == nil {
+			return nil
+		}
+
+		if _, ok := params.Field.Tag.Lookup("required"); ok {
+			return nil
+		}
+
+		for _, tag := range append([]string{rc.PropertyNameTag}, rc.PropertyNameAdditionalTags...) {
+			if strings.Contains(params.Field.Tag.Get(tag), ",omitempty") {
+				return nil
+			}
+		}
+
+		if params.Field.Type.Kind() == reflect.Ptr {
+			return nil
+		}
+
+		params.ParentSchema.Required = append(params.ParentSchema.Required, params.Name)
+
+		return nil
+	})(rc)
+}
+
+// RequiredByDefault is a jsonschema.ReflectContext option to make all properties of request and response bodies
+// required, unless field has `omitempty` in its property name tag or `required:"false"` tag.
+//
+// Parameters and headers are not affected.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.RequiredByDefault)
+func RequiredByDefault(rc *jsonschema.ReflectContext) {
+	jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if params.Processed || params.ParentSchema == nil {
+			return nil
+		}
+
+		if _, ok := params.Field.Tag.Lookup("required"); ok {
+			return nil
+		}
+
+		isBody := false
+
+		for _, tag := range append([]string{rc.PropertyNameTag}, rc.PropertyNameAdditionalTags...) {
+			if strings.Contains(params.Field.Tag.Get(tag), ",omitempty") {
+				return nil
+			}
+
+			if tag == "json" {
+				isBody = true
+			}
+		}
+
+		if isBody {
+			params.ParentSchema.Required = append(params.ParentSchema.Required, params.Name)
+		}
+
+		return nil
+	})(rc)
+}
@@ -14,3 +14,16 @@ type SpecSchema interface {
 	SetAPIKeySecurity(securityName string, fieldName string, fieldIn In, description string)
 	SetHTTPBearerTokenSecurity(securityName string, format string, description string)
 }
+
+// SpecExtender is implemented by specs that support vendor extensions,
+// specs of openapi3 and openapi31 reflectors implement it.
+type SpecExtender interface {
+	// SetExtension sets vendor extension of the document, "x-" prefix is added to key if it is missing.
+	SetExtension(key string, value interface{})
+	// SetInfoExtension sets vendor extension of document info, e.g. "x-logo".
+	SetInfoExtension(key string, value interface{})
+	// SetTagExtension sets vendor extension of a tag, tag is added if it is missing.
+	SetTagExtension(tag string, key string, value interface{})
+	// SetSecuritySchemeExtension sets vendor extension of a security scheme, missing scheme is ignored.
+	SetSecuritySchemeExtension(securityName string, key string, value interface{})
+}
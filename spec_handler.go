@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpecDocument is a spec that can be marshaled to JSON and YAML, e.g. *openapi31.Spec.
+type SpecDocument interface {
+	MarshalJSON() ([]byte, error)
+	MarshalYAML() ([]byte, error)
+}
+
+// SpecHandlerOption configures SpecHandler.
+type SpecHandlerOption func(h *SpecHandler)
+
+// WithGzip is a SpecHandlerOption, it compresses responses for clients that accept gzip encoding.
+func WithGzip() SpecHandlerOption {
+	return func(h *SpecHandler) {
+		h.gzip = true
+	}
+}
+
+// SpecHandler serves spec document as JSON or YAML, format is selected by extension of request path
+// (".json", ".yaml", ".yml") or by Accept header, JSON is served by default.
+//
+// Responses have ETag and Last-Modified headers, conditional and HEAD requests are supported.
+// Document is marshaled on every request, so that later changes of spec are served.
+//
+//	http.Handle("/docs/openapi.json", r.Spec.Handler())
+//	http.Handle("/docs/openapi.yaml", r.Spec.Handler())
+type SpecHandler struct {
+	doc  SpecDocument
+	gzip bool
+
+	mu       sync.Mutex
+	hash     string
+	modified time.Time
+}
+
+// NewSpecHandler creates handler of spec document.
+func NewSpecHandler(doc SpecDocument, options ...SpecHandlerOption) *SpecHandler {
+	h := &SpecHandler{doc: doc}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *SpecHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	yamlFormat := h.acceptsYAML(r)
+
+	vary := "Accept"
+	if h.gzip {
+		vary += ", Accept-Encoding"
+	}
+
+	w.Header().Set("Vary", vary)
+
+	body, err := h.doc.MarshalJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	modified := h.lastModified(body)
+	contentType := "application/json"
+
+	if yamlFormat {
+		if body, err = h.doc.MarshalYAML(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		contentType = "application/yaml"
+	}
+
+	sum := sha256.Sum256(body)
+	etag := hex.EncodeToString(sum[:8])
+
+	if h.gzip && acceptsGzip(r) {
+		buf := bytes.NewBuffer(nil)
+		gw := gzip.NewWriter(buf)
+
+		if _, err := gw.Write(body); err == nil {
+			err = gw.Close()
+		}
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		body = buf.Bytes()
+		etag += "-gzip"
+
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", strconv.Quote(etag))
+
+	http.ServeContent(w, r, "", modified, bytes.NewReader(body))
+}
+
+// lastModified returns time of the first response with current JSON document.
+func (h *SpecHandler) lastModified(jsonDoc []byte) time.Time {
+	sum := sha256.Sum256(jsonDoc)
+	hash := hex.EncodeToString(sum[:])
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if hash != h.hash {
+		h.hash = hash
+		h.modified = time.Now().UTC().Truncate(time.Second)
+	}
+
+	return h.modified
+}
+
+func (h *SpecHandler) acceptsYAML(r *http.Request) bool {
+	switch path.Ext(r.URL.Path) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+
+	best, bestQ := "", 0.0
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > bestQ && (mt == "application/json" || isYAMLMediaType(mt)) {
+			best, bestQ = mt, q
+		}
+	}
+
+	return isYAMLMediaType(best)
+}
+
+func isYAMLMediaType(mt string) bool {
+	return mt == "application/yaml" || mt == "application/x-yaml" || mt == "text/yaml" || mt == "text/x-yaml"
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		enc, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && enc == "gzip" && params["q"] != "0" {
+			return true
+		}
+	}
+
+	return false
+}
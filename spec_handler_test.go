@@ -0,0 +1,83 @@
+package openapi_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestSpecHandler_ServeHTTP(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.Spec.Info.WithTitle("Pets")
+
+	h := r.Spec.Handler(openapi.WithGzip())
+
+	get := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		return rw
+	}
+
+	rw := get("/openapi", nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept, Accept-Encoding", rw.Header().Get("Vary"))
+	assert.Equal(t, `{"openapi":"3.1.0","info":{"title":"Pets","version":""}}`, rw.Body.String())
+
+	etag := rw.Header().Get("ETag")
+	lastModified := rw.Header().Get("Last-Modified")
+
+	assert.NotEmpty(t, etag)
+	assert.NotEmpty(t, lastModified)
+
+	rw = get("/openapi", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Empty(t, rw.Body.String())
+
+	rw = get("/openapi", map[string]string{"If-Modified-Since": lastModified})
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+
+	rw = get("/openapi", map[string]string{"Accept": "application/json;q=0.5, application/yaml"})
+	assert.Equal(t, "application/yaml", rw.Header().Get("Content-Type"))
+	assert.Equal(t, "openapi: 3.1.0\ninfo:\n  title: Pets\n  version: \"\"\n", rw.Body.String())
+
+	rw = get("/openapi.yml", nil)
+	assert.Equal(t, "application/yaml", rw.Header().Get("Content-Type"))
+
+	rw = get("/openapi.json", map[string]string{"Accept": "application/yaml"})
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+	rw = get("/openapi.json", map[string]string{"Accept-Encoding": "gzip"})
+	assert.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+	assert.NotEqual(t, etag, rw.Header().Get("ETag"))
+
+	gr, err := gzip.NewReader(rw.Body)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, `{"openapi":"3.1.0","info":{"title":"Pets","version":""}}`, string(body))
+
+	r.Spec.Info.WithTitle("Pet store")
+
+	rw = get("/openapi", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.NotEqual(t, etag, rw.Header().Get("ETag"))
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/openapi", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+}
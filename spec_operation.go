@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+type specRequest struct {
+	IfNoneMatch string `header:"If-None-Match" description:"Entity tag of previously received document."`
+}
+
+type specHeaders struct {
+	CacheControl string `header:"Cache-Control" description:"Caching policy of the document."`
+	ETag         string `header:"ETag" description:"Entity tag of the document."`
+}
+
+type specNotModified struct {
+	ETag string `header:"ETag" description:"Entity tag of the document."`
+}
+
+// AddSpecOperation registers GET operation that serves the spec document itself, e.g. "/openapi.json".
+//
+// Document content type is inferred from path extension, "application/yaml" for ".yaml" and ".yml",
+// "application/json" otherwise. Conditional requests with If-None-Match and caching headers are documented.
+//
+// Options can customize operation context, e.g. to set tags or operation ID.
+func AddSpecOperation(r Reflector, pathPattern string, options ...func(oc OperationContext)) error {
+	oc, err := r.NewOperationContext(http.MethodGet, pathPattern)
+	if err != nil {
+		return err
+	}
+
+	contentType := "application/json"
+
+	switch strings.ToLower(path.Ext(pathPattern)) {
+	case ".yaml", ".yml":
+		contentType = "application/yaml"
+	}
+
+	oc.SetID("getOpenAPISpec")
+	oc.SetSummary("OpenAPI specification")
+	oc.SetDescription("Serves this OpenAPI document.")
+
+	oc.AddReqStructure(specRequest{})
+	oc.AddRespStructure(map[string]interface{}{}, WithContentType(contentType),
+		func(cu *ContentUnit) {
+			cu.Description = "OpenAPI document."
+		},
+	)
+	// Headers are added after body to keep them in the same response.
+	oc.AddRespStructure(specHeaders{})
+	oc.AddRespStructure(specNotModified{}, WithHTTPStatus(http.StatusNotModified))
+
+	for _, option := range options {
+		option(oc)
+	}
+
+	return r.AddOperation(oc)
+}
@@ -0,0 +1,54 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestAddSpecOperation(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	require.NoError(t, openapi.AddSpecOperation(r, "/openapi.yaml", func(oc openapi.OperationContext) {
+		oc.SetTags("Meta")
+	}))
+
+	assertjson.EqMarshal(t, `{
+	  "tags":["Meta"],"summary":"OpenAPI specification","description":"Serves this OpenAPI document.",
+	  "operationId":"getOpenAPISpec",
+	  "parameters":[
+		{
+		  "name":"If-None-Match","in":"header","description":"Entity tag of previously received document.",
+		  "schema":{"description":"Entity tag of previously received document.","type":"string"}
+		}
+	  ],
+	  "responses":{
+		"200":{
+		  "description":"OpenAPI document.",
+		  "headers":{
+			"Cache-Control":{
+			  "style":"simple","description":"Caching policy of the document.",
+			  "schema":{"description":"Caching policy of the document.","type":"string"}
+			},
+			"ETag":{
+			  "style":"simple","description":"Entity tag of the document.",
+			  "schema":{"description":"Entity tag of the document.","type":"string"}
+			}
+		  },
+		  "content":{"application/yaml":{"schema":{"additionalProperties":{},"type":"object"}}}
+		},
+		"304":{
+		  "description":"Not Modified",
+		  "headers":{
+			"ETag":{
+			  "style":"simple","description":"Entity tag of the document.",
+			  "schema":{"description":"Entity tag of the document.","type":"string"}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/openapi.yaml"].Get)
+}
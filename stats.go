@@ -0,0 +1,31 @@
+package openapi
+
+// ReflectorStats describes metrics of reflected components.
+type ReflectorStats struct {
+	// ComponentsByPackage is a number of schema components contributed by each Go package.
+	//
+	// It helps to find dependency types leaking into the public contract.
+	ComponentsByPackage map[string]int `json:"componentsByPackage"`
+}
+
+// SpecStats describes size and documentation coverage of a spec, it helps to track documentation quality over time.
+type SpecStats struct {
+	// Operations is a total number of operations.
+	Operations int `json:"operations"`
+
+	// OperationsByMethod is a number of operations by lowercase HTTP method.
+	OperationsByMethod map[string]int `json:"operationsByMethod"`
+
+	// Schemas is a number of schema components.
+	Schemas int `json:"schemas"`
+
+	// DescribedOperations is a percentage of operations with summary or description.
+	DescribedOperations float64 `json:"describedOperations"`
+
+	// ExampledOperations is a percentage of operations with examples in parameters, bodies or their schemas.
+	ExampledOperations float64 `json:"exampledOperations"`
+
+	// OrphanComponents are sorted local references of components that are not reachable from paths
+	// and other parts of spec, e.g. "#/components/schemas/Foo", security schemes are not accounted.
+	OrphanComponents []string `json:"orphanComponents,omitempty"`
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"regexp"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// StringFormatRule applies format and pattern to schemas of named string types with matching names.
+type StringFormatRule struct {
+	// Name is matched against Go type name, e.g. regexp.MustCompile(`Email$`).
+	Name *regexp.Regexp
+
+	Format  string
+	Pattern string
+}
+
+// StringFormats is a jsonschema.ReflectContext option to apply format and pattern to schemas of named string types
+// by conventions of type names.
+//
+// First matching rule is applied, format and pattern that are already defined for the type are not changed.
+//
+//	r.DefaultOptions = append(r.DefaultOptions, openapi.StringFormats(
+//		openapi.StringFormatRule{Name: regexp.MustCompile(`Email$`), Format: "email"},
+//		openapi.StringFormatRule{Name: regexp.MustCompile(`Slug$`), Pattern: "^[a-z0-9]+(?:-[a-z0-9]+)*$"},
+//	))
+func StringFormats(rules ...StringFormatRule) func(rc *jsonschema.ReflectContext) {
+	return jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || params.Schema.ReflectType == nil || !params.Schema.HasType(jsonschema.String) {
+			return false, nil
+		}
+
+		t := refl.DeepIndirect(params.Schema.ReflectType)
+		if t.Name() == "" || t.PkgPath() == "" {
+			return false, nil
+		}
+
+		for _, rule := range rules {
+			if rule.Name == nil || !rule.Name.MatchString(t.Name()) {
+				continue
+			}
+
+			if rule.Format != "" && params.Schema.Format == nil {
+				params.Schema.WithFormat(rule.Format)
+			}
+
+			if rule.Pattern != "" && params.Schema.Pattern == nil {
+				params.Schema.WithPattern(rule.Pattern)
+			}
+
+			break
+		}
+
+		return false, nil
+	})
+}
@@ -0,0 +1,22 @@
+package openapi
+
+import "time"
+
+// SunsetExtension is a vendor extension of operation with RFC 3339 date after which it is no longer available.
+const SunsetExtension = "x-sunset"
+
+// Sunset returns a Trait that marks operation deprecated with a date after which it is no longer available,
+// operation receives "x-sunset" vendor extension and every response receives "Deprecation" and "Sunset" headers
+// when operation is added to reflector.
+//
+// See https://www.rfc-editor.org/rfc/rfc8594.
+//
+//	openapi.Sunset(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).Apply(oc)
+func Sunset(sunset time.Time) Trait {
+	return Trait{
+		Extensions: map[string]interface{}{SunsetExtension: sunset.UTC().Format(time.RFC3339)},
+		Setup: func(oc OperationContext) {
+			oc.SetIsDeprecated(true)
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/refl"
+)
+
+// TagResolver derives default tags for an operation that has no tags.
+type TagResolver func(oc OperationContext) []string
+
+// TagsFromPackage is a TagResolver that uses last element of Go package path of request or response structures.
+//
+// Packages of request structures are checked first, the first named type defines the tag.
+func TagsFromPackage(oc OperationContext) []string {
+	for _, cu := range append(oc.Request(), oc.Response()...) {
+		if cu.Structure == nil {
+			continue
+		}
+
+		t := refl.DeepIndirect(reflect.TypeOf(cu.Structure))
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = refl.DeepIndirect(t.Elem())
+		}
+
+		if t.PkgPath() != "" {
+			return []string{path.Base(t.PkgPath())}
+		}
+	}
+
+	return nil
+}
+
+// TagsFromPathPrefix creates a TagResolver that uses tag of the longest matching prefix of operation path.
+//
+//	r.DefaultTags = openapi.TagsFromPathPrefix(map[string]string{"/users": "Users", "/admin": "Admin"})
+func TagsFromPathPrefix(tags map[string]string) TagResolver {
+	return func(oc OperationContext) []string {
+		matched := ""
+
+		for prefix := range tags {
+			if strings.HasPrefix(oc.PathPattern(), prefix) && len(prefix) > len(matched) {
+				matched = prefix
+			}
+		}
+
+		if matched == "" {
+			return nil
+		}
+
+		return []string{tags[matched]}
+	}
+}
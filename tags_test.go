@@ -0,0 +1,56 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestTagsFromPackage(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi31.NewReflector()
+	r.DefaultTags = openapi.TagsFromPackage
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.AddRespStructure([]user{})
+	require.NoError(t, r.AddOperation(oc))
+	assert.Equal(t, []string{"openapi-go_test"}, oc.Tags())
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/health")
+	require.NoError(t, err)
+
+	oc.SetTags("Meta")
+	oc.AddRespStructure([]user{})
+	require.NoError(t, r.AddOperation(oc))
+	assert.Equal(t, []string{"Meta"}, oc.Tags())
+}
+
+func TestTagsFromPathPrefix(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.DefaultTags = openapi.TagsFromPathPrefix(map[string]string{
+		"/admin":       "Admin",
+		"/admin/users": "Users",
+	})
+
+	for path, tags := range map[string][]string{
+		"/admin/users/list": {"Users"},
+		"/admin/settings":   {"Admin"},
+		"/status":           nil,
+	} {
+		oc, err := r.NewOperationContext(http.MethodGet, path)
+		require.NoError(t, err)
+
+		oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+		require.NoError(t, r.AddOperation(oc))
+		assert.Equal(t, tags, oc.Tags(), path)
+	}
+}
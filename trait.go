@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Trait is a reusable bundle of operation settings that can be applied to many operations.
+//
+//	paginated := openapi.Trait{Request: []interface{}{pageParams{}}}
+//	authRequired := openapi.Trait{
+//		Security: map[string][]string{"bearerAuth": nil},
+//		Responses: []openapi.TraitResponse{
+//			{Structure: errResp{}, Options: []openapi.ContentOption{openapi.WithHTTPStatus(401)}},
+//		},
+//	}
+//
+//	paginated.Apply(oc)
+//	authRequired.Apply(oc)
+type Trait struct {
+	// Request structures declare shared parameters, e.g. pagination query parameters.
+	Request []interface{}
+
+	// Responses declare shared responses, e.g. authentication errors.
+	Responses []TraitResponse
+
+	// Security contains scopes by names of security schemes, each scheme is added as an alternative requirement.
+	Security map[string][]string
+
+	// Tags are appended to operation tags.
+	Tags []string
+
+	// Extensions are vendor extensions of operation, "x-" prefix is added to keys if it is missing,
+	// they are ignored if operation context does not implement OperationExtender.
+	Extensions map[string]interface{}
+
+	// Setup is called after other settings are applied, it can change operation context depending on its state,
+	// e.g. to add headers to responses that were added before.
+	Setup func(oc OperationContext)
+}
+
+// TraitApplier is implemented by operation contexts that apply traits,
+// operation contexts of openapi3 and openapi31 reflectors implement it.
+//
+// Trait.Apply can be used with any operation context.
+type TraitApplier interface {
+	// ApplyTrait adds settings of reusable traits to operation.
+	ApplyTrait(traits ...Trait)
+}
+
+// TraitResponse is a response structure of Trait.
+type TraitResponse struct {
+	Structure interface{}
+	Options   []ContentOption
+}
+
+// Apply adds trait settings to operation context.
+func (t Trait) Apply(oc OperationContext) {
+	for _, s := range t.Request {
+		oc.AddReqStructure(s)
+	}
+
+	for _, r := range t.Responses {
+		oc.AddRespStructure(r.Structure, r.Options...)
+	}
+
+	names := make([]string, 0, len(t.Security))
+	for name := range t.Security {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		oc.AddSecurity(name, t.Security[name]...)
+	}
+
+	if len(t.Tags) > 0 {
+		oc.SetTags(append(oc.Tags(), t.Tags...)...)
+	}
+
+	if e, ok := oc.(OperationExtender); ok {
+		for key, value := range t.Extensions {
+			e.SetExtension(key, value)
+		}
+	}
+
+	if t.Setup != nil {
+		t.Setup(oc)
+	}
+}
+
+// responseHeaders returns a Trait that adds headers structure to responses with HTTP statuses,
+// or to every response added before if statuses are omitted.
+func responseHeaders(headers interface{}, httpStatuses ...int) Trait {
+	if len(httpStatuses) > 0 {
+		t := Trait{}
+
+		for _, status := range httpStatuses {
+			t.Responses = append(t.Responses, TraitResponse{
+				Structure: headers,
+				Options:   []ContentOption{WithHTTPStatus(status)},
+			})
+		}
+
+		return t
+	}
+
+	return Trait{Setup: func(oc OperationContext) {
+		seen := map[int]bool{}
+
+		for _, cu := range oc.Response() {
+			status := cu.HTTPStatus
+			if status == 0 && !cu.IsDefault {
+				status = http.StatusOK
+			}
+
+			if seen[status] {
+				continue
+			}
+
+			seen[status] = true
+			isDefault := cu.IsDefault
+
+			oc.AddRespStructure(headers, func(h *ContentUnit) {
+				h.HTTPStatus = status
+				h.IsDefault = isDefault
+			})
+		}
+	}}
+}
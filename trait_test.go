@@ -0,0 +1,166 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestTrait_Apply(t *testing.T) {
+	type pageParams struct {
+		Limit  int `query:"limit"`
+		Offset int `query:"offset"`
+	}
+
+	type errResp struct {
+		Message string `json:"message"`
+	}
+
+	paginated := openapi.Trait{Request: []interface{}{pageParams{}}}
+	authRequired := openapi.Trait{
+		Security: map[string][]string{"bearerAuth": nil},
+		Responses: []openapi.TraitResponse{
+			{Structure: errResp{}, Options: []openapi.ContentOption{openapi.WithHTTPStatus(http.StatusUnauthorized)}},
+		},
+		Tags:       []string{"Private"},
+		Extensions: map[string]interface{}{"rate-limit": 100},
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/users")
+	require.NoError(t, err)
+
+	oc.SetTags("Users")
+	ta, ok := oc.(openapi.TraitApplier)
+	require.True(t, ok)
+
+	ta.ApplyTrait(paginated, authRequired)
+	oc.AddRespStructure([]string{})
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "tags":["Users","Private"],
+	  "parameters":[
+		{"name":"limit","in":"query","schema":{"type":"integer"}},
+		{"name":"offset","in":"query","schema":{"type":"integer"}}
+	  ],
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "content":{"application/json":{"schema":{"items":{"type":"string"},"type":"array"}}}
+		},
+		"401":{
+		  "description":"Unauthorized",
+		  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiGoTestErrResp"}}}
+		}
+	  },
+	  "security":[{"bearerAuth":[]}],
+	  "x-rate-limit":100
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].Get)
+}
+
+func TestExpectContinue(t *testing.T) {
+	type upload struct {
+		Name string `json:"name"`
+	}
+
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/files")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(upload{})
+	openapi.ExpectContinue().Apply(oc)
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{
+		  "name":"Expect","in":"header",
+		  "description":"Asks server to confirm that request body is acceptable before it is sent.",
+		  "schema":{
+			"enum":["100-continue"],"type":"string",
+			"description":"Asks server to confirm that request body is acceptable before it is sent."
+		  }
+		}
+	  ],
+	  "requestBody":{
+		"content":{"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiGoTestUpload"}}}
+	  },
+	  "responses":{"417":{"description":"Expectation Failed"}},
+	  "x-interim-responses":{"100":{"description":"Continue, request body can be sent."}}
+	}`, r.Spec.Paths.MapOfPathItemValues["/files"].Put)
+}
+
+func TestRangeRequests(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/files/{name}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		Name string `path:"name"`
+	}{})
+	oc.AddRespStructure(nil, openapi.WithContentType("video/mp4"))
+	openapi.RangeRequests("video/mp4").Apply(oc)
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+	  "parameters":[
+		{"name":"name","in":"path","required":true,"schema":{"type":"string"}},
+		{
+		  "name":"Range","in":"header",
+		  "description":"Requests only a part of content, e.g. to resume download.",
+		  "schema":{
+			"description":"Requests only a part of content, e.g. to resume download.",
+			"examples":["bytes=0-1023"],"type":"string"
+		  }
+		},
+		{
+		  "name":"If-Range","in":"header",
+		  "description":"Applies Range only if ETag or Last-Modified date matches current content.",
+		  "schema":{
+			"description":"Applies Range only if ETag or Last-Modified date matches current content.",
+			"type":"string"
+		  }
+		}
+	  ],
+	  "responses":{
+		"200":{"description":"OK","content":{"video/mp4":{"schema":{"type":"string"}}}},
+		"206":{
+		  "description":"Partial Content",
+		  "headers":{
+			"Accept-Ranges":{
+			  "style":"simple","description":"Unit of ranges supported by server.",
+			  "schema":{"description":"Unit of ranges supported by server.","enum":["bytes"],"type":"string"}
+			},
+			"Content-Range":{
+			  "style":"simple","description":"Position of partial content in full content.",
+			  "schema":{
+				"description":"Position of partial content in full content.",
+				"examples":["bytes 0-1023/146515"],"type":"string"
+			  }
+			}
+		  },
+		  "content":{"video/mp4":{"schema":{"type":"string","format":"binary"}}}
+		},
+		"416":{
+		  "description":"Requested Range Not Satisfiable",
+		  "headers":{
+			"Content-Range":{
+			  "style":"simple","description":"Size of full content.",
+			  "schema":{"description":"Size of full content.","examples":["bytes */146515"],"type":"string"}
+			}
+		  }
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/files/{name}"].Get)
+}
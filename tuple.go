@@ -0,0 +1,9 @@
+package openapi
+
+// TupleDeclarer is implemented by types that are marshaled to JSON as an array of fixed items, e.g. a coordinate pair.
+//
+// Such types are reflected as arrays with prefixItems in OpenAPI 3.1, if Tuples option of reflector is enabled.
+type TupleDeclarer interface {
+	// TupleItems returns samples of array items in their order.
+	TupleItems() []interface{}
+}
@@ -0,0 +1,27 @@
+package openapi
+
+import "github.com/swaggest/jsonschema-go"
+
+// TypeMappingOption customizes schema of a mapped type.
+type TypeMappingOption func(s *jsonschema.Schema)
+
+// WithFormat is a TypeMappingOption to set schema format, e.g. "uuid".
+func WithFormat(format string) TypeMappingOption {
+	return func(s *jsonschema.Schema) {
+		s.WithFormat(format)
+	}
+}
+
+// WithExample is a TypeMappingOption to add schema example.
+func WithExample(example interface{}) TypeMappingOption {
+	return func(s *jsonschema.Schema) {
+		s.Examples = append(s.Examples[:len(s.Examples):len(s.Examples)], example)
+	}
+}
+
+// WithDescription is a TypeMappingOption to set schema description.
+func WithDescription(description string) TypeMappingOption {
+	return func(s *jsonschema.Schema) {
+		s.WithDescription(description)
+	}
+}
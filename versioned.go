@@ -0,0 +1,42 @@
+package openapi
+
+// ContentVersion describes structure of a version of vendor media type.
+type ContentVersion struct {
+	// ContentType is a versioned media type, e.g. "application/vnd.company.v2+json".
+	ContentType string
+	Structure   interface{}
+
+	// Deprecated marks an old version.
+	Deprecated bool
+}
+
+// AddVersionedReqStructures declares several versions of request media type for the same operation.
+//
+// Each version is added as a separate request structure, content map of request body is keyed by content type.
+// Parameters are reflected from every version, so they should be declared in a separate request structure.
+func AddVersionedReqStructures(oc OperationContext, versions []ContentVersion, options ...ContentOption) {
+	for _, v := range versions {
+		oc.AddReqStructure(v.Structure, v.options(options)...)
+	}
+}
+
+// AddVersionedRespStructures declares several versions of response media type for the same operation.
+//
+// Options apply to all versions, for example to set HTTP status.
+func AddVersionedRespStructures(oc OperationContext, versions []ContentVersion, options ...ContentOption) {
+	for _, v := range versions {
+		oc.AddRespStructure(v.Structure, v.options(options)...)
+	}
+}
+
+func (v ContentVersion) options(options []ContentOption) []ContentOption {
+	res := make([]ContentOption, 0, len(options)+2)
+	res = append(res, options...)
+	res = append(res, WithContentType(v.ContentType))
+
+	if v.Deprecated {
+		res = append(res, WithDeprecatedContentType())
+	}
+
+	return res
+}
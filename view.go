@@ -0,0 +1,23 @@
+package openapi
+
+// OperationViewer is implemented by operation contexts that support views of schemas,
+// operation contexts of openapi3 and openapi31 reflectors implement it.
+type OperationViewer interface {
+	// SetView selects a view of schemas, fields with `view` tag are only reflected if it lists selected view,
+	// e.g. `view:"admin"`, and components that depend on such fields receive view suffix, e.g. UserAdminView.
+	SetView(view string)
+
+	// View returns selected view of schemas, empty if all fields are reflected.
+	View() string
+}
+
+// View returns a Trait that selects a view of schemas for operation context that implements OperationViewer.
+//
+//	openapi.View("admin").Apply(oc)
+func View(view string) Trait {
+	return Trait{Setup: func(oc OperationContext) {
+		if v, ok := oc.(OperationViewer); ok {
+			v.SetView(view)
+		}
+	}}
+}
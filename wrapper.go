@@ -0,0 +1,9 @@
+package openapi
+
+// NullWrapper is implemented by types that hold nullable value, e.g. Nullable[T].
+//
+// Such types are reflected as nullable schema of wrapped value.
+type NullWrapper interface {
+	// NullWrapped returns a sample of wrapped value.
+	NullWrapped() interface{}
+}